@@ -20,21 +20,35 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	_ "backend/docs"
+	"backend/internal/auth"
+	"backend/internal/bankstatement"
+	"backend/internal/compliance"
 	"backend/internal/config"
 	"backend/internal/database"
+	"backend/internal/esign"
+	"backend/internal/estamp"
+	"backend/internal/gateway"
 	"backend/internal/handler"
+	"backend/internal/leasepdf"
 	"backend/internal/middleware"
+	"backend/internal/notify"
+	"backend/internal/policeform"
 	"backend/internal/repository"
+	"backend/internal/scheduler"
 	"backend/internal/service"
+	"backend/internal/stampduty"
+	"backend/internal/storage"
 	customValidator "backend/internal/validator"
 )
 
@@ -50,9 +64,95 @@ func main() {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
+	tokens, err := auth.NewTokenManager(&cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to configure token manager: %v", err)
+	}
+
+	if cfg.OTP.Secret == "" {
+		log.Fatal("OTP_SECRET must be set")
+	}
+
+	smsSender, err := notify.NewSMSSender(&cfg.SMS)
+	if err != nil {
+		log.Fatalf("Failed to configure SMS sender: %v", err)
+	}
+
+	store, err := storage.NewStorage(&cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %v", err)
+	}
+
+	stampDuty, err := stampduty.Load(cfg.StampDuty.RulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load stamp duty rules: %v", err)
+	}
+
+	tenancyLaw, err := compliance.Load(cfg.Compliance.RulesPath)
+	if err != nil {
+		log.Fatalf("Failed to load compliance rules: %v", err)
+	}
+
+	fonts, err := leasepdf.LoadFonts(cfg.LeasePDF.FontDir)
+	if err != nil {
+		log.Fatalf("Failed to load lease fonts: %v", err)
+	}
+
+	policeForms, err := policeform.Load(cfg.PoliceForm.FormatsPath)
+	if err != nil {
+		log.Fatalf("Failed to load police verification form formats: %v", err)
+	}
+
+	bankFormats, err := bankstatement.Load(cfg.Bank.FormatsPath)
+	if err != nil {
+		log.Fatalf("Failed to load bank statement formats: %v", err)
+	}
+
+	estamps, err := estamp.NewEStampProvider(&cfg.EStamp)
+	if err != nil {
+		log.Fatalf("Failed to configure e-stamp provider: %v", err)
+	}
+
+	if cfg.ESign.Provider == "" {
+		log.Fatal("ESIGN_PROVIDER must be set")
+	}
+	if cfg.ESign.Provider == "simulator" && cfg.IsProduction() {
+		log.Fatal("The eSign simulator cannot be used in production")
+	}
+
+	esigner, err := esign.NewESignProvider(&cfg.ESign)
+	if err != nil {
+		log.Fatalf("Failed to configure eSign provider: %v", err)
+	}
+
+	if cfg.Gateway.Provider == "" {
+		log.Fatal("PAYMENT_GATEWAY_PROVIDER must be set")
+	}
+	if cfg.Gateway.Provider == "simulator" && cfg.IsProduction() {
+		log.Fatal("The payment gateway simulator cannot be used in production")
+	}
+
+	payments, err := gateway.NewPaymentGateway(&cfg.Gateway)
+	if err != nil {
+		log.Fatalf("Failed to configure payment gateway: %v", err)
+	}
+
 	repos := repository.NewRepositories(db)
-	services := service.NewServices(db, repos)
-	handlers := handler.NewHandlers(services)
+	services := service.NewServices(db, repos, service.Deps{
+		Config:      cfg,
+		Tokens:      tokens,
+		SMS:         smsSender,
+		Storage:     store,
+		StampDuty:   stampDuty,
+		Compliance:  tenancyLaw,
+		Fonts:       fonts,
+		PoliceForms: policeForms,
+		EStamp:      estamps,
+		ESign:       esigner,
+		Gateway:     payments,
+		BankFormats: bankFormats,
+	})
+	handlers := handler.NewHandlers(services, cfg)
 
 	e := echo.New()
 	e.Validator = customValidator.NewValidator()
@@ -61,8 +161,40 @@ func main() {
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
+	// The simulator also serves the ESP pages the signer's browser is sent to
+	if simulator, ok := esigner.(*esign.Simulator); ok && !cfg.IsProduction() {
+		e.Any(simulator.Path(), echo.WrapHandler(simulator))
+	}
+
+	// So does the payment gateway simulator's checkout page
+	if simulator, ok := payments.(*gateway.Simulator); ok && !cfg.IsProduction() {
+		e.Any(simulator.Path(), echo.WrapHandler(simulator))
+	}
+
 	api := e.Group("/api/v1")
-	handler.RegisterRoutes(api, handlers)
+	handler.RegisterRoutes(api, handlers, middleware.Auth(services.Auth))
+
+	jobs := scheduler.Start(scheduler.Job{
+		Name:     "lease renewals",
+		Interval: time.Duration(cfg.Renewal.JobInterval) * time.Minute,
+		Run: func(ctx context.Context) error {
+			drafted, err := services.Lease.DraftDueRenewals(ctx)
+			if drafted > 0 {
+				log.Printf("Drafted %d lease renewals", drafted)
+			}
+			return err
+		},
+	}, scheduler.Job{
+		Name:     "rent charges",
+		Interval: time.Duration(cfg.Ledger.JobInterval) * time.Minute,
+		Run: func(ctx context.Context) error {
+			posted, err := services.Ledger.PostDueCharges(ctx)
+			if posted > 0 {
+				log.Printf("Posted %d ledger entries", posted)
+			}
+			return err
+		},
+	})
 
 	go func() {
 		log.Printf("Server starting on port %s", cfg.Port)
@@ -76,6 +208,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	jobs.Stop()
 	if err := database.Close(); err != nil {
 		log.Printf("Error closing database: %v", err)
 	}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+)
+
+// GenerateOTP returns a random numeric code of the given length
+func GenerateOTP(length int) (string, error) {
+	var b strings.Builder
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(byte('0' + n.Int64()))
+	}
+	return b.String(), nil
+}
+
+// HashOTP returns a keyed hash of the code bound to the phone number it was sent to
+func HashOTP(secret, phone, code string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(phone))
+	mac.Write([]byte{':'})
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckOTP compares a submitted code with a stored hash in constant time
+func CheckOTP(secret, phone, code, hash string) bool {
+	return hmac.Equal([]byte(HashOTP(secret, phone, code)), []byte(hash))
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrMalformedRefreshToken = errors.New("malformed refresh token")
+
+// GenerateRefreshToken returns an opaque refresh token of the form
+// <session id>.<random secret> together with the hash to store
+func GenerateRefreshToken(sessionID uuid.UUID) (token string, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	return sessionID.String() + "." + encoded, HashRefreshSecret(encoded), nil
+}
+
+// ParseRefreshToken splits a refresh token into its session ID and secret
+func ParseRefreshToken(token string) (uuid.UUID, string, error) {
+	sid, secret, found := strings.Cut(token, ".")
+	if !found || secret == "" {
+		return uuid.Nil, "", ErrMalformedRefreshToken
+	}
+	sessionID, err := uuid.Parse(sid)
+	if err != nil {
+		return uuid.Nil, "", ErrMalformedRefreshToken
+	}
+	return sessionID, secret, nil
+}
+
+// HashRefreshSecret returns the SHA-256 hex digest stored for a refresh secret
+func HashRefreshSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
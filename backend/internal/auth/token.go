@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// Claims are the JWT claims carried by an access token
+type Claims struct {
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the subject of the token as a UUID
+func (c *Claims) UserID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// Session returns the ID of the session the token was issued for
+func (c *Claims) Session() (uuid.UUID, error) {
+	return uuid.Parse(c.SessionID)
+}
+
+// TokenManager issues and verifies signed access tokens
+type TokenManager struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	issuer    string
+	ttl       time.Duration
+}
+
+// NewTokenManager builds a TokenManager from the auth configuration
+func NewTokenManager(cfg *config.AuthConfig) (*TokenManager, error) {
+	tm := &TokenManager{
+		issuer: cfg.JWTIssuer,
+		ttl:    time.Duration(cfg.AccessTokenTTL) * time.Minute,
+	}
+
+	switch cfg.JWTAlgorithm {
+	case AlgorithmHS256:
+		if cfg.JWTSecret == "" {
+			return nil, errors.New("JWT_SECRET must be set for HS256")
+		}
+		tm.method = jwt.SigningMethodHS256
+		tm.signKey = []byte(cfg.JWTSecret)
+		tm.verifyKey = []byte(cfg.JWTSecret)
+	case AlgorithmEdDSA:
+		seed, err := base64.StdEncoding.DecodeString(cfg.JWTPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode JWT_PRIVATE_KEY: %w", err)
+		}
+		if len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY must be a %d byte Ed25519 seed", ed25519.SeedSize)
+		}
+		privateKey := ed25519.NewKeyFromSeed(seed)
+		tm.method = jwt.SigningMethodEdDSA
+		tm.signKey = privateKey
+		tm.verifyKey = privateKey.Public()
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", cfg.JWTAlgorithm)
+	}
+
+	return tm, nil
+}
+
+// Issue signs a new access token for the given user and session
+func (tm *TokenManager) Issue(userID uuid.UUID, role string, sessionID uuid.UUID) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(tm.ttl)
+
+	claims := Claims{
+		Role:      role,
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userID.String(),
+			Issuer:    tm.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(tm.method, claims).SignedString(tm.signKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+// Verify parses the token and checks its signature, algorithm, issuer and expiry
+func (tm *TokenManager) Verify(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return tm.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{tm.method.Alg()}),
+		jwt.WithIssuer(tm.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,357 @@
+// Package bankstatement reads the credits out of bank statement exports, so
+// rent received by NEFT, IMPS or UPI can be matched to what was due. Each
+// bank exports its own columns; formats.json maps them per bank and is
+// embedded as the default, and BANK_STATEMENT_FORMATS_PATH can point to a
+// newer copy. CSV, XLSX, Excel 97-2003 XLS and the HTML tables several banks
+// save as .xls are read.
+package bankstatement
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed formats.json
+var defaultFormats []byte
+
+var (
+	ErrUnknownBank = errors.New("unknown bank")
+	// ErrUnsupportedFile means the file is not a CSV, XLSX, XLS or HTML table export
+	ErrUnsupportedFile = errors.New("unsupported statement file")
+	// ErrNoHeader means no row names the bank's date, narration and credit columns
+	ErrNoHeader = errors.New("statement columns not found")
+)
+
+// headerSearchRows is how far down a statement the header row is looked for
+const headerSearchRows = 60
+
+// File is the parsed formats file
+type File struct {
+	Version     string            `json:"version"`
+	Description string            `json:"description"`
+	Banks       map[string]Format `json:"banks"`
+}
+
+// Format is the layout of one bank's statement export
+type Format struct {
+	Name    string  `json:"name"`
+	Columns Columns `json:"columns"`
+	// CreditMarkers are the values of the type column that mark a credit
+	CreditMarkers []string `json:"credit_markers"`
+	// DateFormats are Go layouts, tried in order
+	DateFormats []string `json:"date_formats"`
+}
+
+// Columns lists the names each column goes by
+type Columns struct {
+	Date      []string `json:"date"`
+	Narration []string `json:"narration"`
+	Reference []string `json:"reference"`
+	Credit    []string `json:"credit"`
+	Debit     []string `json:"debit"`
+	Amount    []string `json:"amount"`
+	Type      []string `json:"type"`
+}
+
+// Bank is a bank whose exports can be read
+type Bank struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Credit is money received, as a statement row shows it
+type Credit struct {
+	// Row is the row's number in the file, counting from 1
+	Row         int
+	Date        time.Time
+	AmountPaise int64
+	Narration   string
+	Reference   string
+	// UTR is the bank's transaction reference, from the reference column or the narration
+	UTR string
+	// PayerName is the remitter's name as far as the narration shows it
+	PayerName string
+}
+
+// Statement is what was read from a statement file
+type Statement struct {
+	Bank    string
+	Credits []Credit
+	// Debits counts the rows skipped as money paid out
+	Debits int
+	// Unreadable counts the rows skipped because their amount could not be read
+	Unreadable int
+}
+
+// Formats holds the loaded formats
+type Formats struct {
+	version string
+	banks   map[string]Format
+}
+
+// Load reads the formats file at path, or the embedded formats when path is empty
+func Load(path string) (*Formats, error) {
+	data := defaultFormats
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read bank statement formats: %w", err)
+		}
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse bank statement formats: %w", err)
+	}
+	if file.Version == "" {
+		return nil, errors.New("bank statement formats: version is required")
+	}
+	for code, format := range file.Banks {
+		if err := format.validate(code); err != nil {
+			return nil, err
+		}
+	}
+	return &Formats{version: file.Version, banks: file.Banks}, nil
+}
+
+func (f *Format) validate(code string) error {
+	switch {
+	case f.Name == "":
+		return fmt.Errorf("bank statement formats: %s has no name", code)
+	case len(f.Columns.Date) == 0 || len(f.Columns.Narration) == 0:
+		return fmt.Errorf("bank statement formats: %s needs date and narration columns", code)
+	case len(f.Columns.Credit) == 0 && (len(f.Columns.Amount) == 0 || len(f.Columns.Type) == 0 || len(f.CreditMarkers) == 0):
+		return fmt.Errorf("bank statement formats: %s needs a credit column, or amount and type columns with credit markers", code)
+	case len(f.DateFormats) == 0:
+		return fmt.Errorf("bank statement formats: %s has no date formats", code)
+	}
+	return nil
+}
+
+// Version identifies the formats file in use
+func (f *Formats) Version() string {
+	return f.version
+}
+
+// Banks lists the banks whose exports can be read, by name
+func (f *Formats) Banks() []Bank {
+	banks := make([]Bank, 0, len(f.banks))
+	for code, format := range f.banks {
+		banks = append(banks, Bank{Code: code, Name: format.Name})
+	}
+	slices.SortFunc(banks, func(a, b Bank) int { return strings.Compare(a.Name, b.Name) })
+	return banks
+}
+
+// Parse reads the credits of a statement exported by bank. Rows whose date
+// cannot be read, such as separators, totals and the notes banks print below
+// the table, are passed over.
+func (f *Formats) Parse(bank string, data []byte) (*Statement, error) {
+	format, ok := f.banks[bank]
+	if !ok {
+		return nil, ErrUnknownBank
+	}
+	rows, err := readTable(data)
+	if err != nil {
+		return nil, err
+	}
+
+	header, cols, ok := findHeader(rows, &format)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a row naming %s", ErrNoHeader, strings.Join([]string{format.Columns.Date[0], format.Columns.Narration[0], format.creditColumn()}, ", "))
+	}
+
+	statement := &Statement{Bank: bank, Credits: []Credit{}}
+	for i := header + 1; i < len(rows); i++ {
+		row := rows[i]
+		date, ok := parseDate(cell(row, cols.date), format.DateFormats)
+		if !ok {
+			continue
+		}
+
+		var value string
+		if cols.credit >= 0 {
+			value = cell(row, cols.credit)
+		} else if format.isCredit(cell(row, cols.kind)) {
+			value = cell(row, cols.amount)
+		}
+		amount, ok := parsePaise(value)
+		if !ok {
+			statement.Unreadable++
+			continue
+		}
+		if amount <= 0 {
+			statement.Debits++
+			continue
+		}
+
+		narration := collapseSpace(cell(row, cols.narration))
+		reference := collapseSpace(cell(row, cols.reference))
+		statement.Credits = append(statement.Credits, Credit{
+			Row:         i + 1,
+			Date:        date,
+			AmountPaise: amount,
+			Narration:   narration,
+			Reference:   reference,
+			UTR:         UTR(reference, narration),
+			PayerName:   PayerName(narration),
+		})
+	}
+	return statement, nil
+}
+
+func (f *Format) creditColumn() string {
+	if len(f.Columns.Credit) > 0 {
+		return f.Columns.Credit[0]
+	}
+	return f.Columns.Amount[0]
+}
+
+func (f *Format) isCredit(kind string) bool {
+	kind = normalize(kind)
+	for _, marker := range f.CreditMarkers {
+		if kind == normalize(marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnIndexes are the positions of a format's columns in the header; -1 when absent
+type columnIndexes struct {
+	date, narration, reference, credit, amount, kind int
+}
+
+// findHeader returns the first row naming the format's date, narration and
+// credit columns, and where each column is
+func findHeader(rows [][]string, format *Format) (int, columnIndexes, bool) {
+	for i := 0; i < len(rows) && i < headerSearchRows; i++ {
+		cols := columnIndexes{
+			date:      column(rows[i], format.Columns.Date),
+			narration: column(rows[i], format.Columns.Narration),
+			reference: column(rows[i], format.Columns.Reference),
+			credit:    column(rows[i], format.Columns.Credit),
+			amount:    column(rows[i], format.Columns.Amount),
+			kind:      column(rows[i], format.Columns.Type),
+		}
+		if cols.date < 0 || cols.narration < 0 {
+			continue
+		}
+		if cols.credit >= 0 || (cols.amount >= 0 && cols.kind >= 0) {
+			return i, cols, true
+		}
+	}
+	return 0, columnIndexes{}, false
+}
+
+// column returns the position of the first cell of row going by one of names
+func column(row []string, names []string) int {
+	for i, value := range row {
+		value = normalize(value)
+		for _, name := range names {
+			if value != "" && value == normalize(name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func cell(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// normalize keeps only the letters and digits of s, in lower case
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// excelEpoch is day zero of spreadsheet date serials
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// parseDate reads a date in one of layouts, ignoring a time of day after it.
+// XLSX files may hold the date as a spreadsheet serial number instead.
+func parseDate(value string, layouts []string) (time.Time, bool) {
+	value = collapseSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	candidates := []string{value}
+	if fields := strings.Fields(value); len(fields) > 1 && strings.Contains(fields[len(fields)-1], ":") {
+		candidates = append(candidates, strings.Join(fields[:len(fields)-1], " "))
+	}
+	for _, candidate := range candidates {
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, candidate); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 20000 && serial < 80000 {
+		return excelEpoch.AddDate(0, 0, int(serial)), true
+	}
+	return time.Time{}, false
+}
+
+// parsePaise reads an amount in rupees such as "1,50,000.00", "₹ 15000" or
+// "15000.00 Cr". A blank amount, as the credit column of a debit shows,
+// reads as zero. It reports false for an unreadable amount.
+func parsePaise(value string) (int64, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	for _, affix := range []string{"₹", "INR", "RS.", "RS", "CR", "DR", ",", " "} {
+		value = strings.ReplaceAll(value, affix, "")
+	}
+	if value == "" || value == "-" {
+		return 0, true
+	}
+	if strings.ContainsAny(value, "E") {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false
+		}
+		return int64(math.Round(f * 100)), true
+	}
+
+	rupees, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > 2 {
+		if strings.Trim(fraction[2:], "0") != "" {
+			return 0, false
+		}
+		fraction = fraction[:2]
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+	r, err := strconv.ParseInt(rupees, 10, 64)
+	if err != nil && rupees != "" {
+		return 0, false
+	}
+	p, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil || p < 0 {
+		return 0, false
+	}
+	if r < 0 {
+		return r*100 - p, true
+	}
+	return r*100 + p, true
+}
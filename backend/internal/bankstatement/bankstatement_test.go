@@ -0,0 +1,764 @@
+package bankstatement
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func TestParsePaise(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   int64
+		wantOK bool
+	}{
+		{"15000", 1500000, true},
+		{"15000.00", 1500000, true},
+		{"15,000.50", 1500050, true},
+		{"1,50,000.00", 15000000, true},
+		{"12,34,56,789.05", 12345678905, true},
+		{"₹ 15,000", 1500000, true},
+		{"₹15000.5", 1500050, true},
+		{"INR 2,500.00", 250000, true},
+		{"Rs. 500", 50000, true},
+		{"Rs 500.75", 50075, true},
+		{"15000.00 Cr", 1500000, true},
+		{"15000.00 Dr", 1500000, true},
+		{"15000.00CR", 1500000, true},
+		{".50", 50, true},
+		{"-250.75", -25075, true},
+		{"15000.000", 1500000, true},
+		{"1.5E+4", 1500000, true},
+		{"1.50E+05", 15000000, true},
+		{"", 0, true},
+		{"  ", 0, true},
+		{"-", 0, true},
+		// float artefacts in text are not rounded away, as they are in XLSX cells
+		{"15000.000000000002", 0, false},
+		{"15000.005", 0, false},
+		{"abc", 0, false},
+		{"n/a", 0, false},
+		{"15,000.00.00", 0, false},
+		{"1.5E", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parsePaise(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parsePaise(%q) = %d, %v; want %d, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRoundNumber(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"15000", "15000"},
+		{"15000.5", "15000.5"},
+		{"15000.000000000002", "15000"},
+		{"14999.999999999998", "15000"},
+		{"1234.5600000000001", "1234.56"},
+		{"0.30000000000000004", "0.3"},
+		{"1.5E+4", "15000"},
+		{"45387.604166666664", "45387.6"},
+		{"123456789012", "123456789012"},
+		{"abc", "abc"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := roundNumber(tt.value); got != tt.want {
+				t.Errorf("roundNumber(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	layouts := []string{"02/01/2006", "02-Jan-2006", "2 Jan 2006"}
+	april5 := time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Time
+		wantOK bool
+	}{
+		{"05/04/2024", april5, true},
+		{"05-Apr-2024", april5, true},
+		{"5 Apr 2024", april5, true},
+		{" 05/04/2024  ", april5, true},
+		{"05/04/2024 14:32:10", april5, true},
+		{"45387", april5, true},
+		{"45387.6", april5, true},
+		{"", time.Time{}, false},
+		{"Opening Balance", time.Time{}, false},
+		{"31/02/2024", time.Time{}, false},
+		{"12", time.Time{}, false},
+		{"2024-04-05", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseDate(tt.value, layouts)
+			if !got.Equal(tt.want) || ok != tt.wantOK {
+				t.Errorf("parseDate(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func testFormats(t *testing.T) *Formats {
+	t.Helper()
+	formats, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return formats
+}
+
+func TestFindHeader(t *testing.T) {
+	formats := testFormats(t)
+	details := [][]string{
+		{"Account Name", "ANITA SHARMA"},
+		{"Account Number", "XXXXXXXX1234"},
+		{"Statement Period", "01/04/2024 to 30/04/2024"},
+		{},
+	}
+	padding := make([][]string, headerSearchRows)
+
+	tests := []struct {
+		name     string
+		bank     string
+		rows     [][]string
+		wantRow  int
+		wantCols columnIndexes
+		wantOK   bool
+	}{
+		{
+			name:     "first row",
+			bank:     "generic",
+			rows:     [][]string{{"Date", "Narration", "Ref No", "Debit", "Credit", "Balance"}},
+			wantCols: columnIndexes{date: 0, narration: 1, reference: 2, credit: 4, amount: -1, kind: -1},
+			wantOK:   true,
+		},
+		{
+			name:     "below the account details",
+			bank:     "generic",
+			rows:     append(slices.Clone(details), []string{"Txn Date", "Value Date", "Description", "Debit", "Credit", "Balance"}),
+			wantRow:  4,
+			wantCols: columnIndexes{date: 0, narration: 2, reference: -1, credit: 4, amount: -1, kind: -1},
+			wantOK:   true,
+		},
+		{
+			name:     "case, spacing and punctuation ignored",
+			bank:     "hdfc",
+			rows:     [][]string{{"DATE", " narration ", "CHQ/REF.NO", "WITHDRAWAL AMT", "deposit-amt"}},
+			wantCols: columnIndexes{date: 0, narration: 1, reference: 2, credit: 4, amount: -1, kind: -1},
+			wantOK:   true,
+		},
+		{
+			name:     "amount and type columns",
+			bank:     "kotak",
+			rows:     [][]string{{"Sl. No.", "Transaction Date", "Description", "Chq / Ref No.", "Amount", "Dr / Cr", "Balance"}},
+			wantCols: columnIndexes{date: 1, narration: 2, reference: 3, credit: -1, amount: 4, kind: 5},
+			wantOK:   true,
+		},
+		{
+			name: "amount without a type column",
+			bank: "kotak",
+			rows: [][]string{{"Transaction Date", "Description", "Amount", "Balance"}},
+		},
+		{
+			name: "no credit column",
+			bank: "generic",
+			rows: [][]string{{"Date", "Narration", "Debit", "Balance"}},
+		},
+		{
+			name: "another bank's columns",
+			bank: "sbi",
+			rows: [][]string{{"Date", "Narration", "Chq./Ref.No.", "Withdrawal Amt.", "Deposit Amt."}},
+		},
+		{
+			name: "too far down",
+			bank: "generic",
+			rows: append(slices.Clone(padding), []string{"Date", "Narration", "Credit"}),
+		},
+		{
+			name: "empty",
+			bank: "generic",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format := formats.banks[tt.bank]
+			row, cols, ok := findHeader(tt.rows, &format)
+			if ok != tt.wantOK {
+				t.Fatalf("findHeader found = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && (row != tt.wantRow || cols != tt.wantCols) {
+				t.Errorf("findHeader = row %d %+v, want row %d %+v", row, cols, tt.wantRow, tt.wantCols)
+			}
+		})
+	}
+}
+
+// zipFile builds a zip archive of the given files
+func zipFile(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("zip: %v", err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("zip: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// xlsxFile builds a workbook holding one worksheet with the given rows XML.
+// Strings are stored as shared strings in the order given.
+func xlsxFile(t *testing.T, sheetPath, rowsXML string, shared ...string) []byte {
+	t.Helper()
+	parts := map[string]string{
+		sheetPath: `<?xml version="1.0" encoding="UTF-8"?><worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rowsXML + `</sheetData></worksheet>`,
+	}
+	if sheetPath != "xl/worksheets/sheet1.xml" {
+		parts["xl/workbook.xml"] = `<?xml version="1.0" encoding="UTF-8"?><workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Statement" sheetId="1" r:id="rId1"/></sheets></workbook>`
+		parts["xl/_rels/workbook.xml.rels"] = `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId2" Target="styles.xml"/><Relationship Id="rId1" Target="` + strings.TrimPrefix(sheetPath, "xl/") + `"/></Relationships>`
+	}
+	if len(shared) > 0 {
+		var b strings.Builder
+		b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+		for _, s := range shared {
+			fmt.Fprintf(&b, "<si><t>%s</t></si>", s)
+		}
+		b.WriteString(`</sst>`)
+		parts["xl/sharedStrings.xml"] = b.String()
+	}
+	return zipFile(t, parts)
+}
+
+// xlsRecord encodes a BIFF record; each value is written little-endian
+func xlsRecord(kind uint16, values ...any) []byte {
+	var body bytes.Buffer
+	for _, v := range values {
+		if err := binary.Write(&body, binary.LittleEndian, v); err != nil {
+			panic(err)
+		}
+	}
+	return append(binary.LittleEndian.AppendUint16(binary.LittleEndian.AppendUint16(nil, kind), uint16(body.Len())), body.Bytes()...)
+}
+
+// xlsString encodes a BIFF8 string with a 16-bit character count
+func xlsString(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := binary.LittleEndian.AppendUint16(nil, uint16(len(units)))
+	for _, u := range units {
+		if u > 0xFF {
+			out = append(out, 1)
+			for _, u := range units {
+				out = binary.LittleEndian.AppendUint16(out, u)
+			}
+			return out
+		}
+	}
+	out = append(out, 0)
+	for _, u := range units {
+		out = append(out, byte(u))
+	}
+	return out
+}
+
+// xlsWorkbook builds a BIFF8 workbook stream with the given globals and one
+// worksheet of cell records
+func xlsWorkbook(globals []byte, cells ...[]byte) []byte {
+	bof := func(kind uint16) []byte {
+		return xlsRecord(0x0809, uint16(0x0600), kind, uint32(0), uint32(0), uint32(0))
+	}
+	eof := xlsRecord(0x000A)
+	sheetName := append([]byte{9, 0}, "Statement"...)
+	head := append(bof(0x0005), globals...)
+	offset := len(head) + len(xlsRecord(0x0085, uint32(0), uint16(0), sheetName)) + len(eof)
+
+	stream := append(head, xlsRecord(0x0085, uint32(offset), uint16(0), sheetName)...)
+	stream = append(stream, eof...)
+	stream = append(stream, bof(0x0010)...)
+	for _, c := range cells {
+		stream = append(stream, c...)
+	}
+	return append(stream, eof...)
+}
+
+// xlsFile keeps a workbook stream in a compound file with 512-byte sectors,
+// in the mini stream when it is under the 4096-byte cutoff as Excel does
+func xlsFile(stream []byte) []byte {
+	const sector, mini = 512, 64
+	le := binary.LittleEndian
+	fat := []uint32{0xFFFFFFFD}
+	var body []byte
+	alloc := func(data []byte) uint32 {
+		start := uint32(len(fat))
+		n := max((len(data)+sector-1)/sector, 1)
+		for i := range n {
+			next := uint32(len(fat)) + 1
+			if i == n-1 {
+				next = 0xFFFFFFFE
+			}
+			fat = append(fat, next)
+		}
+		body = append(body, data...)
+		body = append(body, make([]byte, n*sector-len(data))...)
+		return start
+	}
+
+	streamStart, rootStart, rootSize, miniFATStart := uint32(0xFFFFFFFE), uint32(0xFFFFFFFE), 0, uint32(0xFFFFFFFE)
+	if len(stream) >= 4096 {
+		streamStart = alloc(stream)
+	} else {
+		n := (len(stream) + mini - 1) / mini
+		var miniFAT []byte
+		for i := range n {
+			next := uint32(i + 1)
+			if i == n-1 {
+				next = 0xFFFFFFFE
+			}
+			miniFAT = le.AppendUint32(miniFAT, next)
+		}
+		for len(miniFAT) < sector {
+			miniFAT = le.AppendUint32(miniFAT, 0xFFFFFFFF)
+		}
+		padded := append(stream[:len(stream):len(stream)], make([]byte, n*mini-len(stream))...)
+		miniFATStart = alloc(miniFAT)
+		rootStart, rootSize, streamStart = alloc(padded), len(padded), 0
+	}
+
+	entry := func(name string, kind byte, child, start uint32, size int) []byte {
+		e := make([]byte, 128)
+		units := utf16.Encode([]rune(name))
+		for i, u := range units {
+			le.PutUint16(e[2*i:], u)
+		}
+		le.PutUint16(e[0x40:], uint16(2*len(units)+2))
+		e[0x42], e[0x43] = kind, 1
+		le.PutUint32(e[0x44:], 0xFFFFFFFF)
+		le.PutUint32(e[0x48:], 0xFFFFFFFF)
+		le.PutUint32(e[0x4C:], child)
+		le.PutUint32(e[0x74:], start)
+		le.PutUint64(e[0x78:], uint64(size))
+		return e
+	}
+	directory := append(entry("Root Entry", 5, 1, rootStart, rootSize), entry("Workbook", 2, 0xFFFFFFFF, streamStart, len(stream))...)
+	dirStart := alloc(directory)
+
+	header := make([]byte, sector)
+	copy(header, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1})
+	le.PutUint16(header[0x18:], 0x3E)
+	le.PutUint16(header[0x1A:], 3)
+	le.PutUint16(header[0x1C:], 0xFFFE)
+	le.PutUint16(header[0x1E:], 9)
+	le.PutUint16(header[0x20:], 6)
+	le.PutUint32(header[0x2C:], 1)
+	le.PutUint32(header[0x30:], dirStart)
+	le.PutUint32(header[0x38:], 4096)
+	le.PutUint32(header[0x3C:], miniFATStart)
+	if miniFATStart != 0xFFFFFFFE {
+		le.PutUint32(header[0x40:], 1)
+	}
+	le.PutUint32(header[0x44:], 0xFFFFFFFE)
+	for i := range 109 {
+		le.PutUint32(header[0x4C+4*i:], 0xFFFFFFFF)
+	}
+	le.PutUint32(header[0x4C:], 0)
+
+	fatSector := make([]byte, 0, sector)
+	for _, id := range fat {
+		fatSector = le.AppendUint32(fatSector, id)
+	}
+	for len(fatSector) < sector {
+		fatSector = le.AppendUint32(fatSector, 0xFFFFFFFF)
+	}
+	return append(append(header, fatSector...), body...)
+}
+
+// xlsStatement is a small statement exercising the shared string table, a
+// string split across a CONTINUE record and each kind of number cell
+func xlsStatement(padding int) []byte {
+	narration := xlsString("NEFT-RAVI KUMAR")
+	// The narration breaks after "NEFT-" and carries on in UTF-16
+	rest := []byte{1}
+	for _, r := range "RAVI KUMAR" {
+		rest = binary.LittleEndian.AppendUint16(rest, uint16(r))
+	}
+	// A rich string with one formatting run of four bytes after its characters
+	rich := append([]byte{4, 0, 0x08, 1, 0}, "Note"...)
+	rich = append(rich, 0, 0, 1, 0)
+	sst := slices.Concat(xlsString("Date"), xlsString("Narration"), xlsString("Credit"), rich, narration[:3+5])
+	globals := slices.Concat(
+		xlsRecord(0x00FC, uint32(5), uint32(5), sst),
+		xlsRecord(0x003C, rest),
+	)
+
+	rk := func(row, col uint16, rk uint32) []byte { return xlsRecord(0x027E, row, col, uint16(0), rk) }
+	cells := [][]byte{
+		xlsRecord(0x00FD, uint16(0), uint16(0), uint16(0), uint32(0)),
+		xlsRecord(0x00FD, uint16(0), uint16(1), uint16(0), uint32(1)),
+		xlsRecord(0x00FD, uint16(0), uint16(2), uint16(0), uint32(2)),
+		xlsRecord(0x0204, uint16(1), uint16(0), uint16(0), xlsString("05/04/2024")),
+		xlsRecord(0x00FD, uint16(1), uint16(1), uint16(0), uint32(4)),
+		// 1500000 hundredths
+		rk(1, 2, 1500000<<2|3),
+		xlsRecord(0x0203, uint16(2), uint16(0), uint16(0), math.Float64bits(45388)),
+		xlsRecord(0x00FD, uint16(2), uint16(1), uint16(0), uint32(3)),
+		xlsRecord(0x0203, uint16(2), uint16(2), uint16(0), math.Float64bits(0.1+0.2)),
+		// Row 3 is empty; row 4 holds 2.5 as a float RK, 7 as an integer RK and a formula
+		xlsRecord(0x00BD, uint16(4), uint16(0), uint16(0), uint32(math.Float64bits(2.5)>>32), uint16(0), uint32(7<<2|2), uint16(1)),
+		xlsRecord(0x0006, uint16(4), uint16(2), uint16(0), [6]byte{}, uint16(0xFFFF), uint16(0), uint32(0), uint16(0)),
+		xlsRecord(0x0207, xlsString("किराया")),
+		xlsRecord(0x0205, uint16(4), uint16(3), uint16(0), uint8(1), uint8(0)),
+	}
+	if padding > 0 {
+		// BIFF records not read, such as those of cell formats, only make the stream longer
+		cells = append(cells, bytes.Repeat(xlsRecord(0x00E0, [16]byte{}), padding/20+1))
+	}
+	return xlsFile(xlsWorkbook(globals, cells...))
+}
+
+func TestReadTable(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want [][]string
+	}{
+		{
+			name: "csv",
+			data: []byte("Date,Narration,Credit\n05/04/2024,\"NEFT-RAVI KUMAR, RENT\",\"15,000.00\"\n"),
+			want: [][]string{{"Date", "Narration", "Credit"}, {"05/04/2024", "NEFT-RAVI KUMAR, RENT", "15,000.00"}},
+		},
+		{
+			name: "csv with a byte order mark and ragged rows",
+			data: []byte("\xEF\xBB\xBFAccount,1234\nDate, Narration, Credit\n05/04/2024, UPI, 100\n"),
+			want: [][]string{{"Account", "1234"}, {"Date", "Narration", "Credit"}, {"05/04/2024", "UPI", "100"}},
+		},
+		{
+			name: "csv with blank lines and a quoted line break",
+			data: []byte("Account,1234\n\nDate,Narration,Credit\n05/04/2024,\"UPI\nRAVI\",100\n\n06/04/2024,NEFT,200\n"),
+			want: [][]string{{"Account", "1234"}, nil, {"Date", "Narration", "Credit"}, {"05/04/2024", "UPI\nRAVI", "100"}, nil, nil, {"06/04/2024", "NEFT", "200"}},
+		},
+		{
+			name: "tab separated with empty cells",
+			data: []byte("Date\tNarration\tDebit\tCredit\n05/04/2024\tUPI\t\t100\n"),
+			want: [][]string{{"Date", "Narration", "Debit", "Credit"}, {"05/04/2024", "UPI", "", "100"}},
+		},
+		{
+			name: "semicolon separated",
+			data: []byte("Date;Narration;Credit\n05/04/2024;UPI;1.234,00\n"),
+			want: [][]string{{"Date", "Narration", "Credit"}, {"05/04/2024", "UPI", "1.234,00"}},
+		},
+		{
+			name: "html table saved as xls",
+			data: []byte(`<html><head><style>td{}</style></head><body>
+				<table><tr><th>Date</th><th>Narration</th><th>Credit</th></tr>
+				<tr><td>05/04/2024</td><td><b>NEFT</b>-RAVI
+				KUMAR</td><td>15,000.00</td></tr></table></body></html>`),
+			want: [][]string{{"Date", "Narration", "Credit"}, {"05/04/2024", "NEFT -RAVI KUMAR", "15,000.00"}},
+		},
+		{
+			name: "bare html table",
+			data: []byte(`<table border="1"><tr><td>Date</td><td>Credit</td></tr><tr><td>05/04/2024</td><td>100</td></tr></table>`),
+			want: [][]string{{"Date", "Credit"}, {"05/04/2024", "100"}},
+		},
+		{
+			name: "xlsx with shared, inline and numeric cells",
+			data: xlsxFile(t, "xl/worksheets/sheet1.xml",
+				`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>`+
+					`<row r="2"><c r="A2"><v>45387</v></c><c r="B2" t="inlineStr"><is><t>NEFT-RAVI KUMAR</t></is></c><c r="C2" t="n"><v>15000.000000000002</v></c></row>`,
+				"Date", "Narration", "Credit"),
+			want: [][]string{{"Date", "Narration", "Credit"}, {"45387", "NEFT-RAVI KUMAR", "15000"}},
+		},
+		{
+			name: "xlsx with skipped cells and a renamed sheet",
+			data: xlsxFile(t, "xl/worksheets/statement.xml",
+				`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>`+
+					`<row r="2"><c r="B2" t="s"><v>2</v></c></row>`+
+					`<row r="3"><c><v>1</v></c><c><v>2</v></c></row>`,
+				"Date", "Credit", "Narration"),
+			want: [][]string{{"Date", "", "Credit"}, {"", "Narration"}, {"1", "2"}},
+		},
+		{
+			name: "xlsx with a stray cell far to the right",
+			data: xlsxFile(t, "xl/worksheets/sheet1.xml", `<row r="1"><c r="A1"><v>1</v></c><c r="XFD1"><v>2</v></c></row>`),
+			want: [][]string{{"1", "2"}},
+		},
+		{
+			name: "xlsx with a shared string index out of range",
+			data: xlsxFile(t, "xl/worksheets/sheet1.xml", `<row r="1"><c r="A1" t="s"><v>7</v></c><c r="B1" t="s"><v>-1</v></c></row>`, "Date"),
+			want: [][]string{{"", ""}},
+		},
+		{
+			name: "xls in the mini stream",
+			data: xlsStatement(0),
+			want: [][]string{{"Date", "Narration", "Credit"}, {"05/04/2024", "NEFT-RAVI KUMAR", "15000"}, {"45388", "Note", "0.3"}, nil, {"2.5", "7", "किराया", "true"}},
+		},
+		{
+			name: "xls in regular sectors",
+			data: xlsStatement(8192),
+			want: [][]string{{"Date", "Narration", "Credit"}, {"05/04/2024", "NEFT-RAVI KUMAR", "15000"}, {"45388", "Note", "0.3"}, nil, {"2.5", "7", "किराया", "true"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readTable(tt.data)
+			if err != nil {
+				t.Fatalf("readTable: %v", err)
+			}
+			if !slices.EqualFunc(rows, tt.want, slices.Equal) {
+				t.Errorf("readTable = %q, want %q", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadTableUnsupported(t *testing.T) {
+	tooManyRows := strings.Repeat("<row/>", maxSheetRows+1)
+	tooManyCells := strings.Repeat(`<row><c><v>1</v></c><c><v>2</v></c><c><v>3</v></c><c><v>4</v></c></row>`, maxSheetCells/4+1)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"compound file with an empty header", append([]byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}, make([]byte, 512)...)},
+		{"truncated xls", xlsStatement(8192)[:2048]},
+		{"xls without a worksheet", xlsFile(xlsRecord(0x0809, uint16(0x0600), uint16(0x0005)))},
+		{"password protected xls", xlsFile(xlsWorkbook(xlsRecord(0x002F, uint16(1))))},
+		{"excel 5.0 workbook", xlsFile(slices.Concat(xlsRecord(0x0809, uint16(0x0500), uint16(0x0005)), xlsRecord(0x000A)))},
+		{"xls shared strings cut short", xlsFile(xlsWorkbook(xlsRecord(0x00FC, uint32(2), uint32(2), xlsString("Date"))))},
+		{"binary", []byte{0x00, 0xFF, 0xFE, 0x80, 0x81}},
+		{"broken zip", append([]byte("PK\x03\x04"), make([]byte, 64)...)},
+		{"zip without a worksheet", zipFile(t, map[string]string{"docProps/app.xml": "<Properties/>"})},
+		{"xlsx column past XFD", xlsxFile(t, "xl/worksheets/sheet1.xml", `<row r="1"><c r="XFE1"><v>1</v></c></row>`)},
+		{"xlsx column reference far past XFD", xlsxFile(t, "xl/worksheets/sheet1.xml", `<row r="1"><c r="ZZZZZZZZZZZZ1"><v>1</v></c></row>`)},
+		{"xlsx cells run past XFD", xlsxFile(t, "xl/worksheets/sheet1.xml", `<row r="1"><c r="XFD1"><v>1</v></c><c><v>2</v></c></row>`)},
+		{"xlsx too many rows", xlsxFile(t, "xl/worksheets/sheet1.xml", tooManyRows)},
+		{"xlsx too many cells", xlsxFile(t, "xl/worksheets/sheet1.xml", tooManyCells)},
+		{"xlsx malformed worksheet", xlsxFile(t, "xl/worksheets/sheet1.xml", `<row><c><v>1</row>`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readTable(tt.data); !errors.Is(err, ErrUnsupportedFile) {
+				t.Errorf("readTable error = %v, want %v", err, ErrUnsupportedFile)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	formats := testFormats(t)
+	april := func(day int) time.Time { return time.Date(2024, 4, day, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name           string
+		bank           string
+		data           []byte
+		wantCredits    []Credit
+		wantDebits     int
+		wantUnreadable int
+	}{
+		{
+			name: "credit and debit columns",
+			bank: "generic",
+			data: []byte(`Account Name,ANITA SHARMA
+Account Number,XXXXXXXX1234
+
+Txn Date,Narration,Ref No,Debit,Credit,Balance
+05/04/2024,NEFT-HDFCN52024040512345678-RAVI KUMAR-RENT APRIL,,,"15,000.00","1,15,000.00"
+06/04/2024,ATM WDL,,"2,000.00",,"1,13,000.00"
+07/04/2024,UPI/409812345678/PRIYA S/rent@okaxis,,,abc,
+08/04/2024,IMPS/P2A/SURESH NAIR,000409912345678,,₹ 7500.50,
+09/04/2024,Reversal,,,0.00,
+,Total,,"2,000.00","22,500.50",
+** This is a computer generated statement **
+`),
+			wantCredits: []Credit{
+				{Row: 5, Date: april(5), AmountPaise: 1500000, Narration: "NEFT-HDFCN52024040512345678-RAVI KUMAR-RENT APRIL", UTR: "HDFCN52024040512345678", PayerName: "RAVI KUMAR"},
+				{Row: 8, Date: april(8), AmountPaise: 750050, Narration: "IMPS/P2A/SURESH NAIR", Reference: "000409912345678", UTR: "409912345678", PayerName: "SURESH NAIR"},
+			},
+			wantDebits:     2,
+			wantUnreadable: 1,
+		},
+		{
+			name: "amount and type columns",
+			bank: "kotak",
+			data: []byte("Transaction Date\tDescription\tChq / Ref No.\tAmount\tDr / Cr\tBalance\n" +
+				"05-04-2024\tUPI-RAVI KUMAR-409812345678\tUPI-409812345678\t15,000.00\tCR\t15,000.00\n" +
+				"06-04-2024\tATM WDL\t\t2,000.00\tDR\t13,000.00\n" +
+				"07-04-2024\tNEFT-PRIYA\t\tn/a\tCR\t\n" +
+				"07-04-2024\tCHARGES\t\tn/a\tDR\t\n"),
+			wantCredits: []Credit{
+				{Row: 2, Date: april(5), AmountPaise: 1500000, Narration: "UPI-RAVI KUMAR-409812345678", Reference: "UPI-409812345678", UTR: "409812345678", PayerName: "RAVI KUMAR"},
+			},
+			wantDebits:     2,
+			wantUnreadable: 1,
+		},
+		{
+			name: "xlsx",
+			bank: "hdfc",
+			data: xlsxFile(t, "xl/worksheets/sheet1.xml",
+				`<row><c t="s"><v>0</v></c><c t="s"><v>1</v></c><c t="s"><v>2</v></c><c t="s"><v>3</v></c><c t="s"><v>4</v></c></row>`+
+					`<row><c><v>45387</v></c><c t="s"><v>5</v></c><c t="s"><v>6</v></c><c/><c><v>14999.999999999998</v></c></row>`+
+					`<row><c t="s"><v>7</v></c><c t="s"><v>8</v></c><c/><c><v>500</v></c><c/></row>`,
+				"Date", "Narration", "Chq./Ref.No.", "Withdrawal Amt.", "Deposit Amt.",
+				"NEFT CR-ICIC0000001-RAVI KUMAR", "ICICN52024040500001234", "06/04/24", "CHEQUE"),
+			wantCredits: []Credit{
+				{Row: 2, Date: april(5), AmountPaise: 1500000, Narration: "NEFT CR-ICIC0000001-RAVI KUMAR", Reference: "ICICN52024040500001234", UTR: "ICICN52024040500001234", PayerName: "RAVI KUMAR"},
+			},
+			wantDebits: 1,
+		},
+		{
+			name: "xls",
+			bank: "hdfc",
+			data: xlsFile(xlsWorkbook(
+				xlsRecord(0x00FC, uint32(5), uint32(5), slices.Concat(xlsString("Date"), xlsString("Narration"), xlsString("Chq./Ref.No."), xlsString("Withdrawal Amt."), xlsString("Deposit Amt."))),
+				xlsRecord(0x00FD, uint16(0), uint16(0), uint16(0), uint32(0)),
+				xlsRecord(0x00FD, uint16(0), uint16(1), uint16(0), uint32(1)),
+				xlsRecord(0x00FD, uint16(0), uint16(2), uint16(0), uint32(2)),
+				xlsRecord(0x00FD, uint16(0), uint16(3), uint16(0), uint32(3)),
+				xlsRecord(0x00FD, uint16(0), uint16(4), uint16(0), uint32(4)),
+				xlsRecord(0x027E, uint16(1), uint16(0), uint16(0), uint32(45387<<2|2)),
+				xlsRecord(0x0204, uint16(1), uint16(1), uint16(0), xlsString("NEFT CR-ICIC0000001-RAVI KUMAR")),
+				xlsRecord(0x0204, uint16(1), uint16(2), uint16(0), xlsString("ICICN52024040500001234")),
+				xlsRecord(0x0203, uint16(1), uint16(4), uint16(0), math.Float64bits(14999.999999999998)),
+			)),
+			wantCredits: []Credit{
+				{Row: 2, Date: april(5), AmountPaise: 1500000, Narration: "NEFT CR-ICIC0000001-RAVI KUMAR", Reference: "ICICN52024040500001234", UTR: "ICICN52024040500001234", PayerName: "RAVI KUMAR"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, err := formats.Parse(tt.bank, tt.data)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if statement.Bank != tt.bank {
+				t.Errorf("Bank = %q, want %q", statement.Bank, tt.bank)
+			}
+			if !slices.EqualFunc(statement.Credits, tt.wantCredits, func(a, b Credit) bool { return a == b }) {
+				t.Errorf("Credits = %+v\nwant %+v", statement.Credits, tt.wantCredits)
+			}
+			if statement.Debits != tt.wantDebits || statement.Unreadable != tt.wantUnreadable {
+				t.Errorf("Debits, Unreadable = %d, %d; want %d, %d", statement.Debits, statement.Unreadable, tt.wantDebits, tt.wantUnreadable)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	formats := testFormats(t)
+	tests := []struct {
+		name    string
+		bank    string
+		data    []byte
+		wantErr error
+	}{
+		{"unknown bank", "nobank", []byte("Date,Narration,Credit\n"), ErrUnknownBank},
+		{"no header", "generic", []byte("Posted,Details,Amount\n05/04/2024,UPI,100\n"), ErrNoHeader},
+		{"empty file", "generic", []byte{}, ErrNoHeader},
+		{"truncated xls", "generic", []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}, ErrUnsupportedFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := formats.Parse(tt.bank, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUTR(t *testing.T) {
+	tests := []struct {
+		reference, narration string
+		want                 string
+	}{
+		{"", "NEFT-HDFCN52024040512345678-RAVI KUMAR", "HDFCN52024040512345678"},
+		{"", "UPI/409812345678/RAVI/ravi@okhdfc", "409812345678"},
+		{"", "IMPS-409812345678-RAVI", "409812345678"},
+		{"0000409812345678", "IMPS", "409812345678"},
+		{"SBIN424096123456", "NEFT-ICICN52024040512345678", "SBIN424096123456"},
+		{"", "NEFT:SBIN424096123456", "SBIN424096123456"},
+		{"", "CHQ 123456", ""},
+		{"", "UPI/40981234567/RAVI", ""},
+		{"", "NEFT-1234N52024040512345678", ""},
+		{"", "NEFT-ABCDEFGHIJKLMNOPQR", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference+" "+tt.narration, func(t *testing.T) {
+			if got := UTR(tt.reference, tt.narration); got != tt.want {
+				t.Errorf("UTR(%q, %q) = %q, want %q", tt.reference, tt.narration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayerName(t *testing.T) {
+	tests := []struct {
+		narration string
+		want      string
+	}{
+		{"NEFT-HDFCN52024040512345678-RAVI KUMAR-RENT APRIL", "RAVI KUMAR"},
+		{"UPI/409812345678/Priya Sharma/priya@okaxis/Payment fr", "PRIYA SHARMA"},
+		{"IMPS/P2A/409812345678/SURESH NAIR", "SURESH NAIR"},
+		{"BY TRANSFER-NEFT*SBIN0001234*SBIN424096123456*ANITA RAO", "ANITA RAO"},
+		{"NEFT CR-ICIC0000001-ICICI BANK LTD-RENT", ""},
+		{"UPI/409812345678/AB/ab@ybl", ""},
+		{"ATM WDL 123456", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.narration, func(t *testing.T) {
+			if got := PayerName(tt.narration); got != tt.want {
+				t.Errorf("PayerName(%q) = %q, want %q", tt.narration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"RAVI KUMAR", "Ravi Kumar", 1},
+		{"RAVIKUMAR", "Ravi Kumar", 1},
+		{"Kumar Ravi", "Ravi Kumar", 1},
+		{"RAVI KUM", "Ravi Kumar", 0.9},
+		{"R KUMAR", "Ravi Kumar", 0.75},
+		{"ANITASHARMA", "Anita Sharma Rao", 0.9},
+		{"RAVI", "Ravi Kumar", 2.0 / 3},
+		{"SURESH NAIR", "Ravi Kumar", 0},
+		{"", "Ravi Kumar", 0},
+		{"1234", "Ravi Kumar", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := NameSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("NameSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
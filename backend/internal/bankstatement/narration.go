@@ -0,0 +1,166 @@
+package bankstatement
+
+import (
+	"strings"
+	"unicode"
+)
+
+// narrationStopwords are the words of a narration that are not the payer's
+// name: transfer types, bank names and common remarks
+var narrationStopwords = map[string]bool{
+	"NEFT": true, "IMPS": true, "RTGS": true, "UPI": true, "CR": true, "DR": true, "BY": true, "TO": true,
+	"TRANSFER": true, "TRF": true, "INB": true, "MOB": true, "MB": true, "NET": true, "P2A": true, "P2P": true,
+	"PAYMENT": true, "FROM": true, "RENT": true, "FOR": true, "REF": true, "NA": true, "INFT": true, "FT": true,
+	"BANK": true, "LTD": true, "LIMITED": true, "OF": true, "INDIA": true, "STATE": true, "HDFC": true,
+	"ICICI": true, "SBI": true, "AXIS": true, "KOTAK": true, "MAHINDRA": true, "YES": true, "IDFC": true,
+	"FIRST": true, "INDUSIND": true, "FEDERAL": true, "CANARA": true, "UNION": true, "BARODA": true,
+	"PNB": true, "PUNJAB": true, "NATIONAL": true, "PAYTM": true, "PAYMENTS": true, "AIRTEL": true,
+	"JAN": true, "FEB": true, "MAR": true, "APR": true, "MAY": true, "JUN": true, "JUL": true, "AUG": true,
+	"SEP": true, "OCT": true, "NOV": true, "DEC": true,
+}
+
+// UTR returns the transaction reference of a credit: a 16 to 22 character
+// NEFT/RTGS reference, or the 12 digit reference of an IMPS or UPI payment.
+// The statement's reference column is preferred over the narration.
+func UTR(reference, narration string) string {
+	for _, text := range []string{reference, narration} {
+		for _, token := range tokens(text) {
+			if utr, ok := asUTR(token); ok {
+				return utr
+			}
+		}
+	}
+	return ""
+}
+
+func asUTR(token string) (string, bool) {
+	// reference columns pad references with zeros
+	token = strings.TrimLeft(strings.ToUpper(token), "0")
+	if isDigits(token) {
+		return token, len(token) == 12
+	}
+	if len(token) < 16 || len(token) > 22 {
+		return "", false
+	}
+	digits := 0
+	for i, r := range token {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r >= 'A' && r <= 'Z':
+		default:
+			return "", false
+		}
+		// references start with the sending bank's four letter code
+		if i < 4 && (r < 'A' || r > 'Z') {
+			return "", false
+		}
+	}
+	return token, digits >= 6
+}
+
+// PayerName returns the first part of a narration that reads as a person's
+// or business's name, or "" when none does
+func PayerName(narration string) string {
+	for _, part := range strings.FieldsFunc(narration, isSeparator) {
+		if strings.ContainsAny(part, "@0123456789") {
+			continue
+		}
+		var name []string
+		for _, word := range words(part) {
+			if !narrationStopwords[word] {
+				name = append(name, word)
+			}
+		}
+		if len(name) > 0 && len(strings.Join(name, "")) >= 3 {
+			return strings.Join(name, " ")
+		}
+	}
+	return ""
+}
+
+// NameSimilarity scores from 0 to 1 how alike two names are. Narrations
+// truncate and abbreviate names, so a word that starts another counts
+// nearly as a match and an initial counts half; names run together, as
+// some banks print them, are compared whole.
+func NameSimilarity(a, b string) float64 {
+	wa, wb := words(a), words(b)
+	if len(wa) == 0 || len(wb) == 0 {
+		return 0
+	}
+	joinedA, joinedB := strings.Join(wa, ""), strings.Join(wb, "")
+	if joinedA == joinedB {
+		return 1
+	}
+
+	matched := 0.0
+	used := make([]bool, len(wb))
+	for _, x := range wa {
+		best, at := 0.0, -1
+		for j, y := range wb {
+			if used[j] {
+				continue
+			}
+			if score := wordSimilarity(x, y); score > best {
+				best, at = score, j
+			}
+		}
+		if at >= 0 {
+			used[at] = true
+			matched += best
+		}
+	}
+	score := matched / (float64(len(wa)+len(wb)) / 2)
+
+	short, long := joinedA, joinedB
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	if len(short) >= 6 && strings.HasPrefix(long, short) {
+		score = max(score, 0.9)
+	}
+	return min(score, 1)
+}
+
+func wordSimilarity(a, b string) float64 {
+	switch {
+	case a == b:
+		return 1
+	case len(a) == 1 || len(b) == 1:
+		if a[0] == b[0] {
+			return 0.5
+		}
+	case len(a) >= 3 && len(b) >= 3 && (strings.HasPrefix(a, b) || strings.HasPrefix(b, a)):
+		return 0.8
+	}
+	return 0
+}
+
+// words returns the words of s in upper case, without punctuation
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+func tokens(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return isSeparator(r) || unicode.IsSpace(r) || r == ':'
+	})
+}
+
+func isSeparator(r rune) bool {
+	return r == '/' || r == '-' || r == '*' || r == '|' || r == ',' || r == ';' || r == '_'
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
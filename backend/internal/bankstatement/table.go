@@ -0,0 +1,403 @@
+package bankstatement
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	biffMagic = []byte{0xD0, 0xCF, 0x11, 0xE0}
+	utf8BOM   = []byte{0xEF, 0xBB, 0xBF}
+)
+
+// readTable returns the rows of a statement file. Banks' ".xls" downloads
+// are often HTML tables under another name, so the format is told from the
+// content rather than the file name.
+func readTable(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	switch {
+	case bytes.HasPrefix(data, zipMagic):
+		return readXLSX(data)
+	case bytes.HasPrefix(data, biffMagic):
+		return readXLS(data)
+	case looksLikeHTML(data):
+		return readHTML(data)
+	case utf8.Valid(data):
+		return readCSV(data)
+	}
+	return nil, fmt.Errorf("%w: expected CSV, XLSX, XLS or an HTML table", ErrUnsupportedFile)
+}
+
+func looksLikeHTML(data []byte) bool {
+	head := bytes.ToLower(bytes.TrimSpace(data[:min(len(data), 1024)]))
+	return bytes.HasPrefix(head, []byte("<")) && bytes.Contains(head, []byte("<table")) ||
+		bytes.HasPrefix(head, []byte("<!doctype html")) || bytes.HasPrefix(head, []byte("<html"))
+}
+
+// readCSV reads delimited text, taking the delimiter most used in the
+// first lines. Rows may have different lengths, as the account details
+// above a statement's table do. Each row is placed at the line it starts
+// on, so blank lines, which the reader skips, keep their row numbers.
+func readCSV(data []byte) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	// trimming would swallow the empty cells of tab separated files
+	reader.TrimLeadingSpace = reader.Comma != '\t'
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+		for len(rows) < line-1 {
+			rows = append(rows, nil)
+		}
+		rows = append(rows, record)
+	}
+}
+
+func detectDelimiter(data []byte) rune {
+	head := string(data[:min(len(data), 8192)])
+	best, count := ',', 0
+	for _, delimiter := range []rune{',', '\t', ';', '|'} {
+		if n := strings.Count(head, string(delimiter)); n > count {
+			best, count = delimiter, n
+		}
+	}
+	return best
+}
+
+// readHTML reads the cells of every table row in the document
+func readHTML(data []byte) ([][]string, error) {
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+	}
+
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "tr" {
+			var row []string
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && (c.Data == "td" || c.Data == "th") {
+					row = append(row, collapseSpace(nodeText(c)))
+				}
+			}
+			rows = append(rows, row)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return rows, nil
+}
+
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(nodeText(c))
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// xlsx parts, as far as reading cell values needs them
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText is a string item, either plain or in formatted runs
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+// xlsxCell is a worksheet cell as stored; Ref is its reference such as "B7"
+type xlsxCell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Value  string   `xml:"v"`
+	Inline xlsxText `xml:"is"`
+}
+
+// Worksheet limits. A cell may name any column up to XFD, so rows are built
+// from the cells present and the columns no row uses are left out, within a
+// budget on the cells the sheet takes once laid out.
+const (
+	maxSheetColumns = 16384
+	maxSheetRows    = 100000
+	maxSheetCells   = 2000000
+)
+
+// sheetCell is a cell read from a worksheet, by its zero-based column
+type sheetCell struct {
+	col   int
+	value string
+}
+
+// readXLSX reads the first worksheet of a workbook
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: worksheet %s missing", ErrUnsupportedFile, sheetPath)
+	}
+	sparse, err := readSheetCells(f, shared.Items)
+	if err != nil {
+		return nil, err
+	}
+	return layOutRows(sparse)
+}
+
+// readSheetCells streams a worksheet's rows, keeping only the cells present
+func readSheetCells(f *zip.File, shared []xlsxText) ([][]sheetCell, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+	}
+	defer rc.Close()
+
+	var rows [][]sheetCell
+	cells := 0
+	decoder := xml.NewDecoder(io.LimitReader(rc, 64<<20))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrUnsupportedFile, f.Name, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "row":
+			if len(rows) == maxSheetRows {
+				return nil, fmt.Errorf("%w: the worksheet has more than %d rows", ErrUnsupportedFile, maxSheetRows)
+			}
+			rows = append(rows, nil)
+		case "c":
+			if len(rows) == 0 {
+				continue
+			}
+			var c xlsxCell
+			if err := decoder.DecodeElement(&c, &start); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrUnsupportedFile, f.Name, err)
+			}
+			if cells++; cells > maxSheetCells {
+				return nil, fmt.Errorf("%w: the worksheet has more than %d cells", ErrUnsupportedFile, maxSheetCells)
+			}
+			row := &rows[len(rows)-1]
+			col := 0
+			if len(*row) > 0 {
+				col = (*row)[len(*row)-1].col + 1
+			}
+			if n := columnNumber(c.Ref); n >= 0 {
+				col = n
+			}
+			if col >= maxSheetColumns {
+				return nil, fmt.Errorf("%w: cell %.20q is past the last column", ErrUnsupportedFile, c.Ref)
+			}
+			*row = append(*row, sheetCell{col: col, value: cellValue(c, shared)})
+		}
+	}
+}
+
+func cellValue(c xlsxCell, shared []xlsxText) string {
+	switch c.Type {
+	case "s":
+		var index int
+		if _, err := fmt.Sscan(c.Value, &index); err == nil && index >= 0 && index < len(shared) {
+			return shared[index].String()
+		}
+		return ""
+	case "inlineStr":
+		return c.Inline.String()
+	case "", "n":
+		return roundNumber(c.Value)
+	}
+	return c.Value
+}
+
+// roundNumber rounds a numeric cell to two decimals. Spreadsheets store
+// numbers as binary floats, so 15000 may be saved as 15000.000000000002.
+func roundNumber(value string) string {
+	if !strings.ContainsAny(value, ".eE") {
+		return value
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return value
+	}
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+// layOutRows places each cell at its column, leaving out the columns no row
+// uses so a stray cell far to the right costs no more than any other
+func layOutRows(sparse [][]sheetCell) ([][]string, error) {
+	used := make(map[int]bool)
+	for _, row := range sparse {
+		for _, c := range row {
+			used[c.col] = true
+		}
+	}
+	order := make([]int, 0, len(used))
+	for col := range used {
+		order = append(order, col)
+	}
+	slices.Sort(order)
+	position := make(map[int]int, len(order))
+	for i, col := range order {
+		position[col] = i
+	}
+
+	rows := make([][]string, 0, len(sparse))
+	size := 0
+	for _, cells := range sparse {
+		width := 0
+		for _, c := range cells {
+			width = max(width, position[c.col]+1)
+		}
+		if size += width; size > maxSheetCells {
+			return nil, fmt.Errorf("%w: the worksheet has more than %d cells", ErrUnsupportedFile, maxSheetCells)
+		}
+		row := make([]string, width)
+		for _, c := range cells {
+			row[position[c.col]] = c.value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// firstSheetPath finds the first worksheet through the workbook's relationships
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+	workbookFile, ok := files["xl/workbook.xml"]
+	relsFile, hasRels := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !hasRels {
+		return fallback, nil
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeXML(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	var rels xlsxRelationships
+	if err := decodeXML(relsFile, &rels); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return fallback, nil
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+func decodeXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrUnsupportedFile, f.Name, err)
+	}
+	return nil
+}
+
+// columnNumber returns the zero-based column of a cell reference such as
+// "AB12", -1 when the reference has no column, or maxSheetColumns for a
+// column past XFD
+func columnNumber(ref string) int {
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		if n = n*26 + int(r-'A'+1); n > maxSheetColumns {
+			return maxSheetColumns
+		}
+	}
+	return n - 1
+}
@@ -0,0 +1,558 @@
+package bankstatement
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Legacy Excel 97-2003 workbooks: a BIFF8 "Workbook" stream kept in an OLE2
+// compound file. Only what reading cell values needs is decoded.
+
+var (
+	errTruncated = errors.New("file is truncated")
+	errExcel95   = errors.New("Excel 5.0/95 workbooks cannot be read, save the statement as CSV or XLSX")
+)
+
+const (
+	// Sector IDs above cfbMaxSector mark free sectors and chain ends
+	cfbMaxSector    = 0xFFFFFFFA
+	cfbEndOfChain   = 0xFFFFFFFE
+	cfbHeaderFATs   = 109
+	cfbDirEntrySize = 128
+	maxWorkbookSize = 64 << 20
+)
+
+// compoundFile is an OLE2 compound file held in memory
+type compoundFile struct {
+	data        []byte
+	sectorSize  int
+	miniSize    int
+	miniCutoff  uint32
+	fat         []uint32
+	miniFAT     []uint32
+	miniStream  []byte
+	directories []byte
+}
+
+// readXLS reads the first worksheet of an Excel 97-2003 workbook
+func readXLS(data []byte) ([][]string, error) {
+	cf, err := openCompoundFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+	}
+	stream, err := cf.stream("Workbook")
+	if err != nil {
+		if _, old := cf.find("Book"); old {
+			err = errExcel95
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+	}
+	sparse, err := readBIFF(stream)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+	}
+	return layOutRows(sparse)
+}
+
+func openCompoundFile(data []byte) (*compoundFile, error) {
+	if len(data) < 512 || !bytes.HasPrefix(data, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}) {
+		return nil, errors.New("not a compound file")
+	}
+	le := binary.LittleEndian
+	sectorShift, miniShift := le.Uint16(data[0x1E:]), le.Uint16(data[0x20:])
+	if sectorShift != 9 && sectorShift != 12 || miniShift != 6 {
+		return nil, errors.New("unexpected sector size")
+	}
+	cf := &compoundFile{
+		data:       data,
+		sectorSize: 1 << sectorShift,
+		miniSize:   1 << miniShift,
+		miniCutoff: le.Uint32(data[0x38:]),
+	}
+
+	// The sectors of the allocation table are listed in the header and then
+	// in a chain of DIFAT sectors
+	var fatSectors []uint32
+	for i := range cfbHeaderFATs {
+		if id := le.Uint32(data[0x4C+4*i:]); id <= cfbMaxSector {
+			fatSectors = append(fatSectors, id)
+		}
+	}
+	perSector := cf.sectorSize/4 - 1
+	for id, n := le.Uint32(data[0x44:]), 0; id <= cfbMaxSector; n++ {
+		sector, err := cf.sector(id)
+		if err != nil {
+			return nil, err
+		}
+		if n > len(data)/cf.sectorSize {
+			return nil, errors.New("DIFAT chain loops")
+		}
+		for i := range perSector {
+			if fat := le.Uint32(sector[4*i:]); fat <= cfbMaxSector {
+				fatSectors = append(fatSectors, fat)
+			}
+		}
+		id = le.Uint32(sector[4*perSector:])
+	}
+	for _, id := range fatSectors {
+		sector, err := cf.sector(id)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(sector); i += 4 {
+			cf.fat = append(cf.fat, le.Uint32(sector[i:]))
+		}
+	}
+
+	var err error
+	if cf.directories, err = cf.chain(le.Uint32(data[0x30:]), -1); err != nil {
+		return nil, fmt.Errorf("directory: %w", err)
+	}
+	if miniFAT := le.Uint32(data[0x3C:]); miniFAT <= cfbMaxSector {
+		table, err := cf.chain(miniFAT, -1)
+		if err != nil {
+			return nil, fmt.Errorf("mini FAT: %w", err)
+		}
+		for i := 0; i+4 <= len(table); i += 4 {
+			cf.miniFAT = append(cf.miniFAT, le.Uint32(table[i:]))
+		}
+	}
+	if len(cf.directories) >= cfbDirEntrySize {
+		root := cf.directories[:cfbDirEntrySize]
+		if cf.miniStream, err = cf.chain(le.Uint32(root[0x74:]), int64(le.Uint64(root[0x78:]))); err != nil {
+			return nil, fmt.Errorf("mini stream: %w", err)
+		}
+	}
+	return cf, nil
+}
+
+// sector returns the sector with the given ID
+func (cf *compoundFile) sector(id uint32) ([]byte, error) {
+	start := (int64(id) + 1) * int64(cf.sectorSize)
+	if id > cfbMaxSector || start+int64(cf.sectorSize) > int64(len(cf.data)) {
+		return nil, errTruncated
+	}
+	return cf.data[start : start+int64(cf.sectorSize)], nil
+}
+
+// chain joins the sectors of the chain starting at id, cut to size unless
+// size is negative
+func (cf *compoundFile) chain(id uint32, size int64) ([]byte, error) {
+	var out []byte
+	for id != cfbEndOfChain {
+		if len(out) > len(cf.data) || len(out) > maxWorkbookSize {
+			return nil, errors.New("sector chain loops or is too long")
+		}
+		sector, err := cf.sector(id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sector...)
+		if int(id) >= len(cf.fat) {
+			return nil, errTruncated
+		}
+		id = cf.fat[id]
+	}
+	if size >= 0 {
+		if size > int64(len(out)) {
+			return nil, errTruncated
+		}
+		out = out[:size]
+	}
+	return out, nil
+}
+
+// miniChain joins the mini stream sectors of the chain starting at id
+func (cf *compoundFile) miniChain(id uint32, size int64) ([]byte, error) {
+	var out []byte
+	for id != cfbEndOfChain && int64(len(out)) < size {
+		start := int(id) * cf.miniSize
+		if int(id) >= len(cf.miniFAT) || start+cf.miniSize > len(cf.miniStream) {
+			return nil, errTruncated
+		}
+		out = append(out, cf.miniStream[start:start+cf.miniSize]...)
+		id = cf.miniFAT[id]
+	}
+	if size > int64(len(out)) {
+		return nil, errTruncated
+	}
+	return out[:size], nil
+}
+
+// find returns the directory entry of the stream with the given name
+func (cf *compoundFile) find(name string) ([]byte, bool) {
+	le := binary.LittleEndian
+	for i := 0; i+cfbDirEntrySize <= len(cf.directories); i += cfbDirEntrySize {
+		entry := cf.directories[i : i+cfbDirEntrySize]
+		nameLen := int(le.Uint16(entry[0x40:]))
+		if entry[0x42] != 2 || nameLen < 2 || nameLen > 64 {
+			continue
+		}
+		units := make([]uint16, nameLen/2-1)
+		for j := range units {
+			units[j] = le.Uint16(entry[2*j:])
+		}
+		if string(utf16.Decode(units)) == name {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+// stream returns the contents of the stream with the given name
+func (cf *compoundFile) stream(name string) ([]byte, error) {
+	entry, ok := cf.find(name)
+	if !ok {
+		return nil, fmt.Errorf("no %s stream", name)
+	}
+	le := binary.LittleEndian
+	start, size := le.Uint32(entry[0x74:]), le.Uint64(entry[0x78:])
+	if cf.sectorSize == 512 {
+		// Version 3 files may leave garbage in the high half
+		size &= 0xFFFFFFFF
+	}
+	if size > maxWorkbookSize {
+		return nil, fmt.Errorf("%s stream is larger than %d bytes", name, maxWorkbookSize)
+	}
+	if size < uint64(cf.miniCutoff) {
+		return cf.miniChain(start, int64(size))
+	}
+	return cf.chain(start, int64(size))
+}
+
+// BIFF8 record types
+const (
+	biffFormula    = 0x0006
+	biffEOF        = 0x000A
+	biffFilePass   = 0x002F
+	biffContinue   = 0x003C
+	biffBoundSheet = 0x0085
+	biffMulRK      = 0x00BD
+	biffRString    = 0x00D6
+	biffSST        = 0x00FC
+	biffLabelSST   = 0x00FD
+	biffNumber     = 0x0203
+	biffLabel      = 0x0204
+	biffBoolErr    = 0x0205
+	biffString     = 0x0207
+	biffRK         = 0x027E
+	biffBOF        = 0x0809
+)
+
+// biffRecord is a record with the data of the CONTINUE records following it
+type biffRecord struct {
+	kind     uint16
+	segments [][]byte
+}
+
+func (r biffRecord) data() []byte {
+	return r.segments[0]
+}
+
+// biffRecords splits a workbook stream into records from offset on
+func biffRecords(stream []byte, offset int) ([]biffRecord, error) {
+	var records []biffRecord
+	for pos := offset; pos+4 <= len(stream); {
+		kind, size := binary.LittleEndian.Uint16(stream[pos:]), int(binary.LittleEndian.Uint16(stream[pos+2:]))
+		pos += 4
+		if pos+size > len(stream) {
+			return nil, errTruncated
+		}
+		data := stream[pos : pos+size]
+		pos += size
+		if kind == biffContinue && len(records) > 0 {
+			last := &records[len(records)-1]
+			last.segments = append(last.segments, data)
+			continue
+		}
+		records = append(records, biffRecord{kind: kind, segments: [][]byte{data}})
+		if kind == biffEOF {
+			break
+		}
+	}
+	return records, nil
+}
+
+// readBIFF reads the cells of the first worksheet of a BIFF8 workbook stream
+func readBIFF(stream []byte) ([][]sheetCell, error) {
+	globals, err := biffRecords(stream, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(globals) == 0 || globals[0].kind != biffBOF || len(globals[0].data()) < 2 {
+		return nil, errors.New("workbook stream does not start with a BOF record")
+	}
+	if version := binary.LittleEndian.Uint16(globals[0].data()); version == 0x0500 {
+		return nil, errExcel95
+	} else if version != 0x0600 {
+		return nil, fmt.Errorf("BIFF version %#x is not Excel 97-2003", version)
+	}
+
+	var shared []string
+	sheetOffset := -1
+	for _, record := range globals {
+		switch record.kind {
+		case biffFilePass:
+			return nil, errors.New("the workbook is password protected")
+		case biffSST:
+			if shared, err = readSST(record); err != nil {
+				return nil, fmt.Errorf("shared strings: %w", err)
+			}
+		case biffBoundSheet:
+			// The first sheet that is a worksheet rather than a chart or macro sheet
+			if data := record.data(); sheetOffset < 0 && len(data) >= 6 && data[5] == 0 {
+				sheetOffset = int(binary.LittleEndian.Uint32(data))
+			}
+		}
+	}
+	if sheetOffset < 0 || sheetOffset >= len(stream) {
+		return nil, errors.New("the workbook has no worksheet")
+	}
+
+	records, err := biffRecords(stream, sheetOffset)
+	if err != nil {
+		return nil, err
+	}
+	sheet := &biffSheet{}
+	for i, record := range records {
+		data := record.data()
+		switch record.kind {
+		case biffLabelSST:
+			if len(data) >= 10 {
+				index := int(binary.LittleEndian.Uint32(data[6:]))
+				value := ""
+				if index < len(shared) {
+					value = shared[index]
+				}
+				err = sheet.set(data, value)
+			}
+		case biffLabel, biffRString:
+			if len(data) >= 8 {
+				r := &segmentReader{segments: [][]byte{data[6:]}}
+				value, serr := r.unicodeString(false)
+				if serr != nil {
+					return nil, serr
+				}
+				err = sheet.set(data, value)
+			}
+		case biffNumber:
+			if len(data) >= 14 {
+				err = sheet.set(data, formatNumber(math.Float64frombits(binary.LittleEndian.Uint64(data[6:]))))
+			}
+		case biffRK:
+			if len(data) >= 10 {
+				err = sheet.set(data, formatNumber(rkNumber(binary.LittleEndian.Uint32(data[6:]))))
+			}
+		case biffMulRK:
+			// The row, the first column, six bytes per cell and the last column
+			if len(data) < 6 {
+				continue
+			}
+			row, col := binary.LittleEndian.Uint16(data), int(binary.LittleEndian.Uint16(data[2:]))
+			for pos := 4; pos+6 <= len(data)-2 && err == nil; pos, col = pos+6, col+1 {
+				err = sheet.put(int(row), col, formatNumber(rkNumber(binary.LittleEndian.Uint32(data[pos+2:]))))
+			}
+		case biffFormula:
+			if len(data) >= 14 {
+				err = sheet.set(data, formulaValue(data[6:14], records[i+1:]))
+			}
+		case biffBoolErr:
+			if len(data) >= 8 && data[7] == 0 {
+				err = sheet.set(data, strconv.FormatBool(data[6] != 0))
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sheet.rows, nil
+}
+
+// biffSheet collects a worksheet's cells, each row at its row number
+type biffSheet struct {
+	rows  [][]sheetCell
+	cells int
+}
+
+// set places the value of a cell record, which starts with its row and column
+func (s *biffSheet) set(data []byte, value string) error {
+	return s.put(int(binary.LittleEndian.Uint16(data)), int(binary.LittleEndian.Uint16(data[2:])), value)
+}
+
+func (s *biffSheet) put(row, col int, value string) error {
+	if row >= maxSheetRows {
+		return fmt.Errorf("the worksheet has more than %d rows", maxSheetRows)
+	}
+	if s.cells++; s.cells > maxSheetCells {
+		return fmt.Errorf("the worksheet has more than %d cells", maxSheetCells)
+	}
+	for len(s.rows) <= row {
+		s.rows = append(s.rows, nil)
+	}
+	s.rows[row] = append(s.rows[row], sheetCell{col: col, value: value})
+	return nil
+}
+
+// formulaValue returns the cached result of a formula; a string result is
+// kept in the STRING record that follows
+func formulaValue(result []byte, following []biffRecord) string {
+	if result[6] != 0xFF || result[7] != 0xFF {
+		return formatNumber(math.Float64frombits(binary.LittleEndian.Uint64(result)))
+	}
+	switch result[0] {
+	case 0:
+		for _, record := range following {
+			if record.kind == biffString {
+				r := &segmentReader{segments: record.segments}
+				value, _ := r.unicodeString(false)
+				return value
+			}
+			if record.kind != biffFormula {
+				break
+			}
+		}
+	case 1:
+		return strconv.FormatBool(result[2] != 0)
+	}
+	return ""
+}
+
+// rkNumber decodes an RK value: a 30-bit integer or the high bits of a
+// float, either of them possibly times 100
+func rkNumber(rk uint32) float64 {
+	var f float64
+	if rk&2 != 0 {
+		f = float64(int32(rk) >> 2)
+	} else {
+		f = math.Float64frombits(uint64(rk&^3) << 32)
+	}
+	if rk&1 != 0 {
+		f /= 100
+	}
+	return f
+}
+
+// formatNumber writes a number cell as XLSX stores it, rounded the same way
+func formatNumber(f float64) string {
+	return roundNumber(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// readSST reads the shared string table, whose strings may run on into the
+// CONTINUE records after it
+func readSST(record biffRecord) ([]string, error) {
+	r := &segmentReader{segments: record.segments}
+	header, err := r.read(8)
+	if err != nil {
+		return nil, err
+	}
+	count := int(binary.LittleEndian.Uint32(header[4:]))
+	strings := make([]string, 0, min(count, maxSheetCells))
+	for range count {
+		s, err := r.unicodeString(true)
+		if err != nil {
+			return nil, err
+		}
+		strings = append(strings, s)
+	}
+	return strings, nil
+}
+
+// segmentReader reads across a record and its CONTINUE records
+type segmentReader struct {
+	segments [][]byte
+	seg, pos int
+}
+
+// read returns the next n bytes, crossing into the next segments as needed
+func (r *segmentReader) read(n int) ([]byte, error) {
+	if r.seg < len(r.segments) && r.pos+n <= len(r.segments[r.seg]) {
+		out := r.segments[r.seg][r.pos : r.pos+n]
+		r.pos += n
+		return out, nil
+	}
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		if r.seg >= len(r.segments) {
+			return nil, errTruncated
+		}
+		if r.pos == len(r.segments[r.seg]) {
+			r.seg, r.pos = r.seg+1, 0
+			continue
+		}
+		take := min(n-len(out), len(r.segments[r.seg])-r.pos)
+		out = append(out, r.segments[r.seg][r.pos:r.pos+take]...)
+		r.pos += take
+	}
+	return out, nil
+}
+
+// unicodeString reads a string with a 16-bit character count. Rich strings
+// in the shared string table carry formatting runs and extended data after
+// their characters, which are skipped. Characters are one byte each when
+// their high bytes are all zero; when they run on into the next segment,
+// it starts with a flags byte saying which width they continue in.
+func (r *segmentReader) unicodeString(rich bool) (string, error) {
+	header, err := r.read(3)
+	if err != nil {
+		return "", err
+	}
+	count, flags := int(binary.LittleEndian.Uint16(header)), header[2]
+	var runs, ext int
+	if rich && flags&0x08 != 0 {
+		b, err := r.read(2)
+		if err != nil {
+			return "", err
+		}
+		runs = int(binary.LittleEndian.Uint16(b))
+	}
+	if rich && flags&0x04 != 0 {
+		b, err := r.read(4)
+		if err != nil {
+			return "", err
+		}
+		ext = int(binary.LittleEndian.Uint32(b))
+	}
+
+	units := make([]uint16, 0, count)
+	for len(units) < count {
+		if r.seg >= len(r.segments) {
+			return "", errTruncated
+		}
+		if r.pos == len(r.segments[r.seg]) {
+			r.seg, r.pos = r.seg+1, 0
+			if r.seg >= len(r.segments) || len(r.segments[r.seg]) == 0 {
+				return "", errTruncated
+			}
+			flags = r.segments[r.seg][0]
+			r.pos = 1
+			continue
+		}
+		segment := r.segments[r.seg][r.pos:]
+		if flags&0x01 == 0 {
+			take := min(count-len(units), len(segment))
+			for _, b := range segment[:take] {
+				units = append(units, uint16(b))
+			}
+			r.pos += take
+		} else {
+			take := min(count-len(units), len(segment)/2)
+			if take == 0 {
+				return "", errTruncated
+			}
+			for i := range take {
+				units = append(units, binary.LittleEndian.Uint16(segment[2*i:]))
+			}
+			r.pos += 2 * take
+		}
+	}
+
+	if _, err := r.read(4*runs + ext); err != nil {
+		return "", err
+	}
+	return string(utf16.Decode(units)), nil
+}
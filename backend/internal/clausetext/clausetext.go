@@ -0,0 +1,159 @@
+// Package clausetext renders clause templates such as
+// "The monthly rent of ₹{{rent}} ({{rent|words}}) is due by the {{due_day|ordinal}} of each month"
+// using data from a lease. Every placeholder must name a declared variable,
+// and an optional filter changes how its value is formatted.
+package clausetext
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*(?:\|\s*([a-z_]+)\s*)?\}\}`)
+	// Anything still looking like a placeholder once valid ones are removed is malformed
+	strayPattern = regexp.MustCompile(`\{\{|\}\}`)
+)
+
+// Template is a parsed clause body
+type Template struct {
+	segments []segment
+}
+
+type segment struct {
+	text     string
+	variable *Variable
+	filter   string
+}
+
+// Parse checks that every placeholder in body refers to a declared variable
+// with a filter supported by its type
+func Parse(body string) (*Template, error) {
+	if loc := strayPattern.FindStringIndex(placeholderPattern.ReplaceAllString(body, "")); loc != nil {
+		return nil, fmt.Errorf("malformed placeholder in clause text")
+	}
+
+	t := &Template{}
+	last := 0
+	for _, m := range placeholderPattern.FindAllStringSubmatchIndex(body, -1) {
+		name := body[m[2]:m[3]]
+		filter := ""
+		if m[4] >= 0 {
+			filter = body[m[4]:m[5]]
+		}
+
+		v, ok := lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", name)
+		}
+		if filter != "" && !slices.Contains(filters[v.Type], filter) {
+			return nil, fmt.Errorf("filter %q cannot be used with %s variable %q", filter, v.Type, name)
+		}
+
+		if m[0] > last {
+			t.segments = append(t.segments, segment{text: body[last:m[0]]})
+		}
+		t.segments = append(t.segments, segment{variable: v, filter: filter})
+		last = m[1]
+	}
+	if last < len(body) {
+		t.segments = append(t.segments, segment{text: body[last:]})
+	}
+
+	return t, nil
+}
+
+// UnresolvedError lists variables that have no value for the lease being rendered
+type UnresolvedError struct {
+	Names []string
+}
+
+func (e *UnresolvedError) Error() string {
+	return "missing values for: " + strings.Join(e.Names, ", ")
+}
+
+// Render substitutes every placeholder with its formatted value. It returns
+// an *UnresolvedError naming every variable that cannot be resolved from data.
+func (t *Template) Render(data *Data) (string, error) {
+	var (
+		b       strings.Builder
+		missing []string
+	)
+
+	for _, seg := range t.segments {
+		if seg.variable == nil {
+			b.WriteString(seg.text)
+			continue
+		}
+
+		value, ok := seg.variable.resolve(data)
+		if !ok {
+			if !slices.Contains(missing, seg.variable.Name) {
+				missing = append(missing, seg.variable.Name)
+			}
+			continue
+		}
+		b.WriteString(format(seg.variable.Type, seg.filter, value))
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", &UnresolvedError{Names: missing}
+	}
+	return b.String(), nil
+}
+
+// Draft renders like Render but leaves placeholders that have no value as
+// written, so an incomplete lease can still be shown
+func (t *Template) Draft(data *Data) string {
+	var b strings.Builder
+	for _, seg := range t.segments {
+		if seg.variable == nil {
+			b.WriteString(seg.text)
+			continue
+		}
+
+		value, ok := seg.variable.resolve(data)
+		if !ok {
+			b.WriteString("{{" + seg.variable.Name)
+			if seg.filter != "" {
+				b.WriteString("|" + seg.filter)
+			}
+			b.WriteString("}}")
+			continue
+		}
+		b.WriteString(format(seg.variable.Type, seg.filter, value))
+	}
+	return b.String()
+}
+
+// Render parses and renders body in one step
+func Render(body string, data *Data) (string, error) {
+	t, err := Parse(body)
+	if err != nil {
+		return "", err
+	}
+	return t.Render(data)
+}
+
+// ordinal returns n with its English ordinal suffix, e.g. 1st, 2nd, 11th, 23rd
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 100 {
+	case 11, 12, 13:
+	default:
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
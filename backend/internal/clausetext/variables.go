@@ -0,0 +1,151 @@
+package clausetext
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/model"
+	"backend/pkg/inr"
+)
+
+// Type is the kind of value a variable holds; it decides formatting and the allowed filters
+type Type string
+
+const (
+	TypeMoney     Type = "money"
+	TypeDate      Type = "date"
+	TypeInteger   Type = "integer"
+	TypePartyName Type = "party_name"
+	TypeAddress   Type = "address"
+)
+
+// DateLayout is how dates are written in clauses, e.g. "1 April 2025"
+const DateLayout = "2 January 2006"
+
+// filters lists the filters each type accepts besides the default formatting
+var filters = map[Type][]string{
+	TypeMoney:   {"words"},
+	TypeInteger: {"ordinal", "words"},
+}
+
+// Data is the lease information clause variables are resolved from
+type Data struct {
+	Lease    *model.Lease
+	Property *model.Property
+	Owner    *model.User
+	Tenants  []model.User
+}
+
+// Variable is a placeholder that clause text may use
+type Variable struct {
+	Name        string   `json:"name"`
+	Type        Type     `json:"type"`
+	Description string   `json:"description"`
+	Filters     []string `json:"filters,omitempty"`
+
+	resolve func(d *Data) (any, bool)
+}
+
+var variables = []Variable{
+	{Name: "rent", Type: TypeMoney, Description: "Monthly rent", resolve: leaseValue(func(l *model.Lease) any { return l.MonthlyRentPaise })},
+	{Name: "deposit", Type: TypeMoney, Description: "Refundable security deposit", resolve: leaseValue(func(l *model.Lease) any { return l.SecurityDepositPaise })},
+	{Name: "maintenance", Type: TypeMoney, Description: "Monthly maintenance payable with the rent", resolve: leaseValue(func(l *model.Lease) any { return l.MaintenancePaise })},
+	{Name: "due_day", Type: TypeInteger, Description: "Day of the month rent is due", resolve: leaseValue(func(l *model.Lease) any { return l.RentDueDay })},
+	{Name: "term_months", Type: TypeInteger, Description: "Length of the lease in months", resolve: leaseValue(func(l *model.Lease) any { return l.TermMonths })},
+	{Name: "notice_period_days", Type: TypeInteger, Description: "Notice period in days", resolve: leaseValue(func(l *model.Lease) any { return l.NoticePeriodDays })},
+	{Name: "lock_in_months", Type: TypeInteger, Description: "Lock-in period in months", resolve: leaseValue(func(l *model.Lease) any { return l.LockInMonths })},
+	{Name: "start_date", Type: TypeDate, Description: "First day of the lease", resolve: leaseValue(func(l *model.Lease) any { return l.StartDate })},
+	{Name: "end_date", Type: TypeDate, Description: "Last day of the lease", resolve: leaseValue(func(l *model.Lease) any { return l.EndDate })},
+	{Name: "owner_name", Type: TypePartyName, Description: "Name of the owner (licensor)", resolve: resolveOwnerName},
+	{Name: "tenant_name", Type: TypePartyName, Description: "Names of the tenants (licensees)", resolve: resolveTenantNames},
+	{Name: "property_address", Type: TypeAddress, Description: "Full address of the leased property", resolve: resolvePropertyAddress},
+}
+
+func init() {
+	for i := range variables {
+		variables[i].Filters = filters[variables[i].Type]
+	}
+}
+
+// Variables returns every variable clause text may use
+func Variables() []Variable {
+	return append([]Variable(nil), variables...)
+}
+
+func lookup(name string) (*Variable, bool) {
+	for i := range variables {
+		if variables[i].Name == name {
+			return &variables[i], true
+		}
+	}
+	return nil, false
+}
+
+func leaseValue(get func(l *model.Lease) any) func(d *Data) (any, bool) {
+	return func(d *Data) (any, bool) {
+		if d.Lease == nil {
+			return nil, false
+		}
+		return get(d.Lease), true
+	}
+}
+
+func resolveOwnerName(d *Data) (any, bool) {
+	if d.Owner == nil || strings.TrimSpace(d.Owner.Name) == "" {
+		return nil, false
+	}
+	return d.Owner.Name, true
+}
+
+func resolveTenantNames(d *Data) (any, bool) {
+	var names []string
+	for _, tenant := range d.Tenants {
+		if name := strings.TrimSpace(tenant.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, false
+	}
+	if len(names) == 1 {
+		return names[0], true
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1], true
+}
+
+func resolvePropertyAddress(d *Data) (any, bool) {
+	if d.Property == nil {
+		return nil, false
+	}
+	address := d.Property.Address.Formatted()
+	if d.Property.UnitNumber != "" {
+		address = "Unit " + d.Property.UnitNumber + ", " + address
+	}
+	return address, true
+}
+
+// format writes a resolved value according to its type and filter
+func format(t Type, filter string, value any) string {
+	switch t {
+	case TypeMoney:
+		paise := value.(int64)
+		if filter == "words" {
+			return inr.Words(paise)
+		}
+		return inr.Format(paise)
+	case TypeInteger:
+		n := value.(int)
+		switch filter {
+		case "ordinal":
+			return ordinal(n)
+		case "words":
+			return inr.NumberWords(int64(n))
+		}
+		return strconv.Itoa(n)
+	case TypeDate:
+		return value.(time.Time).Format(DateLayout)
+	default:
+		return value.(string)
+	}
+}
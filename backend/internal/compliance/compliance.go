@@ -0,0 +1,240 @@
+// Package compliance checks a lease against the tenancy law of the
+// property's state. The Model Tenancy Act, 2021 is the default rule set and
+// each state overrides only what its own law changes. The rules live in a
+// versioned file; rules.json is embedded as the default and
+// COMPLIANCE_RULES_PATH can point to a newer copy.
+package compliance
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"backend/internal/model"
+	"backend/pkg/india"
+	"backend/pkg/inr"
+)
+
+//go:embed rules.json
+var defaultRules []byte
+
+// Rule names, as reported in findings
+const (
+	RuleDepositCap       = "deposit_cap"
+	RuleMandatoryClauses = "mandatory_clauses"
+	RuleNoticePeriod     = "notice_period"
+	RuleRegistration     = "registration"
+)
+
+// SeverityOff turns a rule off in a state whose law has no such limit
+const SeverityOff = "off"
+
+var severities = []string{model.ComplianceError, model.ComplianceWarning, SeverityOff}
+
+// Rules is the parsed rules file. Each state's entry is laid over the
+// default, so it only needs the fields that differ.
+type Rules struct {
+	Version     string                     `json:"version"`
+	Description string                     `json:"description"`
+	Default     RuleSet                    `json:"default"`
+	States      map[string]json.RawMessage `json:"states"`
+}
+
+// RuleSet is the law that applies in one state
+type RuleSet struct {
+	Act              string           `json:"act"`
+	DepositCap       DepositCap       `json:"deposit_cap"`
+	MandatoryClauses MandatoryClauses `json:"mandatory_clauses"`
+	NoticePeriod     NoticePeriod     `json:"notice_period"`
+	Registration     Registration     `json:"registration"`
+}
+
+// DepositCap limits the security deposit to a number of months' rent
+type DepositCap struct {
+	Severity             string `json:"severity"`
+	ResidentialMonths    int    `json:"residential_months"`
+	NonResidentialMonths int    `json:"non_residential_months"`
+}
+
+// MandatoryClauses requires every clause marked mandatory in the library
+type MandatoryClauses struct {
+	Severity string `json:"severity"`
+}
+
+// NoticePeriod bounds the notice period; zero means no bound
+type NoticePeriod struct {
+	Severity string `json:"severity"`
+	MinDays  int    `json:"min_days"`
+	MaxDays  int    `json:"max_days"`
+}
+
+// Registration flags terms that must be registered with the Sub-Registrar
+type Registration struct {
+	Severity                  string `json:"severity"`
+	MaxUnregisteredTermMonths int    `json:"max_unregistered_term_months"`
+}
+
+// Input is the lease data the rules look at. Amounts are in paise.
+type Input struct {
+	State            string
+	PremisesUse      string
+	MonthlyRentPaise int64
+	DepositPaise     int64
+	NoticePeriodDays int
+	TermMonths       int
+	// MissingClauses are the titles of mandatory clauses the lease leaves out
+	MissingClauses []string
+}
+
+// Checker applies a rules file
+type Checker struct {
+	version string
+	base    RuleSet
+	states  map[string]RuleSet
+}
+
+// Load reads the rules file at path, or the embedded rules when path is empty
+func Load(path string) (*Checker, error) {
+	data := defaultRules
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read compliance rules: %w", err)
+		}
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse compliance rules: %w", err)
+	}
+	if rules.Version == "" {
+		return nil, errors.New("compliance rules: version is required")
+	}
+	if err := rules.Default.validate("default"); err != nil {
+		return nil, err
+	}
+
+	c := &Checker{version: rules.Version, base: rules.Default, states: make(map[string]RuleSet, len(rules.States))}
+	for state, raw := range rules.States {
+		if !india.IsStateCode(state) {
+			return nil, fmt.Errorf("compliance rules: unknown state %q", state)
+		}
+		set := rules.Default
+		if err := json.Unmarshal(raw, &set); err != nil {
+			return nil, fmt.Errorf("parse compliance rules for %s: %w", state, err)
+		}
+		if err := set.validate(state); err != nil {
+			return nil, err
+		}
+		c.states[state] = set
+	}
+	return c, nil
+}
+
+func (r *RuleSet) validate(name string) error {
+	if r.Act == "" {
+		return fmt.Errorf("compliance rules: %s names no act", name)
+	}
+	for _, severity := range []string{r.DepositCap.Severity, r.MandatoryClauses.Severity, r.NoticePeriod.Severity, r.Registration.Severity} {
+		if !slices.Contains(severities, severity) {
+			return fmt.Errorf("compliance rules: %s uses unknown severity %q", name, severity)
+		}
+	}
+	if r.DepositCap.ResidentialMonths < 0 || r.DepositCap.NonResidentialMonths < 0 ||
+		r.NoticePeriod.MinDays < 0 || r.NoticePeriod.MaxDays < 0 || r.Registration.MaxUnregisteredTermMonths < 0 {
+		return fmt.Errorf("compliance rules: %s has a negative limit", name)
+	}
+	if r.NoticePeriod.MaxDays > 0 && r.NoticePeriod.MinDays > r.NoticePeriod.MaxDays {
+		return fmt.Errorf("compliance rules: %s has a notice period minimum above its maximum", name)
+	}
+	return nil
+}
+
+// Version returns the version of the loaded rules
+func (c *Checker) Version() string {
+	return c.version
+}
+
+// Check returns every finding for the lease, errors first
+func (c *Checker) Check(in Input) []model.ComplianceFinding {
+	rules, ok := c.states[in.State]
+	if !ok {
+		rules = c.base
+	}
+
+	var findings []model.ComplianceFinding
+	add := func(rule, severity, message string) {
+		if severity == SeverityOff {
+			return
+		}
+		findings = append(findings, model.ComplianceFinding{Rule: rule, Severity: severity, Message: message, Act: rules.Act})
+	}
+
+	months, premises := rules.DepositCap.ResidentialMonths, "residential"
+	if in.PremisesUse == model.PremisesNonResidential {
+		months, premises = rules.DepositCap.NonResidentialMonths, "non-residential"
+	}
+	if months > 0 && in.DepositPaise > in.MonthlyRentPaise*int64(months) {
+		add(RuleDepositCap, rules.DepositCap.Severity, fmt.Sprintf(
+			"The security deposit of %s is more than %s rent (%s), the most allowed for %s premises",
+			inr.FormatWithSymbol(in.DepositPaise), possessive(plural(months, "month")),
+			inr.FormatWithSymbol(in.MonthlyRentPaise*int64(months)), premises))
+	}
+
+	for _, title := range in.MissingClauses {
+		add(RuleMandatoryClauses, rules.MandatoryClauses.Severity, "The mandatory clause \""+title+"\" is missing")
+	}
+
+	notice := rules.NoticePeriod
+	switch {
+	case notice.MinDays > 0 && in.NoticePeriodDays < notice.MinDays:
+		add(RuleNoticePeriod, notice.Severity, fmt.Sprintf("The notice period of %s is shorter than the %s required",
+			plural(in.NoticePeriodDays, "day"), plural(notice.MinDays, "day")))
+	case notice.MaxDays > 0 && in.NoticePeriodDays > notice.MaxDays:
+		add(RuleNoticePeriod, notice.Severity, fmt.Sprintf("The notice period of %s is longer than the %s allowed",
+			plural(in.NoticePeriodDays, "day"), plural(notice.MaxDays, "day")))
+	}
+
+	if limit := rules.Registration.MaxUnregisteredTermMonths; in.TermMonths > limit {
+		message := "The agreement must be registered with the Sub-Registrar after it is signed"
+		if limit > 0 {
+			message = fmt.Sprintf("A term of more than %s must be registered with the Sub-Registrar after the agreement is signed",
+				plural(limit, "month"))
+		}
+		add(RuleRegistration, rules.Registration.Severity, message)
+	}
+
+	slices.SortStableFunc(findings, func(a, b model.ComplianceFinding) int {
+		return strings.Compare(a.Severity, b.Severity)
+	})
+	return findings
+}
+
+// Errors returns the findings that block the lease
+func Errors(findings []model.ComplianceFinding) []model.ComplianceFinding {
+	var errs []model.ComplianceFinding
+	for _, f := range findings {
+		if f.Severity == model.ComplianceError {
+			errs = append(errs, f)
+		}
+	}
+	return errs
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func possessive(s string) string {
+	if strings.HasSuffix(s, "s") {
+		return s + "'"
+	}
+	return s + "'s"
+}
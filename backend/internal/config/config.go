@@ -10,6 +10,22 @@ type Config struct {
 	Port        string
 	Environment string
 	Database    DatabaseConfig
+	Auth        AuthConfig
+	OTP         OTPConfig
+	SMS         SMSConfig
+	Storage     StorageConfig
+	LeasePDF    LeasePDFConfig
+	StampDuty   StampDutyConfig
+	Compliance  ComplianceConfig
+	PoliceForm  PoliceFormConfig
+	EStamp      EStampConfig
+	Signing     SigningConfig
+	ESign       ESignConfig
+	Document    DocumentConfig
+	Renewal     RenewalConfig
+	Ledger      LedgerConfig
+	Gateway     GatewayConfig
+	Bank        BankStatementConfig
 }
 
 type DatabaseConfig struct {
@@ -24,6 +40,101 @@ type DatabaseConfig struct {
 	ConnMaxLifetime int // in minutes
 }
 
+type AuthConfig struct {
+	JWTAlgorithm    string // HS256 or EdDSA
+	JWTSecret       string // HMAC secret for HS256
+	JWTPrivateKey   string // base64-encoded Ed25519 seed for EdDSA
+	JWTIssuer       string
+	AccessTokenTTL  int // in minutes
+	RefreshTokenTTL int // in days
+}
+
+type OTPConfig struct {
+	Secret         string // HMAC key used to hash stored codes
+	Length         int
+	TTL            int // in minutes
+	MaxAttempts    int
+	ResendCooldown int // in seconds
+}
+
+type SMSConfig struct {
+	Provider string // log or file
+	FilePath string // outbox for the file provider
+}
+
+type StorageConfig struct {
+	Provider    string // local
+	LocalPath   string // root directory for the local provider
+	MaxUploadMB int
+}
+
+type LeasePDFConfig struct {
+	PaperSize     string // A4 or Legal
+	StampMarginMM int    // blank space at the top of page one for the pre-printed stamp
+	FontDir       string // Noto fonts for Indian languages; leases print in English only when empty
+}
+
+type StampDutyConfig struct {
+	RulesPath string // rules file; the built-in rules are used when empty
+}
+
+type ComplianceConfig struct {
+	RulesPath string // rules file; the built-in rules are used when empty
+}
+
+type PoliceFormConfig struct {
+	FormatsPath string // police verification form formats; the built-in formats are used when empty
+}
+
+type EStampConfig struct {
+	Provider string // fake
+}
+
+type SigningConfig struct {
+	LinkBaseURL string // signing links are <base URL>/<token>
+	LinkTTL     int    // in hours
+}
+
+type ESignConfig struct {
+	Provider          string // simulator
+	ASPID             string // our ID with the eSign service provider
+	CallbackURL       string // where the ESP posts the eSignResponse
+	ReturnURL         string // where signers are sent when eSign is over
+	TxnTTL            int    // in minutes
+	SimulatorURL      string // where the simulator serves the ESP pages
+	SimulatorOTP      string // the OTP the simulator accepts
+	SimulatorCertPath string // PEM test CA; a throwaway CA is generated when empty
+	SimulatorKeyPath  string
+}
+
+type DocumentConfig struct {
+	VerifyBaseURL string // QR codes on documents link to <base URL>/<code>
+}
+
+type RenewalConfig struct {
+	LeadDays    int // renewals are drafted this many days before a lease ends; 0 turns this off
+	JobInterval int // in minutes
+}
+
+type LedgerConfig struct {
+	JobInterval int // in minutes; rent is charged to the ledger as it falls due
+}
+
+type GatewayConfig struct {
+	Provider      string // simulator
+	KeyID         string // public key the checkout is opened with
+	KeySecret     string // signs payment confirmations
+	WebhookSecret string // signs webhook events
+	WebhookURL    string // where the simulator posts webhook events
+	ReturnURL     string // where payers are sent after the simulator's checkout
+	SimulatorURL  string // where the simulator serves its checkout page
+}
+
+type BankStatementConfig struct {
+	FormatsPath    string // bank statement column mappings; the built-in formats are used when empty
+	DateWindowDays int    // a credit can be for rent falling due up to this many days after it
+}
+
 func (d *DatabaseConfig) DSN() string {
 	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -53,9 +164,94 @@ func Load() *Config {
 			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
 			ConnMaxLifetime: getEnvAsInt("DB_CONN_MAX_LIFETIME", 5),
 		},
+		Auth: AuthConfig{
+			JWTAlgorithm:    getEnv("JWT_ALGORITHM", "HS256"),
+			JWTSecret:       getEnv("JWT_SECRET", ""),
+			JWTPrivateKey:   getEnv("JWT_PRIVATE_KEY", ""),
+			JWTIssuer:       getEnv("JWT_ISSUER", "rental-app"),
+			AccessTokenTTL:  getEnvAsInt("JWT_ACCESS_TOKEN_TTL", 15),
+			RefreshTokenTTL: getEnvAsInt("JWT_REFRESH_TOKEN_TTL", 30),
+		},
+		OTP: OTPConfig{
+			Secret:         getEnv("OTP_SECRET", ""),
+			Length:         getEnvAsInt("OTP_LENGTH", 6),
+			TTL:            getEnvAsInt("OTP_TTL", 5),
+			MaxAttempts:    getEnvAsInt("OTP_MAX_ATTEMPTS", 5),
+			ResendCooldown: getEnvAsInt("OTP_RESEND_COOLDOWN", 30),
+		},
+		SMS: SMSConfig{
+			Provider: getEnv("SMS_PROVIDER", "log"),
+			FilePath: getEnv("SMS_FILE_PATH", "sms_outbox.jsonl"),
+		},
+		Storage: StorageConfig{
+			Provider:    getEnv("STORAGE_PROVIDER", "local"),
+			LocalPath:   getEnv("STORAGE_LOCAL_PATH", "storage"),
+			MaxUploadMB: getEnvAsInt("STORAGE_MAX_UPLOAD_MB", 10),
+		},
+		LeasePDF: LeasePDFConfig{
+			PaperSize:     getEnv("LEASE_PDF_PAPER_SIZE", "A4"),
+			StampMarginMM: getEnvAsInt("LEASE_PDF_STAMP_MARGIN_MM", 100),
+			FontDir:       getEnv("LEASE_PDF_FONT_DIR", ""),
+		},
+		StampDuty: StampDutyConfig{
+			RulesPath: getEnv("STAMP_DUTY_RULES_PATH", ""),
+		},
+		Compliance: ComplianceConfig{
+			RulesPath: getEnv("COMPLIANCE_RULES_PATH", ""),
+		},
+		PoliceForm: PoliceFormConfig{
+			FormatsPath: getEnv("POLICE_FORM_FORMATS_PATH", ""),
+		},
+		EStamp: EStampConfig{
+			Provider: getEnv("ESTAMP_PROVIDER", "fake"),
+		},
+		Signing: SigningConfig{
+			LinkBaseURL: getEnv("SIGNING_LINK_BASE_URL", "http://localhost:3000/sign"),
+			LinkTTL:     getEnvAsInt("SIGNING_LINK_TTL", 72),
+		},
+		ESign: ESignConfig{
+			Provider:          getEnv("ESIGN_PROVIDER", ""),
+			ASPID:             getEnv("ESIGN_ASP_ID", "RENTALAPP"),
+			CallbackURL:       getEnv("ESIGN_CALLBACK_URL", "http://localhost:8080/api/v1/esign/callback"),
+			ReturnURL:         getEnv("ESIGN_RETURN_URL", "http://localhost:3000/sign/esign-complete"),
+			TxnTTL:            getEnvAsInt("ESIGN_TXN_TTL", 30),
+			SimulatorURL:      getEnv("ESIGN_SIMULATOR_URL", "http://localhost:8080/esign-simulator"),
+			SimulatorOTP:      getEnv("ESIGN_SIMULATOR_OTP", ""),
+			SimulatorCertPath: getEnv("ESIGN_SIMULATOR_CERT_PATH", ""),
+			SimulatorKeyPath:  getEnv("ESIGN_SIMULATOR_KEY_PATH", ""),
+		},
+		Document: DocumentConfig{
+			VerifyBaseURL: getEnv("DOCUMENT_VERIFY_BASE_URL", "http://localhost:8080/api/v1/verify"),
+		},
+		Renewal: RenewalConfig{
+			LeadDays:    getEnvAsInt("RENEWAL_LEAD_DAYS", 30),
+			JobInterval: getEnvAsInt("RENEWAL_JOB_INTERVAL", 60),
+		},
+		Ledger: LedgerConfig{
+			JobInterval: getEnvAsInt("LEDGER_JOB_INTERVAL", 60),
+		},
+		Gateway: GatewayConfig{
+			Provider:      getEnv("PAYMENT_GATEWAY_PROVIDER", ""),
+			KeyID:         getEnv("PAYMENT_GATEWAY_KEY_ID", "sim_key"),
+			KeySecret:     getEnv("PAYMENT_GATEWAY_KEY_SECRET", ""),
+			WebhookSecret: getEnv("PAYMENT_GATEWAY_WEBHOOK_SECRET", ""),
+			WebhookURL:    getEnv("PAYMENT_GATEWAY_WEBHOOK_URL", "http://localhost:8080/api/v1/payments/webhook"),
+			ReturnURL:     getEnv("PAYMENT_GATEWAY_RETURN_URL", "http://localhost:3000/payments/complete"),
+			SimulatorURL:  getEnv("PAYMENT_GATEWAY_SIMULATOR_URL", "http://localhost:8080/payment-simulator"),
+		},
+		Bank: BankStatementConfig{
+			FormatsPath:    getEnv("BANK_STATEMENT_FORMATS_PATH", ""),
+			DateWindowDays: getEnvAsInt("BANK_MATCH_DATE_WINDOW_DAYS", 15),
+		},
 	}
 }
 
+// IsProduction reports whether ENVIRONMENT is production, where simulators
+// must not run
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,71 @@
+// Package esign signs documents with Aadhaar eSign. We act as the ASP
+// (Application Service Provider): the document hash goes to an ESP (eSign
+// Service Provider) in a request the signer's browser posts, the signer
+// authenticates there with an Aadhaar OTP, and the ESP posts back a PKCS #7
+// signature that is embedded in the PDF.
+package esign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/config"
+)
+
+var (
+	ErrTransactionNotFound = errors.New("eSign transaction not found")
+	// ErrSignatureRejected means the ESP reported a failure, or the signer cancelled
+	ErrSignatureRejected = errors.New("eSign was not completed")
+	// ErrInvalidResponse means the ESP response could not be trusted
+	ErrInvalidResponse = errors.New("invalid eSign response")
+)
+
+// Request is a document to be signed by one person
+type Request struct {
+	Document   []byte
+	SignerName string
+	Reason     string
+	Location   string
+	// DocInfo describes the document to the signer on the ESP's consent page
+	DocInfo string
+}
+
+// Transaction is an initiated eSign. The signer's browser posts RequestXML
+// as the eSignRequest form field to ESPURL.
+type Transaction struct {
+	Txn        string    `json:"txn"`
+	ESPURL     string    `json:"esp_url"`
+	RequestXML string    `json:"request_xml"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Result identifies who signed, from the certificate the ESP issued
+type Result struct {
+	Txn               string
+	SignerName        string
+	CertificateSerial string
+	CertificateIssuer string
+	SignedAt          time.Time
+}
+
+// ESignProvider runs the ASP side of the eSign flow against an ESP
+type ESignProvider interface {
+	// Initiate prepares the document for signing and builds the request for the ESP
+	Initiate(ctx context.Context, req Request) (*Transaction, error)
+	// Verify checks the response the ESP posted back and keeps the signature
+	Verify(ctx context.Context, responseXML []byte) (*Result, error)
+	// SignedPDF returns the document of a verified transaction with the signature embedded
+	SignedPDF(ctx context.Context, txn string) ([]byte, error)
+}
+
+// NewESignProvider returns the provider selected by configuration
+func NewESignProvider(cfg *config.ESignConfig) (ESignProvider, error) {
+	switch cfg.Provider {
+	case "simulator":
+		return NewSimulator(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported eSign provider: %s", cfg.Provider)
+	}
+}
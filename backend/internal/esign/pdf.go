@@ -0,0 +1,345 @@
+package esign
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signatureSize is the room reserved in the PDF for the CMS signature, in bytes
+const signatureSize = 8192
+
+var (
+	errUnsupportedPDF = errors.New("unsupported PDF: only documents with classic cross-reference tables can be signed")
+	errAlreadyHasForm = errors.New("PDF already has form fields")
+)
+
+var (
+	startxrefPattern = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF\s*$`)
+	rootPattern      = regexp.MustCompile(`/Root\s+(\d+)\s+0\s+R`)
+	infoPattern      = regexp.MustCompile(`/Info\s+(\d+)\s+0\s+R`)
+	sizePattern      = regexp.MustCompile(`/Size\s+(\d+)`)
+	prevPattern      = regexp.MustCompile(`/Prev\s+(\d+)`)
+	pagesPattern     = regexp.MustCompile(`/Pages\s+(\d+)\s+0\s+R`)
+	firstKidPattern  = regexp.MustCompile(`/Kids\s*\[\s*(\d+)\s+0\s+R`)
+)
+
+// signatureInfo is written into the PDF signature dictionary
+type signatureInfo struct {
+	Name     string
+	Reason   string
+	Location string
+	Time     time.Time
+}
+
+// preparedPDF is a PDF with an empty signature placeholder. The signature
+// covers every byte except the placeholder, between contentsStart and contentsEnd.
+type preparedPDF struct {
+	data          []byte
+	contentsStart int
+	contentsEnd   int
+}
+
+// digest returns the SHA-256 of the byte ranges the signature covers
+func (p *preparedPDF) digest() []byte {
+	h := sha256.New()
+	h.Write(p.data[:p.contentsStart])
+	h.Write(p.data[p.contentsEnd:])
+	return h.Sum(nil)
+}
+
+// embed returns the PDF with the CMS signature written into the placeholder
+func (p *preparedPDF) embed(signature []byte) ([]byte, error) {
+	encoded := hex.EncodeToString(signature)
+	if len(encoded) > p.contentsEnd-p.contentsStart-2 {
+		return nil, fmt.Errorf("signature of %d bytes does not fit the %d reserved", len(signature), signatureSize)
+	}
+	out := slices.Clone(p.data)
+	copy(out[p.contentsStart+1:], encoded)
+	return out, nil
+}
+
+// prepareSignature appends an incremental update to a PDF adding an
+// invisible signature field on the first page, with a zero-filled
+// placeholder for the signature. The original bytes are left untouched.
+func prepareSignature(pdf []byte, info signatureInfo) (*preparedPDF, error) {
+	doc, err := parseXref(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := doc.object(doc.root)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(catalog, []byte("/AcroForm")) {
+		return nil, errAlreadyHasForm
+	}
+	pagesNum, err := reference(pagesPattern, catalog)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := doc.object(pagesNum)
+	if err != nil {
+		return nil, err
+	}
+	pageNum, err := reference(firstKidPattern, pages)
+	if err != nil {
+		return nil, err
+	}
+	page, err := doc.object(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	sigNum, widgetNum := doc.size, doc.size+1
+	out := bytes.NewBuffer(slices.Clip(pdf))
+	if !bytes.HasSuffix(pdf, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	offsets := map[int]int{}
+	writeObject := func(num int, body string) {
+		offsets[num] = out.Len()
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", num, body)
+	}
+
+	writeObject(doc.root, extendDict(catalog, fmt.Sprintf("/AcroForm <</Fields [%d 0 R] /SigFlags 3>>", widgetNum)))
+	if updated, ok := addAnnotation(page, widgetNum); ok {
+		writeObject(pageNum, updated)
+	}
+
+	offsets[sigNum] = out.Len()
+	fmt.Fprintf(out, "%d 0 obj\n<</Type /Sig /Filter /Adobe.PPKLite /SubFilter /adbe.pkcs7.detached /ByteRange [0 ", sigNum)
+	byteRangeAt := out.Len()
+	out.WriteString(strings.Repeat(" ", 32) + "] /Contents ")
+	contentsStart := out.Len()
+	out.WriteString("<" + strings.Repeat("0", 2*signatureSize) + ">")
+	contentsEnd := out.Len()
+	fmt.Fprintf(out, " /M %s /Name %s /Reason %s /Location %s>>\nendobj\n",
+		pdfDate(info.Time), pdfString(info.Name), pdfString(info.Reason), pdfString(info.Location))
+
+	writeObject(widgetNum, fmt.Sprintf("<</Type /Annot /Subtype /Widget /FT /Sig /Rect [0 0 0 0] /F 132 /T (Signature1) /V %d 0 R /P %d 0 R>>", sigNum, pageNum))
+
+	xrefAt := out.Len()
+	writeXref(out, offsets)
+	trailer := fmt.Sprintf("/Size %d /Root %d 0 R", doc.size+2, doc.root)
+	if doc.info > 0 {
+		trailer += fmt.Sprintf(" /Info %d 0 R", doc.info)
+	}
+	fmt.Fprintf(out, "trailer\n<<%s /Prev %d>>\nstartxref\n%d\n%%%%EOF\n", trailer, doc.xrefAt, xrefAt)
+
+	data := out.Bytes()
+	byteRange := fmt.Sprintf("%d %d %d", contentsStart, contentsEnd, len(data)-contentsEnd)
+	copy(data[byteRangeAt:], byteRange)
+
+	return &preparedPDF{data: data, contentsStart: contentsStart, contentsEnd: contentsEnd}, nil
+}
+
+// writeXref writes a cross-reference section for the given objects, one
+// subsection per run of consecutive object numbers
+func writeXref(out *bytes.Buffer, offsets map[int]int) {
+	nums := make([]int, 0, len(offsets))
+	for num := range offsets {
+		nums = append(nums, num)
+	}
+	slices.Sort(nums)
+
+	out.WriteString("xref\n")
+	for i := 0; i < len(nums); {
+		j := i + 1
+		for j < len(nums) && nums[j] == nums[j-1]+1 {
+			j++
+		}
+		fmt.Fprintf(out, "%d %d\n", nums[i], j-i)
+		for _, num := range nums[i:j] {
+			fmt.Fprintf(out, "%010d 00000 n \n", offsets[num])
+		}
+		i = j
+	}
+}
+
+type xrefDoc struct {
+	pdf     []byte
+	offsets map[int]int
+	root    int
+	info    int
+	size    int
+	xrefAt  int
+}
+
+// parseXref reads the cross-reference tables of a PDF, following /Prev to
+// earlier updates. Later sections take precedence.
+func parseXref(pdf []byte) (*xrefDoc, error) {
+	tail := pdf[max(0, len(pdf)-1024):]
+	m := startxrefPattern.FindSubmatch(tail)
+	if m == nil {
+		return nil, errUnsupportedPDF
+	}
+	xrefAt, _ := strconv.Atoi(string(m[1]))
+
+	doc := &xrefDoc{pdf: pdf, offsets: map[int]int{}, xrefAt: xrefAt}
+	for at, first := xrefAt, true; ; first = false {
+		trailer, err := doc.readSection(at)
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			root, err := reference(rootPattern, trailer)
+			if err != nil {
+				return nil, err
+			}
+			doc.root = root
+			doc.info, _ = reference(infoPattern, trailer)
+			size := sizePattern.FindSubmatch(trailer)
+			if size == nil {
+				return nil, errUnsupportedPDF
+			}
+			doc.size, _ = strconv.Atoi(string(size[1]))
+		}
+		prev := prevPattern.FindSubmatch(trailer)
+		if prev == nil {
+			return doc, nil
+		}
+		at, _ = strconv.Atoi(string(prev[1]))
+	}
+}
+
+// readSection records the entries of the xref section at offset and returns its trailer
+func (d *xrefDoc) readSection(offset int) ([]byte, error) {
+	if offset <= 0 || offset >= len(d.pdf) || !bytes.HasPrefix(d.pdf[offset:], []byte("xref")) {
+		return nil, errUnsupportedPDF
+	}
+	end := bytes.Index(d.pdf[offset:], []byte("trailer"))
+	if end < 0 {
+		return nil, errUnsupportedPDF
+	}
+	lines := strings.Fields(string(d.pdf[offset+len("xref") : offset+end]))
+	for i := 0; i+1 < len(lines); {
+		start, err1 := strconv.Atoi(lines[i])
+		count, err2 := strconv.Atoi(lines[i+1])
+		if err1 != nil || err2 != nil || i+2+3*count > len(lines) {
+			return nil, errUnsupportedPDF
+		}
+		for n := range count {
+			entry := lines[i+2+3*n:]
+			if _, seen := d.offsets[start+n]; !seen && entry[2] == "n" {
+				d.offsets[start+n], _ = strconv.Atoi(entry[0])
+			}
+		}
+		i += 2 + 3*count
+	}
+
+	trailer := d.pdf[offset+end:]
+	if close := bytes.Index(trailer, []byte("startxref")); close >= 0 {
+		trailer = trailer[:close]
+	}
+	return trailer, nil
+}
+
+// object returns the dictionary of an indirect object
+func (d *xrefDoc) object(num int) ([]byte, error) {
+	offset, ok := d.offsets[num]
+	if !ok || offset >= len(d.pdf) {
+		return nil, fmt.Errorf("%w: object %d not found", errUnsupportedPDF, num)
+	}
+	body := d.pdf[offset:]
+	end := bytes.Index(body, []byte("endobj"))
+	start := bytes.Index(body, []byte("obj"))
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("%w: object %d is malformed", errUnsupportedPDF, num)
+	}
+	dict := bytes.TrimSpace(body[start+len("obj") : end])
+	if !bytes.HasPrefix(dict, []byte("<<")) || !bytes.HasSuffix(dict, []byte(">>")) {
+		return nil, fmt.Errorf("%w: object %d is not a dictionary", errUnsupportedPDF, num)
+	}
+	return dict, nil
+}
+
+func reference(pattern *regexp.Regexp, dict []byte) (int, error) {
+	m := pattern.FindSubmatch(dict)
+	if m == nil {
+		return 0, errUnsupportedPDF
+	}
+	return strconv.Atoi(string(m[1]))
+}
+
+// addAnnotation adds an annotation reference to a page dictionary. Pages
+// whose /Annots is an indirect array are left as they are; the signature
+// field is still reachable from the form.
+func addAnnotation(page []byte, num int) (string, bool) {
+	ref := fmt.Sprintf(" %d 0 R", num)
+	at := bytes.Index(page, []byte("/Annots"))
+	if at < 0 {
+		return extendDict(page, "/Annots ["+ref[1:]+"]"), true
+	}
+
+	i := at + len("/Annots")
+	for i < len(page) && (page[i] == ' ' || page[i] == '\n' || page[i] == '\r') {
+		i++
+	}
+	if i == len(page) || page[i] != '[' {
+		return "", false
+	}
+	// Find the closing bracket, skipping nested arrays and literal strings
+	depth, inString := 0, 0
+	for ; i < len(page); i++ {
+		switch c := page[i]; {
+		case inString > 0 && c == '\\':
+			i++
+		case c == '(':
+			inString++
+		case inString > 0 && c == ')':
+			inString--
+		case inString > 0:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return string(page[:i]) + ref + string(page[i:]), true
+			}
+		}
+	}
+	return "", false
+}
+
+// extendDict adds entries before the closing >> of a dictionary
+func extendDict(dict []byte, entries string) string {
+	return string(dict[:len(dict)-2]) + " " + entries + ">>"
+}
+
+// pdfString writes s as a literal string, replacing characters outside ASCII
+func pdfString(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// pdfDate formats t as a PDF date, e.g. (D:20250401103000+05'30')
+func pdfDate(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("(D:%s%c%02d'%02d')", t.Format("20060102150405"), sign, offset/3600, offset%3600/60)
+}
@@ -0,0 +1,439 @@
+package esign
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"backend/internal/config"
+
+	"github.com/google/uuid"
+)
+
+// aadhaarPattern matches a 12-digit Aadhaar number, which never starts with 0 or 1
+var aadhaarPattern = regexp.MustCompile(`^[2-9][0-9]{11}$`)
+
+// Simulator plays both sides of the eSign flow for development: it is the
+// ASP, and also serves the ESP pages at SimulatorURL, where any well-formed
+// Aadhaar number with the configured OTP signs. Each signer gets a fresh
+// certificate from a local test CA, loaded from the configured PEM files or
+// generated at startup. Transactions are held in memory and lost on restart.
+type Simulator struct {
+	cfg   *config.ESignConfig
+	ca    *x509.Certificate
+	caKey *rsa.PrivateKey
+	roots *x509.CertPool
+
+	mu   sync.Mutex
+	txns map[string]*transaction
+}
+
+type transaction struct {
+	prepared   *preparedPDF
+	signerName string
+	expiresAt  time.Time
+	signed     []byte
+}
+
+func NewSimulator(cfg *config.ESignConfig) (*Simulator, error) {
+	if cfg.SimulatorOTP == "" {
+		return nil, errors.New("ESIGN_SIMULATOR_OTP must be set for the simulator")
+	}
+
+	var (
+		ca  *x509.Certificate
+		key *rsa.PrivateKey
+		err error
+	)
+	if cfg.SimulatorCertPath != "" || cfg.SimulatorKeyPath != "" {
+		ca, key, err = loadCA(cfg.SimulatorCertPath, cfg.SimulatorKeyPath)
+	} else {
+		ca, key, err = generateCA()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	return &Simulator{cfg: cfg, ca: ca, caKey: key, roots: roots, txns: map[string]*transaction{}}, nil
+}
+
+// Path is where the ESP pages are served, taken from SimulatorURL
+func (s *Simulator) Path() string {
+	u, err := url.Parse(s.cfg.SimulatorURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+func (s *Simulator) Initiate(ctx context.Context, req Request) (*Transaction, error) {
+	now := time.Now()
+	prepared, err := prepareSignature(req.Document, signatureInfo{
+		Name:     req.SignerName,
+		Reason:   req.Reason,
+		Location: req.Location,
+		Time:     now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	txn := uuid.NewString()
+	requestXML, err := marshalXML(esignRequest{
+		Version:         apiVersion,
+		SignerConsent:   "Y",
+		Timestamp:       timestamp(now),
+		Txn:             txn,
+		EKYCIDType:      "A",
+		ASPID:           s.cfg.ASPID,
+		AuthMode:        "1",
+		ResponseSigType: "pkcs7",
+		ResponseURL:     s.cfg.CallbackURL,
+		Docs: []inputHash{{
+			ID:            "1",
+			HashAlgorithm: "SHA256",
+			DocInfo:       req.DocInfo,
+			Hash:          hex.EncodeToString(prepared.digest()),
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(time.Duration(s.cfg.TxnTTL) * time.Minute)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(now)
+	s.txns[txn] = &transaction{prepared: prepared, signerName: req.SignerName, expiresAt: expiresAt}
+
+	return &Transaction{Txn: txn, ESPURL: s.cfg.SimulatorURL, RequestXML: requestXML, ExpiresAt: expiresAt}, nil
+}
+
+func (s *Simulator) Verify(ctx context.Context, responseXML []byte) (*Result, error) {
+	var resp esignResponse
+	if err := xml.Unmarshal(responseXML, &resp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.txns[resp.Txn]
+	if !ok || time.Now().After(t.expiresAt) {
+		return nil, ErrTransactionNotFound
+	}
+	if resp.Status != responseStatusSuccess {
+		delete(s.txns, resp.Txn)
+		return nil, fmt.Errorf("%w: %s", ErrSignatureRejected, resp.ErrorMessage)
+	}
+	if len(resp.Signatures) != 1 {
+		return nil, fmt.Errorf("%w: expected one signature", ErrInvalidResponse)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(resp.Signatures[0].Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	cert, err := verifyDigest(signature, t.prepared.digest())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: s.roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	signed, err := t.prepared.embed(signature)
+	if err != nil {
+		return nil, err
+	}
+	t.signed = signed
+
+	return &Result{
+		Txn:               resp.Txn,
+		SignerName:        cert.Subject.CommonName,
+		CertificateSerial: fmt.Sprintf("%X", cert.SerialNumber),
+		CertificateIssuer: cert.Issuer.CommonName,
+		SignedAt:          time.Now(),
+	}, nil
+}
+
+func (s *Simulator) SignedPDF(ctx context.Context, txn string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.txns[txn]
+	if !ok || t.signed == nil {
+		return nil, ErrTransactionNotFound
+	}
+	return slices.Clone(t.signed), nil
+}
+
+// prune drops expired transactions. The caller must hold the lock.
+func (s *Simulator) prune(now time.Time) {
+	for txn, t := range s.txns {
+		if now.After(t.expiresAt) {
+			delete(s.txns, txn)
+		}
+	}
+}
+
+// ServeHTTP is the ESP: it receives the eSignRequest posted by the signer's
+// browser, asks for the Aadhaar number and OTP, and posts the eSignResponse
+// back to the ASP's response URL
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post an eSignRequest to start signing", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requestXML := r.PostFormValue("eSignRequest")
+	var req esignRequest
+	if err := xml.Unmarshal([]byte(requestXML), &req); err != nil || len(req.Docs) != 1 {
+		http.Error(w, "Invalid eSignRequest", http.StatusBadRequest)
+		return
+	}
+	if req.ASPID != s.cfg.ASPID || req.ResponseSigType != "pkcs7" || req.Docs[0].HashAlgorithm != "SHA256" {
+		http.Error(w, "Unsupported eSignRequest", http.StatusBadRequest)
+		return
+	}
+	digest, err := hex.DecodeString(req.Docs[0].Hash)
+	if err != nil || len(digest) != 32 {
+		http.Error(w, "Invalid document hash", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	t, ok := s.txns[req.Txn]
+	var name string
+	if ok {
+		name = t.signerName
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "Unknown or expired transaction", http.StatusBadRequest)
+		return
+	}
+
+	page := espPage{Request: requestXML, DocInfo: req.Docs[0].DocInfo, Hash: req.Docs[0].Hash, ASPID: req.ASPID}
+	var resp esignResponse
+	switch r.PostFormValue("action") {
+	case "":
+		s.render(w, page)
+		return
+	case "cancel":
+		resp = s.failure(req.Txn, "ESP-901", "Signer cancelled eSign")
+	default:
+		if !aadhaarPattern.MatchString(r.PostFormValue("aadhaar")) {
+			page.Error = "Enter a valid 12-digit Aadhaar number"
+			s.render(w, page)
+			return
+		}
+		if r.PostFormValue("otp") != s.cfg.SimulatorOTP {
+			page.Error = "Incorrect OTP"
+			s.render(w, page)
+			return
+		}
+		resp, err = s.sign(req.Txn, name, digest)
+		if err != nil {
+			log.Printf("eSign simulator failed to sign transaction %s: %v", req.Txn, err)
+			resp = s.failure(req.Txn, "ESP-999", "Signing failed")
+		}
+	}
+
+	responseXML, err := marshalXML(resp)
+	if err != nil {
+		http.Error(w, "Failed to build eSignResponse", http.StatusInternalServerError)
+		return
+	}
+	s.render(w, espPage{ResponseURL: req.ResponseURL, Response: responseXML})
+}
+
+// sign issues a certificate to the signer and signs the document hash with it
+func (s *Simulator) sign(txn, name string, digest []byte) (esignResponse, error) {
+	now := time.Now()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return esignResponse{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return esignResponse{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name, Country: []string{"IN"}},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(30 * time.Minute),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, s.ca, &key.PublicKey, s.caKey)
+	if err != nil {
+		return esignResponse{}, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return esignResponse{}, err
+	}
+
+	signature, err := signDigest(digest, cert, key, now)
+	if err != nil {
+		return esignResponse{}, err
+	}
+	return esignResponse{
+		Version:      apiVersion,
+		Status:       responseStatusSuccess,
+		Timestamp:    timestamp(now),
+		Txn:          txn,
+		ResponseCode: uuid.NewString(),
+		ErrorCode:    "NA",
+		ErrorMessage: "NA",
+		Certificate:  base64.StdEncoding.EncodeToString(der),
+		Signatures: []docSignature{{
+			ID:               "1",
+			SigHashAlgorithm: "SHA256",
+			Signature:        base64.StdEncoding.EncodeToString(signature),
+		}},
+	}, nil
+}
+
+func (s *Simulator) failure(txn, code, message string) esignResponse {
+	return esignResponse{
+		Version:      apiVersion,
+		Status:       responseStatusFailure,
+		Timestamp:    timestamp(time.Now()),
+		Txn:          txn,
+		ErrorCode:    code,
+		ErrorMessage: message,
+	}
+}
+
+type espPage struct {
+	Request string
+	DocInfo string
+	Hash    string
+	ASPID   string
+	Error   string
+	// ResponseURL and Response are set once signing is over, to post the response back
+	ResponseURL string
+	Response    string
+}
+
+func (s *Simulator) render(w http.ResponseWriter, page espPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := espTemplate.Execute(w, page); err != nil {
+		log.Printf("eSign simulator failed to render page: %v", err)
+	}
+}
+
+var espTemplate = template.Must(template.New("esp").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Aadhaar eSign (simulator)</title></head>
+{{if .Response}}
+<body onload="document.forms[0].submit()">
+<form method="post" action="{{.ResponseURL}}">
+<input type="hidden" name="eSignResponse" value="{{.Response}}">
+<noscript><button type="submit">Return to the application</button></noscript>
+</form>
+</body>
+{{else}}
+<body>
+<h1>Aadhaar eSign</h1>
+<p><strong>Simulator:</strong> no Aadhaar data is checked and the signature is made with a test certificate.</p>
+<p>{{.ASPID}} requests your signature on: {{.DocInfo}}</p>
+<p>Document hash (SHA-256): <code>{{.Hash}}</code></p>
+{{if .Error}}<p style="color:#b00">{{.Error}}</p>{{end}}
+<form method="post">
+<input type="hidden" name="eSignRequest" value="{{.Request}}">
+<p><label>Aadhaar number <input name="aadhaar" inputmode="numeric" maxlength="12" autocomplete="off"></label></p>
+<p><label>OTP <input name="otp" inputmode="numeric" maxlength="6" autocomplete="off"></label></p>
+<p>I consent to authenticate with Aadhaar and sign this document.</p>
+<button type="submit" name="action" value="sign">Sign</button>
+<button type="submit" name="action" value="cancel">Cancel</button>
+</form>
+</body>
+{{end}}
+</html>
+`))
+
+// generateCA creates a throwaway CA, so signatures only verify until restart
+func generateCA() (*x509.Certificate, *rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(now.UnixNano()),
+		Subject:               pkix.Name{CommonName: "eSign Simulator Test CA", Country: []string{"IN"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// loadCA reads a PEM CA certificate and its RSA key in PKCS #1 or PKCS #8 form
+func loadCA(certPath, keyPath string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read eSign simulator certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read eSign simulator key: %w", err)
+	}
+
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, errors.New("eSign simulator certificate is not PEM")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse eSign simulator certificate: %w", err)
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, errors.New("eSign simulator key is not PEM")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		parsed, err8 := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+		if err8 != nil {
+			return nil, nil, fmt.Errorf("parse eSign simulator key: %w", err)
+		}
+		var ok bool
+		if key, ok = parsed.(*rsa.PrivateKey); !ok {
+			return nil, nil, errors.New("eSign simulator key must be RSA")
+		}
+	}
+	return cert, key, nil
+}
@@ -0,0 +1,80 @@
+package esign
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// The request and response documents of the eSign API (version 2.1) that
+// pass between the ASP and the ESP through the signer's browser
+
+const (
+	apiVersion = "2.1"
+	// timestampLayout is the ts attribute format, in IST without an offset
+	timestampLayout = "2006-01-02T15:04:05"
+)
+
+// esignRequest is posted to the ESP as the eSignRequest form field
+type esignRequest struct {
+	XMLName         xml.Name    `xml:"Esign"`
+	Version         string      `xml:"ver,attr"`
+	SignerConsent   string      `xml:"sc,attr"`
+	Timestamp       string      `xml:"ts,attr"`
+	Txn             string      `xml:"txn,attr"`
+	EKYCID          string      `xml:"ekycId,attr"`
+	EKYCIDType      string      `xml:"ekycIdType,attr"`
+	ASPID           string      `xml:"aspId,attr"`
+	AuthMode        string      `xml:"AuthMode,attr"`
+	ResponseSigType string      `xml:"responseSigType,attr"`
+	ResponseURL     string      `xml:"responseUrl,attr"`
+	Docs            []inputHash `xml:"Docs>InputHash"`
+}
+
+type inputHash struct {
+	ID            string `xml:"id,attr"`
+	HashAlgorithm string `xml:"hashAlgorithm,attr"`
+	DocInfo       string `xml:"docInfo,attr"`
+	Hash          string `xml:",chardata"`
+}
+
+// esignResponse is posted back to the ASP as the eSignResponse form field
+type esignResponse struct {
+	XMLName      xml.Name       `xml:"EsignResp"`
+	Version      string         `xml:"ver,attr"`
+	Status       string         `xml:"status,attr"`
+	Timestamp    string         `xml:"ts,attr"`
+	Txn          string         `xml:"txn,attr"`
+	ResponseCode string         `xml:"resCode,attr"`
+	ErrorCode    string         `xml:"errCode,attr"`
+	ErrorMessage string         `xml:"errMsg,attr"`
+	Certificate  string         `xml:"UserX509Certificate,omitempty"`
+	Signatures   []docSignature `xml:"Signatures>DocSignature,omitempty"`
+}
+
+type docSignature struct {
+	ID               string `xml:"id,attr"`
+	SigHashAlgorithm string `xml:"sigHashAlgorithm,attr"`
+	Error            string `xml:"error,attr,omitempty"`
+	// Signature is the base64 detached PKCS #7 over the input hash
+	Signature string `xml:",chardata"`
+}
+
+const (
+	responseStatusSuccess = "1"
+	responseStatusFailure = "0"
+)
+
+// marshalXML encodes v as a standalone XML document
+func marshalXML(v any) (string, error) {
+	out, err := xml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out), nil
+}
+
+func timestamp(t time.Time) string {
+	return t.In(ist).Format(timestampLayout)
+}
+
+var ist = time.FixedZone("IST", 5*3600+1800)
@@ -0,0 +1,72 @@
+// Package estamp checks e-stamp certificates with the issuing authority
+// (SHCIL in most states) before they are relied on for a lease.
+package estamp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+)
+
+var (
+	ErrCertificateNotFound = errors.New("e-stamp certificate not found")
+	// ErrDetailsMismatch means the certificate exists but was issued with different details
+	ErrDetailsMismatch = errors.New("e-stamp certificate details do not match")
+)
+
+// numberPattern matches certificate numbers such as IN-MH12345678901234X
+var numberPattern = regexp.MustCompile(`^IN-([A-Z]{2})[0-9]{14}[A-Z]$`)
+
+// Certificate holds the details printed on an e-stamp certificate
+type Certificate struct {
+	Number            string
+	State             string
+	DenominationPaise int64
+	Purchaser         string
+	IssueDate         time.Time
+}
+
+// EStampProvider confirms that a certificate was issued with the given details
+type EStampProvider interface {
+	Verify(ctx context.Context, certificate Certificate) error
+}
+
+// NewEStampProvider returns the provider selected by configuration
+func NewEStampProvider(cfg *config.EStampConfig) (EStampProvider, error) {
+	switch cfg.Provider {
+	case "fake":
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unsupported e-stamp provider: %s", cfg.Provider)
+	}
+}
+
+// ValidNumber reports whether number is a well-formed certificate number
+func ValidNumber(number string) bool {
+	return numberPattern.MatchString(number)
+}
+
+// FakeProvider accepts any well-formed certificate whose number carries the
+// certificate's state, for development without a live registry. Numbers whose
+// serial is all zeros are treated as unknown so rejection can be exercised.
+type FakeProvider struct{}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{}
+}
+
+func (p *FakeProvider) Verify(ctx context.Context, certificate Certificate) error {
+	m := numberPattern.FindStringSubmatch(certificate.Number)
+	if m == nil || strings.HasPrefix(certificate.Number[5:], "00000000000000") {
+		return ErrCertificateNotFound
+	}
+	if m[1] != certificate.State {
+		return fmt.Errorf("%w: issued in %s", ErrDetailsMismatch, m[1])
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Package gateway collects payments online through a payment gateway. An
+// order is created for the amount to be paid, the payer completes checkout
+// with the gateway, and the gateway confirms the payment both to the payer's
+// browser, with a signature over the order and payment IDs, and to us, with
+// a signed webhook event.
+package gateway
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"backend/internal/config"
+)
+
+var (
+	ErrOrderNotFound   = errors.New("payment order not found")
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrInvalidSignature means a payment confirmation or webhook event was not signed by the gateway
+	ErrInvalidSignature = errors.New("invalid gateway signature")
+	// ErrMalformedEvent means a correctly signed webhook event could not be read
+	ErrMalformedEvent = errors.New("malformed gateway event")
+	// ErrRefundRejected means the gateway would not refund the amount asked for
+	ErrRefundRejected = errors.New("refund rejected by gateway")
+)
+
+// Webhook event types
+const (
+	EventPaymentCaptured = "payment.captured"
+	EventPaymentFailed   = "payment.failed"
+	EventRefundProcessed = "refund.processed"
+)
+
+// OrderRequest asks the gateway to collect an amount in rupees
+type OrderRequest struct {
+	AmountPaise int64
+	// Receipt is our reference for the order, shown in the gateway's dashboard
+	Receipt string
+	Notes   map[string]string
+}
+
+// Order is an amount the gateway is ready to collect. The payer's checkout is
+// opened with KeyID and ID, or by visiting CheckoutURL where the gateway has one.
+type Order struct {
+	ID          string
+	AmountPaise int64
+	Currency    string
+	KeyID       string
+	CheckoutURL string
+}
+
+// PaymentConfirmation is what the checkout hands back to the payer's browser
+type PaymentConfirmation struct {
+	OrderID   string
+	PaymentID string
+	Signature string
+}
+
+// RefundRequest returns some or all of a captured payment to the payer
+type RefundRequest struct {
+	PaymentID   string
+	AmountPaise int64
+	// IdempotencyKey makes a retried request return the refund first made
+	// for it rather than refunding again
+	IdempotencyKey string
+	// Receipt is our reference for the refund, sent back in its webhook events
+	Receipt string
+	Notes   map[string]string
+}
+
+// Refund is a refund the gateway has accepted
+type Refund struct {
+	ID          string
+	PaymentID   string
+	AmountPaise int64
+	Status      string
+}
+
+// Event is a webhook event, read from the gateway's own format
+type Event struct {
+	// ID identifies the event across deliveries; the gateway retries until we acknowledge it
+	ID        string
+	Type      string
+	OrderID   string
+	PaymentID string
+	RefundID  string
+	// Receipt is our reference for a refund, as given when it was asked for
+	Receipt     string
+	AmountPaise int64
+	Currency    string
+	Method      string
+	// Reason is the gateway's explanation of a failed payment
+	Reason     string
+	OccurredAt time.Time
+}
+
+// PaymentGateway collects payments through an online payment gateway
+type PaymentGateway interface {
+	// Name identifies the gateway on stored orders and events
+	Name() string
+	CreateOrder(ctx context.Context, req OrderRequest) (*Order, error)
+	// VerifyPayment checks that a confirmation from the checkout was signed by the gateway
+	VerifyPayment(ctx context.Context, confirmation PaymentConfirmation) error
+	Refund(ctx context.Context, req RefundRequest) (*Refund, error)
+	// ParseWebhook checks the signature of a webhook delivery and reads its event.
+	// Events of types this package does not know are returned with only ID and Type.
+	ParseWebhook(payload []byte, header http.Header) (*Event, error)
+}
+
+// NewPaymentGateway returns the gateway selected by configuration
+func NewPaymentGateway(cfg *config.GatewayConfig) (PaymentGateway, error) {
+	switch cfg.Provider {
+	case "simulator":
+		return NewSimulator(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported payment gateway: %s", cfg.Provider)
+	}
+}
+
+// sign returns the hex HMAC-SHA256 of message, as gateways sign confirmations and webhooks
+func sign(secret, message []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(message)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validSignature compares signature with the HMAC of message in constant time
+func validSignature(secret, message []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	want, _ := hex.DecodeString(sign(secret, message))
+	return hmac.Equal(got, want)
+}
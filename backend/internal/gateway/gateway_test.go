@@ -0,0 +1,346 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/config"
+)
+
+const (
+	testKeySecret     = "test_key_secret"
+	testWebhookSecret = "test_webhook_secret"
+)
+
+// delivery is a webhook event as the simulator posted it
+type delivery struct {
+	payload []byte
+	header  http.Header
+}
+
+// newTestSimulator returns a simulator whose webhook events are sent to the
+// returned channel
+func newTestSimulator(t *testing.T) (*Simulator, <-chan delivery) {
+	t.Helper()
+	deliveries := make(chan delivery, 16)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		deliveries <- delivery{payload: payload, header: r.Header.Clone()}
+	}))
+	t.Cleanup(receiver.Close)
+
+	sim, err := NewSimulator(&config.GatewayConfig{
+		Provider:      "simulator",
+		KeyID:         "test_key",
+		KeySecret:     testKeySecret,
+		WebhookSecret: testWebhookSecret,
+		WebhookURL:    receiver.URL,
+		ReturnURL:     "http://localhost/return",
+		SimulatorURL:  "http://localhost/checkout",
+	})
+	if err != nil {
+		t.Fatalf("NewSimulator: %v", err)
+	}
+	return sim, deliveries
+}
+
+func nextDelivery(t *testing.T, deliveries <-chan delivery) delivery {
+	t.Helper()
+	select {
+	case d := <-deliveries:
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("no webhook event delivered")
+		return delivery{}
+	}
+}
+
+// pay completes checkout for an order on the simulator's page and returns the payment ID
+func pay(t *testing.T, sim *Simulator, orderID string) string {
+	t.Helper()
+	form := url.Values{"order_id": {orderID}, "action": {"pay"}}
+	req := httptest.NewRequest(http.MethodPost, "/checkout", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	sim.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("checkout responded %d", rec.Code)
+	}
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("checkout redirect: %v", err)
+	}
+	return location.Query().Get("payment_id")
+}
+
+func signedHeader(payload []byte) http.Header {
+	header := http.Header{}
+	header.Set(SignatureHeader, sign([]byte(testWebhookSecret), payload))
+	header.Set(EventIDHeader, "evt_test")
+	return header
+}
+
+func TestParseWebhookSignature(t *testing.T) {
+	sim, _ := newTestSimulator(t)
+	payload := []byte(`{"event":"payment.captured","created_at":1760000000,"payload":{"payment":{"id":"pay_1","order_id":"order_1","amount":1500000,"currency":"INR","status":"captured","method":"upi"}}}`)
+	signature := sign([]byte(testWebhookSecret), payload)
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		signature string
+		wantErr   error
+	}{
+		{"valid", payload, signature, nil},
+		{"tampered amount", []byte(strings.Replace(string(payload), "1500000", "150000000", 1)), signature, ErrInvalidSignature},
+		{"appended body", append(append([]byte{}, payload...), ' '), signature, ErrInvalidSignature},
+		{"tampered signature", payload, signature[:len(signature)-1] + "0", ErrInvalidSignature},
+		{"signed with another secret", payload, sign([]byte("other"), payload), ErrInvalidSignature},
+		{"signed with the key secret", payload, sign([]byte(testKeySecret), payload), ErrInvalidSignature},
+		{"not hex", payload, "not-a-signature", ErrInvalidSignature},
+		{"missing", payload, "", ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set(SignatureHeader, tt.signature)
+			event, err := sim.ParseWebhook(tt.payload, header)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseWebhook error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && event != nil {
+				t.Fatalf("ParseWebhook returned an event for a rejected delivery: %+v", event)
+			}
+		})
+	}
+}
+
+func TestParseWebhookEvents(t *testing.T) {
+	sim, _ := newTestSimulator(t)
+
+	tests := []struct {
+		name    string
+		payload string
+		want    Event
+		wantErr error
+	}{
+		{
+			name:    "payment captured",
+			payload: `{"event":"payment.captured","created_at":1760000000,"payload":{"payment":{"id":"pay_1","order_id":"order_1","amount":1500000,"currency":"INR","status":"captured","method":"upi"}}}`,
+			want:    Event{ID: "evt_test", Type: EventPaymentCaptured, OrderID: "order_1", PaymentID: "pay_1", AmountPaise: 1500000, Currency: "INR", Method: "upi", OccurredAt: time.Unix(1760000000, 0)},
+		},
+		{
+			name:    "payment failed",
+			payload: `{"event":"payment.failed","created_at":1760000000,"payload":{"payment":{"id":"pay_2","order_id":"order_1","amount":1500000,"currency":"INR","status":"failed","method":"card","error_description":"Declined"}}}`,
+			want:    Event{ID: "evt_test", Type: EventPaymentFailed, OrderID: "order_1", PaymentID: "pay_2", AmountPaise: 1500000, Currency: "INR", Method: "card", Reason: "Declined", OccurredAt: time.Unix(1760000000, 0)},
+		},
+		{
+			name:    "refund processed",
+			payload: `{"event":"refund.processed","created_at":1760000000,"payload":{"refund":{"id":"rfnd_1","payment_id":"pay_1","amount":50000,"currency":"INR","status":"processed","receipt":"2c5ea4c0-4067-11e9-8bad-9b1deb4d3b7d"}}}`,
+			want:    Event{ID: "evt_test", Type: EventRefundProcessed, PaymentID: "pay_1", RefundID: "rfnd_1", Receipt: "2c5ea4c0-4067-11e9-8bad-9b1deb4d3b7d", AmountPaise: 50000, Currency: "INR", OccurredAt: time.Unix(1760000000, 0)},
+		},
+		{
+			name:    "unknown type",
+			payload: `{"event":"settlement.processed","created_at":1760000000,"payload":{}}`,
+			want:    Event{ID: "evt_test", Type: "settlement.processed", OccurredAt: time.Unix(1760000000, 0)},
+		},
+		{
+			name:    "payment event without a payment",
+			payload: `{"event":"payment.captured","created_at":1760000000,"payload":{}}`,
+			want:    Event{ID: "evt_test", Type: EventPaymentCaptured, OccurredAt: time.Unix(1760000000, 0)},
+			wantErr: ErrMalformedEvent,
+		},
+		{
+			name:    "not json",
+			payload: `event=payment.captured`,
+			want:    Event{ID: "evt_test"},
+			wantErr: ErrMalformedEvent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := []byte(tt.payload)
+			event, err := sim.ParseWebhook(payload, signedHeader(payload))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseWebhook error = %v, want %v", err, tt.wantErr)
+			}
+			// a malformed event still has its ID, so its deliveries are recorded
+			if event == nil {
+				t.Fatal("ParseWebhook returned no event")
+			}
+			if !event.OccurredAt.Equal(tt.want.OccurredAt) {
+				t.Errorf("OccurredAt = %v, want %v", event.OccurredAt, tt.want.OccurredAt)
+			}
+			event.OccurredAt, tt.want.OccurredAt = time.Time{}, time.Time{}
+			if *event != tt.want {
+				t.Errorf("ParseWebhook = %+v, want %+v", *event, tt.want)
+			}
+		})
+	}
+}
+
+// A retried delivery must parse to the same event ID, as the ID is what
+// stops a payment being credited twice
+func TestParseWebhookEventID(t *testing.T) {
+	sim, _ := newTestSimulator(t)
+	payload := []byte(`{"event":"payment.captured","created_at":1760000000,"payload":{"payment":{"id":"pay_1","order_id":"order_1","amount":1500000,"currency":"INR","status":"captured","method":"upi"}}}`)
+	other := []byte(`{"event":"payment.captured","created_at":1760000001,"payload":{"payment":{"id":"pay_1","order_id":"order_1","amount":1500000,"currency":"INR","status":"captured","method":"upi"}}}`)
+
+	parse := func(payload []byte, eventID string) string {
+		t.Helper()
+		header := http.Header{}
+		header.Set(SignatureHeader, sign([]byte(testWebhookSecret), payload))
+		if eventID != "" {
+			header.Set(EventIDHeader, eventID)
+		}
+		event, err := sim.ParseWebhook(payload, header)
+		if err != nil {
+			t.Fatalf("ParseWebhook: %v", err)
+		}
+		return event.ID
+	}
+
+	tests := []struct {
+		name       string
+		first      []byte
+		firstID    string
+		second     []byte
+		secondID   string
+		wantSameID bool
+	}{
+		{"retry with the event ID header", payload, "evt_1", payload, "evt_1", true},
+		{"retry without the event ID header", payload, "", payload, "", true},
+		{"different events without the header", payload, "", other, "", false},
+		{"different event IDs", payload, "evt_1", payload, "evt_2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := parse(tt.first, tt.firstID), parse(tt.second, tt.secondID)
+			if first == "" {
+				t.Fatal("event has no ID")
+			}
+			if (first == second) != tt.wantSameID {
+				t.Errorf("event IDs %q and %q, want same = %v", first, second, tt.wantSameID)
+			}
+		})
+	}
+}
+
+func TestVerifyPayment(t *testing.T) {
+	sim, _ := newTestSimulator(t)
+	valid := sign([]byte(testKeySecret), []byte("order_1|pay_1"))
+
+	tests := []struct {
+		name         string
+		confirmation PaymentConfirmation
+		wantErr      error
+	}{
+		{"valid", PaymentConfirmation{OrderID: "order_1", PaymentID: "pay_1", Signature: valid}, nil},
+		{"another order", PaymentConfirmation{OrderID: "order_2", PaymentID: "pay_1", Signature: valid}, ErrInvalidSignature},
+		{"another payment", PaymentConfirmation{OrderID: "order_1", PaymentID: "pay_2", Signature: valid}, ErrInvalidSignature},
+		{"signed with the webhook secret", PaymentConfirmation{OrderID: "order_1", PaymentID: "pay_1", Signature: sign([]byte(testWebhookSecret), []byte("order_1|pay_1"))}, ErrInvalidSignature},
+		{"unsigned", PaymentConfirmation{OrderID: "order_1", PaymentID: "pay_1"}, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := sim.VerifyPayment(context.Background(), tt.confirmation); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyPayment error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// The simulator's own deliveries carry a signature and event ID that ParseWebhook accepts
+func TestSimulatorDeliversSignedEvents(t *testing.T) {
+	sim, deliveries := newTestSimulator(t)
+	ctx := context.Background()
+
+	order, err := sim.CreateOrder(ctx, OrderRequest{AmountPaise: 1500000, Receipt: "due-1"})
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	paymentID := pay(t, sim, order.ID)
+
+	d := nextDelivery(t, deliveries)
+	event, err := sim.ParseWebhook(d.payload, d.header)
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if event.Type != EventPaymentCaptured || event.OrderID != order.ID || event.PaymentID != paymentID || event.AmountPaise != 1500000 {
+		t.Errorf("delivered event = %+v", *event)
+	}
+	if event.ID != d.header.Get(EventIDHeader) {
+		t.Errorf("event ID = %q, want the delivery's %q", event.ID, d.header.Get(EventIDHeader))
+	}
+}
+
+func TestSimulatorRefund(t *testing.T) {
+	sim, deliveries := newTestSimulator(t)
+	ctx := context.Background()
+
+	order, err := sim.CreateOrder(ctx, OrderRequest{AmountPaise: 100000})
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	paymentID := pay(t, sim, order.ID)
+	nextDelivery(t, deliveries) // payment.captured
+
+	first, err := sim.Refund(ctx, RefundRequest{PaymentID: paymentID, AmountPaise: 60000, IdempotencyKey: "refund-1", Receipt: "refund-1"})
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	d := nextDelivery(t, deliveries)
+	event, err := sim.ParseWebhook(d.payload, d.header)
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if event.Type != EventRefundProcessed || event.RefundID != first.ID || event.Receipt != "refund-1" || event.AmountPaise != 60000 {
+		t.Errorf("refund event = %+v", *event)
+	}
+
+	tests := []struct {
+		name    string
+		req     RefundRequest
+		wantID  string
+		wantErr error
+	}{
+		// a retry returns the first refund rather than refunding again
+		{"retry", RefundRequest{PaymentID: paymentID, AmountPaise: 60000, IdempotencyKey: "refund-1"}, first.ID, nil},
+		// only 40000 is left, so the first refund was counted once
+		{"more than is left", RefundRequest{PaymentID: paymentID, AmountPaise: 60000, IdempotencyKey: "refund-2"}, "", ErrRefundRejected},
+		{"nothing", RefundRequest{PaymentID: paymentID, AmountPaise: 0, IdempotencyKey: "refund-3"}, "", ErrRefundRejected},
+		{"unknown payment", RefundRequest{PaymentID: "pay_unknown", AmountPaise: 100, IdempotencyKey: "refund-4"}, "", ErrPaymentNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refund, err := sim.Refund(ctx, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refund error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && refund.ID != tt.wantID {
+				t.Errorf("Refund ID = %q, want %q", refund.ID, tt.wantID)
+			}
+		})
+	}
+
+	select {
+	case d := <-deliveries:
+		t.Errorf("unexpected webhook event after the first refund: %s", d.payload)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	rest, err := sim.Refund(ctx, RefundRequest{PaymentID: paymentID, AmountPaise: 40000, IdempotencyKey: "refund-5"})
+	if err != nil {
+		t.Fatalf("Refund of the rest: %v", err)
+	}
+	if rest.ID == first.ID {
+		t.Error("a refund under a new key returned the first refund")
+	}
+}
@@ -0,0 +1,361 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"backend/internal/config"
+	"backend/pkg/inr"
+)
+
+// Headers the simulator signs its webhook deliveries with
+const (
+	SignatureHeader = "X-Gateway-Signature"
+	EventIDHeader   = "X-Gateway-Event-Id"
+)
+
+const idAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// Simulator is a payment gateway for development. It serves a checkout page
+// at SimulatorURL where an order can be paid or the payment failed, sends the
+// payer on to ReturnURL with a signed confirmation, and posts signed webhook
+// events to WebhookURL as a real gateway would. Orders are held in memory and
+// lost on restart.
+type Simulator struct {
+	cfg    *config.GatewayConfig
+	client *http.Client
+
+	mu     sync.Mutex
+	orders map[string]*simulatedOrder
+	// payments maps payment IDs to their order
+	payments map[string]*simulatedOrder
+	// refunds maps idempotency keys to the refund made for them
+	refunds map[string]*Refund
+}
+
+type simulatedOrder struct {
+	id          string
+	amountPaise int64
+	receipt     string
+	paymentID   string // set once captured
+	refunded    int64
+}
+
+func NewSimulator(cfg *config.GatewayConfig) (*Simulator, error) {
+	if cfg.KeySecret == "" || cfg.WebhookSecret == "" {
+		return nil, errors.New("PAYMENT_GATEWAY_KEY_SECRET and PAYMENT_GATEWAY_WEBHOOK_SECRET must be set for the simulator")
+	}
+	return &Simulator{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		orders:   map[string]*simulatedOrder{},
+		payments: map[string]*simulatedOrder{},
+		refunds:  map[string]*Refund{},
+	}, nil
+}
+
+func (s *Simulator) Name() string {
+	return "simulator"
+}
+
+// Path is where the checkout page is served, taken from SimulatorURL
+func (s *Simulator) Path() string {
+	u, err := url.Parse(s.cfg.SimulatorURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+func (s *Simulator) CreateOrder(ctx context.Context, req OrderRequest) (*Order, error) {
+	if req.AmountPaise <= 0 {
+		return nil, errors.New("order amount must be positive")
+	}
+	id, err := newID("order_")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.orders[id] = &simulatedOrder{id: id, amountPaise: req.AmountPaise, receipt: req.Receipt}
+	s.mu.Unlock()
+
+	return &Order{
+		ID:          id,
+		AmountPaise: req.AmountPaise,
+		Currency:    "INR",
+		KeyID:       s.cfg.KeyID,
+		CheckoutURL: s.cfg.SimulatorURL + "?" + url.Values{"order_id": {id}}.Encode(),
+	}, nil
+}
+
+func (s *Simulator) VerifyPayment(ctx context.Context, confirmation PaymentConfirmation) error {
+	if !validSignature([]byte(s.cfg.KeySecret), []byte(confirmation.OrderID+"|"+confirmation.PaymentID), confirmation.Signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *Simulator) Refund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	id, err := newID("rfnd_")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if refund, ok := s.refunds[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		s.mu.Unlock()
+		first := *refund
+		return &first, nil
+	}
+	order, ok := s.payments[req.PaymentID]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrPaymentNotFound
+	}
+	if req.AmountPaise <= 0 || req.AmountPaise > order.amountPaise-order.refunded {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s left to refund", ErrRefundRejected, inr.FormatWithSymbol(order.amountPaise-order.refunded))
+	}
+	order.refunded += req.AmountPaise
+	refund := &Refund{ID: id, PaymentID: req.PaymentID, AmountPaise: req.AmountPaise, Status: "processed"}
+	if req.IdempotencyKey != "" {
+		s.refunds[req.IdempotencyKey] = refund
+	}
+	s.mu.Unlock()
+
+	s.send(EventRefundProcessed, webhookEntities{Refund: &webhookRefund{
+		ID:        id,
+		PaymentID: req.PaymentID,
+		Amount:    req.AmountPaise,
+		Currency:  "INR",
+		Status:    "processed",
+		Receipt:   req.Receipt,
+	}})
+	first := *refund
+	return &first, nil
+}
+
+// webhookBody is the body of a webhook delivery, shaped like the common
+// Indian gateways': an event type and the entities it concerns
+type webhookBody struct {
+	Event     string          `json:"event"`
+	CreatedAt int64           `json:"created_at"`
+	Payload   webhookEntities `json:"payload"`
+}
+
+type webhookEntities struct {
+	Payment *webhookPayment `json:"payment,omitempty"`
+	Refund  *webhookRefund  `json:"refund,omitempty"`
+}
+
+type webhookPayment struct {
+	ID               string `json:"id"`
+	OrderID          string `json:"order_id"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	Status           string `json:"status"`
+	Method           string `json:"method"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type webhookRefund struct {
+	ID        string `json:"id"`
+	PaymentID string `json:"payment_id"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Status    string `json:"status"`
+	Receipt   string `json:"receipt,omitempty"`
+}
+
+func (s *Simulator) ParseWebhook(payload []byte, header http.Header) (*Event, error) {
+	if !validSignature([]byte(s.cfg.WebhookSecret), payload, header.Get(SignatureHeader)) {
+		return nil, ErrInvalidSignature
+	}
+
+	// A delivery without an event ID is identified by its content, so a
+	// retry of it is still recognised
+	id := header.Get(EventIDHeader)
+	if id == "" {
+		sum := sha256.Sum256(payload)
+		id = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	var body webhookBody
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return &Event{ID: id}, fmt.Errorf("%w: %v", ErrMalformedEvent, err)
+	}
+	event := &Event{ID: id, Type: body.Event, OccurredAt: time.Unix(body.CreatedAt, 0)}
+	switch body.Event {
+	case EventPaymentCaptured, EventPaymentFailed:
+		p := body.Payload.Payment
+		if p == nil {
+			return event, fmt.Errorf("%w: %s without a payment", ErrMalformedEvent, body.Event)
+		}
+		event.OrderID, event.PaymentID = p.OrderID, p.ID
+		event.AmountPaise, event.Currency, event.Method = p.Amount, p.Currency, p.Method
+		event.Reason = p.ErrorDescription
+	case EventRefundProcessed:
+		r := body.Payload.Refund
+		if r == nil {
+			return event, fmt.Errorf("%w: %s without a refund", ErrMalformedEvent, body.Event)
+		}
+		event.RefundID, event.PaymentID, event.Receipt = r.ID, r.PaymentID, r.Receipt
+		event.AmountPaise, event.Currency = r.Amount, r.Currency
+	}
+	return event, nil
+}
+
+// ServeHTTP is the checkout page. It shows the order and, when the payer
+// pays or fails the payment, notifies the webhook and sends the payer on to
+// ReturnURL with the order and payment IDs and the signature over them.
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	orderID := r.FormValue("order_id")
+	s.mu.Lock()
+	order, ok := s.orders[orderID]
+	var page checkoutPage
+	if ok {
+		page = checkoutPage{OrderID: order.id, Receipt: order.receipt, Amount: inr.FormatWithSymbol(order.amountPaise), Paid: order.paymentID != ""}
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "Unknown order", http.StatusNotFound)
+		return
+	}
+
+	if r.Method != http.MethodPost || page.Paid {
+		s.render(w, page)
+		return
+	}
+
+	paymentID, err := newID("pay_")
+	if err != nil {
+		http.Error(w, "Failed to start payment", http.StatusInternalServerError)
+		return
+	}
+	payment := &webhookPayment{ID: paymentID, OrderID: order.id, Amount: order.amountPaise, Currency: "INR", Method: "upi"}
+	query := url.Values{"order_id": {order.id}, "payment_id": {paymentID}}
+
+	if r.PostFormValue("action") == "fail" {
+		payment.Status, payment.ErrorDescription = "failed", "Payment declined by the payer's bank"
+		s.send(EventPaymentFailed, webhookEntities{Payment: payment})
+		query.Set("status", "failed")
+	} else {
+		s.mu.Lock()
+		order.paymentID = paymentID
+		s.payments[paymentID] = order
+		s.mu.Unlock()
+
+		payment.Status = "captured"
+		s.send(EventPaymentCaptured, webhookEntities{Payment: payment})
+		query.Set("status", "paid")
+		query.Set("signature", sign([]byte(s.cfg.KeySecret), []byte(order.id+"|"+paymentID)))
+	}
+	http.Redirect(w, r, s.cfg.ReturnURL+"?"+query.Encode(), http.StatusSeeOther)
+}
+
+// send posts a signed webhook event in the background, retrying a few times
+// while the receiver fails, as gateways do
+func (s *Simulator) send(eventType string, entities webhookEntities) {
+	id, err := newID("evt_")
+	if err != nil {
+		log.Printf("Payment gateway simulator failed to create event: %v", err)
+		return
+	}
+	payload, err := json.Marshal(webhookBody{Event: eventType, CreatedAt: time.Now().Unix(), Payload: entities})
+	if err != nil {
+		log.Printf("Payment gateway simulator failed to encode event %s: %v", id, err)
+		return
+	}
+	signature := sign([]byte(s.cfg.WebhookSecret), payload)
+
+	go func() {
+		for attempt, wait := 1, time.Second; attempt <= 3; attempt, wait = attempt+1, wait*4 {
+			err := s.deliver(id, payload, signature)
+			if err == nil {
+				return
+			}
+			log.Printf("Payment gateway simulator failed to deliver event %s (attempt %d): %v", id, attempt, err)
+			time.Sleep(wait)
+		}
+	}()
+}
+
+func (s *Simulator) deliver(id string, payload []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, s.cfg.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+	req.Header.Set(EventIDHeader, id)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
+
+type checkoutPage struct {
+	OrderID string
+	Receipt string
+	Amount  string
+	Paid    bool
+}
+
+func (s *Simulator) render(w http.ResponseWriter, page checkoutPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := checkoutTemplate.Execute(w, page); err != nil {
+		log.Printf("Payment gateway simulator failed to render page: %v", err)
+	}
+}
+
+var checkoutTemplate = template.Must(template.New("checkout").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Checkout (simulator)</title></head>
+<body>
+<h1>Checkout</h1>
+<p><strong>Simulator:</strong> no money moves.</p>
+<p>Order {{.OrderID}}{{if .Receipt}} ({{.Receipt}}){{end}}: <strong>{{.Amount}}</strong></p>
+{{if .Paid}}
+<p>This order has been paid.</p>
+{{else}}
+<form method="post">
+<input type="hidden" name="order_id" value="{{.OrderID}}">
+<button type="submit" name="action" value="pay">Pay</button>
+<button type="submit" name="action" value="fail">Fail payment</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+// newID returns a random gateway-style ID with prefix
+func newID(prefix string) (string, error) {
+	random := make([]byte, 14)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	id := make([]byte, len(random))
+	for i, b := range random {
+		id[i] = idAlphabet[int(b)%len(idAlphabet)]
+	}
+	return prefix + string(id), nil
+}
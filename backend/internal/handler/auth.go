@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AuthHandler struct {
+	authService service.AuthService
+	userService service.UserService
+}
+
+func NewAuthHandler(authService service.AuthService, userService service.UserService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		userService: userService,
+	}
+}
+
+// Register godoc
+// @Summary Register a new account
+// @Description Create a user account with email and password, as an owner or a tenant
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param user body model.RegisterRequest true "User details"
+// @Success 201 {object} response.Response{data=model.User}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /auth/register [post]
+func (h *AuthHandler) Register(c echo.Context) error {
+	req := new(model.RegisterRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	user, err := h.userService.Register(c.Request().Context(), service.CreateUserInput{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+		Phone:    req.Phone,
+		Role:     req.Role,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, user)
+}
+
+// Login godoc
+// @Summary Log in with email and password
+// @Description Exchange credentials for a signed access token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body model.LoginRequest true "Login credentials"
+// @Success 200 {object} response.Response{data=model.TokenResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c echo.Context) error {
+	req := new(model.LoginRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	tokens, err := h.authService.Login(c.Request().Context(), service.LoginInput{
+		Email:    req.Email,
+		Password: req.Password,
+		Client:   clientInfo(c, req.DeviceName),
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, tokens)
+}
+
+// RequestOTP godoc
+// @Summary Request a login OTP
+// @Description Send a one-time password by SMS to the given mobile number (+91 assumed when no country code is given)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.RequestOTPRequest true "Mobile number"
+// @Success 200 {object} response.Response{data=model.OTPRequestResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 429 {object} response.ErrorResponse
+// @Router /auth/otp/request [post]
+func (h *AuthHandler) RequestOTP(c echo.Context) error {
+	req := new(model.RequestOTPRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	result, err := h.authService.RequestOTP(c.Request().Context(), req.Phone)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, result)
+}
+
+// VerifyOTP godoc
+// @Summary Verify a login OTP
+// @Description Exchange a valid OTP for an access token. A new account is created when the number is not registered, in which case name is required and role may be owner or tenant.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.VerifyOTPRequest true "Mobile number and OTP"
+// @Success 200 {object} response.Response{data=model.TokenResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 429 {object} response.ErrorResponse
+// @Router /auth/otp/verify [post]
+func (h *AuthHandler) VerifyOTP(c echo.Context) error {
+	req := new(model.VerifyOTPRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	tokens, err := h.authService.VerifyOTP(c.Request().Context(), service.VerifyOTPInput{
+		Phone:  req.Phone,
+		Code:   req.Code,
+		Name:   req.Name,
+		Role:   req.Role,
+		Client: clientInfo(c, req.DeviceName),
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, tokens)
+}
+
+// Refresh godoc
+// @Summary Refresh an access token
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated; presenting an already-used refresh token revokes the session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} response.Response{data=model.TokenResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	req := new(model.RefreshTokenRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	tokens, err := h.authService.Refresh(c.Request().Context(), req.RefreshToken, clientInfo(c, ""))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, tokens)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revoke the session the access token belongs to
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	if err := h.authService.Logout(c.Request().Context(), middleware.CurrentSessionID(c)); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
+
+// ListSessions godoc
+// @Summary List active devices
+// @Description List the current user's active sessions, one per signed-in device
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.Session}
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c echo.Context) error {
+	user := middleware.CurrentUser(c)
+
+	sessions, err := h.authService.ListSessions(c.Request().Context(), user.ID, middleware.CurrentSessionID(c))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, sessions)
+}
+
+// RevokeSession godoc
+// @Summary Revoke a device
+// @Description Sign out one of the current user's sessions, e.g. a lost phone. Takes effect on that device's next request.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid session ID format", nil)
+	}
+
+	user := middleware.CurrentUser(c)
+	if err := h.authService.RevokeSession(c.Request().Context(), user.ID, id); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
+
+// Me godoc
+// @Summary Get the current user
+// @Description Get the user identified by the bearer token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.User}
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/me [get]
+func (h *AuthHandler) Me(c echo.Context) error {
+	return response.Success(c, middleware.CurrentUser(c))
+}
+
+func clientInfo(c echo.Context, deviceName string) service.ClientInfo {
+	return service.ClientInfo{
+		DeviceName: deviceName,
+		UserAgent:  c.Request().UserAgent(),
+		IPAddress:  c.RealIP(),
+	}
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/repository"
+	"backend/internal/service"
+	"backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type BankStatementHandler struct {
+	reconciliationService service.ReconciliationService
+}
+
+func NewBankStatementHandler(reconciliationService service.ReconciliationService) *BankStatementHandler {
+	return &BankStatementHandler{reconciliationService: reconciliationService}
+}
+
+type ListBankStatementImportsResponse struct {
+	Imports []model.BankStatementImport `json:"imports"`
+	Total   int64                       `json:"total"`
+	Limit   int                         `json:"limit"`
+	Offset  int                         `json:"offset"`
+}
+
+type ListBankTransactionsResponse struct {
+	Transactions []model.BankTransaction `json:"transactions"`
+	Total        int64                   `json:"total"`
+	Limit        int                     `json:"limit"`
+	Offset       int                     `json:"offset"`
+}
+
+// ListBankFormats godoc
+// @Summary List bank statement formats
+// @Description List the banks whose statement exports can be imported, by code
+// @Tags bank-statements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]bankstatement.Bank}
+// @Failure 401 {object} response.ErrorResponse
+// @Router /bank-statements/formats [get]
+func (h *BankStatementHandler) ListBankFormats(c echo.Context) error {
+	return response.Success(c, h.reconciliationService.Banks())
+}
+
+// ImportBankStatement godoc
+// @Summary Import a bank statement
+// @Description Import a bank statement exported as CSV, XLSX, Excel 97-2003 XLS or the HTML table some banks save as .xls; Excel 5.0/95 and password-protected workbooks are rejected and must be saved as CSV or XLSX first. Each credit is matched to the open rent dues of the leases the current user manages by amount, date, UTR and payer name. Clear matches are posted to the lease's ledger as payments, ambiguous ones go to the review queue, and credits imported before are skipped. Rows whose amount cannot be read are skipped and counted in unreadable_count.
+// @Tags bank-statements
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param bank formData string true "Bank code from the formats list, e.g. hdfc"
+// @Param file formData file true "Statement file"
+// @Success 201 {object} response.Response{data=model.BankStatementImport}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /bank-statements [post]
+func (h *BankStatementHandler) ImportBankStatement(c echo.Context) error {
+	req := new(model.ImportBankStatementRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, "A statement file is required", nil)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return response.BadRequest(c, "Unable to read uploaded file", nil)
+	}
+	defer src.Close()
+
+	record, err := h.reconciliationService.ImportStatement(c.Request().Context(), middleware.CurrentUser(c), service.ImportBankStatementInput{
+		Bank:     req.Bank,
+		FileName: file.Filename,
+		Size:     file.Size,
+		Content:  src,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, record)
+}
+
+// ListBankStatementImports godoc
+// @Summary List bank statement imports
+// @Description List the statements the current user has imported, newest first, with how their credits were matched
+// @Tags bank-statements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=ListBankStatementImportsResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Router /bank-statements [get]
+func (h *BankStatementHandler) ListBankStatementImports(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	imports, total, err := h.reconciliationService.ListImports(c.Request().Context(), middleware.CurrentUser(c), limit, offset)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, ListBankStatementImportsResponse{
+		Imports: imports,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// ListBankTransactions godoc
+// @Summary List imported bank credits
+// @Description List the credits read from the current user's statements, newest first, with the leases and dues each unposted one may be for. Filtered on status review it is the review queue.
+// @Tags bank-statements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Status" Enums(matched, review, unmatched, ignored, already_recorded)
+// @Param import_id query string false "Statement import ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=ListBankTransactionsResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /bank-transactions [get]
+func (h *BankStatementHandler) ListBankTransactions(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	filter := repository.BankTransactionFilter{Status: c.QueryParam("status")}
+	if value := c.QueryParam("import_id"); value != "" {
+		importID, err := uuid.Parse(value)
+		if err != nil {
+			return response.BadRequest(c, "Invalid import ID format", nil)
+		}
+		filter.ImportID = &importID
+	}
+
+	txns, total, err := h.reconciliationService.ListTransactions(c.Request().Context(), middleware.CurrentUser(c), filter, limit, offset)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, ListBankTransactionsResponse{
+		Transactions: txns,
+		Total:        total,
+		Limit:        limit,
+		Offset:       offset,
+	})
+}
+
+// ConfirmBankMatch godoc
+// @Summary Confirm a bank credit
+// @Description Post an imported credit to a lease's ledger as a payment, whether or not the lease was among its matches. A credit whose payment was posted and then reversed can be confirmed again.
+// @Tags bank-statements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Bank transaction ID"
+// @Param request body model.ConfirmBankMatchRequest true "Lease and due"
+// @Success 200 {object} response.Response{data=model.BankTransaction}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /bank-transactions/{id}/confirm [post]
+func (h *BankStatementHandler) ConfirmBankMatch(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid bank transaction ID format", nil)
+	}
+
+	req := new(model.ConfirmBankMatchRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	input := service.ConfirmBankMatchInput{LeaseID: uuid.MustParse(req.LeaseID)}
+	if req.RentDueID != "" {
+		dueID := uuid.MustParse(req.RentDueID)
+		input.RentDueID = &dueID
+	}
+
+	txn, err := h.reconciliationService.ConfirmMatch(c.Request().Context(), middleware.CurrentUser(c), id, input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, txn)
+}
+
+// IgnoreBankTransaction godoc
+// @Summary Ignore a bank credit
+// @Description Mark an imported credit as not rent, taking it out of the review queue. It can still be confirmed later.
+// @Tags bank-statements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Bank transaction ID"
+// @Success 200 {object} response.Response{data=model.BankTransaction}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /bank-transactions/{id}/ignore [post]
+func (h *BankStatementHandler) IgnoreBankTransaction(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid bank transaction ID format", nil)
+	}
+
+	txn, err := h.reconciliationService.IgnoreTransaction(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, txn)
+}
@@ -0,0 +1,612 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type BuildingHandler struct {
+	buildingService service.BuildingService
+}
+
+func NewBuildingHandler(buildingService service.BuildingService) *BuildingHandler {
+	return &BuildingHandler{buildingService: buildingService}
+}
+
+type ListBuildingsResponse struct {
+	Buildings []model.Building `json:"buildings"`
+	Total     int64            `json:"total"`
+	Limit     int              `json:"limit"`
+	Offset    int              `json:"offset"`
+}
+
+// ListBuildings godoc
+// @Summary List buildings
+// @Description Get a paginated list of the buildings the current user owns or manages (all buildings for admins)
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=ListBuildingsResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Router /buildings [get]
+func (h *BuildingHandler) ListBuildings(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	buildings, total, err := h.buildingService.List(c.Request().Context(), middleware.CurrentUser(c), limit, offset)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, ListBuildingsResponse{
+		Buildings: buildings,
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+	})
+}
+
+// CreateBuilding godoc
+// @Summary Create a new building
+// @Description Create a building or complex whose units are rented out separately (owners only; admins may set owner_id)
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param building body model.CreateBuildingRequest true "Building details"
+// @Success 201 {object} response.Response{data=model.Building}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /buildings [post]
+func (h *BuildingHandler) CreateBuilding(c echo.Context) error {
+	req := new(model.CreateBuildingRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	input := service.CreateBuildingInput{
+		Name:        req.Name,
+		SocietyName: req.SocietyName,
+		Address: model.Address{
+			AddressLine1: req.AddressLine1,
+			AddressLine2: req.AddressLine2,
+			Locality:     req.Locality,
+			City:         req.City,
+			State:        req.State,
+			Pincode:      req.Pincode,
+		},
+		TotalFloors: req.TotalFloors,
+	}
+	if req.OwnerID != "" {
+		ownerID := uuid.MustParse(req.OwnerID)
+		input.OwnerID = &ownerID
+	}
+
+	building, err := h.buildingService.Create(c.Request().Context(), middleware.CurrentUser(c), input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, building)
+}
+
+// GetBuilding godoc
+// @Summary Get a building by ID
+// @Description Get building details, including its shared charges
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Success 200 {object} response.Response{data=model.Building}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id} [get]
+func (h *BuildingHandler) GetBuilding(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	building, err := h.buildingService.GetByID(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, building)
+}
+
+// UpdateBuilding godoc
+// @Summary Update a building
+// @Description Update building details by ID; address changes are copied to every unit (owner or manager)
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param building body model.UpdateBuildingRequest true "Building update details"
+// @Success 200 {object} response.Response{data=model.Building}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id} [put]
+func (h *BuildingHandler) UpdateBuilding(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	req := new(model.UpdateBuildingRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	input := service.UpdateBuildingInput{
+		SocietyName:  req.SocietyName,
+		AddressLine2: req.AddressLine2,
+		Locality:     req.Locality,
+		TotalFloors:  req.TotalFloors,
+	}
+	if req.Name != "" {
+		input.Name = &req.Name
+	}
+	if req.AddressLine1 != "" {
+		input.AddressLine1 = &req.AddressLine1
+	}
+	if req.City != "" {
+		input.City = &req.City
+	}
+	if req.State != "" {
+		input.State = &req.State
+	}
+	if req.Pincode != "" {
+		input.Pincode = &req.Pincode
+	}
+
+	building, err := h.buildingService.Update(c.Request().Context(), middleware.CurrentUser(c), id, input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, building)
+}
+
+// DeleteBuilding godoc
+// @Summary Delete a building
+// @Description Delete a building that no longer has any units (owner only)
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /buildings/{id} [delete]
+func (h *BuildingHandler) DeleteBuilding(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	if err := h.buildingService.Delete(c.Request().Context(), middleware.CurrentUser(c), id); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
+
+// AssignManager godoc
+// @Summary Assign a building manager
+// @Description Let a property manager manage the building; a tenant who rents nothing becomes a manager, and units added afterwards inherit the manager (owner only)
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param member body model.PropertyMemberRequest true "Manager user ID"
+// @Success 200 {object} response.Response{data=model.Building}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/manager [put]
+func (h *BuildingHandler) AssignManager(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	req := new(model.PropertyMemberRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	building, err := h.buildingService.AssignManager(c.Request().Context(), middleware.CurrentUser(c), id, uuid.MustParse(req.UserID))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, building)
+}
+
+// RemoveManager godoc
+// @Summary Remove the building manager
+// @Description Revoke the manager's access to a building (owner only)
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Success 200 {object} response.Response{data=model.Building}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/manager [delete]
+func (h *BuildingHandler) RemoveManager(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	building, err := h.buildingService.RemoveManager(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, building)
+}
+
+// ListUnits godoc
+// @Summary List units in a building
+// @Description Get a paginated list of the rentable units in a building
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=ListPropertiesResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/units [get]
+func (h *BuildingHandler) ListUnits(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	limit, offset := paginationParams(c)
+
+	units, total, err := h.buildingService.ListUnits(c.Request().Context(), middleware.CurrentUser(c), id, limit, offset)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, ListPropertiesResponse{
+		Properties: units,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	})
+}
+
+// CreateUnit godoc
+// @Summary Add a unit to a building
+// @Description Create a rentable unit that shares the building's address, owner and manager
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param unit body model.CreateUnitRequest true "Unit details"
+// @Success 201 {object} response.Response{data=model.Property}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /buildings/{id}/units [post]
+func (h *BuildingHandler) CreateUnit(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	req := new(model.CreateUnitRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	unit, err := h.buildingService.AddUnit(c.Request().Context(), middleware.CurrentUser(c), id, service.CreateUnitInput{
+		UnitNumber:      req.UnitNumber,
+		Name:            req.Name,
+		PropertyType:    req.PropertyType,
+		BHK:             req.BHK,
+		CarpetAreaSqft:  req.CarpetAreaSqft,
+		BuiltUpAreaSqft: req.BuiltUpAreaSqft,
+		Furnishing:      req.Furnishing,
+		Floor:           req.Floor,
+		Parking:         req.Parking,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, unit)
+}
+
+// GetOccupancy godoc
+// @Summary Get building occupancy
+// @Description Count occupied and vacant units in a building
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Success 200 {object} response.Response{data=model.BuildingOccupancy}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/occupancy [get]
+func (h *BuildingHandler) GetOccupancy(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	occupancy, err := h.buildingService.Occupancy(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, occupancy)
+}
+
+// AddCharge godoc
+// @Summary Add a shared charge
+// @Description Add a recurring society or maintenance charge levied on every unit of the building
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param charge body model.CreateBuildingChargeRequest true "Charge details"
+// @Success 201 {object} response.Response{data=model.BuildingCharge}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/charges [post]
+func (h *BuildingHandler) AddCharge(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	req := new(model.CreateBuildingChargeRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	charge, err := h.buildingService.AddCharge(c.Request().Context(), middleware.CurrentUser(c), id, service.CreateBuildingChargeInput{
+		Name:        req.Name,
+		AmountPaise: req.AmountPaise,
+		Frequency:   req.Frequency,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, charge)
+}
+
+// RemoveCharge godoc
+// @Summary Remove a shared charge
+// @Description Remove a society or maintenance charge from the building
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param chargeId path string true "Charge ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/charges/{chargeId} [delete]
+func (h *BuildingHandler) RemoveCharge(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	chargeID, err := uuid.Parse(c.Param("chargeId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid charge ID format", nil)
+	}
+
+	if err := h.buildingService.RemoveCharge(c.Request().Context(), middleware.CurrentUser(c), id, chargeID); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
+
+// ListDocuments godoc
+// @Summary List shared documents
+// @Description List the documents shared by all units of the building
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Success 200 {object} response.Response{data=[]model.BuildingDocument}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/documents [get]
+func (h *BuildingHandler) ListDocuments(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	documents, err := h.buildingService.ListDocuments(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, documents)
+}
+
+// UploadDocument godoc
+// @Summary Upload a shared document
+// @Description Upload a PDF, JPEG or PNG shared by all units, e.g. the society NOC
+// @Tags buildings
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param title formData string true "Document title"
+// @Param document_type formData string true "Document type" Enums(society_noc, occupancy_certificate, property_tax, sale_deed, maintenance_receipt, other)
+// @Param file formData file true "Document file"
+// @Success 201 {object} response.Response{data=model.BuildingDocument}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/documents [post]
+func (h *BuildingHandler) UploadDocument(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	req := new(model.UploadBuildingDocumentRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, "A file is required", nil)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return response.BadRequest(c, "Unable to read uploaded file", nil)
+	}
+	defer src.Close()
+
+	contentType, _, _ := strings.Cut(file.Header.Get(echo.HeaderContentType), ";")
+
+	document, err := h.buildingService.UploadDocument(c.Request().Context(), middleware.CurrentUser(c), id, service.UploadBuildingDocumentInput{
+		Title:        req.Title,
+		DocumentType: req.DocumentType,
+		FileName:     file.Filename,
+		ContentType:  strings.TrimSpace(contentType),
+		Size:         file.Size,
+		Content:      src,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, document)
+}
+
+// DownloadDocument godoc
+// @Summary Download a shared document
+// @Description Download the file of a building document
+// @Tags buildings
+// @Produce application/octet-stream
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param documentId path string true "Document ID"
+// @Success 200 {file} binary
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/documents/{documentId} [get]
+func (h *BuildingHandler) DownloadDocument(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	documentID, err := uuid.Parse(c.Param("documentId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid document ID format", nil)
+	}
+
+	document, content, err := h.buildingService.OpenDocument(c.Request().Context(), middleware.CurrentUser(c), id, documentID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+	defer content.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+	c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(document.SizeBytes, 10))
+	return c.Stream(http.StatusOK, document.ContentType, content)
+}
+
+// DeleteDocument godoc
+// @Summary Delete a shared document
+// @Description Delete a building document and its file
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Building ID"
+// @Param documentId path string true "Document ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /buildings/{id}/documents/{documentId} [delete]
+func (h *BuildingHandler) DeleteDocument(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid building ID format", nil)
+	}
+
+	documentID, err := uuid.Parse(c.Param("documentId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid document ID format", nil)
+	}
+
+	if err := h.buildingService.DeleteDocument(c.Request().Context(), middleware.CurrentUser(c), id, documentID); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"slices"
+
+	"backend/internal/clausetext"
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ClauseHandler struct {
+	clauseService service.ClauseService
+}
+
+func NewClauseHandler(clauseService service.ClauseService) *ClauseHandler {
+	return &ClauseHandler{clauseService: clauseService}
+}
+
+type ListClausesResponse struct {
+	Clauses []model.Clause `json:"clauses"`
+	Total   int64          `json:"total"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+}
+
+// ListClauses godoc
+// @Summary List clauses
+// @Description List the standard clause library plus the current user's custom clauses, with their current wording
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category query string false "Category" Enums(rent, deposit, maintenance, pets, subletting, lock_in, termination)
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=ListClausesResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Router /clauses [get]
+func (h *ClauseHandler) ListClauses(c echo.Context) error {
+	category := c.QueryParam("category")
+	if category != "" && !slices.Contains(model.ClauseCategories, category) {
+		return response.BadRequest(c, "Invalid clause category", nil)
+	}
+
+	limit, offset := paginationParams(c)
+
+	clauses, total, err := h.clauseService.List(c.Request().Context(), middleware.CurrentUser(c), category, limit, offset)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, ListClausesResponse{
+		Clauses: clauses,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// CreateClause godoc
+// @Summary Create a clause
+// @Description Add a custom clause to the current user's library (admins add standard clauses)
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param clause body model.CreateClauseRequest true "Clause details"
+// @Success 201 {object} response.Response{data=model.Clause}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /clauses [post]
+func (h *ClauseHandler) CreateClause(c echo.Context) error {
+	req := new(model.CreateClauseRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	clause, err := h.clauseService.Create(c.Request().Context(), middleware.CurrentUser(c), service.CreateClauseInput{
+		Category:    req.Category,
+		Title:       req.Title,
+		Body:        req.Body,
+		IsMandatory: req.IsMandatory,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, clause)
+}
+
+// GetClause godoc
+// @Summary Get a clause by ID
+// @Description Get a clause with its current wording
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clause ID"
+// @Success 200 {object} response.Response{data=model.Clause}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /clauses/{id} [get]
+func (h *ClauseHandler) GetClause(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid clause ID format", nil)
+	}
+
+	clause, err := h.clauseService.GetByID(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, clause)
+}
+
+// UpdateClause godoc
+// @Summary Update a clause
+// @Description Update a clause; a changed body is saved as a new version and existing leases keep their wording
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clause ID"
+// @Param clause body model.UpdateClauseRequest true "Clause update details"
+// @Success 200 {object} response.Response{data=model.Clause}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /clauses/{id} [put]
+func (h *ClauseHandler) UpdateClause(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid clause ID format", nil)
+	}
+
+	req := new(model.UpdateClauseRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	input := service.UpdateClauseInput{
+		IsMandatory: req.IsMandatory,
+	}
+	if req.Category != "" {
+		input.Category = &req.Category
+	}
+	if req.Title != "" {
+		input.Title = &req.Title
+	}
+	if req.Body != "" {
+		input.Body = &req.Body
+	}
+
+	clause, err := h.clauseService.Update(c.Request().Context(), middleware.CurrentUser(c), id, input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, clause)
+}
+
+// DeleteClause godoc
+// @Summary Archive a clause
+// @Description Remove a clause from the library; leases that already use it are unaffected
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clause ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /clauses/{id} [delete]
+func (h *ClauseHandler) DeleteClause(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid clause ID format", nil)
+	}
+
+	if err := h.clauseService.Archive(c.Request().Context(), middleware.CurrentUser(c), id); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
+
+// ListClauseVersions godoc
+// @Summary List clause versions
+// @Description List every version of a clause's wording, newest first
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clause ID"
+// @Success 200 {object} response.Response{data=[]model.ClauseVersion}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /clauses/{id}/versions [get]
+func (h *ClauseHandler) ListClauseVersions(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid clause ID format", nil)
+	}
+
+	versions, err := h.clauseService.ListVersions(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, versions)
+}
+
+// ListClauseVariables godoc
+// @Summary List clause variables
+// @Description List the variables clause text can use as {{name}} or {{name|filter}}, e.g. {{rent|words}} or {{due_day|ordinal}}
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]clausetext.Variable}
+// @Router /clauses/variables [get]
+func (h *ClauseHandler) ListClauseVariables(c echo.Context) error {
+	return response.Success(c, clausetext.Variables())
+}
+
+// TranslateClause godoc
+// @Summary Translate a clause
+// @Description Set the wording of the clause's current version in another language. Each call adds a new revision of the translation; earlier revisions are kept, and lease versions keep the revision they were drafted with. The text may use the same variables as the English wording. Leases with a clause that has no translation render it in English.
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clause ID"
+// @Param lang path string true "Language" Enums(hi, mr, kn, ta)
+// @Param translation body model.TranslateClauseRequest true "Translated title and text"
+// @Success 200 {object} response.Response{data=model.ClauseTranslation}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /clauses/{id}/translations/{lang} [put]
+func (h *ClauseHandler) TranslateClause(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid clause ID format", nil)
+	}
+
+	req := new(model.TranslateClauseRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	translation, err := h.clauseService.Translate(c.Request().Context(), middleware.CurrentUser(c), id, c.Param("lang"), service.TranslateClauseInput{
+		Title: req.Title,
+		Body:  req.Body,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, translation)
+}
+
+// ListClauseTranslations godoc
+// @Summary List clause translations
+// @Description List every revision of the translations of every version of a clause, newest version first and newest revision first. The latest revision in a language is current unless it is withdrawn.
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clause ID"
+// @Success 200 {object} response.Response{data=[]model.ClauseTranslation}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /clauses/{id}/translations [get]
+func (h *ClauseHandler) ListClauseTranslations(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid clause ID format", nil)
+	}
+
+	translations, err := h.clauseService.ListTranslations(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, translations)
+}
+
+// DeleteClauseTranslation godoc
+// @Summary Delete a clause translation
+// @Description Withdraw the translation of the clause's current version by adding a withdrawn revision; leases drafted from then on render the clause in English
+// @Tags clauses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clause ID"
+// @Param lang path string true "Language" Enums(hi, mr, kn, ta)
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /clauses/{id}/translations/{lang} [delete]
+func (h *ClauseHandler) DeleteClauseTranslation(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid clause ID format", nil)
+	}
+
+	if err := h.clauseService.DeleteTranslation(c.Request().Context(), middleware.CurrentUser(c), id, c.Param("lang")); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
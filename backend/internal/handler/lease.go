@@ -0,0 +1,1473 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/india"
+	"backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const dateLayout = "2006-01-02"
+
+type LeaseHandler struct {
+	leaseService service.LeaseService
+}
+
+func NewLeaseHandler(leaseService service.LeaseService) *LeaseHandler {
+	return &LeaseHandler{leaseService: leaseService}
+}
+
+type ListLeasesResponse struct {
+	Leases []model.Lease `json:"leases"`
+	Total  int64         `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+// ListLeases godoc
+// @Summary List leases
+// @Description Get a paginated list of leases on properties the current user owns, co-owns or manages, and leases they are a tenant on (all leases for admins)
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=ListLeasesResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Router /leases [get]
+func (h *LeaseHandler) ListLeases(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	leases, total, err := h.leaseService.List(c.Request().Context(), middleware.CurrentUser(c), limit, offset)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, ListLeasesResponse{
+		Leases: leases,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// CreateLease godoc
+// @Summary Draft a lease
+// @Description Draft a lease for a property; the mandatory standard clauses are attached automatically. Amounts are in paise. The lease is checked against the tenancy law of the property's state: error findings are refused and warnings are returned in compliance.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param lease body model.CreateLeaseRequest true "Lease terms"
+// @Success 201 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases [post]
+func (h *LeaseHandler) CreateLease(c echo.Context) error {
+	req := new(model.CreateLeaseRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	startDate, _ := time.Parse(dateLayout, req.StartDate)
+
+	lease, err := h.leaseService.Create(c.Request().Context(), middleware.CurrentUser(c), service.CreateLeaseInput{
+		PropertyID:           uuid.MustParse(req.PropertyID),
+		StartDate:            startDate,
+		TermMonths:           req.TermMonths,
+		MonthlyRentPaise:     req.MonthlyRentPaise,
+		SecurityDepositPaise: req.SecurityDepositPaise,
+		MaintenancePaise:     req.MaintenancePaise,
+		RentDueDay:           req.RentDueDay,
+		RentCycleMonths:      req.RentCycleMonths,
+		NoticePeriodDays:     req.NoticePeriodDays,
+		LockInMonths:         req.LockInMonths,
+		PremisesUse:          req.PremisesUse,
+		// Rent escalation on renewal
+		EscalationType:        req.EscalationType,
+		EscalationBasisPoints: req.EscalationBasisPoints,
+		EscalationAmountPaise: req.EscalationAmountPaise,
+		EscalationCompounding: req.EscalationCompounding,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, lease)
+}
+
+// GetLease godoc
+// @Summary Get a lease by ID
+// @Description Get lease details by ID, with any compliance findings under the tenancy law of the property's state
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id} [get]
+func (h *LeaseHandler) GetLease(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	lease, err := h.leaseService.GetByID(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, lease)
+}
+
+// UpdateLease godoc
+// @Summary Update a draft lease
+// @Description Update the terms of a draft lease. Amounts are in paise. Terms breaking the tenancy law of the property's state with error severity are refused; warnings are returned in compliance.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param lease body model.UpdateLeaseRequest true "Lease terms"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id} [put]
+func (h *LeaseHandler) UpdateLease(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	req := new(model.UpdateLeaseRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	input := service.UpdateLeaseInput{
+		TermMonths:           req.TermMonths,
+		MonthlyRentPaise:     req.MonthlyRentPaise,
+		SecurityDepositPaise: req.SecurityDepositPaise,
+		MaintenancePaise:     req.MaintenancePaise,
+		RentDueDay:           req.RentDueDay,
+		RentCycleMonths:      req.RentCycleMonths,
+		NoticePeriodDays:     req.NoticePeriodDays,
+		LockInMonths:         req.LockInMonths,
+		PremisesUse:          req.PremisesUse,
+		// Rent escalation on renewal
+		EscalationType:        req.EscalationType,
+		EscalationBasisPoints: req.EscalationBasisPoints,
+		EscalationAmountPaise: req.EscalationAmountPaise,
+		EscalationCompounding: req.EscalationCompounding,
+	}
+	if req.StartDate != "" {
+		startDate, _ := time.Parse(dateLayout, req.StartDate)
+		input.StartDate = &startDate
+	}
+
+	lease, err := h.leaseService.Update(c.Request().Context(), middleware.CurrentUser(c), id, input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, lease)
+}
+
+// DeleteLease godoc
+// @Summary Delete a draft lease
+// @Description Delete a lease that is still a draft
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id} [delete]
+func (h *LeaseHandler) DeleteLease(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	if err := h.leaseService.Delete(c.Request().Context(), middleware.CurrentUser(c), id); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
+
+// ListLeaseClauses godoc
+// @Summary List lease clauses
+// @Description List the clauses of a lease in order, with the exact wording pinned to the lease
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=[]model.LeaseClause}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/clauses [get]
+func (h *LeaseHandler) ListLeaseClauses(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	clauses, err := h.leaseService.ListClauses(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, clauses)
+}
+
+// SetLeaseClauses godoc
+// @Summary Set lease clauses
+// @Description Replace the clauses of a draft lease with an ordered list. Mandatory clauses must be included; the current wording of each clause is pinned to the lease.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param clauses body model.SetLeaseClausesRequest true "Ordered clause IDs"
+// @Success 200 {object} response.Response{data=[]model.LeaseClause}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/clauses [put]
+func (h *LeaseHandler) SetLeaseClauses(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	req := new(model.SetLeaseClausesRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	clauseIDs := make([]uuid.UUID, 0, len(req.ClauseIDs))
+	for _, clauseID := range req.ClauseIDs {
+		clauseIDs = append(clauseIDs, uuid.MustParse(clauseID))
+	}
+
+	clauses, err := h.leaseService.SetClauses(c.Request().Context(), middleware.CurrentUser(c), id, clauseIDs)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, clauses)
+}
+
+// PreviewLease godoc
+// @Summary Preview lease clauses
+// @Description Render the lease clauses in order with the lease terms filled in. With lang, clauses translated into that language also carry their translation; the others are English only. Fails listing the variables that have no value yet.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param lang query string false "Language of the translations" Enums(en, hi, mr, kn, ta) default(en)
+// @Success 200 {object} response.Response{data=[]model.RenderedClause}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/preview [get]
+func (h *LeaseHandler) PreviewLease(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	lang := c.QueryParam("lang")
+	if lang != "" && !india.IsLanguageCode(lang) {
+		return response.BadRequest(c, "Unsupported language", nil)
+	}
+
+	clauses, err := h.leaseService.Preview(c.Request().Context(), middleware.CurrentUser(c), id, lang)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, clauses)
+}
+
+// DownloadLeasePDF godoc
+// @Summary Download the lease PDF
+// @Description Render the lease as a printable agreement with party details, key terms, clauses, schedule of property and signature blocks. Page one starts below a blank band for the stamp of non-judicial stamp paper; every page carries initials boxes, "Page X of Y" and a QR code linking to the public verification endpoint. Each printout with a given stamp_margin_mm has its own verification code; a printout is superseded when the lease changes, not when it is printed with another margin. Once signed online, the signed version with the audit certificate of the signing is returned exactly as first generated and stamp_margin_mm is ignored. With lang, clauses translated into that language are printed in it (falling back to English for the others), or beside the English wording when bilingual is set; the rest of the agreement stays in English. The translations printed are those kept with the lease's latest version, so later edits to a clause's translation do not change the copy of a signed lease. Translated copies are for reference and carry no verification code.
+// @Tags leases
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param stamp_margin_mm query int false "Blank space at the top of page one in millimetres (defaults to the server setting)"
+// @Param lang query string false "Language of the clauses" Enums(en, hi, mr, kn, ta) default(en)
+// @Param bilingual query bool false "Print the translated clauses beside the English wording"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/pdf [get]
+func (h *LeaseHandler) DownloadLeasePDF(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	var input service.LeasePDFInput
+	if value := c.QueryParam("stamp_margin_mm"); value != "" {
+		margin, err := strconv.Atoi(value)
+		if err != nil || margin < 0 || margin > 200 {
+			return response.BadRequest(c, "stamp_margin_mm must be between 0 and 200", nil)
+		}
+		input.StampMarginMM = &margin
+	}
+	input.Lang = c.QueryParam("lang")
+	if input.Lang != "" && !india.IsLanguageCode(input.Lang) {
+		return response.BadRequest(c, "Unsupported language", nil)
+	}
+	if value := c.QueryParam("bilingual"); value != "" {
+		if input.Bilingual, err = strconv.ParseBool(value); err != nil {
+			return response.BadRequest(c, "bilingual must be true or false", nil)
+		}
+	}
+	if input.Bilingual && (input.Lang == "" || input.Lang == india.English) {
+		return response.BadRequest(c, "A bilingual copy needs a lang other than en", nil)
+	}
+
+	content, err := h.leaseService.PDF(c.Request().Context(), middleware.CurrentUser(c), id, input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	filename := fmt.Sprintf("lease-%s.pdf", id)
+	if input.Lang != "" && input.Lang != india.English {
+		filename = fmt.Sprintf("lease-%s-%s.pdf", id, input.Lang)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"%s\"", filename))
+	return c.Blob(http.StatusOK, "application/pdf", content)
+}
+
+// DownloadReceiptPDF godoc
+// @Summary Download a rent receipt
+// @Description Render the receipt of a payment on the lease's ledger, with a QR code linking to the public verification endpoint. The receipt is recorded when first downloaded and returned exactly as first generated from then on; once the payment is reversed its code verifies as superseded.
+// @Tags ledger
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param entryId path string true "Payment entry ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/ledger/entries/{entryId}/receipt [get]
+func (h *LeaseHandler) DownloadReceiptPDF(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	entryID, err := uuid.Parse(c.Param("entryId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid entry ID format", nil)
+	}
+
+	content, err := h.leaseService.ReceiptPDF(c.Request().Context(), middleware.CurrentUser(c), id, entryID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"receipt-%s.pdf\"", entryID))
+	return c.Blob(http.StatusOK, "application/pdf", content)
+}
+
+// GetLeaseStampDuty godoc
+// @Summary Estimate stamp duty
+// @Description Calculate the stamp duty, registration fee and recommended stamp paper for the lease from the rules of the property's state. Amounts are in paise.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=stampduty.Estimate}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/stamp-duty [get]
+func (h *LeaseHandler) GetLeaseStampDuty(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	estimate, err := h.leaseService.StampDuty(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, estimate)
+}
+
+// AttachEStamp godoc
+// @Summary Attach the e-stamp certificate
+// @Description Attach the e-stamp certificate and its scan (PDF, JPEG or PNG) to a draft lease, replacing any earlier one. The certificate is verified with the e-stamp provider and must be issued in the property's state, within the last six months, for at least the stamp duty. A lease cannot be sent for signatures without one.
+// @Tags leases
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param certificate_number formData string true "Certificate number, e.g. IN-MH12345678901234X"
+// @Param state formData string true "State code the certificate was issued in"
+// @Param denomination_paise formData int true "Stamp duty paid, in paise"
+// @Param purchaser formData string true "Name of the purchaser on the certificate"
+// @Param issue_date formData string true "Issue date (YYYY-MM-DD)"
+// @Param file formData file true "Scanned certificate"
+// @Success 201 {object} response.Response{data=model.EStamp}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/estamp [post]
+func (h *LeaseHandler) AttachEStamp(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	req := new(model.AttachEStampRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, "A scan of the certificate is required", nil)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return response.BadRequest(c, "Unable to read uploaded file", nil)
+	}
+	defer src.Close()
+
+	contentType, _, _ := strings.Cut(file.Header.Get(echo.HeaderContentType), ";")
+	issueDate, _ := time.Parse(dateLayout, req.IssueDate)
+
+	stamp, err := h.leaseService.AttachEStamp(c.Request().Context(), middleware.CurrentUser(c), id, service.AttachEStampInput{
+		CertificateNumber: req.CertificateNumber,
+		State:             req.State,
+		DenominationPaise: req.DenominationPaise,
+		Purchaser:         req.Purchaser,
+		IssueDate:         issueDate,
+		FileName:          file.Filename,
+		ContentType:       strings.TrimSpace(contentType),
+		Size:              file.Size,
+		Content:           src,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, stamp)
+}
+
+// GetEStamp godoc
+// @Summary Get the e-stamp certificate
+// @Description Get the details of the e-stamp certificate attached to a lease
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=model.EStamp}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/estamp [get]
+func (h *LeaseHandler) GetEStamp(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	stamp, err := h.leaseService.GetEStamp(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, stamp)
+}
+
+// DownloadEStamp godoc
+// @Summary Download the e-stamp scan
+// @Description Download the scanned e-stamp certificate of a lease
+// @Tags leases
+// @Produce application/octet-stream
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {file} file
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/estamp/file [get]
+func (h *LeaseHandler) DownloadEStamp(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	stamp, content, err := h.leaseService.OpenEStamp(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+	defer content.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": stamp.FileName}))
+	c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(stamp.SizeBytes, 10))
+	return c.Stream(http.StatusOK, stamp.ContentType, content)
+}
+
+// RemoveEStamp godoc
+// @Summary Remove the e-stamp certificate
+// @Description Detach the e-stamp certificate and delete its scan from a draft lease
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/estamp [delete]
+func (h *LeaseHandler) RemoveEStamp(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	if err := h.leaseService.RemoveEStamp(c.Request().Context(), middleware.CurrentUser(c), id); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
+
+// ListLeaseVersions godoc
+// @Summary List lease versions
+// @Description List the versions of a lease, oldest first. A version is recorded on every change to the terms, clauses or tenants of a draft. Clause text is left out; get a single version for it.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=[]model.LeaseVersion}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/versions [get]
+func (h *LeaseHandler) ListLeaseVersions(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	versions, err := h.leaseService.ListVersions(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, versions)
+}
+
+// GetLeaseVersion godoc
+// @Summary Get a lease version
+// @Description Get a version of a lease with its terms, tenants and the text of each clause. Variables that had no value yet are left as {{name}}.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param number path int true "Version number"
+// @Success 200 {object} response.Response{data=model.LeaseVersion}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/versions/{number} [get]
+func (h *LeaseHandler) GetLeaseVersion(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		return response.BadRequest(c, "Invalid version number", nil)
+	}
+
+	version, err := h.leaseService.GetVersion(c.Request().Context(), middleware.CurrentUser(c), id, number)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, version)
+}
+
+// DiffLeaseVersions godoc
+// @Summary Compare lease versions
+// @Description Compare two versions of a lease for a redline: the terms that changed, tenants added or removed, and every clause in reading order marked added, removed, changed, moved or unchanged. Each clause's text is given as runs marked equal, insert or delete, compared word by word.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param from query int true "Older version number"
+// @Param to query int true "Newer version number"
+// @Success 200 {object} response.Response{data=model.LeaseVersionDiff}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/versions/diff [get]
+func (h *LeaseHandler) DiffLeaseVersions(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	from, err := strconv.Atoi(c.QueryParam("from"))
+	if err != nil || from < 1 {
+		return response.BadRequest(c, "from must be a version number", nil)
+	}
+	to, err := strconv.Atoi(c.QueryParam("to"))
+	if err != nil || to < 1 {
+		return response.BadRequest(c, "to must be a version number", nil)
+	}
+
+	diff, err := h.leaseService.DiffVersions(c.Request().Context(), middleware.CurrentUser(c), id, from, to)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, diff)
+}
+
+// StartLeaseSigning godoc
+// @Summary Collect signatures online
+// @Description Render the latest version of the lease as it will be signed, store its SHA-256 and send each party a signing link by SMS: owners, then tenants, then up to two witnesses. With sequential set, each party is sent their link only after the previous one has signed. The lease is marked signed when the last party signs. The round is bound to that version; pass version to refuse to start if the lease has changed since it was reviewed.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param request body model.StartSigningRequest true "Signing options"
+// @Success 201 {object} response.Response{data=model.LeaseSigning}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/signing [post]
+func (h *LeaseHandler) StartLeaseSigning(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	req := new(model.StartSigningRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	input := service.StartSigningInput{Sequential: req.Sequential, Version: req.Version}
+	for _, w := range req.Witnesses {
+		input.Witnesses = append(input.Witnesses, service.WitnessInput{Name: w.Name, Phone: w.Phone})
+	}
+
+	signing, err := h.leaseService.StartSigning(c.Request().Context(), middleware.CurrentUser(c), id, input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, signing)
+}
+
+// GetLeaseSigning godoc
+// @Summary Get signing progress
+// @Description Get the latest signing round of a lease with each signer's status and the full audit trail
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=model.LeaseSigning}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/signing [get]
+func (h *LeaseHandler) GetLeaseSigning(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	signing, err := h.leaseService.GetSigning(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, signing)
+}
+
+// DownloadLeaseSigningDocument godoc
+// @Summary Download the document under signature
+// @Description Download the lease exactly as it was presented for signature in the latest signing round. Its SHA-256 matches document_sha256.
+// @Tags leases
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {file} file
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/signing/document [get]
+func (h *LeaseHandler) DownloadLeaseSigningDocument(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	signing, content, err := h.leaseService.OpenSigningDocument(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+	defer content.Close()
+
+	return streamSigningDocument(c, signing, content)
+}
+
+// ResendSigningLink godoc
+// @Summary Resend a signing link
+// @Description Send a pending signer a new signing link, replacing the earlier one. In sequential signing only the party whose turn it is can be sent a link.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param signerId path string true "Signer ID"
+// @Success 200 {object} response.Response{data=model.LeaseSigner}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/signing/signers/{signerId}/resend [post]
+func (h *LeaseHandler) ResendSigningLink(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	signerID, err := uuid.Parse(c.Param("signerId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid signer ID format", nil)
+	}
+
+	signer, err := h.leaseService.ResendSigningLink(c.Request().Context(), middleware.CurrentUser(c), id, signerID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, signer)
+}
+
+// DownloadSignedDocument godoc
+// @Summary Download a signer's eSigned copy
+// @Description Download the document under signature with the signer's Aadhaar eSign embedded, from the latest signing round. The original document is unchanged at the start of the file.
+// @Tags leases
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param signerId path string true "Signer ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/signing/signers/{signerId}/document [get]
+func (h *LeaseHandler) DownloadSignedDocument(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	signerID, err := uuid.Parse(c.Param("signerId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid signer ID format", nil)
+	}
+
+	signer, content, err := h.leaseService.OpenSignedDocument(c.Request().Context(), middleware.CurrentUser(c), id, signerID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+	defer content.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"lease-%s-esigned-%s.pdf\"", id, signer.ID))
+	return c.Stream(http.StatusOK, "application/pdf", content)
+}
+
+// AddLeaseTenant godoc
+// @Summary Add a tenant
+// @Description Add a user with the tenant role as a party to a draft lease
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param tenant body model.LeaseTenantRequest true "Tenant user ID"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/tenants [post]
+func (h *LeaseHandler) AddLeaseTenant(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	req := new(model.LeaseTenantRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	lease, err := h.leaseService.AddTenant(c.Request().Context(), middleware.CurrentUser(c), id, uuid.MustParse(req.UserID))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, lease)
+}
+
+// RemoveLeaseTenant godoc
+// @Summary Remove a tenant
+// @Description Remove a tenant from a draft lease
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param userId path string true "Tenant user ID"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/tenants/{userId} [delete]
+func (h *LeaseHandler) RemoveLeaseTenant(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	lease, err := h.leaseService.RemoveTenant(c.Request().Context(), middleware.CurrentUser(c), id, userID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, lease)
+}
+
+// ListTenantVerifications godoc
+// @Summary List tenant police verifications
+// @Description List the police verification records of a lease's tenants. A tenant sees only their own.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=[]model.TenantVerification}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/verifications [get]
+func (h *LeaseHandler) ListTenantVerifications(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	verifications, err := h.leaseService.ListVerifications(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, verifications)
+}
+
+// GetTenantVerification godoc
+// @Summary Get a tenant's police verification
+// @Description Get the police verification record of one of the lease's tenants. Tenants can see their own; owners, co-owners and managers can see every tenant's.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param userId path string true "Tenant's user ID"
+// @Success 200 {object} response.Response{data=model.TenantVerification}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/verifications/{userId} [get]
+func (h *LeaseHandler) GetTenantVerification(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	verification, err := h.leaseService.GetVerification(c.Request().Context(), middleware.CurrentUser(c), id, userID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, verification)
+}
+
+// SaveTenantVerification godoc
+// @Summary Save a tenant's police verification details
+// @Description Save the details the police ask for to verify a tenant: father's name, date of birth, permanent and previous address, identity proof and workplace. They can be changed until the verification is submitted. Aadhaar numbers are checked and only the last four digits are kept; PAN, passport and voter ID numbers must be well formed.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param userId path string true "Tenant's user ID"
+// @Param request body model.SaveTenantVerificationRequest true "Verification details"
+// @Success 200 {object} response.Response{data=model.TenantVerification}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/verifications/{userId} [put]
+func (h *LeaseHandler) SaveTenantVerification(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	req := new(model.SaveTenantVerificationRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	dateOfBirth, _ := time.Parse(dateLayout, req.DateOfBirth)
+	input := service.SaveVerificationInput{
+		FatherName:       req.FatherName,
+		DateOfBirth:      dateOfBirth,
+		PermanentAddress: req.PermanentAddress.Address(),
+		IDProofType:      req.IDProofType,
+		IDProofNumber:    req.IDProofNumber,
+		Occupation:       req.Occupation,
+		Workplace:        req.Workplace,
+		WorkplaceAddress: req.WorkplaceAddress,
+	}
+	if req.PreviousAddress != nil {
+		input.PreviousAddress = req.PreviousAddress.Address()
+	}
+
+	verification, err := h.leaseService.SaveVerification(c.Request().Context(), middleware.CurrentUser(c), id, userID, input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, verification)
+}
+
+// UploadVerificationPhoto godoc
+// @Summary Upload a tenant's photograph
+// @Description Upload the passport size photograph (JPEG or PNG) printed on the tenant's police verification form, replacing any earlier one. The details must be saved first.
+// @Tags leases
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param userId path string true "Tenant's user ID"
+// @Param file formData file true "Photograph"
+// @Success 200 {object} response.Response{data=model.TenantVerification}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/verifications/{userId}/photo [put]
+func (h *LeaseHandler) UploadVerificationPhoto(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, "A photograph is required", nil)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return response.BadRequest(c, "Unable to read uploaded file", nil)
+	}
+	defer src.Close()
+
+	contentType, _, _ := strings.Cut(file.Header.Get(echo.HeaderContentType), ";")
+
+	verification, err := h.leaseService.UploadVerificationPhoto(c.Request().Context(), middleware.CurrentUser(c), id, userID, service.VerificationPhotoInput{
+		ContentType: strings.TrimSpace(contentType),
+		Size:        file.Size,
+		Content:     src,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, verification)
+}
+
+// DownloadVerificationPhoto godoc
+// @Summary Download a tenant's photograph
+// @Description Download the photograph uploaded for a tenant's police verification
+// @Tags leases
+// @Produce image/jpeg,image/png
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param userId path string true "Tenant's user ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/verifications/{userId}/photo [get]
+func (h *LeaseHandler) DownloadVerificationPhoto(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	verification, content, err := h.leaseService.OpenVerificationPhoto(c.Request().Context(), middleware.CurrentUser(c), id, userID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+	defer content.Close()
+
+	return c.Stream(http.StatusOK, verification.PhotoContentType, content)
+}
+
+// DownloadVerificationForm godoc
+// @Summary Download a tenant's police verification form
+// @Description Print the tenant's police verification form, pre-filled from the lease and the saved details, in the format of the state the property is in. Blank fields are left for writing in by hand, and a box to affix a photograph is printed when none has been uploaded.
+// @Tags leases
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param userId path string true "Tenant's user ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/verifications/{userId}/form [get]
+func (h *LeaseHandler) DownloadVerificationForm(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	content, err := h.leaseService.VerificationForm(c.Request().Context(), middleware.CurrentUser(c), id, userID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"police-verification-%s.pdf\"", userID))
+	return c.Blob(http.StatusOK, "application/pdf", content)
+}
+
+// SetVerificationStatus godoc
+// @Summary Update a tenant's police verification status
+// @Description Track a tenant's police verification. pending moves to submitted once filed, which needs the police station; submitted moves to verified, or back to pending when the police ask for corrections. A verified record is final. Only owners, co-owners and managers can update the status.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param userId path string true "Tenant's user ID"
+// @Param request body model.UpdateVerificationStatusRequest true "New status"
+// @Success 200 {object} response.Response{data=model.TenantVerification}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/verifications/{userId}/status [put]
+func (h *LeaseHandler) SetVerificationStatus(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	req := new(model.UpdateVerificationStatusRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	verification, err := h.leaseService.SetVerificationStatus(c.Request().Context(), middleware.CurrentUser(c), id, userID, service.SetVerificationStatusInput{
+		Status:          req.Status,
+		PoliceStation:   req.PoliceStation,
+		ReferenceNumber: req.ReferenceNumber,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, verification)
+}
+
+// ListLeaseDues godoc
+// @Summary List rent dues
+// @Description List the unpaid rent of a lease: overdue items, oldest first, and upcoming items, soonest first. The schedule is generated when the lease is activated, renewed or terminated. Rent is due in advance on the rent due day for each rent cycle (monthly, quarterly or every rent_cycle_months months); periods shorter than the cycle at the start or end of the lease are pro-rated by days. credit_paise is what the tenants have paid beyond every due. next_escalation_date is when the rent goes up on renewal, if the lease provides for escalation.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=model.RentDueSchedule}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/dues [get]
+func (h *LeaseHandler) ListLeaseDues(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	schedule, err := h.leaseService.ListDues(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, schedule)
+}
+
+// ListLeaseTransitions godoc
+// @Summary Lease history
+// @Description List every status change of a lease with who made it and when, oldest first
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=[]model.LeaseTransition}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/transitions [get]
+func (h *LeaseHandler) ListLeaseTransitions(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	transitions, err := h.leaseService.ListTransitions(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, transitions)
+}
+
+// SubmitLease godoc
+// @Summary Send a lease for signatures
+// @Description Move a draft lease to pending_signatures. The lease needs at least one tenant, every clause must render, a valid e-stamp certificate must be attached and the lease must have no compliance errors.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param transition body model.LeaseTransitionRequest false "Optional note for the lease history"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/submit [post]
+func (h *LeaseHandler) SubmitLease(c echo.Context) error {
+	return h.transition(c, h.leaseService.Submit)
+}
+
+// WithdrawLease godoc
+// @Summary Withdraw a lease
+// @Description Return a lease awaiting signatures to draft so it can be revised
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param transition body model.LeaseTransitionRequest false "Optional note for the lease history"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/withdraw [post]
+func (h *LeaseHandler) WithdrawLease(c echo.Context) error {
+	return h.transition(c, h.leaseService.Withdraw)
+}
+
+// SignLease godoc
+// @Summary Mark a lease signed
+// @Description Record that all parties have signed a lease awaiting signatures on paper. Not allowed while signatures are being collected online; that round marks the lease signed itself.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param transition body model.LeaseTransitionRequest false "Optional note for the lease history"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/sign [post]
+func (h *LeaseHandler) SignLease(c echo.Context) error {
+	return h.transition(c, h.leaseService.MarkSigned)
+}
+
+// ActivateLease godoc
+// @Summary Activate a lease
+// @Description Start a signed lease on or after its start date; the property is marked occupied
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param transition body model.LeaseTransitionRequest false "Optional note for the lease history"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/activate [post]
+func (h *LeaseHandler) ActivateLease(c echo.Context) error {
+	return h.transition(c, h.leaseService.Activate)
+}
+
+// GiveLeaseNotice godoc
+// @Summary Serve notice
+// @Description Start the notice period of an active lease. Either the owner side or a tenant may serve notice; the vacate-by date is set from the notice period.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param transition body model.LeaseTransitionRequest false "Optional note for the lease history"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/notice [post]
+func (h *LeaseHandler) GiveLeaseNotice(c echo.Context) error {
+	return h.transition(c, h.leaseService.GiveNotice)
+}
+
+// TerminateLease godoc
+// @Summary Terminate a lease
+// @Description End an active lease early; the property is marked vacant
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param transition body model.LeaseTransitionRequest false "Optional note for the lease history"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/terminate [post]
+func (h *LeaseHandler) TerminateLease(c echo.Context) error {
+	return h.transition(c, h.leaseService.Terminate)
+}
+
+// ExpireLease godoc
+// @Summary Expire a lease
+// @Description Close a lease whose end date has passed; the property is marked vacant
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param transition body model.LeaseTransitionRequest false "Optional note for the lease history"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/expire [post]
+func (h *LeaseHandler) ExpireLease(c echo.Context) error {
+	return h.transition(c, h.leaseService.Expire)
+}
+
+// DraftLeaseRenewal godoc
+// @Summary Draft a lease renewal
+// @Description Draft a lease renewing an active one from the day after it ends, with the same tenants, terms and clause wording. The rent is escalated as the lease provides and the security deposit is carried forward. Activating the renewal marks this lease renewed.
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param renewal body model.RenewLeaseRequest false "Renewal term"
+// @Success 201 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/renewal [post]
+func (h *LeaseHandler) DraftLeaseRenewal(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	req := new(model.RenewLeaseRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	renewal, err := h.leaseService.DraftRenewal(c.Request().Context(), middleware.CurrentUser(c), id, service.RenewLeaseInput{
+		TermMonths: req.TermMonths,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, renewal)
+}
+
+// GetLeaseRenewal godoc
+// @Summary Get a lease's renewal
+// @Description Get the lease renewing this one, whether drafted by hand or by the renewal job
+// @Tags leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=model.Lease}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/renewal [get]
+func (h *LeaseHandler) GetLeaseRenewal(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	renewal, err := h.leaseService.GetRenewal(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, renewal)
+}
+
+// transition parses the optional reason and applies a lifecycle event to the lease
+func (h *LeaseHandler) transition(c echo.Context, apply func(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error)) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	req := new(model.LeaseTransitionRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	lease, err := apply(c.Request().Context(), middleware.CurrentUser(c), id, req.Reason)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, lease)
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"time"
+
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type LedgerHandler struct {
+	ledgerService service.LedgerService
+}
+
+func NewLedgerHandler(ledgerService service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: ledgerService}
+}
+
+// GetLeaseLedger godoc
+// @Summary Lease ledger balance
+// @Description Get the balances of a lease's ledger: what the tenants owe (negative when they have paid in advance), the security deposit held for them, what has been collected, the balance of each account on its normal side, and what each tenant owes and holds on deposit
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=model.LedgerBalance}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/ledger [get]
+func (h *LedgerHandler) GetLeaseLedger(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	balance, err := h.ledgerService.LeaseBalance(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, balance)
+}
+
+// GetLeaseStatement godoc
+// @Summary Lease account statement
+// @Description Get the tenants' account statement for a lease: every charge, payment, refund and deposit movement in date order with the running balance. Entries before from are summed into the opening balances.
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param from query string false "First date (YYYY-MM-DD)"
+// @Param to query string false "Last date (YYYY-MM-DD)"
+// @Success 200 {object} response.Response{data=model.LedgerStatement}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/ledger/statement [get]
+func (h *LedgerHandler) GetLeaseStatement(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	period, ok := statementPeriod(c)
+	if !ok {
+		return response.BadRequest(c, "from and to must be dates (YYYY-MM-DD), from not after to", nil)
+	}
+
+	statement, err := h.ledgerService.LeaseStatement(c.Request().Context(), middleware.CurrentUser(c), id, period)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, statement)
+}
+
+// PostLedgerEntry godoc
+// @Summary Record a ledger entry
+// @Description Record money received from the tenants (payment), a late fee or maintenance charge, a refund of what they paid in advance, or a refund or application of the security deposit. tenant_id names the tenant the money is from or to, or who is charged; without it a payment is shared among the tenants by what each owes, a refund by what each has paid in advance, the deposit by what each holds, and a charge equally. Payments are applied to the oldest charges first. Rent and the security deposit are charged from the lease's terms and cannot be posted by hand.
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param request body model.PostLedgerEntryRequest true "Entry"
+// @Success 201 {object} response.Response{data=model.JournalEntry}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/ledger/entries [post]
+func (h *LedgerHandler) PostLedgerEntry(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	req := new(model.PostLedgerEntryRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	var occurredOn time.Time
+	if req.OccurredOn != "" {
+		occurredOn, _ = time.Parse(dateLayout, req.OccurredOn)
+	}
+
+	entry, err := h.ledgerService.Post(c.Request().Context(), middleware.CurrentUser(c), id, service.PostLedgerEntryInput{
+		Kind:        req.Kind,
+		AmountPaise: req.AmountPaise,
+		OccurredOn:  occurredOn,
+		Description: req.Description,
+		Reference:   req.Reference,
+		TenantID:    req.TenantID,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, entry)
+}
+
+// GetLedgerEntry godoc
+// @Summary Get a ledger entry
+// @Description Get a journal entry of a lease with its postings
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param entryId path string true "Entry ID"
+// @Success 200 {object} response.Response{data=model.JournalEntry}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/ledger/entries/{entryId} [get]
+func (h *LedgerHandler) GetLedgerEntry(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	entryID, err := uuid.Parse(c.Param("entryId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid entry ID format", nil)
+	}
+
+	entry, err := h.ledgerService.GetEntry(c.Request().Context(), middleware.CurrentUser(c), id, entryID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, entry)
+}
+
+// ReverseLedgerEntry godoc
+// @Summary Reverse a ledger entry
+// @Description Undo an entry recorded by hand by posting a reversal dated today; entries are never changed or deleted. An entry can be reversed once, and a reversal cannot be reversed.
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param entryId path string true "Entry ID"
+// @Param request body model.ReverseLedgerEntryRequest true "Reason"
+// @Success 201 {object} response.Response{data=model.JournalEntry}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/ledger/entries/{entryId}/reverse [post]
+func (h *LedgerHandler) ReverseLedgerEntry(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	entryID, err := uuid.Parse(c.Param("entryId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid entry ID format", nil)
+	}
+
+	req := new(model.ReverseLedgerEntryRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	reversal, err := h.ledgerService.Reverse(c.Request().Context(), middleware.CurrentUser(c), id, entryID, req.Reason)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, reversal)
+}
+
+// GetTenantLedger godoc
+// @Summary Tenant ledger balance
+// @Description Get what a tenant owes on their own accounts and the deposit held for them on each of their leases. Owners and managers see only the leases on their properties.
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=model.TenantLedgerBalance}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /users/{id}/ledger [get]
+func (h *LedgerHandler) GetTenantLedger(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	balance, err := h.ledgerService.TenantBalance(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, balance)
+}
+
+// GetTenantStatement godoc
+// @Summary Tenant account statement
+// @Description Get a tenant's statement of their own accounts across their leases, each line marked with its lease. Owners and managers see only the leases on their properties.
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param from query string false "First date (YYYY-MM-DD)"
+// @Param to query string false "Last date (YYYY-MM-DD)"
+// @Success 200 {object} response.Response{data=model.LedgerStatement}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /users/{id}/ledger/statement [get]
+func (h *LedgerHandler) GetTenantStatement(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+	period, ok := statementPeriod(c)
+	if !ok {
+		return response.BadRequest(c, "from and to must be dates (YYYY-MM-DD), from not after to", nil)
+	}
+
+	statement, err := h.ledgerService.TenantStatement(c.Request().Context(), middleware.CurrentUser(c), id, period)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, statement)
+}
+
+// statementPeriod reads the optional from and to query parameters
+func statementPeriod(c echo.Context) (service.StatementPeriod, bool) {
+	var period service.StatementPeriod
+	for param, date := range map[string]**time.Time{"from": &period.From, "to": &period.To} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return period, false
+		}
+		*date = &parsed
+	}
+	if period.From != nil && period.To != nil && period.From.After(*period.To) {
+		return period, false
+	}
+	return period, true
+}
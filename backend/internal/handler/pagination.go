@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// paginationParams reads limit (default 20, max 100) and offset from the query string
+func paginationParams(c echo.Context) (limit, offset int) {
+	limit, _ = strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, _ = strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
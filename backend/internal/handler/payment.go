@@ -0,0 +1,380 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultQRSizePx = 512
+	minQRSizePx     = 128
+	maxQRSizePx     = 1024
+	// maxWebhookBytes bounds a webhook delivery; gateway events are a few kilobytes
+	maxWebhookBytes = 1 << 20
+)
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+}
+
+func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// GetPayoutProfile godoc
+// @Summary Get a payout profile
+// @Description Get the UPI ID a user collects rent on
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=model.PayoutProfile}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /users/{id}/payout-profile [get]
+func (h *PaymentHandler) GetPayoutProfile(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	profile, err := h.paymentService.GetPayoutProfile(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, profile)
+}
+
+// SavePayoutProfile godoc
+// @Summary Set a payout profile
+// @Description Set the UPI ID (VPA) a user collects rent on, and the payee name their bank has registered for it. Rent payment links for the user's leases are made out to it.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body model.SavePayoutProfileRequest true "Payout profile"
+// @Success 200 {object} response.Response{data=model.PayoutProfile}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /users/{id}/payout-profile [put]
+func (h *PaymentHandler) SavePayoutProfile(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	req := new(model.SavePayoutProfileRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	profile, err := h.paymentService.SavePayoutProfile(c.Request().Context(), middleware.CurrentUser(c), id, service.SavePayoutProfileInput{
+		VPA:       req.VPA,
+		PayeeName: req.PayeeName,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, profile)
+}
+
+// RequestUPIPayment godoc
+// @Summary Generate a UPI payment link
+// @Description Generate a upi://pay link for what is outstanding on a rent due, made out to the lease owner's UPI ID with a unique transaction reference in the note, so the payment can be matched back to the due. The last link for the due is returned again while it still asks for the right amount.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param dueId path string true "Rent due ID"
+// @Success 201 {object} response.Response{data=model.UPIPaymentRequest}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/dues/{dueId}/upi [post]
+func (h *PaymentHandler) RequestUPIPayment(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	dueID, err := uuid.Parse(c.Param("dueId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid rent due ID format", nil)
+	}
+
+	request, err := h.paymentService.RequestUPIPayment(c.Request().Context(), middleware.CurrentUser(c), id, dueID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, request)
+}
+
+// ListUPIRequests godoc
+// @Summary List UPI payment links
+// @Description List the UPI payment links generated for a lease's rent dues, newest first
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=[]model.UPIPaymentRequest}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/upi-requests [get]
+func (h *PaymentHandler) ListUPIRequests(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	requests, err := h.paymentService.ListUPIRequests(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, requests)
+}
+
+// GetUPIQRCode godoc
+// @Summary UPI payment QR code
+// @Description Get the QR code of a UPI payment link, to scan with any UPI app
+// @Tags payments
+// @Produce png
+// @Produce image/svg+xml
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param requestId path string true "UPI payment request ID"
+// @Param format query string false "png or svg" default(png)
+// @Param size query int false "PNG width and height in pixels, 128 to 1024" default(512)
+// @Success 200 {file} binary
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/upi-requests/{requestId}/qr [get]
+func (h *PaymentHandler) GetUPIQRCode(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	requestID, err := uuid.Parse(c.Param("requestId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid UPI payment request ID format", nil)
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = service.QRFormatPNG
+	}
+	size := defaultQRSizePx
+	if value := c.QueryParam("size"); value != "" {
+		size, err = strconv.Atoi(value)
+		if err != nil || size < minQRSizePx || size > maxQRSizePx {
+			return response.BadRequest(c, fmt.Sprintf("size must be between %d and %d pixels", minQRSizePx, maxQRSizePx), nil)
+		}
+	}
+
+	content, contentType, err := h.paymentService.UPIQRCode(c.Request().Context(), middleware.CurrentUser(c), id, requestID, format, size)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"upi-%s.%s\"", requestID, format))
+	return c.Blob(http.StatusOK, contentType, content)
+}
+
+// CreatePaymentOrder godoc
+// @Summary Pay a rent due online
+// @Description Place an order with the payment gateway for what is outstanding on a rent due. Open the gateway's checkout with key_id and gateway_order_id, or at checkout_url where the gateway has one. The payment is credited when the gateway's webhook arrives, or earlier when the checkout's confirmation is posted to the confirm endpoint.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param dueId path string true "Rent due ID"
+// @Success 201 {object} response.Response{data=model.PaymentOrder}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/dues/{dueId}/pay [post]
+func (h *PaymentHandler) CreatePaymentOrder(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	dueID, err := uuid.Parse(c.Param("dueId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid rent due ID format", nil)
+	}
+
+	order, err := h.paymentService.CreatePaymentOrder(c.Request().Context(), middleware.CurrentUser(c), id, dueID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, order)
+}
+
+// ListPaymentOrders godoc
+// @Summary List online payments
+// @Description List the payment gateway orders placed for a lease's rent dues, newest first, with their refunds
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} response.Response{data=[]model.PaymentOrder}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/payment-orders [get]
+func (h *PaymentHandler) ListPaymentOrders(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+
+	orders, err := h.paymentService.ListPaymentOrders(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, orders)
+}
+
+// ConfirmPayment godoc
+// @Summary Confirm an online payment
+// @Description Post the payment ID and signature the gateway's checkout returned. Once the signature is verified the payment is credited, unless the webhook has already done so.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param orderId path string true "Payment order ID"
+// @Param request body model.ConfirmPaymentRequest true "Checkout confirmation"
+// @Success 200 {object} response.Response{data=model.PaymentOrder}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /leases/{id}/payment-orders/{orderId}/confirm [post]
+func (h *PaymentHandler) ConfirmPayment(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	orderID, err := uuid.Parse(c.Param("orderId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid payment order ID format", nil)
+	}
+
+	req := new(model.ConfirmPaymentRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	order, err := h.paymentService.ConfirmPayment(c.Request().Context(), middleware.CurrentUser(c), id, orderID, service.ConfirmPaymentInput{
+		PaymentID: req.PaymentID,
+		Signature: req.Signature,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, order)
+}
+
+// RefundPayment godoc
+// @Summary Refund an online payment
+// @Description Refund some or all of a paid order through the payment gateway and record the refund on the lease's ledger. The refund is stored as pending before the gateway is asked; if the gateway's answer is lost it stays pending until the gateway's webhook completes it. A refund still pending is asked for again, with the same idempotency key, and returned in place of a new one.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param orderId path string true "Payment order ID"
+// @Param request body model.RefundPaymentRequest true "Refund"
+// @Success 201 {object} response.Response{data=model.PaymentRefund}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /leases/{id}/payment-orders/{orderId}/refund [post]
+func (h *PaymentHandler) RefundPayment(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid lease ID format", nil)
+	}
+	orderID, err := uuid.Parse(c.Param("orderId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid payment order ID format", nil)
+	}
+
+	req := new(model.RefundPaymentRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	refund, err := h.paymentService.RefundPayment(c.Request().Context(), middleware.CurrentUser(c), id, orderID, service.RefundPaymentInput{
+		AmountPaise: req.AmountPaise,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, refund)
+}
+
+// GatewayWebhook godoc
+// @Summary Receive a payment gateway webhook
+// @Description Webhook of the payment gateway, signed with the webhook secret. Every delivery is stored; a repeated event ID is stored as a duplicate and changes nothing, and events that cannot be acted on are stored as ignored with the reason. Both are acknowledged, so the gateway stops retrying them.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Gateway-Signature header string true "Hex HMAC-SHA256 of the body"
+// @Param X-Gateway-Event-Id header string false "Event ID"
+// @Success 200 {object} response.Response{data=model.GatewayEvent}
+// @Failure 401 {object} response.ErrorResponse
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) GatewayWebhook(c echo.Context) error {
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBytes))
+	if err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	event, err := h.paymentService.HandleGatewayWebhook(c.Request().Context(), payload, c.Request().Header)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, event)
+}
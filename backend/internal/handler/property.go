@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type PropertyHandler struct {
+	propertyService service.PropertyService
+}
+
+func NewPropertyHandler(propertyService service.PropertyService) *PropertyHandler {
+	return &PropertyHandler{propertyService: propertyService}
+}
+
+type ListPropertiesResponse struct {
+	Properties []model.Property `json:"properties"`
+	Total      int64            `json:"total"`
+	Limit      int              `json:"limit"`
+	Offset     int              `json:"offset"`
+}
+
+// ListProperties godoc
+// @Summary List properties
+// @Description Get a paginated list of the properties the current user owns, co-owns or manages (all properties for admins)
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=ListPropertiesResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Router /properties [get]
+func (h *PropertyHandler) ListProperties(c echo.Context) error {
+	limit, offset := paginationParams(c)
+
+	properties, total, err := h.propertyService.List(c.Request().Context(), middleware.CurrentUser(c), limit, offset)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, ListPropertiesResponse{
+		Properties: properties,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	})
+}
+
+// CreateProperty godoc
+// @Summary Create a new property
+// @Description Create a property owned by the current user (owners only; admins may set owner_id)
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param property body model.CreatePropertyRequest true "Property details"
+// @Success 201 {object} response.Response{data=model.Property}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /properties [post]
+func (h *PropertyHandler) CreateProperty(c echo.Context) error {
+	req := new(model.CreatePropertyRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	input := service.CreatePropertyInput{
+		Name:            req.Name,
+		PropertyType:    req.PropertyType,
+		AddressLine1:    req.AddressLine1,
+		AddressLine2:    req.AddressLine2,
+		Locality:        req.Locality,
+		City:            req.City,
+		State:           req.State,
+		Pincode:         req.Pincode,
+		BHK:             req.BHK,
+		CarpetAreaSqft:  req.CarpetAreaSqft,
+		BuiltUpAreaSqft: req.BuiltUpAreaSqft,
+		Furnishing:      req.Furnishing,
+		Floor:           req.Floor,
+		TotalFloors:     req.TotalFloors,
+		Parking:         req.Parking,
+	}
+	if req.OwnerID != "" {
+		ownerID := uuid.MustParse(req.OwnerID)
+		input.OwnerID = &ownerID
+	}
+
+	property, err := h.propertyService.Create(c.Request().Context(), middleware.CurrentUser(c), input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Created(c, property)
+}
+
+// GetProperty godoc
+// @Summary Get a property by ID
+// @Description Get property details by ID
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Success 200 {object} response.Response{data=model.Property}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /properties/{id} [get]
+func (h *PropertyHandler) GetProperty(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid property ID format", nil)
+	}
+
+	property, err := h.propertyService.GetByID(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, property)
+}
+
+// UpdateProperty godoc
+// @Summary Update a property
+// @Description Update property details by ID (owner, co-owner or manager)
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param property body model.UpdatePropertyRequest true "Property update details"
+// @Success 200 {object} response.Response{data=model.Property}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /properties/{id} [put]
+func (h *PropertyHandler) UpdateProperty(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid property ID format", nil)
+	}
+
+	req := new(model.UpdatePropertyRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	input := service.UpdatePropertyInput{
+		AddressLine2:    req.AddressLine2,
+		Locality:        req.Locality,
+		BHK:             req.BHK,
+		CarpetAreaSqft:  req.CarpetAreaSqft,
+		BuiltUpAreaSqft: req.BuiltUpAreaSqft,
+		Floor:           req.Floor,
+		TotalFloors:     req.TotalFloors,
+	}
+	if req.Name != "" {
+		input.Name = &req.Name
+	}
+	if req.PropertyType != "" {
+		input.PropertyType = &req.PropertyType
+	}
+	if req.AddressLine1 != "" {
+		input.AddressLine1 = &req.AddressLine1
+	}
+	if req.City != "" {
+		input.City = &req.City
+	}
+	if req.State != "" {
+		input.State = &req.State
+	}
+	if req.Pincode != "" {
+		input.Pincode = &req.Pincode
+	}
+	if req.Furnishing != "" {
+		input.Furnishing = &req.Furnishing
+	}
+	if req.Parking != "" {
+		input.Parking = &req.Parking
+	}
+	if req.OccupancyStatus != "" {
+		input.OccupancyStatus = &req.OccupancyStatus
+	}
+
+	property, err := h.propertyService.Update(c.Request().Context(), middleware.CurrentUser(c), id, input)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, property)
+}
+
+// DeleteProperty godoc
+// @Summary Delete a property
+// @Description Delete a property by ID (owner only)
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /properties/{id} [delete]
+func (h *PropertyHandler) DeleteProperty(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid property ID format", nil)
+	}
+
+	if err := h.propertyService.Delete(c.Request().Context(), middleware.CurrentUser(c), id); err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.NoContent(c)
+}
+
+// AddCoOwner godoc
+// @Summary Add a co-owner
+// @Description Give another owner co-owner access to a property; a tenant who rents nothing becomes a co-owner (owner only)
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param member body model.PropertyMemberRequest true "Co-owner user ID"
+// @Success 200 {object} response.Response{data=model.Property}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /properties/{id}/co-owners [post]
+func (h *PropertyHandler) AddCoOwner(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid property ID format", nil)
+	}
+
+	req := new(model.PropertyMemberRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	property, err := h.propertyService.AddCoOwner(c.Request().Context(), middleware.CurrentUser(c), id, uuid.MustParse(req.UserID))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, property)
+}
+
+// RemoveCoOwner godoc
+// @Summary Remove a co-owner
+// @Description Revoke a co-owner's access to a property (owner only)
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param userId path string true "Co-owner user ID"
+// @Success 200 {object} response.Response{data=model.Property}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /properties/{id}/co-owners/{userId} [delete]
+func (h *PropertyHandler) RemoveCoOwner(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid property ID format", nil)
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	property, err := h.propertyService.RemoveCoOwner(c.Request().Context(), middleware.CurrentUser(c), id, userID)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, property)
+}
+
+// AssignManager godoc
+// @Summary Assign a property manager
+// @Description Let a property manager or agent manage the property; a tenant who rents nothing becomes a manager (owner only)
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param member body model.PropertyMemberRequest true "Manager user ID"
+// @Success 200 {object} response.Response{data=model.Property}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /properties/{id}/manager [put]
+func (h *PropertyHandler) AssignManager(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid property ID format", nil)
+	}
+
+	req := new(model.PropertyMemberRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	property, err := h.propertyService.AssignManager(c.Request().Context(), middleware.CurrentUser(c), id, uuid.MustParse(req.UserID))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, property)
+}
+
+// RemoveManager godoc
+// @Summary Remove the property manager
+// @Description Revoke the manager's access to a property (owner only)
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Success 200 {object} response.Response{data=model.Property}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /properties/{id}/manager [delete]
+func (h *PropertyHandler) RemoveManager(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid property ID format", nil)
+	}
+
+	property, err := h.propertyService.RemoveManager(c.Request().Context(), middleware.CurrentUser(c), id)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, property)
+}
@@ -1,30 +1,207 @@
 package handler
 
 import (
+	"backend/internal/config"
+	"backend/internal/middleware"
+	"backend/internal/model"
 	"backend/internal/service"
 
 	"github.com/labstack/echo/v4"
 )
 
 type Handlers struct {
-	User *UserHandler
+	User     *UserHandler
+	Auth     *AuthHandler
+	Property *PropertyHandler
+	Building *BuildingHandler
+	Clause   *ClauseHandler
+	Lease    *LeaseHandler
+	Signing  *SigningHandler
+	Verify   *VerifyHandler
+	Ledger   *LedgerHandler
+	Payment  *PaymentHandler
+	Bank     *BankStatementHandler
 }
 
-func NewHandlers(services *service.Services) *Handlers {
+func NewHandlers(services *service.Services, cfg *config.Config) *Handlers {
 	return &Handlers{
-		User: NewUserHandler(services.User),
+		User:     NewUserHandler(services.User),
+		Auth:     NewAuthHandler(services.Auth, services.User),
+		Property: NewPropertyHandler(services.Property),
+		Building: NewBuildingHandler(services.Building),
+		Clause:   NewClauseHandler(services.Clause),
+		Lease:    NewLeaseHandler(services.Lease),
+		Signing:  NewSigningHandler(services.Lease, cfg.ESign.ReturnURL),
+		Verify:   NewVerifyHandler(services.Lease),
+		Ledger:   NewLedgerHandler(services.Ledger),
+		Payment:  NewPaymentHandler(services.Payment),
+		Bank:     NewBankStatementHandler(services.Bank),
 	}
 }
 
-func RegisterRoutes(g *echo.Group, handlers *Handlers) {
+func RegisterRoutes(g *echo.Group, handlers *Handlers, requireAuth echo.MiddlewareFunc) {
 	g.GET("/health", HealthCheck)
 
-	users := g.Group("/users")
+	authRoutes := g.Group("/auth")
 	{
-		users.GET("", handlers.User.ListUsers)
-		users.POST("", handlers.User.CreateUser)
+		authRoutes.POST("/register", handlers.Auth.Register)
+		authRoutes.POST("/login", handlers.Auth.Login)
+		authRoutes.POST("/otp/request", handlers.Auth.RequestOTP)
+		authRoutes.POST("/otp/verify", handlers.Auth.VerifyOTP)
+		authRoutes.POST("/refresh", handlers.Auth.Refresh)
+		authRoutes.POST("/logout", handlers.Auth.Logout, requireAuth)
+		authRoutes.GET("/me", handlers.Auth.Me, requireAuth)
+		authRoutes.GET("/sessions", handlers.Auth.ListSessions, requireAuth)
+		authRoutes.DELETE("/sessions/:id", handlers.Auth.RevokeSession, requireAuth)
+	}
+
+	users := g.Group("/users", requireAuth)
+	{
+		users.GET("", handlers.User.ListUsers, middleware.RequireRole(model.RoleAdmin))
+		users.POST("", handlers.User.CreateUser, middleware.RequireRole(model.RoleAdmin))
 		users.GET("/:id", handlers.User.GetUser)
 		users.PUT("/:id", handlers.User.UpdateUser)
+		users.POST("/:id/phone/otp", handlers.User.RequestPhoneChangeOTP)
 		users.DELETE("/:id", handlers.User.DeleteUser)
+		users.GET("/:id/ledger", handlers.Ledger.GetTenantLedger)
+		users.GET("/:id/ledger/statement", handlers.Ledger.GetTenantStatement)
+		users.GET("/:id/payout-profile", handlers.Payment.GetPayoutProfile)
+		users.PUT("/:id/payout-profile", handlers.Payment.SavePayoutProfile)
+	}
+
+	properties := g.Group("/properties", requireAuth)
+	{
+		properties.GET("", handlers.Property.ListProperties)
+		properties.POST("", handlers.Property.CreateProperty)
+		properties.GET("/:id", handlers.Property.GetProperty)
+		properties.PUT("/:id", handlers.Property.UpdateProperty)
+		properties.DELETE("/:id", handlers.Property.DeleteProperty)
+		properties.POST("/:id/co-owners", handlers.Property.AddCoOwner)
+		properties.DELETE("/:id/co-owners/:userId", handlers.Property.RemoveCoOwner)
+		properties.PUT("/:id/manager", handlers.Property.AssignManager)
+		properties.DELETE("/:id/manager", handlers.Property.RemoveManager)
+	}
+
+	buildings := g.Group("/buildings", requireAuth)
+	{
+		buildings.GET("", handlers.Building.ListBuildings)
+		buildings.POST("", handlers.Building.CreateBuilding)
+		buildings.GET("/:id", handlers.Building.GetBuilding)
+		buildings.PUT("/:id", handlers.Building.UpdateBuilding)
+		buildings.DELETE("/:id", handlers.Building.DeleteBuilding)
+		buildings.PUT("/:id/manager", handlers.Building.AssignManager)
+		buildings.DELETE("/:id/manager", handlers.Building.RemoveManager)
+		buildings.GET("/:id/units", handlers.Building.ListUnits)
+		buildings.POST("/:id/units", handlers.Building.CreateUnit)
+		buildings.GET("/:id/occupancy", handlers.Building.GetOccupancy)
+		buildings.POST("/:id/charges", handlers.Building.AddCharge)
+		buildings.DELETE("/:id/charges/:chargeId", handlers.Building.RemoveCharge)
+		buildings.GET("/:id/documents", handlers.Building.ListDocuments)
+		buildings.POST("/:id/documents", handlers.Building.UploadDocument)
+		buildings.GET("/:id/documents/:documentId", handlers.Building.DownloadDocument)
+		buildings.DELETE("/:id/documents/:documentId", handlers.Building.DeleteDocument)
 	}
+
+	clauses := g.Group("/clauses", requireAuth)
+	{
+		clauses.GET("", handlers.Clause.ListClauses)
+		clauses.POST("", handlers.Clause.CreateClause)
+		clauses.GET("/variables", handlers.Clause.ListClauseVariables)
+		clauses.GET("/:id", handlers.Clause.GetClause)
+		clauses.PUT("/:id", handlers.Clause.UpdateClause)
+		clauses.DELETE("/:id", handlers.Clause.DeleteClause)
+		clauses.GET("/:id/versions", handlers.Clause.ListClauseVersions)
+		clauses.GET("/:id/translations", handlers.Clause.ListClauseTranslations)
+		clauses.PUT("/:id/translations/:lang", handlers.Clause.TranslateClause)
+		clauses.DELETE("/:id/translations/:lang", handlers.Clause.DeleteClauseTranslation)
+	}
+
+	leases := g.Group("/leases", requireAuth)
+	{
+		leases.GET("", handlers.Lease.ListLeases)
+		leases.POST("", handlers.Lease.CreateLease)
+		leases.GET("/:id", handlers.Lease.GetLease)
+		leases.PUT("/:id", handlers.Lease.UpdateLease)
+		leases.DELETE("/:id", handlers.Lease.DeleteLease)
+		leases.GET("/:id/clauses", handlers.Lease.ListLeaseClauses)
+		leases.PUT("/:id/clauses", handlers.Lease.SetLeaseClauses)
+		leases.GET("/:id/preview", handlers.Lease.PreviewLease)
+		leases.GET("/:id/pdf", handlers.Lease.DownloadLeasePDF)
+		leases.GET("/:id/stamp-duty", handlers.Lease.GetLeaseStampDuty)
+		leases.POST("/:id/estamp", handlers.Lease.AttachEStamp)
+		leases.GET("/:id/estamp", handlers.Lease.GetEStamp)
+		leases.GET("/:id/estamp/file", handlers.Lease.DownloadEStamp)
+		leases.DELETE("/:id/estamp", handlers.Lease.RemoveEStamp)
+		leases.POST("/:id/tenants", handlers.Lease.AddLeaseTenant)
+		leases.DELETE("/:id/tenants/:userId", handlers.Lease.RemoveLeaseTenant)
+		leases.GET("/:id/verifications", handlers.Lease.ListTenantVerifications)
+		leases.GET("/:id/verifications/:userId", handlers.Lease.GetTenantVerification)
+		leases.PUT("/:id/verifications/:userId", handlers.Lease.SaveTenantVerification)
+		leases.PUT("/:id/verifications/:userId/photo", handlers.Lease.UploadVerificationPhoto)
+		leases.GET("/:id/verifications/:userId/photo", handlers.Lease.DownloadVerificationPhoto)
+		leases.GET("/:id/verifications/:userId/form", handlers.Lease.DownloadVerificationForm)
+		leases.PUT("/:id/verifications/:userId/status", handlers.Lease.SetVerificationStatus)
+		leases.GET("/:id/dues", handlers.Lease.ListLeaseDues)
+		leases.POST("/:id/dues/:dueId/upi", handlers.Payment.RequestUPIPayment)
+		leases.GET("/:id/upi-requests", handlers.Payment.ListUPIRequests)
+		leases.GET("/:id/upi-requests/:requestId/qr", handlers.Payment.GetUPIQRCode)
+		leases.POST("/:id/dues/:dueId/pay", handlers.Payment.CreatePaymentOrder)
+		leases.GET("/:id/payment-orders", handlers.Payment.ListPaymentOrders)
+		leases.POST("/:id/payment-orders/:orderId/confirm", handlers.Payment.ConfirmPayment)
+		leases.POST("/:id/payment-orders/:orderId/refund", handlers.Payment.RefundPayment)
+		leases.GET("/:id/ledger", handlers.Ledger.GetLeaseLedger)
+		leases.GET("/:id/ledger/statement", handlers.Ledger.GetLeaseStatement)
+		leases.POST("/:id/ledger/entries", handlers.Ledger.PostLedgerEntry)
+		leases.GET("/:id/ledger/entries/:entryId", handlers.Ledger.GetLedgerEntry)
+		leases.GET("/:id/ledger/entries/:entryId/receipt", handlers.Lease.DownloadReceiptPDF)
+		leases.POST("/:id/ledger/entries/:entryId/reverse", handlers.Ledger.ReverseLedgerEntry)
+		leases.GET("/:id/transitions", handlers.Lease.ListLeaseTransitions)
+		leases.GET("/:id/versions", handlers.Lease.ListLeaseVersions)
+		leases.GET("/:id/versions/diff", handlers.Lease.DiffLeaseVersions)
+		leases.GET("/:id/versions/:number", handlers.Lease.GetLeaseVersion)
+		leases.POST("/:id/submit", handlers.Lease.SubmitLease)
+		leases.POST("/:id/withdraw", handlers.Lease.WithdrawLease)
+		leases.POST("/:id/sign", handlers.Lease.SignLease)
+		leases.POST("/:id/activate", handlers.Lease.ActivateLease)
+		leases.POST("/:id/notice", handlers.Lease.GiveLeaseNotice)
+		leases.POST("/:id/terminate", handlers.Lease.TerminateLease)
+		leases.POST("/:id/expire", handlers.Lease.ExpireLease)
+		leases.POST("/:id/renewal", handlers.Lease.DraftLeaseRenewal)
+		leases.GET("/:id/renewal", handlers.Lease.GetLeaseRenewal)
+		leases.POST("/:id/signing", handlers.Lease.StartLeaseSigning)
+		leases.GET("/:id/signing", handlers.Lease.GetLeaseSigning)
+		leases.GET("/:id/signing/document", handlers.Lease.DownloadLeaseSigningDocument)
+		leases.POST("/:id/signing/signers/:signerId/resend", handlers.Lease.ResendSigningLink)
+		leases.GET("/:id/signing/signers/:signerId/document", handlers.Lease.DownloadSignedDocument)
+	}
+
+	bankStatements := g.Group("/bank-statements", requireAuth)
+	{
+		bankStatements.GET("/formats", handlers.Bank.ListBankFormats)
+		bankStatements.GET("", handlers.Bank.ListBankStatementImports)
+		bankStatements.POST("", handlers.Bank.ImportBankStatement)
+	}
+
+	bankTransactions := g.Group("/bank-transactions", requireAuth)
+	{
+		bankTransactions.GET("", handlers.Bank.ListBankTransactions)
+		bankTransactions.POST("/:id/confirm", handlers.Bank.ConfirmBankMatch)
+		bankTransactions.POST("/:id/ignore", handlers.Bank.IgnoreBankTransaction)
+	}
+
+	signing := g.Group("/signing")
+	{
+		signing.GET("/:token", handlers.Signing.ViewSigning)
+		signing.GET("/:token/document", handlers.Signing.DownloadSigningDocument)
+		signing.POST("/:token", handlers.Signing.Sign)
+		signing.POST("/:token/esign", handlers.Signing.StartESign)
+	}
+
+	// Posted by the ESP from the signer's browser
+	g.POST("/esign/callback", handlers.Signing.ESignCallback)
+
+	// Posted by the payment gateway, signed with the webhook secret
+	g.POST("/payments/webhook", handlers.Payment.GatewayWebhook)
+
+	g.GET("/verify/:code", handlers.Verify.VerifyDocument)
 }
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/apperr"
+	"backend/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SigningHandler serves the signing links sent to lease parties. The link
+// token is the only credential, so these routes need no login.
+type SigningHandler struct {
+	leaseService service.LeaseService
+	// esignReturnURL is where signers are sent once the ESP posts back
+	esignReturnURL string
+}
+
+func NewSigningHandler(leaseService service.LeaseService, esignReturnURL string) *SigningHandler {
+	return &SigningHandler{leaseService: leaseService, esignReturnURL: esignReturnURL}
+}
+
+// ViewSigning godoc
+// @Summary Open a signing link
+// @Description Get the lease the link holder is asked to sign, the SHA-256 of the document and the progress of the other parties. The visit is recorded in the audit trail.
+// @Tags signing
+// @Accept json
+// @Produce json
+// @Param token path string true "Signing link token"
+// @Success 200 {object} response.Response{data=model.SigningView}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /signing/{token} [get]
+func (h *SigningHandler) ViewSigning(c echo.Context) error {
+	view, err := h.leaseService.ViewSigning(c.Request().Context(), c.Param("token"), clientInfo(c, ""))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, view)
+}
+
+// DownloadSigningDocument godoc
+// @Summary Download the document to sign
+// @Description Download the lease PDF the link holder is asked to sign
+// @Tags signing
+// @Produce application/pdf
+// @Param token path string true "Signing link token"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /signing/{token}/document [get]
+func (h *SigningHandler) DownloadSigningDocument(c echo.Context) error {
+	signing, content, err := h.leaseService.OpenSigningDocumentByToken(c.Request().Context(), c.Param("token"))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+	defer content.Close()
+
+	return streamSigningDocument(c, signing, content)
+}
+
+// Sign godoc
+// @Summary Sign the lease
+// @Description Sign with a typed full name or a drawn signature (base64 PNG). The signature is recorded with the time, IP address and user agent, and the link stops working.
+// @Tags signing
+// @Accept json
+// @Produce json
+// @Param token path string true "Signing link token"
+// @Param request body model.SignRequest true "Signature"
+// @Success 200 {object} response.Response{data=model.LeaseSigner}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /signing/{token} [post]
+func (h *SigningHandler) Sign(c echo.Context) error {
+	req := new(model.SignRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	signer, err := h.leaseService.Sign(c.Request().Context(), c.Param("token"), service.SignInput{
+		SignatureType: req.SignatureType,
+		Signature:     req.Signature,
+		Consent:       req.Consent,
+		Client:        clientInfo(c, ""),
+	})
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, signer)
+}
+
+// StartESign godoc
+// @Summary Start Aadhaar eSign
+// @Description Start signing with Aadhaar eSign instead of a typed or drawn signature. Post request_xml as the eSignRequest form field to esp_url from the signer's browser; after the Aadhaar OTP the ESP returns the signer to the application.
+// @Tags signing
+// @Produce json
+// @Param token path string true "Signing link token"
+// @Success 200 {object} response.Response{data=esign.Transaction}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /signing/{token}/esign [post]
+func (h *SigningHandler) StartESign(c echo.Context) error {
+	txn, err := h.leaseService.StartESign(c.Request().Context(), c.Param("token"), clientInfo(c, ""))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, txn)
+}
+
+// ESignCallback godoc
+// @Summary Receive an eSign response
+// @Description Response URL of the ESP, posted from the signer's browser. The signature is verified and recorded, and the signer is redirected to the application with status signed, or failed and a message.
+// @Tags signing
+// @Accept x-www-form-urlencoded
+// @Param eSignResponse formData string true "EsignResp XML"
+// @Success 303
+// @Router /esign/callback [post]
+func (h *SigningHandler) ESignCallback(c echo.Context) error {
+	query := url.Values{"status": {"signed"}}
+	_, err := h.leaseService.CompleteESign(c.Request().Context(), c.FormValue("eSignResponse"), clientInfo(c, ""))
+	if err != nil {
+		c.Logger().Error(err)
+		message := "Failed to complete eSign"
+		var ae *apperr.AppError
+		if errors.As(err, &ae) && ae.Code != apperr.CodeInternal {
+			message = ae.Message
+		}
+		query = url.Values{"status": {"failed"}, "message": {message}}
+	}
+
+	return c.Redirect(http.StatusSeeOther, h.esignReturnURL+"?"+query.Encode())
+}
+
+func streamSigningDocument(c echo.Context, signing *model.LeaseSigning, content io.Reader) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"lease-%s.pdf\"", signing.LeaseID))
+	c.Response().Header().Set("X-Document-SHA256", signing.DocumentSHA256)
+	return c.Stream(http.StatusOK, "application/pdf", content)
+}
@@ -1,8 +1,7 @@
 package handler
 
 import (
-	"strconv"
-
+	"backend/internal/middleware"
 	"backend/internal/model"
 	"backend/internal/service"
 	"backend/pkg/response"
@@ -28,26 +27,20 @@ type ListUsersResponse struct {
 
 // ListUsers godoc
 // @Summary List all users
-// @Description Get a paginated list of all users
+// @Description Get a paginated list of all users (admin only)
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param limit query int false "Limit" default(20)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} response.Response{data=ListUsersResponse}
+// @Failure 403 {object} response.ErrorResponse
 // @Router /users [get]
 func (h *UserHandler) ListUsers(c echo.Context) error {
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-
-	offset, _ := strconv.Atoi(c.QueryParam("offset"))
-	if offset < 0 {
-		offset = 0
-	}
+	limit, offset := paginationParams(c)
 
-	users, total, err := h.userService.List(c.Request().Context(), limit, offset)
+	users, total, err := h.userService.List(c.Request().Context(), middleware.CurrentUser(c), limit, offset)
 	if err != nil {
 		return response.FromError(c, err)
 	}
@@ -62,13 +55,16 @@ func (h *UserHandler) ListUsers(c echo.Context) error {
 
 // CreateUser godoc
 // @Summary Create a new user
-// @Description Create a new user with the provided details
+// @Description Create a new user with the provided details (admin only)
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param user body model.CreateUserRequest true "User details"
 // @Success 201 {object} response.Response{data=model.User}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 409 {object} response.ErrorResponse
 // @Router /users [post]
 func (h *UserHandler) CreateUser(c echo.Context) error {
@@ -82,10 +78,12 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 		return err
 	}
 
-	user, err := h.userService.Create(c.Request().Context(), service.CreateUserInput{
-		Name:  req.Name,
-		Email: req.Email,
-		Role:  req.Role,
+	user, err := h.userService.Create(c.Request().Context(), middleware.CurrentUser(c), service.CreateUserInput{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+		Phone:    req.Phone,
+		Role:     req.Role,
 	})
 	if err != nil {
 		return response.FromError(c, err)
@@ -100,8 +98,10 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "User ID"
 // @Success 200 {object} response.Response{data=model.User}
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
 // @Router /users/{id} [get]
 func (h *UserHandler) GetUser(c echo.Context) error {
@@ -110,7 +110,7 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 		return response.BadRequest(c, "Invalid user ID format", nil)
 	}
 
-	user, err := h.userService.GetByID(c.Request().Context(), id)
+	user, err := h.userService.GetByID(c.Request().Context(), middleware.CurrentUser(c), id)
 	if err != nil {
 		return response.FromError(c, err)
 	}
@@ -120,15 +120,20 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 
 // UpdateUser godoc
 // @Summary Update a user
-// @Description Update user details by user ID
+// @Description Update user details by user ID. A new phone number is saved only with phone_code, the code sent to it by POST /users/{id}/phone/otp.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "User ID"
 // @Param user body model.UpdateUserRequest true "User update details"
 // @Success 200 {object} response.Response{data=model.User}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 429 {object} response.ErrorResponse
 // @Router /users/{id} [put]
 func (h *UserHandler) UpdateUser(c echo.Context) error {
 	id, err := uuid.Parse(c.Param("id"))
@@ -149,11 +154,15 @@ func (h *UserHandler) UpdateUser(c echo.Context) error {
 	if req.Name != "" {
 		input.Name = &req.Name
 	}
+	if req.Phone != "" {
+		input.Phone = &req.Phone
+		input.PhoneCode = req.PhoneCode
+	}
 	if req.Role != "" {
 		input.Role = &req.Role
 	}
 
-	user, err := h.userService.Update(c.Request().Context(), id, input)
+	user, err := h.userService.Update(c.Request().Context(), middleware.CurrentUser(c), id, input)
 	if err != nil {
 		return response.FromError(c, err)
 	}
@@ -161,14 +170,55 @@ func (h *UserHandler) UpdateUser(c echo.Context) error {
 	return response.Success(c, user)
 }
 
+// RequestPhoneChangeOTP godoc
+// @Summary Request a code to change a user's phone number
+// @Description Send a one-time password by SMS to the number the user's phone is to be changed to. Update the user with the number and the code as phone_code to save it.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body model.RequestOTPRequest true "New mobile number"
+// @Success 200 {object} response.Response{data=model.OTPRequestResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 429 {object} response.ErrorResponse
+// @Router /users/{id}/phone/otp [post]
+func (h *UserHandler) RequestPhoneChangeOTP(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid user ID format", nil)
+	}
+
+	req := new(model.RequestOTPRequest)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequest(c, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	result, err := h.userService.RequestPhoneChange(c.Request().Context(), middleware.CurrentUser(c), id, req.Phone)
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, result)
+}
+
 // DeleteUser godoc
 // @Summary Delete a user
 // @Description Delete a user by user ID
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "User ID"
 // @Success 204 "No Content"
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
 // @Router /users/{id} [delete]
 func (h *UserHandler) DeleteUser(c echo.Context) error {
@@ -177,7 +227,7 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 		return response.BadRequest(c, "Invalid user ID format", nil)
 	}
 
-	if err := h.userService.Delete(c.Request().Context(), id); err != nil {
+	if err := h.userService.Delete(c.Request().Context(), middleware.CurrentUser(c), id); err != nil {
 		return response.FromError(c, err)
 	}
 
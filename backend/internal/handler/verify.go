@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"backend/internal/service"
+	"backend/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// VerifyHandler answers whether a printed document is genuine. The QR code
+// on the document links here, so these routes need no login.
+type VerifyHandler struct {
+	leaseService service.LeaseService
+}
+
+func NewVerifyHandler(leaseService service.LeaseService) *VerifyHandler {
+	return &VerifyHandler{leaseService: leaseService}
+}
+
+// VerifyDocument godoc
+// @Summary Verify a document
+// @Description Look up a generated document by the verification code printed on it. Returns the parties' names, the lease dates and status, the document's SHA-256 and whether a later version has replaced it. No other personal details are returned.
+// @Tags verify
+// @Produce json
+// @Param code path string true "Verification code"
+// @Success 200 {object} response.Response{data=model.DocumentVerification}
+// @Failure 404 {object} response.ErrorResponse
+// @Router /verify/{code} [get]
+func (h *VerifyHandler) VerifyDocument(c echo.Context) error {
+	verification, err := h.leaseService.VerifyDocument(c.Request().Context(), c.Param("code"))
+	if err != nil {
+		return response.FromError(c, err)
+	}
+
+	return response.Success(c, verification)
+}
@@ -0,0 +1,214 @@
+package leasepdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/go-text/typesetting/font"
+)
+
+// Private Use characters stand for the glyphs no character maps to
+const (
+	privateUseFirst = 0xE000
+	privateUseLast  = 0xF8FF
+)
+
+// withGlyphCodes gives every glyph of a TrueType font a character fpdf can
+// write it as: the lowest character the font maps to it, or a Private Use
+// character for the conjuncts, half forms and other glyphs only shaping
+// reaches. fpdf finds glyphs through the font's Unicode cmap, so it returns a
+// copy of the font whose cmap holds both, with the character of each glyph
+// by glyph ID. A glyph left without one, past the Private Use Area, is 0.
+func withGlyphCodes(data []byte, f *font.Font) ([]byte, []rune, error) {
+	tables, err := sfntTables(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	maxp, ok := tables["maxp"]
+	if !ok || len(maxp) < 6 {
+		return nil, nil, errors.New("font has no maxp table")
+	}
+	if _, ok := tables["glyf"]; !ok {
+		return nil, nil, errors.New("font has no TrueType outlines")
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+
+	glyphs := map[rune]font.GID{}
+	codes := make([]rune, numGlyphs)
+	for it := f.Cmap.Iter(); it.Next(); {
+		r, gid := it.Char()
+		if r < 0x20 || r >= 0xFFFF || gid == 0 || int(gid) >= numGlyphs {
+			continue
+		}
+		glyphs[r] = gid
+		if codes[gid] == 0 || r < codes[gid] {
+			codes[gid] = r
+		}
+	}
+	next := rune(privateUseFirst)
+	for gid := 1; gid < numGlyphs; gid++ {
+		if codes[gid] != 0 {
+			continue
+		}
+		for _, taken := glyphs[next]; taken; _, taken = glyphs[next] {
+			next++
+		}
+		if next > privateUseLast {
+			break
+		}
+		glyphs[next], codes[gid] = font.GID(gid), next
+	}
+
+	cmap, err := unicodeCmap(glyphs)
+	if err != nil {
+		return nil, nil, err
+	}
+	tables["cmap"] = cmap
+	return sfntFile(binary.BigEndian.Uint32(data), tables), codes, nil
+}
+
+// sfntTables returns the tables of a font file by tag
+func sfntTables(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, errors.New("font file is truncated")
+	}
+	count := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*count {
+		return nil, errors.New("font file is truncated")
+	}
+	tables := make(map[string][]byte, count)
+	for i := range count {
+		record := data[12+16*i:]
+		offset, length := int64(binary.BigEndian.Uint32(record[8:])), int64(binary.BigEndian.Uint32(record[12:]))
+		if offset+length > int64(len(data)) {
+			return nil, fmt.Errorf("font table %q is truncated", record[:4])
+		}
+		tables[string(record[:4])] = data[offset : offset+length]
+	}
+	return tables, nil
+}
+
+// unicodeCmap builds a cmap table with a single Windows Unicode BMP subtable
+// (format 4) holding glyphs
+func unicodeCmap(glyphs map[rune]font.GID) ([]byte, error) {
+	chars := make([]rune, 0, len(glyphs))
+	for r := range glyphs {
+		chars = append(chars, r)
+	}
+	slices.Sort(chars)
+
+	// Runs of consecutive characters with consecutive glyphs share a segment
+	type segment struct {
+		start, end rune
+		delta      uint16
+	}
+	var segments []segment
+	for _, r := range chars {
+		delta := uint16(int(glyphs[r]) - int(r))
+		if n := len(segments); n > 0 && segments[n-1].end == r-1 && segments[n-1].delta == delta {
+			segments[n-1].end = r
+			continue
+		}
+		segments = append(segments, segment{start: r, end: r, delta: delta})
+	}
+	segments = append(segments, segment{start: 0xFFFF, end: 0xFFFF, delta: 1})
+
+	segCount := len(segments)
+	length := 16 + 8*segCount
+	if length > 0xFFFF {
+		return nil, errors.New("font maps too many characters")
+	}
+	searchRange, entrySelector := 2, 0
+	for searchRange*2 <= 2*segCount {
+		searchRange *= 2
+		entrySelector++
+	}
+
+	be := binary.BigEndian
+	out := be.AppendUint16(nil, 0) // version
+	out = be.AppendUint16(out, 1)  // subtables
+	out = be.AppendUint16(out, 3)  // Windows
+	out = be.AppendUint16(out, 1)  // Unicode BMP
+	out = be.AppendUint32(out, 12) // offset
+	out = be.AppendUint16(out, 4)  // format
+	out = be.AppendUint16(out, uint16(length))
+	out = be.AppendUint16(out, 0) // language
+	out = be.AppendUint16(out, uint16(2*segCount))
+	out = be.AppendUint16(out, uint16(searchRange))
+	out = be.AppendUint16(out, uint16(entrySelector))
+	out = be.AppendUint16(out, uint16(2*segCount-searchRange))
+	for _, s := range segments {
+		out = be.AppendUint16(out, uint16(s.end))
+	}
+	out = be.AppendUint16(out, 0) // reserved
+	for _, s := range segments {
+		out = be.AppendUint16(out, uint16(s.start))
+	}
+	for _, s := range segments {
+		out = be.AppendUint16(out, s.delta)
+	}
+	for range segments {
+		out = be.AppendUint16(out, 0) // idRangeOffset
+	}
+	return out, nil
+}
+
+// sfntFile writes a font file holding tables, with the checksums recomputed
+func sfntFile(version uint32, tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+
+	be := binary.BigEndian
+	count := len(tags)
+	searchRange, entrySelector := 1, 0
+	for searchRange*2 <= count {
+		searchRange *= 2
+		entrySelector++
+	}
+	out := be.AppendUint32(nil, version)
+	out = be.AppendUint16(out, uint16(count))
+	out = be.AppendUint16(out, uint16(16*searchRange))
+	out = be.AppendUint16(out, uint16(entrySelector))
+	out = be.AppendUint16(out, uint16(16*(count-searchRange)))
+
+	offset := len(out) + 16*count
+	var body []byte
+	headAt := -1
+	for _, tag := range tags {
+		table := slices.Clone(tables[tag])
+		if tag == "head" && len(table) >= 12 {
+			// checkSumAdjustment is computed over the whole file below
+			be.PutUint32(table[8:], 0)
+			headAt = offset + len(body)
+		}
+		out = append(out, tag...)
+		out = be.AppendUint32(out, tableChecksum(table))
+		out = be.AppendUint32(out, uint32(offset+len(body)))
+		out = be.AppendUint32(out, uint32(len(table)))
+		body = append(body, table...)
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+	}
+	out = append(out, body...)
+	if headAt >= 0 {
+		be.PutUint32(out[headAt+8:], 0xB1B0AFBA-tableChecksum(out))
+	}
+	return out
+}
+
+// tableChecksum sums data as big-endian 32-bit words, zero padded
+func tableChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
@@ -0,0 +1,716 @@
+// Package leasepdf lays out a lease as a printable agreement, and the rent
+// receipts of its payments. The agreement's page one starts below a blank
+// band so it can be printed on Indian non-judicial stamp paper, whose stamp
+// is pre-printed at the top.
+package leasepdf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/clausetext"
+	"backend/internal/model"
+	"backend/internal/stampduty"
+	"backend/pkg/india"
+	"backend/pkg/inr"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	PaperA4    = "A4"
+	PaperLegal = "Legal"
+)
+
+const (
+	marginMM       = 20.0
+	footerHeightMM = 32.0
+	lineHeightMM   = 5.5
+	initialsBoxMM  = 22.0
+	qrCodeMM       = 16.0
+	columnGapMM    = 6.0
+	fontFamily     = "Times"
+)
+
+// qrImage names the registered verification QR code
+const qrImage = "verification-qr"
+
+// ist is the zone audit times are printed in
+var ist = time.FixedZone("IST", 5*60*60+30*60)
+
+// Options controls the page layout
+type Options struct {
+	PaperSize string
+	// StampMarginMM is the blank space left at the top of page one
+	StampMarginMM float64
+	// Fonts draw clauses translated into Indian languages
+	Fonts *Fonts
+}
+
+// Document is everything printed in the agreement
+type Document struct {
+	Lease    *model.Lease
+	Property *model.Property
+	// Owners lists the owner first, then any co-owners
+	Owners  []model.User
+	Tenants []model.User
+	Clauses []model.RenderedClause
+	// StampDuty is printed with the key terms when set
+	StampDuty *stampduty.Estimate
+	// EStamp is referenced at the top of page one when set
+	EStamp *model.EStamp
+	// Witnesses names the witnesses in their signature blocks, when known
+	Witnesses []string
+	// Signing, once completed, is appended as an audit certificate
+	Signing *model.LeaseSigning
+	// Version is the number of the lease version rendered, printed in the footer when set
+	Version int
+	// Verification, when set, is printed in every footer as a QR code
+	Verification *Verification
+	// GeneratedAt is the PDF creation date, so that the same content always renders to the same bytes
+	GeneratedAt time.Time
+
+	// Lang, other than English, prints each clause's translation into that
+	// language where it has one. The rest of the agreement stays in English.
+	Lang string
+	// Bilingual prints translated clauses beside the English wording
+	Bilingual bool
+}
+
+// Verification identifies the document at the public verification endpoint
+type Verification struct {
+	Code string
+	URL  string
+}
+
+type party struct {
+	user model.User
+	role string
+}
+
+type renderer struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+	doc *Document
+	// script draws translated clauses; it is nil for English
+	script *scriptWriter
+}
+
+// Render returns the agreement as a PDF
+func Render(doc *Document, opts Options) ([]byte, error) {
+	if opts.PaperSize != PaperLegal {
+		opts.PaperSize = PaperA4
+	}
+
+	pdf := fpdf.New("P", "mm", opts.PaperSize, "")
+	pdf.SetMargins(marginMM, marginMM, marginMM)
+	pdf.SetAutoPageBreak(true, footerHeightMM)
+	pdf.AliasNbPages("{nb}")
+	pdf.SetTitle("Rent Agreement", true)
+	pdf.SetCatalogSort(true)
+	if !doc.GeneratedAt.IsZero() {
+		pdf.SetCreationDate(doc.GeneratedAt.UTC())
+		pdf.SetModificationDate(doc.GeneratedAt.UTC())
+	}
+
+	r := &renderer{
+		pdf: pdf,
+		tr:  pdf.UnicodeTranslatorFromDescriptor(""),
+		doc: doc,
+	}
+	if doc.Lang != "" && doc.Lang != india.English {
+		script, err := newScriptWriter(pdf, opts.Fonts, india.Languages[doc.Lang].Script, doc.Lang)
+		if err != nil {
+			return nil, err
+		}
+		r.script = script
+	}
+	pdf.SetFooterFunc(r.footer)
+	if doc.Verification != nil {
+		qr, err := qrcode.Encode(doc.Verification.URL, qrcode.Medium, 256)
+		if err != nil {
+			return nil, fmt.Errorf("render verification qr code: %w", err)
+		}
+		pdf.RegisterImageOptionsReader(qrImage, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	}
+
+	pdf.AddPage()
+	if opts.StampMarginMM > marginMM {
+		pdf.SetY(opts.StampMarginMM)
+	}
+
+	r.estamp()
+	r.title()
+	r.parties()
+	r.keyTerms()
+	r.clauses()
+	r.schedule()
+	r.signatures()
+	r.certificate()
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render lease pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// text converts UTF-8 to the core fonts' encoding. The rupee sign is not in
+// it, so it is written as "Rs.".
+func (r *renderer) text(s string) string {
+	return r.tr(strings.ReplaceAll(s, "₹", "Rs. "))
+}
+
+func (r *renderer) heading(s string) {
+	r.ensureSpace(3 * lineHeightMM)
+	r.pdf.Ln(lineHeightMM)
+	r.pdf.SetFont(fontFamily, "B", 12)
+	r.pdf.CellFormat(0, lineHeightMM+1, r.text(s), "", 1, "C", false, 0, "")
+	r.pdf.Ln(2)
+}
+
+func (r *renderer) paragraph(s string) {
+	r.pdf.SetFont(fontFamily, "", 11)
+	r.pdf.MultiCell(0, lineHeightMM, r.text(s), "", "J", false)
+	r.pdf.Ln(2)
+}
+
+// ensureSpace starts a new page unless h millimetres fit above the footer
+func (r *renderer) ensureSpace(h float64) {
+	_, pageHeight := r.pdf.GetPageSize()
+	if r.pdf.GetY()+h > pageHeight-footerHeightMM {
+		r.pdf.AddPage()
+	}
+}
+
+// estamp prints the e-stamp certificate the agreement is executed on
+func (r *renderer) estamp() {
+	stamp := r.doc.EStamp
+	if stamp == nil {
+		return
+	}
+
+	r.pdf.SetFont(fontFamily, "B", 10)
+	r.pdf.CellFormat(0, lineHeightMM, r.text("E-stamp certificate no. "+stamp.CertificateNumber), "", 1, "R", false, 0, "")
+	r.pdf.SetFont(fontFamily, "", 9)
+	r.pdf.CellFormat(0, lineHeightMM, r.text(fmt.Sprintf("Issued %s, %s, purchased by %s",
+		stamp.IssueDate.Format(clausetext.DateLayout), inr.FormatWithSymbol(stamp.DenominationPaise), stamp.Purchaser)), "", 1, "R", false, 0, "")
+	r.pdf.Ln(3)
+}
+
+func (r *renderer) title() {
+	r.pdf.SetFont(fontFamily, "B", 16)
+	r.pdf.CellFormat(0, 10, "RENT AGREEMENT", "", 1, "C", false, 0, "")
+	r.pdf.Ln(4)
+
+	place := r.doc.Property.City
+	r.paragraph(fmt.Sprintf("This Rent Agreement is made and executed at %s on this ______ day of ______________, 20____, by and between:", place))
+}
+
+func (r *renderer) parties() {
+	for i, owner := range r.doc.Owners {
+		r.partyDetails(i+1, owner)
+	}
+	r.paragraph("(hereinafter jointly and severally called the \"Owner\", which expression shall include their heirs, legal representatives and assigns) of the ONE PART;")
+
+	r.pdf.SetFont(fontFamily, "B", 11)
+	r.pdf.CellFormat(0, lineHeightMM+1, "AND", "", 1, "C", false, 0, "")
+	r.pdf.Ln(1)
+
+	for i, tenant := range r.doc.Tenants {
+		r.partyDetails(i+1, tenant)
+	}
+	r.paragraph("(hereinafter jointly and severally called the \"Tenant\", which expression shall include their heirs, legal representatives and assigns) of the OTHER PART.")
+
+	r.paragraph("WHEREAS the Owner is the lawful owner of the premises described in the Schedule of Property below, and the Owner has agreed to let and the Tenant has agreed to take the premises on rent on the following terms and conditions.")
+}
+
+func (r *renderer) partyDetails(n int, user model.User) {
+	r.pdf.SetFont(fontFamily, "B", 11)
+	r.pdf.MultiCell(0, lineHeightMM, r.text(fmt.Sprintf("%d. %s", n, user.Name)), "", "L", false)
+
+	var contact []string
+	if user.Phone != nil && *user.Phone != "" {
+		contact = append(contact, "Phone: "+*user.Phone)
+	}
+	if user.Email != nil && *user.Email != "" {
+		contact = append(contact, "Email: "+*user.Email)
+	}
+	if len(contact) > 0 {
+		r.pdf.SetFont(fontFamily, "", 11)
+		r.pdf.SetX(marginMM + 5)
+		r.pdf.MultiCell(0, lineHeightMM, r.text(strings.Join(contact, ", ")), "", "L", false)
+	}
+	r.pdf.Ln(1)
+}
+
+func (r *renderer) keyTerms() {
+	lease := r.doc.Lease
+
+	deposit := inr.FormatWithSymbol(lease.SecurityDepositPaise) + " (" + inr.Words(lease.SecurityDepositPaise) + ")"
+	if lease.RenewalOfID != nil {
+		deposit += ", carried forward from the previous agreement"
+	}
+	use := "Residential"
+	if lease.PremisesUse == model.PremisesNonResidential {
+		use = "Non-residential"
+	}
+	rows := [][2]string{
+		{"Use of premises", use},
+		{"Term", fmt.Sprintf("%d months, from %s to %s", lease.TermMonths,
+			lease.StartDate.Format(clausetext.DateLayout), lease.EndDate.Format(clausetext.DateLayout))},
+		{"Monthly rent", inr.FormatWithSymbol(lease.MonthlyRentPaise) + " (" + inr.Words(lease.MonthlyRentPaise) + ")"},
+		{"Rent due", rentDue(lease)},
+		{"Security deposit", deposit},
+	}
+	if lease.MaintenancePaise > 0 {
+		rows = append(rows, [2]string{"Monthly maintenance", inr.FormatWithSymbol(lease.MaintenancePaise)})
+	}
+	rows = append(rows, [2]string{"Notice period", fmt.Sprintf("%d days", lease.NoticePeriodDays)})
+	if lease.LockInMonths > 0 {
+		rows = append(rows, [2]string{"Lock-in period", fmt.Sprintf("%d months", lease.LockInMonths)})
+	}
+	switch lease.EscalationType {
+	case model.EscalationPercent:
+		basis := "the original rent"
+		if lease.EscalationCompounding {
+			basis = "the rent then payable"
+		}
+		rows = append(rows, [2]string{"Rent on renewal", fmt.Sprintf("Increases by %s of %s on each renewal",
+			percent(lease.EscalationBasisPoints), basis)})
+	case model.EscalationFixed:
+		rows = append(rows, [2]string{"Rent on renewal", "Increases by " + inr.FormatWithSymbol(lease.EscalationAmountPaise) + " a month on each renewal"})
+	}
+	if duty := r.doc.StampDuty; duty != nil {
+		rows = append(rows, [2]string{"Stamp duty", fmt.Sprintf("%s payable in %s (%s)",
+			inr.FormatWithSymbol(duty.StampDutyPaise), duty.StateName, duty.Basis)})
+		if duty.RegistrationFeePaise > 0 {
+			rows = append(rows, [2]string{"Registration fee", inr.FormatWithSymbol(duty.RegistrationFeePaise)})
+		}
+	}
+
+	r.heading("KEY TERMS")
+	r.table(rows)
+}
+
+// rentDue describes when rent is paid. Rent for a cycle longer than a month
+// is paid in advance for the whole cycle.
+func rentDue(lease *model.Lease) string {
+	switch lease.RentCycleMonths {
+	case 0, 1:
+		return fmt.Sprintf("On or before day %d of each month", lease.RentDueDay)
+	case 3:
+		return fmt.Sprintf("Quarterly in advance, %s on or before day %d of the first month of each quarter",
+			inr.FormatWithSymbol(lease.MonthlyRentPaise*3), lease.RentDueDay)
+	default:
+		return fmt.Sprintf("Every %d months in advance, %s on or before day %d of the first month of each period",
+			lease.RentCycleMonths, inr.FormatWithSymbol(lease.MonthlyRentPaise*int64(lease.RentCycleMonths)), lease.RentDueDay)
+	}
+}
+
+// percent formats basis points as a percentage, e.g. 550 as 5.5%
+func percent(basisPoints int) string {
+	return strconv.FormatFloat(float64(basisPoints)/100, 'f', -1, 64) + "%"
+}
+
+// table prints label/value rows with the value wrapping in the second column
+func (r *renderer) table(rows [][2]string) {
+	pageWidth, _ := r.pdf.GetPageSize()
+	labelWidth := 50.0
+	valueWidth := pageWidth - 2*marginMM - labelWidth
+
+	for _, row := range rows {
+		r.pdf.SetFont(fontFamily, "", 11)
+		lines := r.pdf.SplitLines([]byte(r.text(row[1])), valueWidth-2)
+		height := float64(len(lines)) * lineHeightMM
+		r.ensureSpace(height)
+
+		x, y := r.pdf.GetXY()
+		r.pdf.SetFont(fontFamily, "B", 11)
+		r.pdf.Rect(x, y, labelWidth, height, "D")
+		r.pdf.MultiCell(labelWidth, lineHeightMM, r.text(row[0]), "", "L", false)
+
+		r.pdf.SetXY(x+labelWidth, y)
+		r.pdf.SetFont(fontFamily, "", 11)
+		r.pdf.Rect(x+labelWidth, y, valueWidth, height, "D")
+		r.pdf.MultiCell(valueWidth, lineHeightMM, r.text(row[1]), "", "L", false)
+
+		r.pdf.SetXY(x, y+height)
+	}
+	r.pdf.Ln(2)
+}
+
+func (r *renderer) clauses() {
+	r.heading("TERMS AND CONDITIONS")
+	for i, clause := range r.doc.Clauses {
+		r.ensureSpace(3 * lineHeightMM)
+		translation := clause.Translation
+		switch {
+		case r.script == nil || translation == nil:
+			r.pdf.SetFont(fontFamily, "B", 11)
+			r.pdf.MultiCell(0, lineHeightMM, r.text(fmt.Sprintf("%d. %s", i+1, clause.Title)), "", "L", false)
+			r.paragraph(clause.Text)
+		case r.doc.Bilingual:
+			r.bilingualClause(i+1, clause)
+		default:
+			r.scriptParagraph(fmt.Sprintf("%d. %s", i+1, translation.Title), true)
+			r.scriptParagraph(translation.Text, false)
+			r.pdf.Ln(2)
+		}
+	}
+}
+
+// scriptParagraph prints text in the translation's script across the page
+func (r *renderer) scriptParagraph(text string, bold bool) {
+	pageWidth, _ := r.pdf.GetPageSize()
+	for _, line := range r.script.wrap(text, bold, 11, pageWidth-2*marginMM) {
+		r.ensureSpace(scriptLineHeightMM)
+		y := r.pdf.GetY()
+		r.script.drawLine(line, bold, 11, marginMM, y+scriptBaselineMM)
+		r.pdf.SetY(y + scriptLineHeightMM)
+	}
+}
+
+// bilingualClause prints a clause in two columns, English on the left and
+// the translation on the right. Both columns continue onto the next page
+// together.
+func (r *renderer) bilingualClause(n int, clause model.RenderedClause) {
+	type englishLine struct {
+		text string
+		bold bool
+	}
+	type scriptLine struct {
+		words []shapedWord
+		bold  bool
+	}
+
+	pageWidth, pageHeight := r.pdf.GetPageSize()
+	columnWidth := (pageWidth - 2*marginMM - columnGapMM) / 2
+
+	var left []englishLine
+	for _, part := range []englishLine{{fmt.Sprintf("%d. %s", n, clause.Title), true}, {clause.Text, false}} {
+		style := ""
+		if part.bold {
+			style = "B"
+		}
+		r.pdf.SetFont(fontFamily, style, 11)
+		for _, line := range r.pdf.SplitLines([]byte(r.text(part.text)), columnWidth) {
+			left = append(left, englishLine{string(line), part.bold})
+		}
+	}
+	var right []scriptLine
+	for _, line := range r.script.wrap(fmt.Sprintf("%d. %s", n, clause.Translation.Title), true, 11, columnWidth) {
+		right = append(right, scriptLine{line, true})
+	}
+	for _, line := range r.script.wrap(clause.Translation.Text, false, 11, columnWidth) {
+		right = append(right, scriptLine{line, false})
+	}
+
+	for {
+		r.ensureSpace(scriptLineHeightMM)
+		y := r.pdf.GetY()
+		available := pageHeight - footerHeightMM - y
+		leftCount := min(len(left), int(available/lineHeightMM))
+		rightCount := min(len(right), int(available/scriptLineHeightMM))
+
+		for i, line := range left[:leftCount] {
+			style := ""
+			if line.bold {
+				style = "B"
+			}
+			r.pdf.SetFont(fontFamily, style, 11)
+			r.pdf.SetXY(marginMM, y+float64(i)*lineHeightMM)
+			r.pdf.CellFormat(columnWidth, lineHeightMM, line.text, "", 0, "L", false, 0, "")
+		}
+		for i, line := range right[:rightCount] {
+			r.script.drawLine(line.words, line.bold, 11, marginMM+columnWidth+columnGapMM,
+				y+float64(i)*scriptLineHeightMM+scriptBaselineMM)
+		}
+
+		left, right = left[leftCount:], right[rightCount:]
+		r.pdf.SetY(y + max(float64(leftCount)*lineHeightMM, float64(rightCount)*scriptLineHeightMM))
+		if len(left) == 0 && len(right) == 0 {
+			break
+		}
+		r.pdf.AddPage()
+	}
+	r.pdf.Ln(2)
+}
+
+func (r *renderer) schedule() {
+	p := r.doc.Property
+
+	address := p.Address.Formatted()
+	if p.UnitNumber != "" {
+		address = "Unit " + p.UnitNumber + ", " + address
+	}
+	area := fmt.Sprintf("%.0f sq. ft. carpet", p.CarpetAreaSqft)
+	if p.BuiltUpAreaSqft != nil {
+		area += fmt.Sprintf(", %.0f sq. ft. built-up", *p.BuiltUpAreaSqft)
+	}
+	floor := fmt.Sprintf("%d", p.Floor)
+	if p.TotalFloors != nil {
+		floor += fmt.Sprintf(" of %d", *p.TotalFloors)
+	}
+
+	rows := [][2]string{
+		{"Address", address},
+		{"Type", label(p.PropertyType)},
+	}
+	if p.BHK > 0 {
+		rows = append(rows, [2]string{"Configuration", fmt.Sprintf("%d BHK", p.BHK)})
+	}
+	rows = append(rows,
+		[2]string{"Area", area},
+		[2]string{"Floor", floor},
+		[2]string{"Furnishing", label(p.Furnishing)},
+		[2]string{"Parking", label(p.Parking)},
+	)
+
+	r.heading("SCHEDULE OF PROPERTY")
+	r.paragraph("All that premises, together with the fittings and fixtures therein, described as follows:")
+	r.table(rows)
+}
+
+func (r *renderer) signatures() {
+	r.heading("SIGNATURES")
+	r.paragraph("IN WITNESS WHEREOF the parties have signed this agreement on the date and at the place first written above.")
+
+	for _, p := range r.allParties() {
+		r.signatureBlock(p.role, p.user.Name)
+	}
+	for i := range 2 {
+		var name string
+		if i < len(r.doc.Witnesses) {
+			name = r.doc.Witnesses[i]
+		}
+		r.signatureBlock(fmt.Sprintf("Witness %d", i+1), name)
+	}
+}
+
+func (r *renderer) signatureBlock(role, name string) {
+	const height = 28.0
+	r.ensureSpace(height)
+
+	x, y := r.pdf.GetXY()
+	r.pdf.SetFont(fontFamily, "B", 11)
+	r.pdf.CellFormat(0, lineHeightMM, r.text(role), "", 1, "L", false, 0, "")
+
+	r.pdf.SetFont(fontFamily, "", 11)
+	if name == "" {
+		name = "______________________________"
+	}
+	r.pdf.CellFormat(0, lineHeightMM, r.text("Name: "+name), "", 1, "L", false, 0, "")
+	r.pdf.CellFormat(0, lineHeightMM, "Date: ________________", "", 1, "L", false, 0, "")
+
+	pageWidth, _ := r.pdf.GetPageSize()
+	boxWidth := 70.0
+	r.pdf.Rect(pageWidth-marginMM-boxWidth, y, boxWidth, 20, "D")
+	r.pdf.SetFont(fontFamily, "I", 8)
+	r.pdf.SetXY(pageWidth-marginMM-boxWidth, y+20)
+	r.pdf.CellFormat(boxWidth, 4, "Signature", "", 0, "C", false, 0, "")
+
+	r.pdf.SetXY(x, y+height)
+}
+
+// certificate appends the audit trail of an online signing on a new page
+func (r *renderer) certificate() {
+	signing := r.doc.Signing
+	if signing == nil {
+		return
+	}
+
+	r.pdf.AddPage()
+	r.pdf.SetFont(fontFamily, "B", 14)
+	r.pdf.CellFormat(0, 10, "AUDIT CERTIFICATE", "", 1, "C", false, 0, "")
+	r.paragraph("This certificate records the electronic signing of the agreement above. The SHA-256 digest identifies " +
+		"the document exactly as it was presented to every party for signature.")
+
+	order := "All parties in parallel"
+	if signing.Sequential {
+		order = "One party after another, in the order below"
+	}
+	rows := [][2]string{
+		{"Lease", r.doc.Lease.ID.String()},
+		{"Document SHA-256", signing.DocumentSHA256},
+		{"Signing order", order},
+		{"Started", auditTime(signing.CreatedAt)},
+	}
+	if signing.CompletedAt != nil {
+		rows = append(rows, [2]string{"Completed", auditTime(*signing.CompletedAt)})
+	}
+	r.table(rows)
+
+	r.heading("SIGNATORIES")
+	names := make(map[string]string, len(signing.Signers))
+	for _, signer := range signing.Signers {
+		names[signer.ID.String()] = signer.Name
+		r.signatory(signer)
+	}
+
+	r.heading("EVENT LOG")
+	var events [][2]string
+	for _, e := range signing.Events {
+		description := label(e.Event)
+		if e.SignerID != nil {
+			description += ": " + names[e.SignerID.String()]
+		}
+		if e.Detail != "" {
+			description += ". " + e.Detail
+		}
+		if e.IPAddress != "" {
+			description += ". IP " + e.IPAddress
+		}
+		events = append(events, [2]string{auditTime(e.CreatedAt), description})
+	}
+	r.table(events)
+}
+
+// signatory prints a signer's captured signature and where it was made from
+func (r *renderer) signatory(signer model.LeaseSigner) {
+	const (
+		height   = 30.0
+		boxWidth = 60.0
+	)
+	r.ensureSpace(height)
+
+	x, y := r.pdf.GetXY()
+	pageWidth, _ := r.pdf.GetPageSize()
+	textWidth := pageWidth - 2*marginMM - boxWidth - 5
+
+	r.pdf.SetFont(fontFamily, "B", 11)
+	r.pdf.MultiCell(textWidth, lineHeightMM, r.text(label(signer.Role)+": "+signer.Name), "", "L", false)
+	r.pdf.SetFont(fontFamily, "", 9)
+	var details []string
+	if signer.SignedAt != nil {
+		details = append(details, "Signed "+auditTime(*signer.SignedAt))
+	}
+	if signer.IPAddress != "" {
+		details = append(details, "IP "+signer.IPAddress)
+	}
+	if signer.SignatureType == model.SignatureTypeESign {
+		details = append(details, "Aadhaar eSign by "+signer.SignatureData)
+	}
+	if signer.UserAgent != "" {
+		details = append(details, signer.UserAgent)
+	}
+	r.pdf.MultiCell(textWidth, 4, r.text(strings.Join(details, "\n")), "", "L", false)
+
+	boxX := pageWidth - marginMM - boxWidth
+	r.pdf.Rect(boxX, y, boxWidth, 22, "D")
+	switch signer.SignatureType {
+	case model.SignatureTypeDrawn:
+		r.signatureImage(signer, boxX+1, y+1, boxWidth-2, 20)
+	case model.SignatureTypeTyped:
+		r.pdf.SetFont(fontFamily, "BI", 16)
+		r.pdf.SetXY(boxX, y+6)
+		r.pdf.CellFormat(boxWidth, 10, r.text(signer.SignatureData), "", 0, "C", false, 0, "")
+	case model.SignatureTypeESign:
+		r.pdf.SetFont(fontFamily, "B", 12)
+		r.pdf.SetXY(boxX, y+4)
+		r.pdf.CellFormat(boxWidth, 7, "Aadhaar eSign", "", 2, "C", false, 0, "")
+		r.pdf.SetFont(fontFamily, "", 8)
+		r.pdf.CellFormat(boxWidth, 5, "Digitally signed", "", 0, "C", false, 0, "")
+	}
+
+	r.pdf.SetXY(x, y+height)
+}
+
+// signatureImage draws a drawn signature scaled to fit the box, keeping its proportions
+func (r *renderer) signatureImage(signer model.LeaseSigner, x, y, w, h float64) {
+	data, err := base64.StdEncoding.DecodeString(signer.SignatureData)
+	if err != nil {
+		return
+	}
+
+	name := "signature-" + signer.ID.String()
+	info := r.pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(data))
+	if info == nil || info.Width() == 0 || info.Height() == 0 {
+		return
+	}
+
+	scale := min(w/info.Width(), h/info.Height())
+	iw, ih := info.Width()*scale, info.Height()*scale
+	r.pdf.ImageOptions(name, x+(w-iw)/2, y+(h-ih)/2, iw, ih, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+}
+
+func auditTime(t time.Time) string {
+	return t.In(ist).Format("2 Jan 2006 15:04:05 MST")
+}
+
+// footer draws an initials box per party and the page number on every page
+func (r *renderer) footer() {
+	pageWidth, pageHeight := r.pdf.GetPageSize()
+	parties := r.allParties()
+
+	width := pageWidth - 2*marginMM
+	if v := r.doc.Verification; v != nil {
+		width -= qrCodeMM + 3
+		r.pdf.ImageOptions(qrImage, pageWidth-marginMM-qrCodeMM, pageHeight-footerHeightMM+6, qrCodeMM, qrCodeMM,
+			false, fpdf.ImageOptions{ImageType: "PNG"}, 0, v.URL)
+		r.pdf.SetFont(fontFamily, "", 7)
+		r.pdf.SetXY(marginMM, pageHeight-marginMM+9)
+		line := fmt.Sprintf("Verify this document at %s (code %s)", v.URL, v.Code)
+		if r.doc.Version > 0 {
+			line = fmt.Sprintf("Version %d. %s", r.doc.Version, line)
+		}
+		r.pdf.CellFormat(width, 4, r.text(line), "", 0, "C", false, 0, "")
+	} else if r.script != nil {
+		r.pdf.SetFont(fontFamily, "", 7)
+		r.pdf.SetXY(marginMM, pageHeight-marginMM+9)
+		r.pdf.CellFormat(width, 4, india.LanguageName(r.doc.Lang)+" translation for reference. The English agreement prevails.",
+			"", 0, "C", false, 0, "")
+	}
+
+	boxWidth := initialsBoxMM
+	if n := float64(len(parties)); n > 0 && n*(boxWidth+3) > width {
+		boxWidth = width/n - 3
+	}
+
+	y := pageHeight - footerHeightMM + 6
+	x := marginMM
+	r.pdf.SetFont(fontFamily, "", 7)
+	for _, p := range parties {
+		r.pdf.Rect(x, y, boxWidth, 10, "D")
+		r.pdf.SetXY(x, y+10)
+		r.pdf.CellFormat(boxWidth, 3.5, r.text(p.role), "", 0, "C", false, 0, "")
+		x += boxWidth + 3
+	}
+
+	r.pdf.SetFont(fontFamily, "", 9)
+	r.pdf.SetXY(marginMM, pageHeight-marginMM+4)
+	r.pdf.CellFormat(width, 5, fmt.Sprintf("Page %d of {nb}", r.pdf.PageNo()), "", 0, "C", false, 0, "")
+}
+
+// allParties lists the owners then the tenants, labelled for signature and initials boxes
+func (r *renderer) allParties() []party {
+	var parties []party
+	for i, owner := range r.doc.Owners {
+		parties = append(parties, party{user: owner, role: numbered("Owner", i, len(r.doc.Owners))})
+	}
+	for i, tenant := range r.doc.Tenants {
+		parties = append(parties, party{user: tenant, role: numbered("Tenant", i, len(r.doc.Tenants))})
+	}
+	return parties
+}
+
+func numbered(role string, i, count int) string {
+	if count == 1 {
+		return role
+	}
+	return fmt.Sprintf("%s %d", role, i+1)
+}
+
+// label turns a stored value such as "semi_furnished" into "Semi furnished"
+func label(value string) string {
+	if value == "" {
+		return "-"
+	}
+	value = strings.ReplaceAll(value, "_", " ")
+	return strings.ToUpper(value[:1]) + value[1:]
+}
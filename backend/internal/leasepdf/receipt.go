@@ -0,0 +1,125 @@
+package leasepdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/clausetext"
+	"backend/internal/model"
+	"backend/pkg/inr"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// Receipt is everything printed on the rent receipt for a payment
+type Receipt struct {
+	Lease    *model.Lease
+	Property *model.Property
+	Owners   []model.User
+	Tenants  []model.User
+	// Payment is the payment entry on the lease's ledger
+	Payment     *model.JournalEntry
+	AmountPaise int64
+	// Verification, when set, is printed in the footer as a QR code
+	Verification *Verification
+	// GeneratedAt is the PDF creation date
+	GeneratedAt time.Time
+}
+
+// RenderReceipt returns the receipt as a PDF
+func RenderReceipt(receipt *Receipt, opts Options) ([]byte, error) {
+	if opts.PaperSize != PaperLegal {
+		opts.PaperSize = PaperA4
+	}
+
+	pdf := fpdf.New("P", "mm", opts.PaperSize, "")
+	pdf.SetMargins(marginMM, marginMM, marginMM)
+	pdf.SetAutoPageBreak(true, footerHeightMM)
+	pdf.SetTitle("Rent Receipt", true)
+	pdf.SetCatalogSort(true)
+	if !receipt.GeneratedAt.IsZero() {
+		pdf.SetCreationDate(receipt.GeneratedAt.UTC())
+		pdf.SetModificationDate(receipt.GeneratedAt.UTC())
+	}
+
+	r := &renderer{
+		pdf: pdf,
+		tr:  pdf.UnicodeTranslatorFromDescriptor(""),
+		doc: &Document{Verification: receipt.Verification},
+	}
+	pdf.SetFooterFunc(r.receiptFooter)
+	if receipt.Verification != nil {
+		qr, err := qrcode.Encode(receipt.Verification.URL, qrcode.Medium, 256)
+		if err != nil {
+			return nil, fmt.Errorf("render verification qr code: %w", err)
+		}
+		pdf.RegisterImageOptionsReader(qrImage, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	}
+
+	pdf.AddPage()
+	pdf.SetFont(fontFamily, "B", 16)
+	pdf.CellFormat(0, 10, "RENT RECEIPT", "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	payment := receipt.Payment
+	p := receipt.Property
+	address := p.Address.Formatted()
+	if p.UnitNumber != "" {
+		address = "Unit " + p.UnitNumber + ", " + address
+	}
+	r.paragraph(fmt.Sprintf("Received with thanks from %s the sum of %s (%s only) on %s towards rent and charges for the premises at %s, let under the rent agreement for the period %s to %s.",
+		names(receipt.Tenants), inr.FormatWithSymbol(receipt.AmountPaise), inr.Words(receipt.AmountPaise),
+		payment.OccurredOn.Format(clausetext.DateLayout), address,
+		receipt.Lease.StartDate.Format(clausetext.DateLayout), receipt.Lease.EndDate.Format(clausetext.DateLayout)))
+
+	rows := [][2]string{
+		{"Amount", inr.FormatWithSymbol(receipt.AmountPaise)},
+		{"Paid on", payment.OccurredOn.Format(clausetext.DateLayout)},
+	}
+	if payment.Reference != "" {
+		rows = append(rows, [2]string{"Reference", payment.Reference})
+	}
+	if payment.Description != "" {
+		rows = append(rows, [2]string{"Particulars", payment.Description})
+	}
+	rows = append(rows,
+		[2]string{"Received from", names(receipt.Tenants)},
+		[2]string{"Received by", names(receipt.Owners)},
+	)
+	r.table(rows)
+
+	pdf.Ln(lineHeightMM)
+	r.signatureBlock("For the Owner", names(receipt.Owners))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render receipt pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// receiptFooter prints the verification QR code
+func (r *renderer) receiptFooter() {
+	v := r.doc.Verification
+	if v == nil {
+		return
+	}
+	pageWidth, pageHeight := r.pdf.GetPageSize()
+	width := pageWidth - 2*marginMM - qrCodeMM - 3
+	r.pdf.ImageOptions(qrImage, pageWidth-marginMM-qrCodeMM, pageHeight-footerHeightMM+6, qrCodeMM, qrCodeMM,
+		false, fpdf.ImageOptions{ImageType: "PNG"}, 0, v.URL)
+	r.pdf.SetFont(fontFamily, "", 7)
+	r.pdf.SetXY(marginMM, pageHeight-marginMM+9)
+	r.pdf.CellFormat(width, 4, r.text(fmt.Sprintf("Verify this receipt at %s (code %s)", v.URL, v.Code)), "", 0, "C", false, 0, "")
+}
+
+func names(users []model.User) string {
+	list := make([]string, len(users))
+	for i, user := range users {
+		list[i] = user.Name
+	}
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,317 @@
+package leasepdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/font"
+	"github.com/go-text/typesetting/language"
+	"github.com/go-text/typesetting/shaping"
+	"golang.org/x/image/math/fixed"
+)
+
+// ErrFontMissing is returned when a document asks for a script whose fonts are not installed
+var ErrFontMissing = errors.New("no font installed for script")
+
+// fontFiles names the Noto family each script is drawn with. Latin covers the
+// English words, digits and rupee sign that translated clauses still contain.
+var fontFiles = map[string]string{
+	"Latn": "NotoSans",
+	"Deva": "NotoSansDevanagari",
+	"Knda": "NotoSansKannada",
+	"Taml": "NotoSansTamil",
+}
+
+// weightStyles are the fpdf styles of the regular and bold faces
+var weightStyles = [2]string{"", "B"}
+
+const (
+	// scriptLineHeightMM leaves room for the vowel signs above and below Indic letters
+	scriptLineHeightMM = 7.0
+	scriptBaselineMM   = 5.0
+)
+
+// Fonts are the OpenType fonts text in Indian scripts is drawn with, by ISO
+// 15924 script code. They are parsed once and shared by every render.
+type Fonts struct {
+	regular map[string]*fontFile
+	bold    map[string]*fontFile
+}
+
+// fontFile is a parsed font and the copy of it embedded in the PDF
+type fontFile struct {
+	font *font.Font
+	// data is the font with a character for every glyph, see withGlyphCodes
+	data []byte
+	// codes holds the character each glyph is written as, by glyph ID
+	codes []rune
+}
+
+// LoadFonts reads <family>-Regular.ttf and, when present, <family>-Bold.ttf
+// for each script from dir. Scripts without a font are left out; an empty dir
+// loads none, so leases render in English only.
+func LoadFonts(dir string) (*Fonts, error) {
+	f := &Fonts{regular: map[string]*fontFile{}, bold: map[string]*fontFile{}}
+	if dir == "" {
+		return f, nil
+	}
+
+	for script, family := range fontFiles {
+		regular, err := loadFont(filepath.Join(dir, family+"-Regular.ttf"))
+		if err != nil {
+			return nil, err
+		}
+		if regular == nil {
+			continue
+		}
+		f.regular[script] = regular
+
+		bold, err := loadFont(filepath.Join(dir, family+"-Bold.ttf"))
+		if err != nil {
+			return nil, err
+		}
+		if bold == nil {
+			bold = regular
+		}
+		f.bold[script] = bold
+	}
+	return f, nil
+}
+
+// loadFont parses the font at path, returning nil if there is no such file
+func loadFont(path string) (*fontFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read font: %w", err)
+	}
+	return parseFont(filepath.Base(path), data)
+}
+
+func parseFont(name string, data []byte) (*fontFile, error) {
+	face, err := font.ParseTTF(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse font %s: %w", name, err)
+	}
+	embedded, codes, err := withGlyphCodes(data, face.Font)
+	if err != nil {
+		return nil, fmt.Errorf("parse font %s: %w", name, err)
+	}
+	return &fontFile{font: face.Font, data: embedded, codes: codes}, nil
+}
+
+// Supports reports whether text in script can be drawn
+func (f *Fonts) Supports(script string) bool {
+	if f == nil {
+		return false
+	}
+	_, ok := f.regular[script]
+	_, latin := f.regular["Latn"]
+	return ok && latin
+}
+
+// scriptWriter draws text in an Indian script. The core PDF fonts cannot
+// show it and fpdf's TrueType support maps one rune to one glyph, which
+// breaks conjuncts and vowel signs, so the text is shaped with HarfBuzz and
+// each glyph is placed where the shaper put it. The fonts are embedded with
+// a character for every glyph: the one it stands for, or a Private Use
+// character for conjuncts and other forms that stand for none. Each word is
+// marked with its text as ActualText, so it can be selected and searched.
+type scriptWriter struct {
+	pdf    *fpdf.Fpdf
+	script language.Script
+	lang   language.Language
+	// faces are the script's regular and bold faces followed by Latin's; faces
+	// are not safe for concurrent use, so every render makes its own
+	faces [2][2]*font.Face
+	files [2][2]*fontFile
+	// families are the fpdf font families the files are registered as
+	families [2]string
+	shaper   shaping.HarfbuzzShaper
+}
+
+// shapedWord is a word laid out as glyph runs. Widths and positions are in
+// ems, so the same word can be drawn at any size.
+type shapedWord struct {
+	text  string
+	runs  []shapedRun
+	width float64
+}
+
+type shapedRun struct {
+	// font indexes faces and files, as script or Latin and then weight
+	font   [2]int
+	glyphs []shaping.Glyph
+}
+
+func newScriptWriter(pdf *fpdf.Fpdf, fonts *Fonts, script, lang string) (*scriptWriter, error) {
+	if !fonts.Supports(script) {
+		return nil, fmt.Errorf("%w %s", ErrFontMissing, script)
+	}
+	sc, err := language.ParseScript(script)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &scriptWriter{
+		pdf:    pdf,
+		script: sc,
+		lang:   language.NewLanguage(lang),
+	}
+	for i, s := range []string{script, "Latn"} {
+		w.files[i] = [2]*fontFile{fonts.regular[s], fonts.bold[s]}
+		w.families[i] = "Noto" + s
+		for weight, file := range w.files[i] {
+			w.faces[i][weight] = font.NewFace(file.font)
+			pdf.AddUTF8FontFromBytes(w.families[i], weightStyles[weight], file.data)
+		}
+	}
+	return w, nil
+}
+
+// wrap breaks text into lines no wider than width millimetres at sizePt.
+// Line breaks in the text are kept.
+func (w *scriptWriter) wrap(text string, bold bool, sizePt, width float64) [][]shapedWord {
+	emMM := sizePt * 25.4 / 72
+	space := w.spaceWidth(bold)
+
+	var lines [][]shapedWord
+	for _, paragraph := range strings.Split(text, "\n") {
+		var (
+			line      []shapedWord
+			lineWidth float64
+		)
+		for _, field := range strings.Fields(paragraph) {
+			word := w.shape([]rune(field), bold)
+			if len(line) > 0 && (lineWidth+space+word.width)*emMM > width {
+				lines = append(lines, line)
+				line, lineWidth = nil, 0
+			}
+			if len(line) > 0 {
+				lineWidth += space
+			}
+			line = append(line, word)
+			lineWidth += word.width
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// drawLine draws the words of a line from x with their baseline at y
+func (w *scriptWriter) drawLine(line []shapedWord, bold bool, sizePt, x, y float64) {
+	emMM := sizePt * 25.4 / 72
+	space := w.spaceWidth(bold)
+
+	pen := 0.0
+	for i, word := range line {
+		text := word.text
+		if i < len(line)-1 {
+			text += " "
+		}
+		w.pdf.RawWriteStr(actualText(text))
+		for _, run := range word.runs {
+			face, file := w.faces[run.font[0]][run.font[1]], w.files[run.font[0]][run.font[1]]
+			w.pdf.SetFont(w.families[run.font[0]], weightStyles[run.font[1]], sizePt)
+			upem := float64(face.Upem()) * 64
+			for _, g := range run.glyphs {
+				gx := x + (pen+float64(g.XOffset)/upem)*emMM
+				gy := y - float64(g.YOffset)/upem*emMM
+				if int(g.GlyphID) < len(file.codes) && file.codes[g.GlyphID] != 0 {
+					w.pdf.Text(gx, gy, string(file.codes[g.GlyphID]))
+				}
+				pen += float64(g.XAdvance) / upem
+			}
+		}
+		w.pdf.RawWriteStr("EMC")
+		pen += space
+	}
+}
+
+// actualText opens a marked-content span whose text is s, for the glyphs
+// drawn until the matching EMC
+func actualText(s string) string {
+	var b strings.Builder
+	b.WriteString("/Span <</ActualText <FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">>> BDC")
+	return b.String()
+}
+
+// shape lays out a word, drawing each rune the script's font lacks, such as
+// Latin letters, with the Latin font. Combining marks and joiners stay with
+// the letter before them.
+func (w *scriptWriter) shape(text []rune, bold bool) shapedWord {
+	weight := 0
+	if bold {
+		weight = 1
+	}
+
+	word := shapedWord{text: string(text)}
+	start, current := 0, -1
+	flush := func(end int) {
+		if current < 0 || end <= start {
+			return
+		}
+		face := w.faces[current][weight]
+		script := w.script
+		if current == 1 {
+			script = language.Latin
+		}
+		out := w.shaper.Shape(shaping.Input{
+			Text:      text,
+			RunStart:  start,
+			RunEnd:    end,
+			Direction: di.DirectionLTR,
+			Face:      face,
+			Size:      fixed.I(int(face.Upem())),
+			Script:    script,
+			Language:  w.lang,
+		})
+		word.runs = append(word.runs, shapedRun{font: [2]int{current, weight}, glyphs: out.Glyphs})
+		word.width += float64(out.Advance) / 64 / float64(face.Upem())
+	}
+
+	for i, r := range text {
+		choice := 0
+		if _, ok := w.faces[0][weight].NominalGlyph(r); !ok {
+			if _, ok := w.faces[1][weight].NominalGlyph(r); ok {
+				choice = 1
+			}
+		}
+		if current >= 0 && unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me, unicode.Cf) {
+			choice = current
+		}
+		if choice != current {
+			flush(i)
+			start, current = i, choice
+		}
+	}
+	flush(len(text))
+	return word
+}
+
+// spaceWidth is the width of a space in ems
+func (w *scriptWriter) spaceWidth(bold bool) float64 {
+	face := w.faces[0][0]
+	if bold {
+		face = w.faces[0][1]
+	}
+	gid, ok := face.NominalGlyph(' ')
+	if !ok {
+		return 0.25
+	}
+	return float64(face.HorizontalAdvance(gid)) / float64(face.Upem())
+}
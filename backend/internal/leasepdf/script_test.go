@@ -0,0 +1,111 @@
+package leasepdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/go-text/typesetting/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestWithGlyphCodes(t *testing.T) {
+	original, err := font.ParseTTF(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		t.Fatalf("parse font: %v", err)
+	}
+	data, codes, err := withGlyphCodes(goregular.TTF, original.Font)
+	if err != nil {
+		t.Fatalf("withGlyphCodes: %v", err)
+	}
+	rewritten, err := font.ParseTTF(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("parse rewritten font: %v", err)
+	}
+
+	// The whole file sums to the magic number once checkSumAdjustment is set
+	if sum := tableChecksum(data); sum != 0xB1B0AFBA {
+		t.Errorf("file checksum = %#x, want 0xB1B0AFBA", sum)
+	}
+	for it := original.Cmap.Iter(); it.Next(); {
+		r, gid := it.Char()
+		if r < 0x20 || r >= 0xFFFF {
+			continue
+		}
+		if got, _ := rewritten.NominalGlyph(r); got != gid {
+			t.Errorf("%U maps to glyph %d, want %d as before", r, got, gid)
+		}
+	}
+
+	privateUse := 0
+	for gid := 1; gid < len(codes); gid++ {
+		code := codes[gid]
+		if code >= privateUseFirst && code <= privateUseLast {
+			privateUse++
+		}
+		if got, ok := rewritten.NominalGlyph(code); !ok || int(got) != gid {
+			t.Errorf("glyph %d is written as %U, which maps to glyph %d", gid, code, got)
+		}
+	}
+	if privateUse == 0 {
+		t.Error("no glyph was given a Private Use character; the test font should have unmapped glyphs")
+	}
+}
+
+func TestScriptWriterSelectableText(t *testing.T) {
+	regular, err := parseFont("Go-Regular.ttf", goregular.TTF)
+	if err != nil {
+		t.Fatalf("parseFont: %v", err)
+	}
+	bold, err := parseFont("Go-Bold.ttf", gobold.TTF)
+	if err != nil {
+		t.Fatalf("parseFont: %v", err)
+	}
+	// The Go fonts stand in for the Noto ones; only Latin letters are drawn
+	fonts := &Fonts{
+		regular: map[string]*fontFile{"Deva": regular, "Latn": regular},
+		bold:    map[string]*fontFile{"Deva": bold, "Latn": bold},
+	}
+
+	render := func() []byte {
+		pdf := fpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(false)
+		pdf.SetCatalogSort(true)
+		pdf.SetCreationDate(time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC))
+		pdf.AddPage()
+		w, err := newScriptWriter(pdf, fonts, "Deva", "hi")
+		if err != nil {
+			t.Fatalf("newScriptWriter: %v", err)
+		}
+		for i, line := range w.wrap("Rent is due monthly", true, 11, 170) {
+			w.drawLine(line, true, 11, 20, 30+float64(i)*scriptLineHeightMM)
+		}
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatalf("Output: %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	out := render()
+	for _, want := range []string{
+		actualText("Rent "),
+		actualText("monthly"),
+		"/Subtype /Type0",
+		"/ToUnicode",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+	// "R" is drawn as itself, so its text is found even without ActualText
+	if !bytes.Contains(out, binary.BigEndian.AppendUint16([]byte("Td ("), 'R')) {
+		t.Error("PDF does not draw R as its own character")
+	}
+	if !bytes.Equal(out, render()) {
+		t.Error("rendering twice gave different bytes")
+	}
+}
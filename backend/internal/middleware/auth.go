@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"strings"
+
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/apperr"
+	"backend/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	currentUserKey    = "current_user"
+	currentSessionKey = "current_session"
+)
+
+// Auth verifies the bearer access token and stores the authenticated user in the context
+func Auth(authService service.AuthService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			scheme, token, found := strings.Cut(header, " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+				return response.FromError(c, apperr.Unauthorized("Missing or malformed bearer token", nil))
+			}
+
+			principal, err := authService.Authenticate(c.Request().Context(), strings.TrimSpace(token))
+			if err != nil {
+				return response.FromError(c, err)
+			}
+
+			c.Set(currentUserKey, principal.User)
+			c.Set(currentSessionKey, principal.SessionID)
+			return next(c)
+		}
+	}
+}
+
+// CurrentUser returns the user authenticated by the Auth middleware, or nil
+func CurrentUser(c echo.Context) *model.User {
+	user, _ := c.Get(currentUserKey).(*model.User)
+	return user
+}
+
+// CurrentSessionID returns the session of the authenticated access token, or uuid.Nil
+func CurrentSessionID(c echo.Context) uuid.UUID {
+	sessionID, _ := c.Get(currentSessionKey).(uuid.UUID)
+	return sessionID
+}
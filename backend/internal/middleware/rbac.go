@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"slices"
+
+	"backend/pkg/apperr"
+	"backend/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireRole rejects requests from users whose role is not listed. It must run
+// after Auth. Use it for coarse route-level gating; per-resource checks belong
+// in the service layer via the policy package.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user := CurrentUser(c)
+			if user == nil {
+				return response.FromError(c, apperr.Unauthorized("Authentication required", nil))
+			}
+			if !user.IsAdmin() && !slices.Contains(roles, user.Role) {
+				return response.FromError(c, apperr.Forbidden("Your role cannot access this resource", nil))
+			}
+			return next(c)
+		}
+	}
+}
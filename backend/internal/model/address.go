@@ -0,0 +1,28 @@
+package model
+
+import (
+	"strings"
+
+	"backend/pkg/india"
+)
+
+// Address is a postal address in India. State holds the ISO 3166-2:IN code.
+type Address struct {
+	AddressLine1 string `json:"address_line1" gorm:"type:varchar(255);not null"`
+	AddressLine2 string `json:"address_line2" gorm:"type:varchar(255);not null;default:''"`
+	Locality     string `json:"locality" gorm:"type:varchar(100);not null;default:''"`
+	City         string `json:"city" gorm:"type:varchar(100);not null"`
+	State        string `json:"state" gorm:"type:char(2);not null"`
+	Pincode      string `json:"pincode" gorm:"type:char(6);not null"`
+}
+
+// Formatted returns the address on one line, e.g. "12 MG Road, Indiranagar, Bengaluru, Karnataka - 560038"
+func (a Address) Formatted() string {
+	var parts []string
+	for _, part := range []string{a.AddressLine1, a.AddressLine2, a.Locality, a.City, india.StateName(a.State)} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ") + " - " + a.Pincode
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BankStatementImport is a bank statement file imported to reconcile rent
+type BankStatementImport struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Bank           string    `json:"bank" gorm:"type:varchar(30);not null"`
+	FileName       string    `json:"file_name" gorm:"type:varchar(255);not null;default:''"`
+	FileSHA256     string    `json:"file_sha256" gorm:"column:file_sha256;type:char(64);not null"`
+	FormatsVersion string    `json:"formats_version" gorm:"type:varchar(20);not null"`
+	CreditCount    int       `json:"credit_count" gorm:"not null;default:0"`
+	DebitCount     int       `json:"debit_count" gorm:"not null;default:0"`
+	// UnreadableCount is how many rows were skipped because their amount could not be read
+	UnreadableCount int `json:"unreadable_count" gorm:"not null;default:0"`
+	// DuplicateCount is how many credits had been imported before
+	DuplicateCount       int       `json:"duplicate_count" gorm:"not null;default:0"`
+	MatchedCount         int       `json:"matched_count" gorm:"not null;default:0"`
+	ReviewCount          int       `json:"review_count" gorm:"not null;default:0"`
+	UnmatchedCount       int       `json:"unmatched_count" gorm:"not null;default:0"`
+	AlreadyRecordedCount int       `json:"already_recorded_count" gorm:"not null;default:0"`
+	CreatedAt            time.Time `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (i *BankStatementImport) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+func (BankStatementImport) TableName() string {
+	return "bank_statement_imports"
+}
+
+// Bank transaction statuses
+const (
+	// BankTransactionMatched has been posted to the lease's ledger as a payment
+	BankTransactionMatched = "matched"
+	// BankTransactionReview matched too weakly or too many leases to be posted and awaits the owner's choice
+	BankTransactionReview    = "review"
+	BankTransactionUnmatched = "unmatched"
+	// BankTransactionIgnored was marked by the owner as not rent
+	BankTransactionIgnored = "ignored"
+	// BankTransactionAlreadyRecorded was on the ledger under its reference before it was imported
+	BankTransactionAlreadyRecorded = "already_recorded"
+)
+
+// BankTransactionStatuses lists every bank transaction status
+var BankTransactionStatuses = []string{
+	BankTransactionMatched,
+	BankTransactionReview,
+	BankTransactionUnmatched,
+	BankTransactionIgnored,
+	BankTransactionAlreadyRecorded,
+}
+
+// BankTransaction is a credit read from an imported bank statement
+type BankTransaction struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID       uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	ImportID     uuid.UUID `json:"import_id" gorm:"type:uuid;not null"`
+	StatementRow int       `json:"statement_row" gorm:"not null"`
+	TxnDate      time.Time `json:"txn_date" gorm:"type:date;not null"`
+	AmountPaise  int64     `json:"amount_paise" gorm:"not null"`
+	Narration    string    `json:"narration" gorm:"type:text;not null;default:''"`
+	Reference    string    `json:"reference,omitempty" gorm:"type:varchar(100);not null;default:''"`
+	UTR          string    `json:"utr,omitempty" gorm:"column:utr;type:varchar(30);not null;default:''"`
+	PayerName    string    `json:"payer_name,omitempty" gorm:"type:varchar(100);not null;default:''"`
+	// Fingerprint tells a credit apart from the others of the user's statements
+	Fingerprint    string     `json:"-" gorm:"type:varchar(100);not null"`
+	Status         string     `json:"status" gorm:"type:varchar(20);not null"`
+	LeaseID        *uuid.UUID `json:"lease_id,omitempty" gorm:"type:uuid"`
+	RentDueID      *uuid.UUID `json:"rent_due_id,omitempty" gorm:"type:uuid"`
+	JournalEntryID *uuid.UUID `json:"journal_entry_id,omitempty" gorm:"type:uuid"`
+	// MatchScore and MatchReason explain the best match found, out of 100
+	MatchScore  int        `json:"match_score" gorm:"not null;default:0"`
+	MatchReason string     `json:"match_reason,omitempty" gorm:"type:text;not null;default:''"`
+	ReviewedBy  *uuid.UUID `json:"reviewed_by,omitempty" gorm:"type:uuid"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null;default:now()"`
+
+	Candidates []BankMatchCandidate `json:"candidates,omitempty" gorm:"foreignKey:TransactionID"`
+}
+
+func (t *BankTransaction) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (BankTransaction) TableName() string {
+	return "bank_transactions"
+}
+
+// BankMatchCandidate is a lease and due a credit that was not posted may be for
+type BankMatchCandidate struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TransactionID uuid.UUID  `json:"transaction_id" gorm:"type:uuid;not null"`
+	LeaseID       uuid.UUID  `json:"lease_id" gorm:"type:uuid;not null"`
+	RentDueID     *uuid.UUID `json:"rent_due_id,omitempty" gorm:"type:uuid"`
+	Score         int        `json:"score" gorm:"not null"`
+	// Reasons says what matched, separated by semicolons
+	Reasons string `json:"reasons" gorm:"type:text;not null;default:''"`
+}
+
+func (c *BankMatchCandidate) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+func (BankMatchCandidate) TableName() string {
+	return "bank_match_candidates"
+}
+
+// ConfirmBankMatchRequest names the lease a credit is for. The due is kept for
+// the record; the payment is spread over the lease's dues oldest first.
+type ConfirmBankMatchRequest struct {
+	LeaseID   string `json:"lease_id" validate:"required,uuid"`
+	RentDueID string `json:"rent_due_id" validate:"omitempty,uuid"`
+}
+
+// ImportBankStatementRequest holds the form fields sent with the statement file
+type ImportBankStatementRequest struct {
+	Bank string `form:"bank" validate:"required,max=30"`
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ChargeFrequencyMonthly   = "monthly"
+	ChargeFrequencyQuarterly = "quarterly"
+	ChargeFrequencyAnnual    = "annual"
+)
+
+// Building is an independent house, apartment block or society whose units
+// are rented out separately. Each unit is a Property with BuildingID set and
+// shares the building's address.
+type Building struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OwnerID     uuid.UUID  `json:"owner_id" gorm:"type:uuid;not null"`
+	ManagerID   *uuid.UUID `json:"manager_id,omitempty" gorm:"type:uuid"`
+	Name        string     `json:"name" gorm:"type:varchar(255);not null"`
+	SocietyName string     `json:"society_name" gorm:"type:varchar(255);not null;default:''"`
+
+	Address
+
+	TotalFloors *int      `json:"total_floors,omitempty" gorm:"type:smallint"`
+	CreatedAt   time.Time `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"not null;default:now()"`
+
+	Charges []BuildingCharge `json:"charges,omitempty" gorm:"foreignKey:BuildingID"`
+}
+
+func (b *Building) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+func (Building) TableName() string {
+	return "buildings"
+}
+
+// BuildingCharge is a recurring society or maintenance charge levied on every unit
+type BuildingCharge struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BuildingID  uuid.UUID `json:"building_id" gorm:"type:uuid;not null"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null"`
+	AmountPaise int64     `json:"amount_paise" gorm:"not null"` // per unit, per period
+	Frequency   string    `json:"frequency" gorm:"type:varchar(20);not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"not null;default:now()"`
+}
+
+func (c *BuildingCharge) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+func (BuildingCharge) TableName() string {
+	return "building_charges"
+}
+
+// BuildingDocument is a file shared by all units, e.g. the society NOC or occupancy certificate
+type BuildingDocument struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BuildingID   uuid.UUID `json:"building_id" gorm:"type:uuid;not null"`
+	Title        string    `json:"title" gorm:"type:varchar(255);not null"`
+	DocumentType string    `json:"document_type" gorm:"type:varchar(30);not null"`
+	FileName     string    `json:"file_name" gorm:"type:varchar(255);not null"`
+	ContentType  string    `json:"content_type" gorm:"type:varchar(100);not null"`
+	SizeBytes    int64     `json:"size_bytes" gorm:"not null"`
+	StorageKey   string    `json:"-" gorm:"type:varchar(500);not null"`
+	UploadedBy   uuid.UUID `json:"uploaded_by" gorm:"type:uuid;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (d *BuildingDocument) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+func (BuildingDocument) TableName() string {
+	return "building_documents"
+}
+
+// BuildingOccupancy aggregates the occupancy status of a building's units
+type BuildingOccupancy struct {
+	BuildingID    uuid.UUID `json:"building_id"`
+	TotalUnits    int64     `json:"total_units"`
+	Occupied      int64     `json:"occupied"`
+	Vacant        int64     `json:"vacant"`
+	OccupancyRate float64   `json:"occupancy_rate"` // percentage, 0-100
+}
+
+type CreateBuildingRequest struct {
+	Name         string `json:"name" validate:"required,min=2,max=255"`
+	SocietyName  string `json:"society_name" validate:"omitempty,max=255"`
+	AddressLine1 string `json:"address_line1" validate:"required,max=255"`
+	AddressLine2 string `json:"address_line2" validate:"omitempty,max=255"`
+	Locality     string `json:"locality" validate:"omitempty,max=100"`
+	City         string `json:"city" validate:"required,max=100"`
+	State        string `json:"state" validate:"required,indian_state"`
+	Pincode      string `json:"pincode" validate:"required,pincode"`
+	TotalFloors  *int   `json:"total_floors" validate:"omitempty,gte=1,lte=200"`
+	// OwnerID lets an admin create a building on behalf of an owner; ignored for other roles
+	OwnerID string `json:"owner_id" validate:"omitempty,uuid"`
+}
+
+type UpdateBuildingRequest struct {
+	Name         string  `json:"name" validate:"omitempty,min=2,max=255"`
+	SocietyName  *string `json:"society_name" validate:"omitempty,max=255"`
+	AddressLine1 string  `json:"address_line1" validate:"omitempty,max=255"`
+	AddressLine2 *string `json:"address_line2" validate:"omitempty,max=255"`
+	Locality     *string `json:"locality" validate:"omitempty,max=100"`
+	City         string  `json:"city" validate:"omitempty,max=100"`
+	State        string  `json:"state" validate:"omitempty,indian_state"`
+	Pincode      string  `json:"pincode" validate:"omitempty,pincode"`
+	TotalFloors  *int    `json:"total_floors" validate:"omitempty,gte=1,lte=200"`
+}
+
+// CreateUnitRequest adds a rentable unit to a building. The address, owner
+// and manager are taken from the building.
+type CreateUnitRequest struct {
+	UnitNumber      string   `json:"unit_number" validate:"required,max=20"`
+	Name            string   `json:"name" validate:"omitempty,min=2,max=255"`
+	PropertyType    string   `json:"property_type" validate:"required,oneof=apartment flat condo villa"`
+	BHK             int      `json:"bhk" validate:"gte=0,lte=20"`
+	CarpetAreaSqft  float64  `json:"carpet_area_sqft" validate:"required,gt=0"`
+	BuiltUpAreaSqft *float64 `json:"built_up_area_sqft" validate:"omitempty,gt=0"`
+	Furnishing      string   `json:"furnishing" validate:"required,oneof=unfurnished semi_furnished fully_furnished"`
+	Floor           int      `json:"floor" validate:"gte=-5,lte=200"`
+	Parking         string   `json:"parking" validate:"omitempty,oneof=none two_wheeler four_wheeler both"`
+}
+
+type CreateBuildingChargeRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	AmountPaise int64  `json:"amount_paise" validate:"required,gt=0"`
+	Frequency   string `json:"frequency" validate:"required,oneof=monthly quarterly annual"`
+}
+
+// UploadBuildingDocumentRequest holds the form fields sent with a document upload
+type UploadBuildingDocumentRequest struct {
+	Title        string `form:"title" validate:"required,max=255"`
+	DocumentType string `form:"document_type" validate:"required,oneof=society_noc occupancy_certificate property_tax sale_deed maintenance_receipt other"`
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ClauseCategoryRent        = "rent"
+	ClauseCategoryDeposit     = "deposit"
+	ClauseCategoryMaintenance = "maintenance"
+	ClauseCategoryPets        = "pets"
+	ClauseCategorySubletting  = "subletting"
+	ClauseCategoryLockIn      = "lock_in"
+	ClauseCategoryTermination = "termination"
+)
+
+// ClauseCategories lists the categories in the order their clauses appear in a lease
+var ClauseCategories = []string{
+	ClauseCategoryRent,
+	ClauseCategoryDeposit,
+	ClauseCategoryMaintenance,
+	ClauseCategoryPets,
+	ClauseCategorySubletting,
+	ClauseCategoryLockIn,
+	ClauseCategoryTermination,
+}
+
+// Clause is an entry in the clause library. System clauses have no owner and
+// are available to everyone; custom clauses belong to the owner who wrote them.
+// The wording lives in ClauseVersion so that leases keep the text they were drafted with.
+type Clause struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OwnerID          *uuid.UUID `json:"owner_id,omitempty" gorm:"type:uuid"`
+	Category         string     `json:"category" gorm:"type:varchar(20);not null"`
+	Title            string     `json:"title" gorm:"type:varchar(255);not null"`
+	IsMandatory      bool       `json:"is_mandatory" gorm:"not null;default:false"`
+	CurrentVersionID *uuid.UUID `json:"current_version_id,omitempty" gorm:"type:uuid"`
+	ArchivedAt       *time.Time `json:"archived_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"not null;default:now()"`
+
+	CurrentVersion *ClauseVersion `json:"current_version,omitempty" gorm:"foreignKey:CurrentVersionID"`
+}
+
+func (c *Clause) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+func (Clause) TableName() string {
+	return "clauses"
+}
+
+// IsSystem reports whether the clause is part of the standard library
+func (c *Clause) IsSystem() bool {
+	return c.OwnerID == nil
+}
+
+// ClauseVersion is an immutable revision of a clause's wording
+type ClauseVersion struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ClauseID  uuid.UUID  `json:"clause_id" gorm:"type:uuid;not null"`
+	Version   int        `json:"version" gorm:"not null"`
+	Body      string     `json:"body" gorm:"type:text;not null"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (v *ClauseVersion) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+func (ClauseVersion) TableName() string {
+	return "clause_versions"
+}
+
+// ClauseTranslation is a revision of the wording of a clause version in
+// another language. It belongs to the version it translates, so leases
+// drafted with a later version fall back to English until that version is
+// translated too. Revisions are never changed: an edit adds the next one, and
+// removing the translation adds a withdrawn one. The latest is current.
+type ClauseTranslation struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ClauseVersionID uuid.UUID `json:"clause_version_id" gorm:"type:uuid;not null"`
+	Lang            string    `json:"lang" gorm:"type:varchar(5);not null"`
+	Revision        int       `json:"revision" gorm:"not null"`
+	Title           string    `json:"title" gorm:"type:varchar(255);not null"`
+	Body            string    `json:"body" gorm:"type:text;not null"`
+	// Withdrawn marks the translation removed; leases render the clause in English
+	Withdrawn bool       `json:"withdrawn" gorm:"not null;default:false"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null;default:now()"`
+
+	// Version is the number of the clause version translated
+	Version int `json:"version" gorm:"->;-:migration"`
+}
+
+func (t *ClauseTranslation) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (ClauseTranslation) TableName() string {
+	return "clause_translations"
+}
+
+type CreateClauseRequest struct {
+	Category string `json:"category" validate:"required,oneof=rent deposit maintenance pets subletting lock_in termination"`
+	Title    string `json:"title" validate:"required,min=3,max=255"`
+	Body     string `json:"body" validate:"required,min=10,max=5000"`
+	// IsMandatory may only be set by admins on system clauses
+	IsMandatory bool `json:"is_mandatory"`
+}
+
+type UpdateClauseRequest struct {
+	Category    string `json:"category" validate:"omitempty,oneof=rent deposit maintenance pets subletting lock_in termination"`
+	Title       string `json:"title" validate:"omitempty,min=3,max=255"`
+	Body        string `json:"body" validate:"omitempty,min=10,max=5000"`
+	IsMandatory *bool  `json:"is_mandatory"`
+}
+
+type TranslateClauseRequest struct {
+	Title string `json:"title" validate:"required,min=3,max=255"`
+	Body  string `json:"body" validate:"required,min=10,max=10000"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// How serious a compliance finding is. Errors block saving a lease and
+// sending it for signatures; warnings are shown with the lease.
+const (
+	ComplianceError   = "error"
+	ComplianceWarning = "warning"
+)
+
+// ComplianceFinding is one way a lease falls short of the tenancy law of
+// the property's state
+type ComplianceFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Act      string `json:"act"`
+}
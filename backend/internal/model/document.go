@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of generated document
+const (
+	// DocumentKindLease is the agreement as printed, before any online signing
+	DocumentKindLease = "lease"
+	// DocumentKindLeaseSigning is the agreement as presented for online signature
+	DocumentKindLeaseSigning = "lease_signing"
+	// DocumentKindLeaseSigned is the agreement with its audit certificate once everyone signed
+	DocumentKindLeaseSigned = "lease_signed"
+	// DocumentKindReceipt is the rent receipt for a payment on the ledger
+	DocumentKindReceipt = "receipt"
+)
+
+// Document records the SHA-256 of a generated PDF under a public
+// verification code, which is printed on the document as a QR code.
+// Documents are never updated.
+type Document struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Code      string     `json:"code" gorm:"type:varchar(16);not null;uniqueIndex"`
+	Kind      string     `json:"kind" gorm:"type:varchar(20);not null"`
+	LeaseID   uuid.UUID  `json:"lease_id" gorm:"type:uuid;not null"`
+	SigningID *uuid.UUID `json:"signing_id,omitempty" gorm:"type:uuid"`
+	SHA256    string     `json:"sha256" gorm:"column:sha256;type:varchar(64);not null"`
+	// ContentSHA256 identifies what a printed lease says, whatever its layout
+	ContentSHA256 string `json:"-" gorm:"column:content_sha256;type:varchar(64);not null;default:''"`
+	// StampMarginMM is the blank space a printed lease was laid out with
+	StampMarginMM  *int       `json:"-"`
+	JournalEntryID *uuid.UUID `json:"journal_entry_id,omitempty" gorm:"type:uuid"`
+	// StorageKey is set for documents kept in storage, which are served as stored
+	StorageKey string     `json:"-" gorm:"type:varchar(500);not null;default:''"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (d *Document) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+func (Document) TableName() string {
+	return "documents"
+}
+
+// DocumentVerification is what anyone holding a verification code is shown.
+// It names the parties but carries no other personal details.
+type DocumentVerification struct {
+	Code        string    `json:"code"`
+	Kind        string    `json:"kind"`
+	SHA256      string    `json:"sha256"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// Superseded is set when the lease has since changed, or the payment of a receipt was reversed
+	Superseded    bool            `json:"superseded"`
+	LeaseStatus   string          `json:"lease_status"`
+	StartDate     time.Time       `json:"start_date"`
+	EndDate       time.Time       `json:"end_date"`
+	PropertyCity  string          `json:"property_city"`
+	PropertyState string          `json:"property_state"`
+	Parties       []DocumentParty `json:"parties"`
+	// SignedAt is when the last party signed, for documents signed online
+	SignedAt *time.Time `json:"signed_at,omitempty"`
+	// AmountPaise and PaidOn are the payment a receipt is for
+	AmountPaise int64      `json:"amount_paise,omitempty"`
+	PaidOn      *time.Time `json:"paid_on,omitempty"`
+}
+
+type DocumentParty struct {
+	Name     string     `json:"name"`
+	Role     string     `json:"role"`
+	SignedAt *time.Time `json:"signed_at,omitempty"`
+}
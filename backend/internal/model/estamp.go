@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EStamp is the e-stamp certificate a lease is executed on, with a scan of the certificate
+type EStamp struct {
+	ID                uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID           uuid.UUID `json:"lease_id" gorm:"type:uuid;not null"`
+	CertificateNumber string    `json:"certificate_number" gorm:"type:varchar(30);not null"`
+	State             string    `json:"state" gorm:"type:varchar(2);not null"`
+	DenominationPaise int64     `json:"denomination_paise" gorm:"not null"`
+	Purchaser         string    `json:"purchaser" gorm:"type:varchar(255);not null"`
+	IssueDate         time.Time `json:"issue_date" gorm:"type:date;not null"`
+	FileName          string    `json:"file_name" gorm:"type:varchar(255);not null"`
+	ContentType       string    `json:"content_type" gorm:"type:varchar(100);not null"`
+	SizeBytes         int64     `json:"size_bytes" gorm:"not null"`
+	StorageKey        string    `json:"-" gorm:"type:varchar(500);not null"`
+	VerifiedAt        time.Time `json:"verified_at" gorm:"not null"`
+	CreatedBy         uuid.UUID `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt         time.Time `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (e *EStamp) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+func (EStamp) TableName() string {
+	return "lease_estamps"
+}
+
+// AttachEStampRequest holds the form fields sent with the scanned certificate
+type AttachEStampRequest struct {
+	CertificateNumber string `form:"certificate_number" validate:"required,max=30"`
+	State             string `form:"state" validate:"required,indian_state"`
+	DenominationPaise int64  `form:"denomination_paise" validate:"required,gt=0"`
+	Purchaser         string `form:"purchaser" validate:"required,max=255"`
+	IssueDate         string `form:"issue_date" validate:"required,datetime=2006-01-02"`
+}
@@ -0,0 +1,247 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	LeaseStatusDraft             = "draft"
+	LeaseStatusPendingSignatures = "pending_signatures"
+	LeaseStatusSigned            = "signed"
+	LeaseStatusActive            = "active"
+	LeaseStatusNoticePeriod      = "notice_period"
+	LeaseStatusTerminated        = "terminated"
+	LeaseStatusExpired           = "expired"
+	LeaseStatusRenewed           = "renewed"
+)
+
+// How the rent changes when a lease is renewed
+const (
+	EscalationNone    = "none"
+	EscalationPercent = "percent"
+	EscalationFixed   = "fixed"
+)
+
+// What the premises are let for; tenancy law caps the deposit differently for each
+const (
+	PremisesResidential    = "residential"
+	PremisesNonResidential = "non_residential"
+)
+
+// Lease is a leave-and-licence or rent agreement for a property. Amounts are in paise.
+type Lease struct {
+	ID                   uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	PropertyID           uuid.UUID `json:"property_id" gorm:"type:uuid;not null"`
+	OwnerID              uuid.UUID `json:"owner_id" gorm:"type:uuid;not null"`
+	Status               string    `json:"status" gorm:"type:varchar(30);not null;default:'draft'"`
+	StartDate            time.Time `json:"start_date" gorm:"type:date;not null"`
+	EndDate              time.Time `json:"end_date" gorm:"type:date;not null"`
+	TermMonths           int       `json:"term_months" gorm:"type:smallint;not null"`
+	MonthlyRentPaise     int64     `json:"monthly_rent_paise" gorm:"not null"`
+	SecurityDepositPaise int64     `json:"security_deposit_paise" gorm:"not null;default:0"`
+	MaintenancePaise     int64     `json:"maintenance_paise" gorm:"not null;default:0"` // monthly, payable with rent
+	RentDueDay           int       `json:"rent_due_day" gorm:"type:smallint;not null"`
+	RentCycleMonths      int       `json:"rent_cycle_months" gorm:"type:smallint;not null;default:1"` // months of rent due at a time: 1 monthly, 3 quarterly
+	NoticePeriodDays     int       `json:"notice_period_days" gorm:"type:smallint;not null"`
+	LockInMonths         int       `json:"lock_in_months" gorm:"type:smallint;not null;default:0"`
+	CreatedBy            uuid.UUID `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt            time.Time `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt            time.Time `json:"updated_at" gorm:"not null;default:now()"`
+
+	// Set when either party serves notice
+	NoticeGivenAt *time.Time `json:"notice_given_at,omitempty"`
+	NoticeGivenBy *uuid.UUID `json:"notice_given_by,omitempty" gorm:"type:uuid"`
+	VacateBy      *time.Time `json:"vacate_by,omitempty" gorm:"type:date"`
+
+	// Rent escalation on renewal: a percentage in basis points (500 = 5%) or a fixed amount
+	EscalationType        string `json:"escalation_type" gorm:"type:varchar(10);not null;default:'none'"`
+	EscalationBasisPoints int    `json:"escalation_basis_points" gorm:"not null;default:0"`
+	EscalationAmountPaise int64  `json:"escalation_amount_paise" gorm:"not null;default:0"`
+	// EscalationCompounding applies a percentage to the rent then payable
+	// rather than to the rent of the first lease in the chain of renewals
+	EscalationCompounding bool `json:"escalation_compounding" gorm:"not null;default:false"`
+
+	PremisesUse string `json:"premises_use" gorm:"type:varchar(20);not null;default:'residential'"`
+
+	// RenewalOfID is the lease this one renews; the deposit is carried forward from it
+	RenewalOfID *uuid.UUID `json:"renewal_of_id,omitempty" gorm:"type:uuid"`
+	// RenewalDraftedAt is when a renewal of this lease was first drafted
+	RenewalDraftedAt *time.Time `json:"renewal_drafted_at,omitempty"`
+
+	Property *Property     `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+	Tenants  []LeaseTenant `json:"tenants,omitempty" gorm:"foreignKey:LeaseID"`
+
+	// Compliance lists where the lease falls short of the tenancy law of the property's state
+	Compliance []ComplianceFinding `json:"compliance,omitempty" gorm:"-"`
+}
+
+func (l *Lease) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+func (Lease) TableName() string {
+	return "leases"
+}
+
+// IsClosed reports whether the lease has reached a final state
+func (l *Lease) IsClosed() bool {
+	switch l.Status {
+	case LeaseStatusTerminated, LeaseStatusExpired, LeaseStatusRenewed:
+		return true
+	}
+	return false
+}
+
+// TenantIDs returns the IDs of the lease's tenants. Tenants must be preloaded.
+func (l *Lease) TenantIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(l.Tenants))
+	for _, t := range l.Tenants {
+		ids = append(ids, t.UserID)
+	}
+	return ids
+}
+
+// LeaseTenant is a tenant (licensee) party to a lease
+type LeaseTenant struct {
+	LeaseID   uuid.UUID `json:"lease_id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:now()"`
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (LeaseTenant) TableName() string {
+	return "lease_tenants"
+}
+
+// LeaseTransition records a change of lease status and who made it
+type LeaseTransition struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID    uuid.UUID  `json:"lease_id" gorm:"type:uuid;not null"`
+	Event      string     `json:"event" gorm:"type:varchar(30);not null"`
+	FromStatus string     `json:"from_status" gorm:"type:varchar(30);not null"`
+	ToStatus   string     `json:"to_status" gorm:"type:varchar(30);not null"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty" gorm:"type:uuid"` // nil when the system made the change
+	Reason     string     `json:"reason,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (lt *LeaseTransition) BeforeCreate(tx *gorm.DB) error {
+	if lt.ID == uuid.Nil {
+		lt.ID = uuid.New()
+	}
+	return nil
+}
+
+func (LeaseTransition) TableName() string {
+	return "lease_transitions"
+}
+
+// LeaseClause pins a specific clause version to a lease at a position
+type LeaseClause struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID         uuid.UUID `json:"lease_id" gorm:"type:uuid;not null"`
+	ClauseID        uuid.UUID `json:"clause_id" gorm:"type:uuid;not null"`
+	ClauseVersionID uuid.UUID `json:"clause_version_id" gorm:"type:uuid;not null"`
+	Position        int       `json:"position" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at" gorm:"not null;default:now()"`
+
+	Clause        *Clause        `json:"clause,omitempty" gorm:"foreignKey:ClauseID"`
+	ClauseVersion *ClauseVersion `json:"clause_version,omitempty" gorm:"foreignKey:ClauseVersionID"`
+}
+
+func (lc *LeaseClause) BeforeCreate(tx *gorm.DB) error {
+	if lc.ID == uuid.Nil {
+		lc.ID = uuid.New()
+	}
+	return nil
+}
+
+func (LeaseClause) TableName() string {
+	return "lease_clauses"
+}
+
+type CreateLeaseRequest struct {
+	PropertyID           string `json:"property_id" validate:"required,uuid"`
+	StartDate            string `json:"start_date" validate:"required,datetime=2006-01-02"`
+	TermMonths           int    `json:"term_months" validate:"required,gte=1,lte=120"`
+	MonthlyRentPaise     int64  `json:"monthly_rent_paise" validate:"required,gt=0"`
+	SecurityDepositPaise int64  `json:"security_deposit_paise" validate:"gte=0"`
+	MaintenancePaise     int64  `json:"maintenance_paise" validate:"gte=0"`
+	RentDueDay           int    `json:"rent_due_day" validate:"required,gte=1,lte=28"`
+	RentCycleMonths      int    `json:"rent_cycle_months" validate:"omitempty,gte=1,lte=12"` // defaults to 1 (monthly); 3 is quarterly
+	NoticePeriodDays     int    `json:"notice_period_days" validate:"gte=0,lte=365"`
+	LockInMonths         int    `json:"lock_in_months" validate:"gte=0,lte=120"`
+	// EscalationType defaults to none. Percent escalations are given in basis points (500 = 5%).
+	EscalationType        string `json:"escalation_type" validate:"omitempty,oneof=none percent fixed"`
+	EscalationBasisPoints int    `json:"escalation_basis_points" validate:"gte=0,lte=10000"`
+	EscalationAmountPaise int64  `json:"escalation_amount_paise" validate:"gte=0"`
+	EscalationCompounding bool   `json:"escalation_compounding"`
+	// PremisesUse defaults to residential
+	PremisesUse string `json:"premises_use" validate:"omitempty,oneof=residential non_residential"`
+}
+
+type UpdateLeaseRequest struct {
+	StartDate            string `json:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	TermMonths           *int   `json:"term_months" validate:"omitempty,gte=1,lte=120"`
+	MonthlyRentPaise     *int64 `json:"monthly_rent_paise" validate:"omitempty,gt=0"`
+	SecurityDepositPaise *int64 `json:"security_deposit_paise" validate:"omitempty,gte=0"`
+	MaintenancePaise     *int64 `json:"maintenance_paise" validate:"omitempty,gte=0"`
+	RentDueDay           *int   `json:"rent_due_day" validate:"omitempty,gte=1,lte=28"`
+	RentCycleMonths      *int   `json:"rent_cycle_months" validate:"omitempty,gte=1,lte=12"`
+	NoticePeriodDays     *int   `json:"notice_period_days" validate:"omitempty,gte=0,lte=365"`
+	LockInMonths         *int   `json:"lock_in_months" validate:"omitempty,gte=0,lte=120"`
+	// Escalation fields left out keep their value; changing the type clears the amount of the other type
+	EscalationType        *string `json:"escalation_type" validate:"omitempty,oneof=none percent fixed"`
+	EscalationBasisPoints *int    `json:"escalation_basis_points" validate:"omitempty,gte=0,lte=10000"`
+	EscalationAmountPaise *int64  `json:"escalation_amount_paise" validate:"omitempty,gte=0"`
+	EscalationCompounding *bool   `json:"escalation_compounding"`
+	PremisesUse           *string `json:"premises_use" validate:"omitempty,oneof=residential non_residential"`
+}
+
+// RenewLeaseRequest drafts a renewal starting the day after the lease ends
+type RenewLeaseRequest struct {
+	// TermMonths defaults to the term of the lease being renewed
+	TermMonths *int `json:"term_months" validate:"omitempty,gte=1,lte=120"`
+}
+
+// SetLeaseClausesRequest replaces the clauses of a draft lease; the order of
+// clause_ids is the order they appear in the agreement
+type SetLeaseClausesRequest struct {
+	ClauseIDs []string `json:"clause_ids" validate:"dive,uuid"`
+}
+
+type LeaseTenantRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
+
+// LeaseTransitionRequest carries an optional note recorded with a status change
+type LeaseTransitionRequest struct {
+	Reason string `json:"reason" validate:"omitempty,max=1000"`
+}
+
+// RenderedClause is a lease clause with its variables filled in from the lease terms
+type RenderedClause struct {
+	Position int       `json:"position"`
+	ClauseID uuid.UUID `json:"clause_id"`
+	Category string    `json:"category"`
+	Title    string    `json:"title"`
+	Version  int       `json:"version"`
+	Text     string    `json:"text"`
+
+	// Translation is the clause in the language asked for, when it has been translated
+	Translation *TranslatedClause `json:"translation,omitempty"`
+}
+
+// TranslatedClause is a rendered clause in another language
+type TranslatedClause struct {
+	Lang  string `json:"lang"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
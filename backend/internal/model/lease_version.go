@@ -0,0 +1,146 @@
+package model
+
+import (
+	"time"
+
+	"backend/pkg/textdiff"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LeaseVersion is a snapshot of a draft lease's terms, tenants and clause
+// text with its translations, taken whenever any of them changes. Versions are numbered from 1 for
+// each lease and are never updated.
+type LeaseVersion struct {
+	ID                   uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID              uuid.UUID `json:"lease_id" gorm:"type:uuid;not null"`
+	Number               int       `json:"number" gorm:"not null"`
+	StartDate            time.Time `json:"start_date" gorm:"type:date;not null"`
+	EndDate              time.Time `json:"end_date" gorm:"type:date;not null"`
+	TermMonths           int       `json:"term_months" gorm:"type:smallint;not null"`
+	MonthlyRentPaise     int64     `json:"monthly_rent_paise" gorm:"not null"`
+	SecurityDepositPaise int64     `json:"security_deposit_paise" gorm:"not null"`
+	MaintenancePaise     int64     `json:"maintenance_paise" gorm:"not null"`
+	RentDueDay           int       `json:"rent_due_day" gorm:"type:smallint;not null"`
+	RentCycleMonths      int       `json:"rent_cycle_months" gorm:"type:smallint;not null"`
+	NoticePeriodDays     int       `json:"notice_period_days" gorm:"type:smallint;not null"`
+	LockInMonths         int       `json:"lock_in_months" gorm:"type:smallint;not null"`
+	// Rent escalation on renewal, as on the lease
+	EscalationType        string `json:"escalation_type" gorm:"type:varchar(10);not null"`
+	EscalationBasisPoints int    `json:"escalation_basis_points" gorm:"not null"`
+	EscalationAmountPaise int64  `json:"escalation_amount_paise" gorm:"not null"`
+	EscalationCompounding bool   `json:"escalation_compounding" gorm:"not null"`
+	PremisesUse           string `json:"premises_use" gorm:"type:varchar(20);not null"`
+	// ContentSHA256 covers the terms, tenants, clauses and translations, so an
+	// edit that changes nothing does not start a new version
+	ContentSHA256 string     `json:"content_sha256" gorm:"column:content_sha256;type:varchar(64);not null"`
+	CreatedBy     *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"not null;default:now()"`
+
+	Tenants []LeaseVersionTenant `json:"tenants" gorm:"foreignKey:VersionID"`
+	Clauses []LeaseVersionClause `json:"clauses,omitempty" gorm:"foreignKey:VersionID"`
+	// ClauseTranslations are the translations the clauses had, by position
+	ClauseTranslations []LeaseVersionClauseTranslation `json:"clause_translations,omitempty" gorm:"foreignKey:VersionID"`
+}
+
+func (lv *LeaseVersion) BeforeCreate(tx *gorm.DB) error {
+	if lv.ID == uuid.Nil {
+		lv.ID = uuid.New()
+	}
+	return nil
+}
+
+func (LeaseVersion) TableName() string {
+	return "lease_versions"
+}
+
+// LeaseVersionTenant is a tenant as named in a lease version
+type LeaseVersionTenant struct {
+	VersionID uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null"`
+}
+
+func (LeaseVersionTenant) TableName() string {
+	return "lease_version_tenants"
+}
+
+// LeaseVersionClause is a clause as worded in a lease version. Variables
+// without a value yet are left as placeholders in the text.
+type LeaseVersionClause struct {
+	VersionID       uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	Position        int       `json:"position" gorm:"primaryKey"`
+	ClauseID        uuid.UUID `json:"clause_id" gorm:"type:uuid;not null"`
+	ClauseVersionID uuid.UUID `json:"clause_version_id" gorm:"type:uuid;not null"`
+	ClauseVersion   int       `json:"clause_version" gorm:"not null"`
+	Category        string    `json:"category" gorm:"type:varchar(20);not null"`
+	Title           string    `json:"title" gorm:"type:varchar(255);not null"`
+	Text            string    `json:"text" gorm:"type:text;not null"`
+}
+
+func (LeaseVersionClause) TableName() string {
+	return "lease_version_clauses"
+}
+
+// LeaseVersionClauseTranslation is the translation of a clause as worded in
+// a lease version, copied from the clause translation current at the time
+type LeaseVersionClauseTranslation struct {
+	VersionID     uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	Position      int       `json:"position" gorm:"primaryKey"`
+	Lang          string    `json:"lang" gorm:"type:varchar(5);primaryKey"`
+	TranslationID uuid.UUID `json:"translation_id" gorm:"type:uuid;not null"`
+	Title         string    `json:"title" gorm:"type:varchar(255);not null"`
+	Text          string    `json:"text" gorm:"type:text;not null"`
+}
+
+func (LeaseVersionClauseTranslation) TableName() string {
+	return "lease_version_clause_translations"
+}
+
+// How a clause or tenant differs between two lease versions
+const (
+	VersionChangeAdded     = "added"
+	VersionChangeRemoved   = "removed"
+	VersionChangeChanged   = "changed"
+	VersionChangeMoved     = "moved"
+	VersionChangeUnchanged = "unchanged"
+)
+
+// LeaseVersionDiff is a redline between two versions of a lease
+type LeaseVersionDiff struct {
+	LeaseID uuid.UUID           `json:"lease_id"`
+	From    int                 `json:"from"`
+	To      int                 `json:"to"`
+	Terms   []LeaseTermChange   `json:"terms"`
+	Tenants []LeaseTenantChange `json:"tenants"`
+	Clauses []LeaseClauseChange `json:"clauses"`
+}
+
+// LeaseTermChange is a lease term whose value differs. Values are as stored:
+// amounts in paise and dates as YYYY-MM-DD.
+type LeaseTermChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type LeaseTenantChange struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	Change string    `json:"change"`
+}
+
+// LeaseClauseChange is one clause of either version, in reading order with
+// removed clauses where they used to be. Words is the clause text as a redline.
+type LeaseClauseChange struct {
+	ClauseID          uuid.UUID       `json:"clause_id"`
+	Category          string          `json:"category"`
+	Title             string          `json:"title"`
+	Change            string          `json:"change"`
+	FromPosition      *int            `json:"from_position,omitempty"`
+	ToPosition        *int            `json:"to_position,omitempty"`
+	FromClauseVersion *int            `json:"from_clause_version,omitempty"`
+	ToClauseVersion   *int            `json:"to_clause_version,omitempty"`
+	Words             []textdiff.Edit `json:"words"`
+}
@@ -0,0 +1,227 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Ledger account kinds. Each tenant of a running lease has a receivable and a
+// deposit account, and the owner has one account of each of the other kinds.
+const (
+	// LedgerAccountReceivable is what the tenants owe
+	LedgerAccountReceivable = "tenant_receivable"
+	// LedgerAccountDeposit is the security deposit held for the tenants
+	LedgerAccountDeposit           = "security_deposit"
+	LedgerAccountCash              = "cash"
+	LedgerAccountRentIncome        = "rent_income"
+	LedgerAccountMaintenanceIncome = "maintenance_income"
+	LedgerAccountLateFeeIncome     = "late_fee_income"
+)
+
+// LedgerAccountKinds lists every account kind
+var LedgerAccountKinds = []string{
+	LedgerAccountReceivable,
+	LedgerAccountDeposit,
+	LedgerAccountCash,
+	LedgerAccountRentIncome,
+	LedgerAccountMaintenanceIncome,
+	LedgerAccountLateFeeIncome,
+}
+
+// TenantAccountKinds lists the kinds of account each tenant has
+var TenantAccountKinds = []string{LedgerAccountReceivable, LedgerAccountDeposit}
+
+// Journal entry kinds. Rent charges, rent adjustments and the deposit are
+// posted from the lease's terms; the others are recorded by the owner's side.
+const (
+	JournalRentCharge        = "rent_charge"
+	JournalRentAdjustment    = "rent_adjustment"
+	JournalDepositCharge     = "deposit_charge"
+	JournalDepositTransfer   = "deposit_transfer"
+	JournalPayment           = "payment"
+	JournalLateFee           = "late_fee"
+	JournalMaintenanceCharge = "maintenance_charge"
+	JournalRefund            = "refund"
+	JournalDepositRefund     = "deposit_refund"
+	JournalDepositAdjustment = "deposit_adjustment"
+)
+
+// Where the money of a settled payment or refund came from. Entries recorded
+// by hand, such as cash and cheques, have no source.
+const (
+	JournalSourceBankStatement  = "bank_statement"
+	JournalSourcePaymentGateway = "payment_gateway"
+)
+
+// LedgerAccount is one party's account of a lease's ledger
+type LedgerAccount struct {
+	ID      uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID uuid.UUID `json:"lease_id" gorm:"type:uuid;not null"`
+	Kind    string    `json:"kind" gorm:"type:varchar(30);not null"`
+	// PartyID is the tenant a receivable or deposit account belongs to, and the owner for the rest
+	PartyID   uuid.UUID `json:"party_id" gorm:"type:uuid;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:now()"`
+}
+
+// IsTenantAccount reports whether the account is one of a tenant's
+func (a *LedgerAccount) IsTenantAccount() bool {
+	return slices.Contains(TenantAccountKinds, a.Kind)
+}
+
+func (a *LedgerAccount) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+func (LedgerAccount) TableName() string {
+	return "ledger_accounts"
+}
+
+// JournalEntry is a balanced set of postings. Entries are never changed but
+// for the stamp of their reversal; a mistake is undone by a reversal entry of
+// the same kind.
+type JournalEntry struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID     uuid.UUID `json:"lease_id" gorm:"type:uuid;not null"`
+	Kind        string    `json:"kind" gorm:"type:varchar(30);not null"`
+	OccurredOn  time.Time `json:"occurred_on" gorm:"type:date;not null"`
+	Description string    `json:"description" gorm:"type:text;not null;default:''"`
+	// Reference is the payment's UTR, cheque number or receipt number
+	Reference string `json:"reference,omitempty" gorm:"type:varchar(100);not null;default:''"`
+	// Source is where a settled payment or refund came from; empty when recorded by hand
+	Source string `json:"source,omitempty" gorm:"type:varchar(20);not null;default:''"`
+	// RentDueID is the due a rent charge or adjustment is for
+	RentDueID  *uuid.UUID `json:"rent_due_id,omitempty" gorm:"type:uuid"`
+	ReversesID *uuid.UUID `json:"reverses_id,omitempty" gorm:"type:uuid"`
+	// ReversedByID is the reversal that undid the entry, the one change made to an entry
+	ReversedByID *uuid.UUID `json:"reversed_by_id,omitempty" gorm:"type:uuid"`
+	CreatedBy    *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"` // nil when posted from the lease's terms
+	CreatedAt    time.Time  `json:"created_at" gorm:"not null;default:now()"`
+
+	Postings []LedgerPosting `json:"postings" gorm:"foreignKey:EntryID"`
+}
+
+func (e *JournalEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+func (JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+// LedgerPosting is a debit or a credit to one account
+type LedgerPosting struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	EntryID     uuid.UUID `json:"entry_id" gorm:"type:uuid;not null"`
+	AccountID   uuid.UUID `json:"account_id" gorm:"type:uuid;not null"`
+	DebitPaise  int64     `json:"debit_paise" gorm:"not null;default:0"`
+	CreditPaise int64     `json:"credit_paise" gorm:"not null;default:0"`
+
+	Account *LedgerAccount `json:"account,omitempty" gorm:"foreignKey:AccountID"`
+}
+
+func (p *LedgerPosting) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+func (LedgerPosting) TableName() string {
+	return "ledger_postings"
+}
+
+// LedgerAccountBalance is the balance of one account, positive on the side
+// the account normally carries: debit for the receivable and cash, credit for
+// the deposit and income
+type LedgerAccountBalance struct {
+	Kind         string `json:"kind"`
+	BalancePaise int64  `json:"balance_paise"`
+}
+
+// LedgerBalance sums up a lease's ledger
+type LedgerBalance struct {
+	LeaseID uuid.UUID `json:"lease_id"`
+	// OutstandingPaise is what the tenants owe; negative when they are in credit
+	OutstandingPaise int64 `json:"outstanding_paise"`
+	// DepositHeldPaise is the deposit to be returned to the tenants; any of it
+	// not yet paid is part of what they owe
+	DepositHeldPaise int64                  `json:"deposit_held_paise"`
+	CollectedPaise   int64                  `json:"collected_paise"`
+	Accounts         []LedgerAccountBalance `json:"accounts"`
+	// Tenants breaks what is owed and held down by tenant
+	Tenants []LedgerTenantBalance `json:"tenants"`
+}
+
+// LedgerTenantBalance is what one tenant owes and has on deposit on a lease
+type LedgerTenantBalance struct {
+	LeaseID uuid.UUID `json:"lease_id"`
+	UserID  uuid.UUID `json:"user_id"`
+	// OutstandingPaise is what the tenant owes; negative when they are in credit
+	OutstandingPaise int64 `json:"outstanding_paise"`
+	DepositHeldPaise int64 `json:"deposit_held_paise"`
+}
+
+// TenantLedgerBalance sums up a tenant's own accounts on each of their leases
+type TenantLedgerBalance struct {
+	UserID           uuid.UUID             `json:"user_id"`
+	OutstandingPaise int64                 `json:"outstanding_paise"`
+	DepositHeldPaise int64                 `json:"deposit_held_paise"`
+	Leases           []LedgerTenantBalance `json:"leases"`
+}
+
+// LedgerStatement is the tenants' side of the ledger over a period, or one
+// tenant's, as their account statement shows it
+type LedgerStatement struct {
+	From                *time.Time            `json:"from,omitempty"`
+	To                  *time.Time            `json:"to,omitempty"`
+	OpeningBalancePaise int64                 `json:"opening_balance_paise"`
+	OpeningDepositPaise int64                 `json:"opening_deposit_paise"`
+	Lines               []LedgerStatementLine `json:"lines"`
+	ClosingBalancePaise int64                 `json:"closing_balance_paise"`
+	ClosingDepositPaise int64                 `json:"closing_deposit_paise"`
+}
+
+// LedgerStatementLine is one entry of a statement. Charges and payments move
+// what the tenants owe; deposit movements are shown beside them.
+type LedgerStatementLine struct {
+	EntryID     uuid.UUID  `json:"entry_id"`
+	LeaseID     uuid.UUID  `json:"lease_id"`
+	Date        time.Time  `json:"date"`
+	Kind        string     `json:"kind"`
+	Description string     `json:"description"`
+	Reference   string     `json:"reference,omitempty"`
+	ReversesID  *uuid.UUID `json:"reverses_id,omitempty"`
+	ChargePaise int64      `json:"charge_paise"`
+	PaidPaise   int64      `json:"paid_paise"`
+	// BalancePaise is what the tenants owe after the entry
+	BalancePaise int64 `json:"balance_paise"`
+	// DepositPaise is the change in the deposit held: positive when received, negative when refunded or applied
+	DepositPaise     int64 `json:"deposit_paise"`
+	DepositHeldPaise int64 `json:"deposit_held_paise"`
+}
+
+// PostLedgerEntryRequest records money received or paid out, or a charge, on a lease
+type PostLedgerEntryRequest struct {
+	Kind        string `json:"kind" validate:"required,oneof=payment late_fee maintenance_charge refund deposit_refund deposit_adjustment"`
+	AmountPaise int64  `json:"amount_paise" validate:"required,gt=0"`
+	OccurredOn  string `json:"occurred_on" validate:"omitempty,datetime=2006-01-02"`
+	Description string `json:"description" validate:"max=500"`
+	Reference   string `json:"reference" validate:"max=100"`
+	// TenantID is the tenant the money is from or to, or who is charged;
+	// without one it is shared among the lease's tenants
+	TenantID *uuid.UUID `json:"tenant_id,omitempty"`
+}
+
+// ReverseLedgerEntryRequest gives the reason an entry is reversed
+type ReverseLedgerEntryRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// What an OTP was sent for
+const (
+	OTPPurposeLogin = "login"
+	// OTPPurposePhoneChange confirms a new phone number for the user it was sent for
+	OTPPurposePhoneChange = "phone_change"
+)
+
+type OTPChallenge struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Phone       string     `json:"phone" gorm:"type:varchar(16);not null"`
+	Purpose     string     `json:"purpose" gorm:"type:varchar(20);not null;default:'login'"`
+	UserID      *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"`
+	CodeHash    string     `json:"-" gorm:"type:varchar(64);not null"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int        `json:"max_attempts" gorm:"not null"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
+	ConsumedAt  *time.Time `json:"consumed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (o *OTPChallenge) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+func (OTPChallenge) TableName() string {
+	return "otp_challenges"
+}
+
+type RequestOTPRequest struct {
+	Phone string `json:"phone" validate:"required,min=10,max=20"`
+}
+
+type VerifyOTPRequest struct {
+	Phone      string `json:"phone" validate:"required,min=10,max=20"`
+	Code       string `json:"code" validate:"required,numeric,min=4,max=8"`
+	Name       string `json:"name" validate:"omitempty,min=2,max=100"`
+	Role       string `json:"role" validate:"omitempty,oneof=owner tenant"`
+	DeviceName string `json:"device_name" validate:"omitempty,max=100"`
+}
+
+type OTPRequestResponse struct {
+	Phone       string    `json:"phone"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ResendAfter time.Time `json:"resend_after"`
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PayoutProfile is where a user is paid: the UPI ID rent is collected on
+type PayoutProfile struct {
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	VPA    string    `json:"vpa" gorm:"type:varchar(255);not null"`
+	// PayeeName is the name the payer's UPI app shows, as registered with the bank
+	PayeeName string    `json:"payee_name" gorm:"type:varchar(100);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null;default:now()"`
+}
+
+func (PayoutProfile) TableName() string {
+	return "payout_profiles"
+}
+
+// UPIPaymentRequest is a UPI payment link generated for a rent due. Its
+// transaction reference is carried in the payment note, so an incoming
+// payment can be matched back to the due.
+type UPIPaymentRequest struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID        uuid.UUID  `json:"lease_id" gorm:"type:uuid;not null"`
+	RentDueID      uuid.UUID  `json:"rent_due_id" gorm:"type:uuid;not null"`
+	PayeeVPA       string     `json:"payee_vpa" gorm:"type:varchar(255);not null"`
+	PayeeName      string     `json:"payee_name" gorm:"type:varchar(100);not null"`
+	AmountPaise    int64      `json:"amount_paise" gorm:"not null"`
+	TransactionRef string     `json:"transaction_ref" gorm:"type:varchar(35);not null"`
+	Note           string     `json:"note" gorm:"type:varchar(80);not null"`
+	URI            string     `json:"uri" gorm:"type:text;not null"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (r *UPIPaymentRequest) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (UPIPaymentRequest) TableName() string {
+	return "upi_payment_requests"
+}
+
+type SavePayoutProfileRequest struct {
+	VPA       string `json:"vpa" validate:"required,max=255,upi_vpa"`
+	PayeeName string `json:"payee_name" validate:"required,min=2,max=100"`
+}
+
+// Payment order statuses. A failed order can still be paid: the payer may try
+// again with the same order.
+const (
+	PaymentOrderCreated = "created"
+	PaymentOrderFailed  = "failed"
+	PaymentOrderPaid    = "paid"
+)
+
+// PaymentOrder is an order placed with the payment gateway to collect a rent due
+type PaymentOrder struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID          uuid.UUID  `json:"lease_id" gorm:"type:uuid;not null"`
+	RentDueID        *uuid.UUID `json:"rent_due_id,omitempty" gorm:"type:uuid"`
+	Gateway          string     `json:"gateway" gorm:"type:varchar(30);not null"`
+	GatewayOrderID   string     `json:"gateway_order_id" gorm:"type:varchar(100);not null"`
+	AmountPaise      int64      `json:"amount_paise" gorm:"not null"`
+	Status           string     `json:"status" gorm:"type:varchar(20);not null;default:'created'"`
+	GatewayPaymentID *string    `json:"gateway_payment_id,omitempty" gorm:"type:varchar(100)"`
+	FailureReason    string     `json:"failure_reason,omitempty" gorm:"type:text;not null;default:''"`
+	// RefundedPaise counts refunds still pending with the gateway as well as processed ones
+	RefundedPaise  int64      `json:"refunded_paise" gorm:"not null;default:0"`
+	JournalEntryID *uuid.UUID `json:"journal_entry_id,omitempty" gorm:"type:uuid"`
+	PaidAt         *time.Time `json:"paid_at,omitempty"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"not null;default:now()"`
+
+	// KeyID and CheckoutURL open the gateway's checkout; they are not stored
+	KeyID       string `json:"key_id,omitempty" gorm:"-"`
+	CheckoutURL string `json:"checkout_url,omitempty" gorm:"-"`
+
+	Refunds []PaymentRefund `json:"refunds,omitempty" gorm:"foreignKey:OrderID"`
+}
+
+func (o *PaymentOrder) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+func (PaymentOrder) TableName() string {
+	return "payment_orders"
+}
+
+// Payment refund statuses
+const (
+	// PaymentRefundPending has been asked of the gateway, which has not yet confirmed it
+	PaymentRefundPending   = "pending"
+	PaymentRefundProcessed = "processed"
+	// PaymentRefundFailed was rejected by the gateway, with the reason
+	PaymentRefundFailed = "failed"
+)
+
+// PaymentRefund is money returned to the payer of an order through the gateway.
+// It is stored as pending before the gateway is asked, with its ID as the
+// idempotency key, and is on the ledger once processed.
+type PaymentRefund struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID         uuid.UUID  `json:"order_id" gorm:"type:uuid;not null"`
+	Status          string     `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	GatewayRefundID *string    `json:"gateway_refund_id,omitempty" gorm:"type:varchar(100)"`
+	AmountPaise     int64      `json:"amount_paise" gorm:"not null"`
+	Reason          string     `json:"reason" gorm:"type:text;not null"`
+	FailureReason   string     `json:"failure_reason,omitempty" gorm:"type:text;not null;default:''"`
+	JournalEntryID  *uuid.UUID `json:"journal_entry_id,omitempty" gorm:"type:uuid"`
+	CreatedBy       *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"not null;default:now()"`
+}
+
+func (r *PaymentRefund) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (PaymentRefund) TableName() string {
+	return "payment_refunds"
+}
+
+// Gateway event statuses
+const (
+	GatewayEventProcessed = "processed"
+	// GatewayEventIgnored is an event stored without effect, with the reason
+	GatewayEventIgnored   = "ignored"
+	GatewayEventDuplicate = "duplicate"
+)
+
+// GatewayEvent is one webhook delivery from the payment gateway
+type GatewayEvent struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Gateway        string     `json:"gateway" gorm:"type:varchar(30);not null"`
+	EventID        string     `json:"event_id" gorm:"type:varchar(100);not null"`
+	EventType      string     `json:"event_type" gorm:"type:varchar(50);not null;default:''"`
+	Payload        string     `json:"payload" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"type:varchar(20);not null"`
+	Reason         string     `json:"reason,omitempty" gorm:"type:text;not null;default:''"`
+	DuplicateOf    *uuid.UUID `json:"duplicate_of,omitempty" gorm:"type:uuid"`
+	PaymentOrderID *uuid.UUID `json:"payment_order_id,omitempty" gorm:"type:uuid"`
+	ReceivedAt     time.Time  `json:"received_at" gorm:"not null;default:now()"`
+}
+
+func (e *GatewayEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+func (GatewayEvent) TableName() string {
+	return "gateway_events"
+}
+
+// ConfirmPaymentRequest is what the gateway's checkout returned to the payer
+type ConfirmPaymentRequest struct {
+	PaymentID string `json:"payment_id" validate:"required,max=100"`
+	Signature string `json:"signature" validate:"required,max=200"`
+}
+
+// RefundPaymentRequest refunds some or all of a paid order; all that is left when amount_paise is omitted
+type RefundPaymentRequest struct {
+	AmountPaise int64  `json:"amount_paise" validate:"omitempty,gt=0"`
+	Reason      string `json:"reason" validate:"required,max=500"`
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PropertyTypeApartment = "apartment"
+	PropertyTypeFlat      = "flat"
+	PropertyTypeCondo     = "condo"
+	PropertyTypeVilla     = "villa"
+)
+
+const (
+	FurnishingUnfurnished = "unfurnished"
+	FurnishingSemi        = "semi_furnished"
+	FurnishingFully       = "fully_furnished"
+)
+
+const (
+	OccupancyVacant   = "vacant"
+	OccupancyOccupied = "occupied"
+)
+
+const (
+	ParkingNone        = "none"
+	ParkingTwoWheeler  = "two_wheeler"
+	ParkingFourWheeler = "four_wheeler"
+	ParkingBoth        = "both"
+)
+
+type Property struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OwnerID      uuid.UUID  `json:"owner_id" gorm:"type:uuid;not null"`
+	ManagerID    *uuid.UUID `json:"manager_id,omitempty" gorm:"type:uuid"`
+	BuildingID   *uuid.UUID `json:"building_id,omitempty" gorm:"type:uuid"`
+	UnitNumber   string     `json:"unit_number,omitempty" gorm:"type:varchar(20);not null;default:''"`
+	Name         string     `json:"name" gorm:"type:varchar(255);not null"`
+	PropertyType string     `json:"property_type" gorm:"type:varchar(20);not null"`
+
+	Address
+
+	BHK             int       `json:"bhk" gorm:"column:bhk;type:smallint;not null"`
+	CarpetAreaSqft  float64   `json:"carpet_area_sqft" gorm:"type:numeric(10,2);not null"`
+	BuiltUpAreaSqft *float64  `json:"built_up_area_sqft,omitempty" gorm:"type:numeric(10,2)"`
+	Furnishing      string    `json:"furnishing" gorm:"type:varchar(20);not null"`
+	Floor           int       `json:"floor" gorm:"type:smallint;not null;default:0"`
+	TotalFloors     *int      `json:"total_floors,omitempty" gorm:"type:smallint"`
+	Parking         string    `json:"parking" gorm:"type:varchar(20);not null;default:'none'"`
+	OccupancyStatus string    `json:"occupancy_status" gorm:"type:varchar(20);not null;default:'vacant'"`
+	CreatedAt       time.Time `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"not null;default:now()"`
+
+	CoOwners []PropertyCoOwner `json:"co_owners,omitempty" gorm:"foreignKey:PropertyID"`
+}
+
+func (p *Property) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+func (Property) TableName() string {
+	return "properties"
+}
+
+// CoOwnerIDs returns the user IDs of the property's co-owners
+func (p *Property) CoOwnerIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(p.CoOwners))
+	for _, co := range p.CoOwners {
+		ids = append(ids, co.UserID)
+	}
+	return ids
+}
+
+type PropertyCoOwner struct {
+	PropertyID uuid.UUID `json:"property_id" gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	CreatedAt  time.Time `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (PropertyCoOwner) TableName() string {
+	return "property_co_owners"
+}
+
+type CreatePropertyRequest struct {
+	Name            string   `json:"name" validate:"required,min=2,max=255"`
+	PropertyType    string   `json:"property_type" validate:"required,oneof=apartment flat condo villa"`
+	AddressLine1    string   `json:"address_line1" validate:"required,max=255"`
+	AddressLine2    string   `json:"address_line2" validate:"omitempty,max=255"`
+	Locality        string   `json:"locality" validate:"omitempty,max=100"`
+	City            string   `json:"city" validate:"required,max=100"`
+	State           string   `json:"state" validate:"required,indian_state"`
+	Pincode         string   `json:"pincode" validate:"required,pincode"`
+	BHK             int      `json:"bhk" validate:"gte=0,lte=20"`
+	CarpetAreaSqft  float64  `json:"carpet_area_sqft" validate:"required,gt=0"`
+	BuiltUpAreaSqft *float64 `json:"built_up_area_sqft" validate:"omitempty,gt=0"`
+	Furnishing      string   `json:"furnishing" validate:"required,oneof=unfurnished semi_furnished fully_furnished"`
+	Floor           int      `json:"floor" validate:"gte=-5,lte=200"`
+	TotalFloors     *int     `json:"total_floors" validate:"omitempty,gte=1,lte=200"`
+	Parking         string   `json:"parking" validate:"omitempty,oneof=none two_wheeler four_wheeler both"`
+	// OwnerID lets an admin create a property on behalf of an owner; ignored for other roles
+	OwnerID string `json:"owner_id" validate:"omitempty,uuid"`
+}
+
+type UpdatePropertyRequest struct {
+	Name            string   `json:"name" validate:"omitempty,min=2,max=255"`
+	PropertyType    string   `json:"property_type" validate:"omitempty,oneof=apartment flat condo villa"`
+	AddressLine1    string   `json:"address_line1" validate:"omitempty,max=255"`
+	AddressLine2    *string  `json:"address_line2" validate:"omitempty,max=255"`
+	Locality        *string  `json:"locality" validate:"omitempty,max=100"`
+	City            string   `json:"city" validate:"omitempty,max=100"`
+	State           string   `json:"state" validate:"omitempty,indian_state"`
+	Pincode         string   `json:"pincode" validate:"omitempty,pincode"`
+	BHK             *int     `json:"bhk" validate:"omitempty,gte=0,lte=20"`
+	CarpetAreaSqft  *float64 `json:"carpet_area_sqft" validate:"omitempty,gt=0"`
+	BuiltUpAreaSqft *float64 `json:"built_up_area_sqft" validate:"omitempty,gt=0"`
+	Furnishing      string   `json:"furnishing" validate:"omitempty,oneof=unfurnished semi_furnished fully_furnished"`
+	Floor           *int     `json:"floor" validate:"omitempty,gte=-5,lte=200"`
+	TotalFloors     *int     `json:"total_floors" validate:"omitempty,gte=1,lte=200"`
+	Parking         string   `json:"parking" validate:"omitempty,oneof=none two_wheeler four_wheeler both"`
+	OccupancyStatus string   `json:"occupancy_status" validate:"omitempty,oneof=vacant occupied"`
+}
+
+type PropertyMemberRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
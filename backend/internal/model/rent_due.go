@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RentDue is the rent and maintenance falling due for one period of a lease.
+// Amounts are in paise.
+type RentDue struct {
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID          uuid.UUID `json:"lease_id" gorm:"type:uuid;not null"`
+	PeriodStart      time.Time `json:"period_start" gorm:"type:date;not null"`
+	PeriodEnd        time.Time `json:"period_end" gorm:"type:date;not null"`
+	DueDate          time.Time `json:"due_date" gorm:"type:date;not null"`
+	RentPaise        int64     `json:"rent_paise" gorm:"not null"`
+	MaintenancePaise int64     `json:"maintenance_paise" gorm:"not null;default:0"`
+	AmountPaise      int64     `json:"amount_paise" gorm:"not null"`
+	PaidPaise        int64     `json:"paid_paise" gorm:"not null;default:0"`
+	// Prorated is set for a period shorter than the rent cycle, at the start or end of the lease
+	Prorated bool `json:"prorated" gorm:"not null;default:false"`
+	// Escalated is set on the first period of a renewal whose rent went up
+	Escalated bool      `json:"escalated" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null;default:now()"`
+}
+
+func (d *RentDue) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+func (RentDue) TableName() string {
+	return "rent_dues"
+}
+
+// OutstandingPaise is what remains to be paid
+func (d *RentDue) OutstandingPaise() int64 {
+	return max(d.AmountPaise-d.PaidPaise, 0)
+}
+
+// RentDueSchedule is the unpaid rent of a lease
+type RentDueSchedule struct {
+	LeaseID uuid.UUID `json:"lease_id"`
+	// Overdue lists unpaid dues whose due date has passed, oldest first
+	Overdue      []RentDue `json:"overdue"`
+	OverduePaise int64     `json:"overdue_paise"`
+	// Upcoming lists the dues still to fall due, soonest first
+	Upcoming []RentDue `json:"upcoming"`
+	// CreditPaise is what the tenants have paid beyond every due, carried to rent not yet scheduled
+	CreditPaise int64 `json:"credit_paise"`
+	// NextEscalationDate is when the rent next goes up, on renewal, if the lease provides for it
+	NextEscalationDate *time.Time `json:"next_escalation_date,omitempty"`
+}
@@ -0,0 +1,20 @@
+package model
+
+// Platform roles. A user's role decides what they may create; access to an
+// existing property or lease is decided by their relation to it (see policy).
+const (
+	RoleOwner   = "owner"
+	RoleCoOwner = "co_owner"
+	RoleManager = "manager"
+	RoleTenant  = "tenant"
+	RoleAdmin   = "admin"
+)
+
+// SignUpRoles are the roles users may choose for themselves. A user becomes
+// a co-owner or manager when an owner adds them to a property or building.
+var SignUpRoles = []string{RoleOwner, RoleTenant}
+
+// IsAdmin reports whether the user is a platform administrator
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
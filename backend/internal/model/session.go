@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	SessionRevokedLogout = "logout"
+	SessionRevokedByUser = "revoked_by_user"
+	SessionRevokedReuse  = "refresh_token_reuse"
+)
+
+type Session struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID           uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	RefreshTokenHash string     `json:"-" gorm:"type:varchar(64);not null"`
+	DeviceName       string     `json:"device_name" gorm:"type:varchar(100);not null;default:''"`
+	UserAgent        string     `json:"user_agent" gorm:"type:varchar(500);not null;default:''"`
+	IPAddress        string     `json:"ip_address" gorm:"type:varchar(45);not null;default:''"`
+	LastUsedAt       time.Time  `json:"last_used_at" gorm:"not null;default:now()"`
+	ExpiresAt        time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason    string     `json:"revoked_reason,omitempty" gorm:"type:varchar(50);not null;default:''"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"not null;default:now()"`
+
+	// Current marks the session the request was made from; not persisted
+	Current bool `json:"current" gorm:"-"`
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// IsActive reports whether the session can still be used at the given time
+func (s *Session) IsActive(at time.Time) bool {
+	return s.RevokedAt == nil && at.Before(s.ExpiresAt)
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
@@ -0,0 +1,207 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	SigningStatusInProgress = "in_progress"
+	SigningStatusCompleted  = "completed"
+	SigningStatusCancelled  = "cancelled"
+)
+
+const (
+	SignerRoleOwner   = "owner"
+	SignerRoleTenant  = "tenant"
+	SignerRoleWitness = "witness"
+)
+
+const (
+	SignerStatusPending = "pending"
+	SignerStatusSigned  = "signed"
+)
+
+const (
+	SignatureTypeDrawn = "drawn"
+	SignatureTypeTyped = "typed"
+	// SignatureTypeESign is an Aadhaar eSign, embedded in a signed copy of the document
+	SignatureTypeESign = "esign"
+)
+
+// Signing events, recorded in the audit trail
+const (
+	SigningEventStarted   = "started"
+	SigningEventLinkSent  = "link_sent"
+	SigningEventViewed    = "viewed"
+	SigningEventSigned    = "signed"
+	SigningEventESign     = "esign_started"
+	SigningEventCompleted = "completed"
+	SigningEventCancelled = "cancelled"
+)
+
+// LeaseSigning is one round of collecting signatures online on a lease. The
+// document every party signs is rendered once when the round starts and
+// stored with its SHA-256.
+type LeaseSigning struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID        uuid.UUID `json:"lease_id" gorm:"type:uuid;not null"`
+	Status         string    `json:"status" gorm:"type:varchar(20);not null;default:'in_progress'"`
+	Sequential     bool      `json:"sequential" gorm:"not null;default:false"`
+	DocumentKey    string    `json:"-" gorm:"type:varchar(500);not null"`
+	DocumentSHA256 string    `json:"document_sha256" gorm:"column:document_sha256;type:varchar(64);not null"`
+	// VersionID is the lease version put up for signature. Any later edit to
+	// the lease voids the round.
+	VersionID   *uuid.UUID `json:"version_id,omitempty" gorm:"type:uuid"`
+	CreatedBy   uuid.UUID  `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null;default:now()"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+
+	Signers []LeaseSigner  `json:"signers,omitempty" gorm:"foreignKey:SigningID"`
+	Events  []SigningEvent `json:"events,omitempty" gorm:"foreignKey:SigningID"`
+}
+
+func (ls *LeaseSigning) BeforeCreate(tx *gorm.DB) error {
+	if ls.ID == uuid.Nil {
+		ls.ID = uuid.New()
+	}
+	return nil
+}
+
+func (LeaseSigning) TableName() string {
+	return "lease_signings"
+}
+
+// Signer returns the signer with the given ID. Signers must be preloaded.
+func (ls *LeaseSigning) Signer(id uuid.UUID) *LeaseSigner {
+	for i := range ls.Signers {
+		if ls.Signers[i].ID == id {
+			return &ls.Signers[i]
+		}
+	}
+	return nil
+}
+
+// NextSigners returns the pending signers who may sign now: all of them, or
+// in sequential signing only the first. Signers must be preloaded in signing order.
+func (ls *LeaseSigning) NextSigners() []*LeaseSigner {
+	var next []*LeaseSigner
+	for i := range ls.Signers {
+		signer := &ls.Signers[i]
+		if signer.Status != SignerStatusPending {
+			continue
+		}
+		if ls.Sequential && len(next) > 0 && signer.SigningOrder != next[0].SigningOrder {
+			break
+		}
+		next = append(next, signer)
+	}
+	return next
+}
+
+// LeaseSigner is a party asked to sign in a signing round. Witnesses need not
+// have an account. The link secret is stored only as a hash and cleared once used.
+type LeaseSigner struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	SigningID     uuid.UUID  `json:"signing_id" gorm:"type:uuid;not null"`
+	Role          string     `json:"role" gorm:"type:varchar(20);not null"`
+	UserID        *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"`
+	Name          string     `json:"name" gorm:"type:varchar(100);not null"`
+	Phone         string     `json:"phone" gorm:"type:varchar(16);not null"`
+	SigningOrder  int        `json:"signing_order" gorm:"type:smallint;not null"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	TokenHash     *string    `json:"-" gorm:"type:varchar(64)"`
+	LinkExpiresAt *time.Time `json:"link_expires_at,omitempty"`
+	SignedAt      *time.Time `json:"signed_at,omitempty"`
+	SignatureType string     `json:"signature_type,omitempty" gorm:"type:varchar(10);not null;default:''"`
+	// SignatureData is the typed name, a base64 PNG of a drawn signature, or
+	// the certificate details of an eSign
+	SignatureData string `json:"-" gorm:"type:text;not null;default:''"`
+	// ESignTxn is the latest eSign transaction started from the signer's link
+	ESignTxn *string `json:"-" gorm:"column:esign_txn;type:varchar(64)"`
+	// SignedDocumentKey is the copy of the document carrying the signer's eSign
+	SignedDocumentKey string    `json:"-" gorm:"type:varchar(500);not null;default:''"`
+	IPAddress         string    `json:"ip_address,omitempty" gorm:"type:varchar(45);not null;default:''"`
+	UserAgent         string    `json:"user_agent,omitempty" gorm:"type:varchar(500);not null;default:''"`
+	CreatedAt         time.Time `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (s *LeaseSigner) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (LeaseSigner) TableName() string {
+	return "lease_signers"
+}
+
+// SigningEvent is an entry in the audit trail of a signing round
+type SigningEvent struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	SigningID uuid.UUID  `json:"signing_id" gorm:"type:uuid;not null"`
+	SignerID  *uuid.UUID `json:"signer_id,omitempty" gorm:"type:uuid"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty" gorm:"type:uuid"`
+	Event     string     `json:"event" gorm:"type:varchar(20);not null"`
+	IPAddress string     `json:"ip_address,omitempty" gorm:"type:varchar(45);not null;default:''"`
+	UserAgent string     `json:"user_agent,omitempty" gorm:"type:varchar(500);not null;default:''"`
+	Detail    string     `json:"detail,omitempty" gorm:"type:text;not null;default:''"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (e *SigningEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+func (SigningEvent) TableName() string {
+	return "lease_signing_events"
+}
+
+type WitnessRequest struct {
+	Name  string `json:"name" validate:"required,min=2,max=100"`
+	Phone string `json:"phone" validate:"required,min=10,max=20"`
+}
+
+// StartSigningRequest starts collecting signatures on a lease awaiting them.
+// Owners sign first, then tenants, then witnesses; with sequential set each
+// party is sent their link only after the previous one has signed.
+type StartSigningRequest struct {
+	Sequential bool             `json:"sequential"`
+	Witnesses  []WitnessRequest `json:"witnesses" validate:"max=2,dive"`
+	// Version is the lease version the owner reviewed. When given, signing
+	// only starts if it is still the latest.
+	Version int `json:"version" validate:"omitempty,min=1"`
+}
+
+type SignRequest struct {
+	SignatureType string `json:"signature_type" validate:"required,oneof=drawn typed"`
+	// Signature is the typed full name, or a base64 PNG (optionally as a data URL) of the drawn signature
+	Signature string `json:"signature" validate:"required,max=500000"`
+	Consent   bool   `json:"consent"`
+}
+
+// SigningView is what a signer sees when opening their link
+type SigningView struct {
+	SignerName     string         `json:"signer_name"`
+	Role           string         `json:"role"`
+	LinkExpiresAt  time.Time      `json:"link_expires_at"`
+	DocumentSHA256 string         `json:"document_sha256"`
+	Lease          *Lease         `json:"lease"`
+	Signers        []SignerStatus `json:"signers"`
+}
+
+// SignerStatus is the progress of another party, without their contact details
+type SignerStatus struct {
+	Name         string     `json:"name"`
+	Role         string     `json:"role"`
+	SigningOrder int        `json:"signing_order"`
+	Status       string     `json:"status"`
+	SignedAt     *time.Time `json:"signed_at,omitempty"`
+}
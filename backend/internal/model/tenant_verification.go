@@ -0,0 +1,111 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Police verification statuses
+const (
+	VerificationStatusPending   = "pending"
+	VerificationStatusSubmitted = "submitted"
+	VerificationStatusVerified  = "verified"
+)
+
+// Identity documents accepted as proof for police verification
+const (
+	IDProofAadhaar        = "aadhaar"
+	IDProofPAN            = "pan"
+	IDProofPassport       = "passport"
+	IDProofVoterID        = "voter_id"
+	IDProofDrivingLicence = "driving_licence"
+)
+
+// TenantVerification holds the details the police ask for to verify a tenant
+// of a lease, and tracks the verification once submitted. For Aadhaar only
+// the last four digits of the number are kept.
+type TenantVerification struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LeaseID          uuid.UUID  `json:"lease_id" gorm:"type:uuid;not null"`
+	UserID           uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	Status           string     `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	FatherName       string     `json:"father_name" gorm:"type:varchar(100);not null"`
+	DateOfBirth      time.Time  `json:"date_of_birth" gorm:"type:date;not null"`
+	PermanentAddress Address    `json:"permanent_address" gorm:"embedded;embeddedPrefix:permanent_"`
+	PreviousAddress  Address    `json:"previous_address" gorm:"embedded;embeddedPrefix:previous_"`
+	IDProofType      string     `json:"id_proof_type" gorm:"type:varchar(20);not null"`
+	IDProofNumber    string     `json:"id_proof_number" gorm:"type:varchar(30);not null"`
+	Occupation       string     `json:"occupation" gorm:"type:varchar(100);not null"`
+	Workplace        string     `json:"workplace" gorm:"type:varchar(255);not null;default:''"`
+	WorkplaceAddress string     `json:"workplace_address" gorm:"type:varchar(500);not null;default:''"`
+	PhotoContentType string     `json:"photo_content_type,omitempty" gorm:"type:varchar(100);not null;default:''"`
+	PhotoStorageKey  string     `json:"-" gorm:"type:varchar(500);not null;default:''"`
+	PoliceStation    string     `json:"police_station" gorm:"type:varchar(255);not null;default:''"`
+	ReferenceNumber  string     `json:"reference_number" gorm:"type:varchar(100);not null;default:''"`
+	SubmittedAt      *time.Time `json:"submitted_at,omitempty"`
+	VerifiedAt       *time.Time `json:"verified_at,omitempty"`
+	UpdatedBy        uuid.UUID  `json:"updated_by" gorm:"type:uuid;not null"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"not null;default:now()"`
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (v *TenantVerification) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+func (TenantVerification) TableName() string {
+	return "tenant_verifications"
+}
+
+// HasPhoto reports whether the tenant's photograph has been uploaded
+func (v *TenantVerification) HasPhoto() bool {
+	return v.PhotoStorageKey != ""
+}
+
+// AddressRequest is a postal address in a request body
+type AddressRequest struct {
+	AddressLine1 string `json:"address_line1" validate:"required,max=255"`
+	AddressLine2 string `json:"address_line2" validate:"omitempty,max=255"`
+	Locality     string `json:"locality" validate:"omitempty,max=100"`
+	City         string `json:"city" validate:"required,max=100"`
+	State        string `json:"state" validate:"required,indian_state"`
+	Pincode      string `json:"pincode" validate:"required,pincode"`
+}
+
+// Address returns the requested address with the state code upper-cased
+func (r AddressRequest) Address() Address {
+	return Address{
+		AddressLine1: r.AddressLine1,
+		AddressLine2: r.AddressLine2,
+		Locality:     r.Locality,
+		City:         r.City,
+		State:        strings.ToUpper(r.State),
+		Pincode:      r.Pincode,
+	}
+}
+
+type SaveTenantVerificationRequest struct {
+	FatherName       string          `json:"father_name" validate:"required,min=2,max=100"`
+	DateOfBirth      string          `json:"date_of_birth" validate:"required,datetime=2006-01-02"`
+	PermanentAddress AddressRequest  `json:"permanent_address" validate:"required"`
+	PreviousAddress  *AddressRequest `json:"previous_address" validate:"omitempty"`
+	IDProofType      string          `json:"id_proof_type" validate:"required,oneof=aadhaar pan passport voter_id driving_licence"`
+	IDProofNumber    string          `json:"id_proof_number" validate:"required,max=30"`
+	Occupation       string          `json:"occupation" validate:"required,max=100"`
+	Workplace        string          `json:"workplace" validate:"omitempty,max=255"`
+	WorkplaceAddress string          `json:"workplace_address" validate:"omitempty,max=500"`
+}
+
+type UpdateVerificationStatusRequest struct {
+	Status          string `json:"status" validate:"required,oneof=pending submitted verified"`
+	PoliceStation   string `json:"police_station" validate:"omitempty,max=255"`
+	ReferenceNumber string `json:"reference_number" validate:"omitempty,max=100"`
+}
@@ -8,12 +8,14 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
-	Email     string    `json:"email" gorm:"type:varchar(255);not null;uniqueIndex"`
-	Role      string    `json:"role" gorm:"type:varchar(20);not null;default:'user'"`
-	CreatedAt time.Time `json:"created_at" gorm:"not null;default:now()"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"not null;default:now()"`
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name         string    `json:"name" gorm:"type:varchar(100);not null"`
+	Email        *string   `json:"email,omitempty" gorm:"type:varchar(255);uniqueIndex"`
+	Phone        *string   `json:"phone,omitempty" gorm:"type:varchar(16);uniqueIndex"`
+	Role         string    `json:"role" gorm:"type:varchar(20);not null;default:'tenant'"`
+	PasswordHash string    `json:"-" gorm:"type:varchar(255);not null;default:''"`
+	CreatedAt    time.Time `json:"created_at" gorm:"not null;default:now()"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"not null;default:now()"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
@@ -28,12 +30,28 @@ func (User) TableName() string {
 }
 
 type CreateUserRequest struct {
-	Name  string `json:"name" validate:"required,min=2,max=100"`
-	Email string `json:"email" validate:"required,email"`
-	Role  string `json:"role" validate:"required,oneof=admin user guest"`
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	Phone    string `json:"phone" validate:"omitempty,min=10,max=20"`
+	Role     string `json:"role" validate:"required,oneof=owner co_owner manager tenant admin"`
 }
 
+// RegisterRequest is used for self sign-up, where only the owner and tenant
+// roles can be chosen
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	Phone    string `json:"phone" validate:"omitempty,min=10,max=20"`
+	Role     string `json:"role" validate:"required,oneof=owner tenant"`
+}
+
+// UpdateUserRequest changes a user. A new phone number needs the code sent
+// to it with POST /users/{id}/phone/otp.
 type UpdateUserRequest struct {
-	Name string `json:"name" validate:"omitempty,min=2,max=100"`
-	Role string `json:"role" validate:"omitempty,oneof=admin user guest"`
+	Name      string `json:"name" validate:"omitempty,min=2,max=100"`
+	Phone     string `json:"phone" validate:"omitempty,min=10,max=20"`
+	PhoneCode string `json:"phone_code" validate:"omitempty,numeric,min=4,max=8"`
+	Role      string `json:"role" validate:"omitempty,oneof=owner co_owner manager tenant admin"`
 }
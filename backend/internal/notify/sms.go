@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"backend/internal/config"
+	"backend/pkg/phone"
+)
+
+// SMSSender delivers text messages to a phone number in E.164 format
+type SMSSender interface {
+	SendSMS(ctx context.Context, to, message string) error
+}
+
+// NewSMSSender returns the SMS sender selected by configuration
+func NewSMSSender(cfg *config.SMSConfig) (SMSSender, error) {
+	switch cfg.Provider {
+	case "log":
+		return NewLogSMSSender(), nil
+	case "file":
+		return NewFileSMSSender(cfg.FilePath), nil
+	default:
+		return nil, fmt.Errorf("unsupported SMS provider: %s", cfg.Provider)
+	}
+}
+
+// LogSMSSender writes messages to the application log instead of sending them
+type LogSMSSender struct{}
+
+func NewLogSMSSender() *LogSMSSender {
+	return &LogSMSSender{}
+}
+
+func (s *LogSMSSender) SendSMS(ctx context.Context, to, message string) error {
+	log.Printf("SMS to %s: %s", phone.Mask(to), message)
+	return nil
+}
+
+// FileSMSSender appends each message as a JSON line to a file, so local
+// tests can read the OTP back without a live SMS gateway
+type FileSMSSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSMSSender(path string) *FileSMSSender {
+	return &FileSMSSender{path: path}
+}
+
+type fileSMSRecord struct {
+	To      string    `json:"to"`
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+func (s *FileSMSSender) SendSMS(ctx context.Context, to, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open SMS outbox: %w", err)
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(fileSMSRecord{
+		To:      to,
+		Message: message,
+		SentAt:  time.Now(),
+	})
+}
@@ -0,0 +1,168 @@
+// Package policeform prints a tenant's police verification form, pre-filled
+// from the lease and the details the tenant gave. Every state police asks for
+// much the same details under its own title and wording; formats.json holds a
+// default format and each state overrides only what its form changes. The
+// formats are versioned; formats.json is embedded as the default and
+// POLICE_FORM_FORMATS_PATH can point to a newer copy.
+package policeform
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+
+	"backend/pkg/india"
+)
+
+//go:embed formats.json
+var defaultFormats []byte
+
+// fieldLabels are the fields a form can print, with their default labels
+var fieldLabels = map[string]string{
+	"owner_name":        "Name of owner",
+	"owner_phone":       "Phone of owner",
+	"owner_email":       "Email of owner",
+	"property_address":  "Address of the rented premises",
+	"lease_start":       "Tenancy from",
+	"monthly_rent":      "Monthly rent",
+	"tenant_name":       "Name of tenant",
+	"father_name":       "Father's name",
+	"date_of_birth":     "Date of birth",
+	"phone":             "Phone",
+	"email":             "Email",
+	"id_proof":          "Identity proof",
+	"co_tenants":        "Other tenants",
+	"permanent_address": "Permanent address",
+	"previous_address":  "Previous address",
+	"occupation":        "Occupation",
+	"workplace":         "Employer / place of work",
+	"workplace_address": "Address of place of work",
+	"police_station":    "Police station",
+}
+
+// File is the parsed formats file. Each state's entry is laid over the
+// default, so it only needs the fields that differ.
+type File struct {
+	Version     string                     `json:"version"`
+	Description string                     `json:"description"`
+	Default     Format                     `json:"default"`
+	States      map[string]json.RawMessage `json:"states"`
+}
+
+// Format is the layout of one state's form
+type Format struct {
+	Title string `json:"title"`
+	// Authority is who the form is addressed to; the police station is added after it
+	Authority string    `json:"authority"`
+	Intro     string    `json:"intro"`
+	Sections  []Section `json:"sections"`
+	// Labels replace the default labels of fields
+	Labels      map[string]string `json:"labels"`
+	Declaration string            `json:"declaration"`
+	// Portal is where the form can also be filed online
+	Portal string `json:"portal"`
+}
+
+// Section is a group of fields printed under a heading
+type Section struct {
+	Heading string   `json:"heading"`
+	Fields  []string `json:"fields"`
+}
+
+// Formats holds the loaded formats
+type Formats struct {
+	version string
+	base    Format
+	states  map[string]Format
+}
+
+// Load reads the formats file at path, or the embedded formats when path is empty
+func Load(path string) (*Formats, error) {
+	data := defaultFormats
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read police form formats: %w", err)
+		}
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse police form formats: %w", err)
+	}
+	if file.Version == "" {
+		return nil, errors.New("police form formats: version is required")
+	}
+	if err := file.Default.validate("default"); err != nil {
+		return nil, err
+	}
+
+	f := &Formats{version: file.Version, base: file.Default, states: make(map[string]Format, len(file.States))}
+	for state, raw := range file.States {
+		if !india.IsStateCode(state) {
+			return nil, fmt.Errorf("police form formats: unknown state %q", state)
+		}
+		// Decoding reuses slices and maps, so lay the state over its own copy
+		// of the default's labels and take the sections only if it has its own
+		format := file.Default
+		format.Sections = nil
+		format.Labels = maps.Clone(file.Default.Labels)
+		if err := json.Unmarshal(raw, &format); err != nil {
+			return nil, fmt.Errorf("parse police form format for %s: %w", state, err)
+		}
+		if format.Sections == nil {
+			format.Sections = file.Default.Sections
+		}
+		if err := format.validate(state); err != nil {
+			return nil, err
+		}
+		f.states[state] = format
+	}
+	return f, nil
+}
+
+func (f *Format) validate(name string) error {
+	if f.Title == "" || f.Authority == "" || f.Declaration == "" {
+		return fmt.Errorf("police form formats: %s needs a title, authority and declaration", name)
+	}
+	if len(f.Sections) == 0 {
+		return fmt.Errorf("police form formats: %s has no sections", name)
+	}
+	for _, section := range f.Sections {
+		for _, field := range section.Fields {
+			if _, ok := fieldLabels[field]; !ok {
+				return fmt.Errorf("police form formats: %s uses unknown field %q", name, field)
+			}
+		}
+	}
+	for field := range f.Labels {
+		if _, ok := fieldLabels[field]; !ok {
+			return fmt.Errorf("police form formats: %s labels unknown field %q", name, field)
+		}
+	}
+	return nil
+}
+
+// Version returns the version of the loaded formats
+func (f *Formats) Version() string {
+	return f.version
+}
+
+// For returns the format of a state's form, or the default format
+func (f *Formats) For(state string) Format {
+	if format, ok := f.states[state]; ok {
+		return format
+	}
+	return f.base
+}
+
+// label returns the label a format prints for a field
+func (f Format) label(field string) string {
+	if label, ok := f.Labels[field]; ok {
+		return label
+	}
+	return fieldLabels[field]
+}
@@ -0,0 +1,282 @@
+package policeform
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/clausetext"
+	"backend/internal/model"
+	"backend/pkg/india"
+	"backend/pkg/inr"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	marginMM       = 18.0
+	footerHeightMM = 18.0
+	lineHeightMM   = 5.5
+	labelWidthMM   = 60.0
+	photoWidthMM   = 35.0
+	photoHeightMM  = 45.0
+	fontFamily     = "Times"
+)
+
+// photoImage names the registered photograph
+const photoImage = "tenant-photo"
+
+// idProofNames are the printed names of identity documents
+var idProofNames = map[string]string{
+	model.IDProofAadhaar:        "Aadhaar",
+	model.IDProofPAN:            "PAN card",
+	model.IDProofPassport:       "Passport",
+	model.IDProofVoterID:        "Voter ID",
+	model.IDProofDrivingLicence: "Driving licence",
+}
+
+// Form is everything printed in a tenant's verification form
+type Form struct {
+	Lease        *model.Lease
+	Property     *model.Property
+	Owner        model.User
+	Tenant       model.User
+	Verification *model.TenantVerification
+	// CoTenants names the lease's other tenants
+	CoTenants []string
+	// Photo is the tenant's photograph, a JPEG or PNG as the verification's
+	// content type says; a box to affix one is printed when it is empty
+	Photo []byte
+	// GeneratedAt is the PDF creation date
+	GeneratedAt time.Time
+}
+
+type renderer struct {
+	pdf    *fpdf.Fpdf
+	tr     func(string) string
+	form   *Form
+	format Format
+}
+
+// Render returns the form in the format of the property's state as a PDF
+func (f *Formats) Render(form *Form) ([]byte, error) {
+	state := form.Property.State
+	format := f.For(state)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(marginMM, marginMM, marginMM)
+	pdf.SetAutoPageBreak(true, footerHeightMM)
+	pdf.AliasNbPages("{nb}")
+	pdf.SetTitle("Tenant Verification Form", true)
+	pdf.SetCatalogSort(true)
+	if !form.GeneratedAt.IsZero() {
+		pdf.SetCreationDate(form.GeneratedAt.UTC())
+		pdf.SetModificationDate(form.GeneratedAt.UTC())
+	}
+
+	r := &renderer{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor(""), form: form, format: format}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-footerHeightMM + 6)
+		pdf.SetFont(fontFamily, "I", 8)
+		pdf.CellFormat(0, 4, r.text(fmt.Sprintf("Prepared in the %s format, version %s. Page %d of {nb}",
+			india.StateName(state), f.version, pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+
+	if len(form.Photo) > 0 {
+		imageType := "JPG"
+		if form.Verification.PhotoContentType == "image/png" {
+			imageType = "PNG"
+		}
+		pdf.RegisterImageOptionsReader(photoImage, fpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(form.Photo))
+		if err := pdf.Error(); err != nil {
+			return nil, fmt.Errorf("read tenant photograph: %w", err)
+		}
+	}
+
+	pdf.AddPage()
+	r.header()
+	r.sections()
+	r.declaration()
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render police verification form: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// text converts UTF-8 to the core fonts' encoding, writing the rupee sign as "Rs."
+func (r *renderer) text(s string) string {
+	return r.tr(strings.ReplaceAll(s, "₹", "Rs. "))
+}
+
+// header prints the title, the addressee and the introduction beside the photograph
+func (r *renderer) header() {
+	pageWidth, _ := r.pdf.GetPageSize()
+	width := pageWidth - 2*marginMM - photoWidthMM - 6
+
+	r.pdf.SetFont(fontFamily, "B", 14)
+	r.pdf.MultiCell(0, 7, r.text(r.format.Title), "", "C", false)
+	r.pdf.Ln(4)
+
+	top := r.pdf.GetY()
+	r.photo(pageWidth-marginMM-photoWidthMM, top)
+
+	station := r.form.Verification.PoliceStation
+	if station == "" {
+		station = "______________________________"
+	}
+	r.pdf.SetFont(fontFamily, "", 11)
+	r.pdf.MultiCell(width, lineHeightMM, r.text(r.format.Authority+","), "", "L", false)
+	r.pdf.MultiCell(width, lineHeightMM, r.text(station), "", "L", false)
+	r.pdf.Ln(3)
+	if r.format.Intro != "" {
+		r.pdf.MultiCell(width, lineHeightMM, r.text(r.format.Intro), "", "J", false)
+	}
+
+	if bottom := top + photoHeightMM; r.pdf.GetY() < bottom {
+		r.pdf.SetY(bottom)
+	}
+	r.pdf.Ln(4)
+}
+
+// photo prints the tenant's photograph, or a box to affix one
+func (r *renderer) photo(x, y float64) {
+	if len(r.form.Photo) > 0 {
+		r.pdf.ImageOptions(photoImage, x, y, photoWidthMM, photoHeightMM, false, fpdf.ImageOptions{}, 0, "")
+		r.pdf.Rect(x, y, photoWidthMM, photoHeightMM, "D")
+		return
+	}
+	r.pdf.Rect(x, y, photoWidthMM, photoHeightMM, "D")
+	r.pdf.SetFont(fontFamily, "I", 9)
+	r.pdf.SetXY(x, y+photoHeightMM/2-lineHeightMM)
+	r.pdf.MultiCell(photoWidthMM, lineHeightMM, "Affix recent passport size photograph", "", "C", false)
+	r.pdf.SetXY(marginMM, y)
+}
+
+func (r *renderer) sections() {
+	values := r.values()
+	for i, section := range r.format.Sections {
+		r.ensureSpace(3 * lineHeightMM)
+		r.pdf.SetFont(fontFamily, "B", 11)
+		r.pdf.CellFormat(0, lineHeightMM+1, r.text(fmt.Sprintf("%d. %s", i+1, section.Heading)), "", 1, "L", false, 0, "")
+		r.pdf.Ln(1)
+		for _, field := range section.Fields {
+			r.row(r.format.label(field), values[field])
+		}
+		r.pdf.Ln(3)
+	}
+}
+
+// row prints a label/value row; an empty value leaves room to write one in
+func (r *renderer) row(label, value string) {
+	pageWidth, _ := r.pdf.GetPageSize()
+	valueWidth := pageWidth - 2*marginMM - labelWidthMM
+
+	r.pdf.SetFont(fontFamily, "B", 10)
+	labelLines := r.pdf.SplitLines([]byte(r.text(label)), labelWidthMM-2)
+	r.pdf.SetFont(fontFamily, "", 10)
+	valueLines := r.pdf.SplitLines([]byte(r.text(value)), valueWidth-2)
+	height := float64(max(len(labelLines), len(valueLines), 1)) * lineHeightMM
+	if value == "" {
+		height = max(height, 2*lineHeightMM)
+	}
+	r.ensureSpace(height)
+
+	x, y := r.pdf.GetXY()
+	r.pdf.SetFont(fontFamily, "B", 10)
+	r.pdf.Rect(x, y, labelWidthMM, height, "D")
+	r.pdf.MultiCell(labelWidthMM, lineHeightMM, r.text(label), "", "L", false)
+
+	r.pdf.SetXY(x+labelWidthMM, y)
+	r.pdf.SetFont(fontFamily, "", 10)
+	r.pdf.Rect(x+labelWidthMM, y, valueWidth, height, "D")
+	r.pdf.MultiCell(valueWidth, lineHeightMM, r.text(value), "", "L", false)
+
+	r.pdf.SetXY(x, y+height)
+}
+
+// values returns the printed value of every field
+func (r *renderer) values() map[string]string {
+	form, v := r.form, r.form.Verification
+
+	idProof := idProofNames[v.IDProofType]
+	if v.IDProofNumber != "" {
+		idProof += " no. " + v.IDProofNumber
+	}
+	previous := ""
+	if v.PreviousAddress.AddressLine1 != "" {
+		previous = v.PreviousAddress.Formatted()
+	}
+
+	return map[string]string{
+		"owner_name":        form.Owner.Name,
+		"owner_phone":       deref(form.Owner.Phone),
+		"owner_email":       deref(form.Owner.Email),
+		"property_address":  form.Property.Address.Formatted(),
+		"lease_start":       form.Lease.StartDate.Format(clausetext.DateLayout),
+		"monthly_rent":      inr.FormatWithSymbol(form.Lease.MonthlyRentPaise),
+		"tenant_name":       form.Tenant.Name,
+		"father_name":       v.FatherName,
+		"date_of_birth":     v.DateOfBirth.Format(clausetext.DateLayout),
+		"phone":             deref(form.Tenant.Phone),
+		"email":             deref(form.Tenant.Email),
+		"id_proof":          idProof,
+		"co_tenants":        strings.Join(form.CoTenants, ", "),
+		"permanent_address": v.PermanentAddress.Formatted(),
+		"previous_address":  previous,
+		"occupation":        v.Occupation,
+		"workplace":         v.Workplace,
+		"workplace_address": v.WorkplaceAddress,
+		"police_station":    v.PoliceStation,
+	}
+}
+
+// declaration prints the declaration, the signature lines and the online portal
+func (r *renderer) declaration() {
+	r.ensureSpace(10 * lineHeightMM)
+	r.pdf.SetFont(fontFamily, "", 10)
+	r.pdf.MultiCell(0, lineHeightMM, r.text(r.format.Declaration), "", "J", false)
+	r.pdf.Ln(16)
+
+	pageWidth, _ := r.pdf.GetPageSize()
+	column := (pageWidth - 2*marginMM) / 2
+	y := r.pdf.GetY()
+	for i, signer := range []struct{ role, name string }{
+		{"Signature of owner", r.form.Owner.Name},
+		{"Signature of tenant", r.form.Tenant.Name},
+	} {
+		x := marginMM + float64(i)*column
+		r.pdf.Line(x, y, x+column-10, y)
+		r.pdf.SetXY(x, y+1)
+		r.pdf.SetFont(fontFamily, "B", 10)
+		r.pdf.CellFormat(column-10, lineHeightMM, r.text(signer.role), "", 2, "L", false, 0, "")
+		r.pdf.SetFont(fontFamily, "", 10)
+		r.pdf.CellFormat(column-10, lineHeightMM, r.text(signer.name), "", 0, "L", false, 0, "")
+	}
+	r.pdf.SetXY(marginMM, y+3*lineHeightMM)
+	r.pdf.CellFormat(column, lineHeightMM, "Date: ____________________", "", 0, "L", false, 0, "")
+	r.pdf.CellFormat(column, lineHeightMM, r.text("Place: "+r.form.Property.City), "", 1, "L", false, 0, "")
+
+	if r.format.Portal != "" {
+		r.pdf.Ln(4)
+		r.pdf.SetFont(fontFamily, "I", 9)
+		r.pdf.MultiCell(0, lineHeightMM, r.text("This form can also be filed online at "+r.format.Portal+"."), "", "L", false)
+	}
+}
+
+// ensureSpace starts a new page unless h millimetres fit above the footer
+func (r *renderer) ensureSpace(h float64) {
+	_, pageHeight := r.pdf.GetPageSize()
+	if r.pdf.GetY()+h > pageHeight-footerHeightMM {
+		r.pdf.AddPage()
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,213 @@
+package policy
+
+import (
+	"slices"
+
+	"backend/internal/model"
+	"backend/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionRead   Action = "read"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	// ActionManage covers changing who else has access, e.g. co-owners and managers
+	ActionManage Action = "manage"
+	// ActionNotice covers serving notice to end a lease, which either party may do
+	ActionNotice Action = "notice"
+)
+
+type ResourceType string
+
+const (
+	ResourceUser     ResourceType = "user"
+	ResourceProperty ResourceType = "property"
+	ResourceBuilding ResourceType = "building"
+	ResourceClause   ResourceType = "clause"
+	ResourceLease    ResourceType = "lease"
+)
+
+// Relation is how a user is connected to a specific resource
+type Relation string
+
+const (
+	RelationOwner   Relation = "owner"
+	RelationCoOwner Relation = "co_owner"
+	RelationManager Relation = "manager"
+	RelationTenant  Relation = "tenant"
+)
+
+// Resource identifies the users related to a protected object. Services build
+// one from the loaded model with the For* helpers and pass it to Authorize.
+type Resource struct {
+	Type       ResourceType
+	OwnerIDs   []uuid.UUID
+	CoOwnerIDs []uuid.UUID
+	ManagerIDs []uuid.UUID
+	TenantIDs  []uuid.UUID
+	// Public resources may be read by any authenticated user
+	Public bool
+}
+
+// relations returns every relation the user has to the resource
+func (r Resource) relations(userID uuid.UUID) []Relation {
+	var rels []Relation
+	if slices.Contains(r.OwnerIDs, userID) {
+		rels = append(rels, RelationOwner)
+	}
+	if slices.Contains(r.CoOwnerIDs, userID) {
+		rels = append(rels, RelationCoOwner)
+	}
+	if slices.Contains(r.ManagerIDs, userID) {
+		rels = append(rels, RelationManager)
+	}
+	if slices.Contains(r.TenantIDs, userID) {
+		rels = append(rels, RelationTenant)
+	}
+	return rels
+}
+
+// creators lists the roles allowed to create each resource type
+var creators = map[ResourceType][]string{
+	ResourceUser:     {},
+	ResourceProperty: {model.RoleOwner},
+	ResourceBuilding: {model.RoleOwner},
+	ResourceClause:   {model.RoleOwner, model.RoleCoOwner, model.RoleManager},
+	ResourceLease:    {model.RoleOwner, model.RoleCoOwner, model.RoleManager},
+}
+
+// grants lists, per resource type and action, the relations that allow it
+var grants = map[ResourceType]map[Action][]Relation{
+	// A user record is "owned" by the user it describes
+	ResourceUser: {
+		ActionRead:   {RelationOwner},
+		ActionUpdate: {RelationOwner},
+		ActionDelete: {RelationOwner},
+	},
+	ResourceProperty: {
+		ActionRead:   {RelationOwner, RelationCoOwner, RelationManager, RelationTenant},
+		ActionUpdate: {RelationOwner, RelationCoOwner, RelationManager},
+		ActionDelete: {RelationOwner},
+		ActionManage: {RelationOwner},
+	},
+	ResourceBuilding: {
+		ActionRead:   {RelationOwner, RelationManager},
+		ActionUpdate: {RelationOwner, RelationManager},
+		ActionDelete: {RelationOwner},
+		ActionManage: {RelationOwner},
+	},
+	// System clauses have no owner, so only admins may change them
+	ResourceClause: {
+		ActionRead:   {RelationOwner},
+		ActionUpdate: {RelationOwner},
+		ActionDelete: {RelationOwner},
+	},
+	// Lease relations are inherited from the leased property, plus the lease tenants
+	ResourceLease: {
+		ActionRead:   {RelationOwner, RelationCoOwner, RelationManager, RelationTenant},
+		ActionUpdate: {RelationOwner, RelationCoOwner, RelationManager},
+		ActionDelete: {RelationOwner, RelationCoOwner},
+		ActionNotice: {RelationOwner, RelationCoOwner, RelationManager, RelationTenant},
+	},
+}
+
+// Authorize returns apperr.Forbidden unless the actor may perform the action on the resource.
+// Platform admins are always allowed.
+func Authorize(actor *model.User, action Action, resource Resource) error {
+	if actor == nil {
+		return apperr.Unauthorized("Authentication required", nil)
+	}
+	if actor.IsAdmin() || (action == ActionRead && resource.Public) {
+		return nil
+	}
+
+	allowed := grants[resource.Type][action]
+	for _, rel := range resource.relations(actor.ID) {
+		if slices.Contains(allowed, rel) {
+			return nil
+		}
+	}
+
+	return apperr.Forbidden("You do not have permission to "+string(action)+" this "+string(resource.Type), nil)
+}
+
+// AuthorizeCreate returns apperr.Forbidden unless the actor's role may create the resource type
+func AuthorizeCreate(actor *model.User, resourceType ResourceType) error {
+	if actor == nil {
+		return apperr.Unauthorized("Authentication required", nil)
+	}
+	if actor.IsAdmin() || slices.Contains(creators[resourceType], actor.Role) {
+		return nil
+	}
+
+	return apperr.Forbidden("Your role cannot create a "+string(resourceType), nil)
+}
+
+// RequireAdmin returns apperr.Forbidden unless the actor is a platform admin
+func RequireAdmin(actor *model.User) error {
+	if actor == nil {
+		return apperr.Unauthorized("Authentication required", nil)
+	}
+	if !actor.IsAdmin() {
+		return apperr.Forbidden("Administrator access required", nil)
+	}
+	return nil
+}
+
+// ForUser describes a user record as a resource
+func ForUser(user *model.User) Resource {
+	return Resource{
+		Type:     ResourceUser,
+		OwnerIDs: []uuid.UUID{user.ID},
+	}
+}
+
+// ForProperty describes a property as a resource. The co-owners must be preloaded.
+func ForProperty(property *model.Property) Resource {
+	res := Resource{
+		Type:       ResourceProperty,
+		OwnerIDs:   []uuid.UUID{property.OwnerID},
+		CoOwnerIDs: property.CoOwnerIDs(),
+	}
+	if property.ManagerID != nil {
+		res.ManagerIDs = []uuid.UUID{*property.ManagerID}
+	}
+	return res
+}
+
+// ForBuilding describes a building as a resource
+func ForBuilding(building *model.Building) Resource {
+	res := Resource{
+		Type:     ResourceBuilding,
+		OwnerIDs: []uuid.UUID{building.OwnerID},
+	}
+	if building.ManagerID != nil {
+		res.ManagerIDs = []uuid.UUID{*building.ManagerID}
+	}
+	return res
+}
+
+// ForClause describes a clause as a resource. System clauses are public.
+func ForClause(clause *model.Clause) Resource {
+	if clause.IsSystem() {
+		return Resource{Type: ResourceClause, Public: true}
+	}
+	return Resource{
+		Type:     ResourceClause,
+		OwnerIDs: []uuid.UUID{*clause.OwnerID},
+	}
+}
+
+// ForLease describes a lease as a resource. The property, its co-owners and
+// the lease tenants must be preloaded.
+func ForLease(lease *model.Lease) Resource {
+	res := ForProperty(lease.Property)
+	res.Type = ResourceLease
+	res.TenantIDs = lease.TenantIDs()
+	return res
+}
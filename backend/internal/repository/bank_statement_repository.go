@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrBankTransactionNotFound = errors.New("bank transaction not found")
+
+// BankTransactionFilter narrows a list of bank transactions; zero fields match all
+type BankTransactionFilter struct {
+	Status   string
+	ImportID *uuid.UUID
+}
+
+type BankStatementRepository interface {
+	CreateImport(ctx context.Context, statement *model.BankStatementImport) error
+	SaveImport(ctx context.Context, statement *model.BankStatementImport) error
+	ListImports(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.BankStatementImport, int64, error)
+	CreateTransaction(ctx context.Context, txn *model.BankTransaction) (bool, error)
+	SaveTransaction(ctx context.Context, txn *model.BankTransaction) error
+	GetTransaction(ctx context.Context, userID, id uuid.UUID) (*model.BankTransaction, error)
+	LockTransaction(ctx context.Context, userID, id uuid.UUID) (*model.BankTransaction, error)
+	ListTransactions(ctx context.Context, userID uuid.UUID, filter BankTransactionFilter, limit, offset int) ([]model.BankTransaction, int64, error)
+	CreateCandidates(ctx context.Context, candidates []model.BankMatchCandidate) error
+	DeleteCandidates(ctx context.Context, transactionID uuid.UUID) error
+}
+
+type bankStatementRepository struct {
+	db *gorm.DB
+}
+
+func NewBankStatementRepository(db *gorm.DB) BankStatementRepository {
+	return &bankStatementRepository{db: db}
+}
+
+func (r *bankStatementRepository) CreateImport(ctx context.Context, statement *model.BankStatementImport) error {
+	return r.db.WithContext(ctx).Create(statement).Error
+}
+
+func (r *bankStatementRepository) SaveImport(ctx context.Context, statement *model.BankStatementImport) error {
+	return r.db.WithContext(ctx).Save(statement).Error
+}
+
+func (r *bankStatementRepository) ListImports(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.BankStatementImport, int64, error) {
+	var imports []model.BankStatementImport
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.BankStatementImport{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&imports).Error; err != nil {
+		return nil, 0, err
+	}
+	return imports, total, nil
+}
+
+// CreateTransaction stores a credit unless the user has imported it before,
+// reporting whether it was stored
+func (r *bankStatementRepository) CreateTransaction(ctx context.Context, txn *model.BankTransaction) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "fingerprint"}},
+		DoNothing: true,
+	}).Omit("Candidates").Create(txn)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *bankStatementRepository) SaveTransaction(ctx context.Context, txn *model.BankTransaction) error {
+	return r.db.WithContext(ctx).Omit("Candidates").Save(txn).Error
+}
+
+func (r *bankStatementRepository) GetTransaction(ctx context.Context, userID, id uuid.UUID) (*model.BankTransaction, error) {
+	return r.firstTransaction(r.db.WithContext(ctx).
+		Preload("Candidates", func(db *gorm.DB) *gorm.DB { return db.Order("score DESC") }).
+		Where("id = ? AND user_id = ?", id, userID))
+}
+
+// LockTransaction returns the transaction locked for update until the transaction ends
+func (r *bankStatementRepository) LockTransaction(ctx context.Context, userID, id uuid.UUID) (*model.BankTransaction, error) {
+	return r.firstTransaction(r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND user_id = ?", id, userID))
+}
+
+func (r *bankStatementRepository) firstTransaction(query *gorm.DB) (*model.BankTransaction, error) {
+	var txn model.BankTransaction
+	if err := query.First(&txn).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBankTransactionNotFound
+		}
+		return nil, err
+	}
+	return &txn, nil
+}
+
+// ListTransactions returns the user's imported credits, newest first, with
+// the candidates of those awaiting review
+func (r *bankStatementRepository) ListTransactions(ctx context.Context, userID uuid.UUID, filter BankTransactionFilter, limit, offset int) ([]model.BankTransaction, int64, error) {
+	var txns []model.BankTransaction
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.BankTransaction{}).Where("user_id = ?", userID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ImportID != nil {
+		query = query.Where("import_id = ?", *filter.ImportID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.
+		Preload("Candidates", func(db *gorm.DB) *gorm.DB { return db.Order("score DESC") }).
+		Order("txn_date DESC, statement_row").
+		Limit(limit).
+		Offset(offset).
+		Find(&txns).Error; err != nil {
+		return nil, 0, err
+	}
+	return txns, total, nil
+}
+
+func (r *bankStatementRepository) CreateCandidates(ctx context.Context, candidates []model.BankMatchCandidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&candidates).Error
+}
+
+func (r *bankStatementRepository) DeleteCandidates(ctx context.Context, transactionID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.BankMatchCandidate{}, "transaction_id = ?", transactionID).Error
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBuildingNotFound         = errors.New("building not found")
+	ErrBuildingChargeNotFound   = errors.New("building charge not found")
+	ErrBuildingDocumentNotFound = errors.New("building document not found")
+)
+
+type BuildingRepository interface {
+	Create(ctx context.Context, building *model.Building) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Building, error)
+	List(ctx context.Context, limit, offset int) ([]model.Building, int64, error)
+	ListAccessibleBy(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Building, int64, error)
+	Update(ctx context.Context, building *model.Building) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CreateCharge(ctx context.Context, charge *model.BuildingCharge) error
+	DeleteCharge(ctx context.Context, buildingID, chargeID uuid.UUID) error
+	CreateDocument(ctx context.Context, document *model.BuildingDocument) error
+	GetDocument(ctx context.Context, buildingID, documentID uuid.UUID) (*model.BuildingDocument, error)
+	ListDocuments(ctx context.Context, buildingID uuid.UUID) ([]model.BuildingDocument, error)
+	DeleteDocument(ctx context.Context, buildingID, documentID uuid.UUID) error
+}
+
+type buildingRepository struct {
+	db *gorm.DB
+}
+
+func NewBuildingRepository(db *gorm.DB) BuildingRepository {
+	return &buildingRepository{db: db}
+}
+
+func (r *buildingRepository) Create(ctx context.Context, building *model.Building) error {
+	return r.db.WithContext(ctx).Omit("Charges").Create(building).Error
+}
+
+func (r *buildingRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Building, error) {
+	var building model.Building
+	if err := r.db.WithContext(ctx).Preload("Charges", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).First(&building, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBuildingNotFound
+		}
+		return nil, err
+	}
+	return &building, nil
+}
+
+func (r *buildingRepository) List(ctx context.Context, limit, offset int) ([]model.Building, int64, error) {
+	return r.paginate(r.db.WithContext(ctx).Model(&model.Building{}), limit, offset)
+}
+
+// ListAccessibleBy returns buildings the user owns or manages
+func (r *buildingRepository) ListAccessibleBy(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Building, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Building{}).
+		Where("owner_id = ? OR manager_id = ?", userID, userID)
+	return r.paginate(query, limit, offset)
+}
+
+func (r *buildingRepository) paginate(query *gorm.DB, limit, offset int) ([]model.Building, int64, error) {
+	var buildings []model.Building
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&buildings).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return buildings, total, nil
+}
+
+func (r *buildingRepository) Update(ctx context.Context, building *model.Building) error {
+	result := r.db.WithContext(ctx).Omit("Charges").Save(building)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBuildingNotFound
+	}
+	return nil
+}
+
+func (r *buildingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.Building{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBuildingNotFound
+	}
+	return nil
+}
+
+func (r *buildingRepository) CreateCharge(ctx context.Context, charge *model.BuildingCharge) error {
+	return r.db.WithContext(ctx).Create(charge).Error
+}
+
+func (r *buildingRepository) DeleteCharge(ctx context.Context, buildingID, chargeID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.BuildingCharge{}, "id = ? AND building_id = ?", chargeID, buildingID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBuildingChargeNotFound
+	}
+	return nil
+}
+
+func (r *buildingRepository) CreateDocument(ctx context.Context, document *model.BuildingDocument) error {
+	return r.db.WithContext(ctx).Create(document).Error
+}
+
+func (r *buildingRepository) GetDocument(ctx context.Context, buildingID, documentID uuid.UUID) (*model.BuildingDocument, error) {
+	var document model.BuildingDocument
+	if err := r.db.WithContext(ctx).First(&document, "id = ? AND building_id = ?", documentID, buildingID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBuildingDocumentNotFound
+		}
+		return nil, err
+	}
+	return &document, nil
+}
+
+func (r *buildingRepository) ListDocuments(ctx context.Context, buildingID uuid.UUID) ([]model.BuildingDocument, error) {
+	var documents []model.BuildingDocument
+	if err := r.db.WithContext(ctx).
+		Where("building_id = ?", buildingID).
+		Order("created_at DESC").
+		Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (r *buildingRepository) DeleteDocument(ctx context.Context, buildingID, documentID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.BuildingDocument{}, "id = ? AND building_id = ?", documentID, buildingID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBuildingDocumentNotFound
+	}
+	return nil
+}
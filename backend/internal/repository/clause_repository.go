@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrClauseNotFound            = errors.New("clause not found")
+	ErrClauseTranslationNotFound = errors.New("clause translation not found")
+)
+
+// ClauseFilter narrows a clause listing. A nil OwnerID lists every clause;
+// otherwise the system clauses plus that owner's custom clauses are returned.
+type ClauseFilter struct {
+	OwnerID  *uuid.UUID
+	Category string
+}
+
+type ClauseRepository interface {
+	Create(ctx context.Context, clause *model.Clause) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Clause, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Clause, error)
+	List(ctx context.Context, filter ClauseFilter, limit, offset int) ([]model.Clause, int64, error)
+	ListMandatory(ctx context.Context) ([]model.Clause, error)
+	Update(ctx context.Context, clause *model.Clause) error
+	CreateVersion(ctx context.Context, version *model.ClauseVersion) error
+	ListVersions(ctx context.Context, clauseID uuid.UUID) ([]model.ClauseVersion, error)
+	CreateTranslation(ctx context.Context, translation *model.ClauseTranslation) error
+	ListTranslations(ctx context.Context, clauseID uuid.UUID) ([]model.ClauseTranslation, error)
+	GetTranslations(ctx context.Context, versionIDs []uuid.UUID, lang string) ([]model.ClauseTranslation, error)
+}
+
+type clauseRepository struct {
+	db *gorm.DB
+}
+
+func NewClauseRepository(db *gorm.DB) ClauseRepository {
+	return &clauseRepository{db: db}
+}
+
+func (r *clauseRepository) Create(ctx context.Context, clause *model.Clause) error {
+	return r.db.WithContext(ctx).Omit("CurrentVersion").Create(clause).Error
+}
+
+func (r *clauseRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Clause, error) {
+	var clause model.Clause
+	if err := r.db.WithContext(ctx).Preload("CurrentVersion").First(&clause, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrClauseNotFound
+		}
+		return nil, err
+	}
+	return &clause, nil
+}
+
+func (r *clauseRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Clause, error) {
+	var clauses []model.Clause
+	if err := r.db.WithContext(ctx).Preload("CurrentVersion").Where("id IN ?", ids).Find(&clauses).Error; err != nil {
+		return nil, err
+	}
+	return clauses, nil
+}
+
+// List returns active (not archived) clauses
+func (r *clauseRepository) List(ctx context.Context, filter ClauseFilter, limit, offset int) ([]model.Clause, int64, error) {
+	var clauses []model.Clause
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Clause{}).Where("archived_at IS NULL")
+	if filter.OwnerID != nil {
+		query = query.Where("owner_id IS NULL OR owner_id = ?", *filter.OwnerID)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Preload("CurrentVersion").
+		Order("category, owner_id NULLS FIRST, created_at").
+		Limit(limit).
+		Offset(offset).
+		Find(&clauses).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return clauses, total, nil
+}
+
+func (r *clauseRepository) ListMandatory(ctx context.Context) ([]model.Clause, error) {
+	var clauses []model.Clause
+	if err := r.db.WithContext(ctx).
+		Preload("CurrentVersion").
+		Where("is_mandatory AND archived_at IS NULL").
+		Order("created_at").
+		Find(&clauses).Error; err != nil {
+		return nil, err
+	}
+	return clauses, nil
+}
+
+func (r *clauseRepository) Update(ctx context.Context, clause *model.Clause) error {
+	result := r.db.WithContext(ctx).Omit("CurrentVersion").Save(clause)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrClauseNotFound
+	}
+	return nil
+}
+
+func (r *clauseRepository) CreateVersion(ctx context.Context, version *model.ClauseVersion) error {
+	return r.db.WithContext(ctx).Create(version).Error
+}
+
+func (r *clauseRepository) ListVersions(ctx context.Context, clauseID uuid.UUID) ([]model.ClauseVersion, error) {
+	var versions []model.ClauseVersion
+	if err := r.db.WithContext(ctx).
+		Where("clause_id = ?", clauseID).
+		Order("version DESC").
+		Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// CreateTranslation adds the translation as the next revision of the
+// version's translation into its language, setting its Revision
+func (r *clauseRepository) CreateTranslation(ctx context.Context, translation *model.ClauseTranslation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Revisions of the version are numbered one at a time
+		var version model.ClauseVersion
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&version, "id = ?", translation.ClauseVersionID).Error; err != nil {
+			return err
+		}
+		var latest int
+		if err := tx.Model(&model.ClauseTranslation{}).
+			Where("clause_version_id = ? AND lang = ?", translation.ClauseVersionID, translation.Lang).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		translation.Revision = latest + 1
+		return tx.Create(translation).Error
+	})
+}
+
+// ListTranslations returns every revision of the translations of every
+// version of a clause, newest version first and newest revision first
+func (r *clauseRepository) ListTranslations(ctx context.Context, clauseID uuid.UUID) ([]model.ClauseTranslation, error) {
+	var translations []model.ClauseTranslation
+	if err := r.db.WithContext(ctx).
+		Select("clause_translations.*, clause_versions.version").
+		Joins("JOIN clause_versions ON clause_versions.id = clause_translations.clause_version_id").
+		Where("clause_versions.clause_id = ?", clauseID).
+		Order("clause_versions.version DESC, clause_translations.lang, clause_translations.revision DESC").
+		Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// GetTranslations returns the current translations into lang of the given
+// clause versions that have one, or into every language when lang is empty
+func (r *clauseRepository) GetTranslations(ctx context.Context, versionIDs []uuid.UUID, lang string) ([]model.ClauseTranslation, error) {
+	query := r.db.WithContext(ctx).
+		Select("DISTINCT ON (clause_version_id, lang) *").
+		Where("clause_version_id IN ?", versionIDs)
+	if lang != "" {
+		query = query.Where("lang = ?", lang)
+	}
+	var latest []model.ClauseTranslation
+	if err := query.Order("clause_version_id, lang, revision DESC").Find(&latest).Error; err != nil {
+		return nil, err
+	}
+
+	translations := latest[:0]
+	for _, t := range latest {
+		if !t.Withdrawn {
+			translations = append(translations, t)
+		}
+	}
+	return translations, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrDocumentNotFound = errors.New("document not found")
+
+type DocumentRepository interface {
+	Create(ctx context.Context, document *model.Document) error
+	GetByCode(ctx context.Context, code string) (*model.Document, error)
+	GetLatest(ctx context.Context, leaseID uuid.UUID, kind string) (*model.Document, error)
+	GetBySigning(ctx context.Context, signingID uuid.UUID, kind string) (*model.Document, error)
+	GetByContent(ctx context.Context, leaseID uuid.UUID, contentSHA256 string, stampMarginMM int) (*model.Document, error)
+	GetByJournalEntry(ctx context.Context, entryID uuid.UUID) (*model.Document, error)
+}
+
+type documentRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentRepository(db *gorm.DB) DocumentRepository {
+	return &documentRepository{db: db}
+}
+
+func (r *documentRepository) Create(ctx context.Context, document *model.Document) error {
+	return r.db.WithContext(ctx).Create(document).Error
+}
+
+func (r *documentRepository) GetByCode(ctx context.Context, code string) (*model.Document, error) {
+	return r.first(r.db.WithContext(ctx).Where("code = ?", code))
+}
+
+// GetLatest returns the lease's most recent document of the given kind
+func (r *documentRepository) GetLatest(ctx context.Context, leaseID uuid.UUID, kind string) (*model.Document, error) {
+	return r.first(r.db.WithContext(ctx).Where("lease_id = ? AND kind = ?", leaseID, kind).Order("created_at DESC"))
+}
+
+func (r *documentRepository) GetBySigning(ctx context.Context, signingID uuid.UUID, kind string) (*model.Document, error) {
+	return r.first(r.db.WithContext(ctx).Where("signing_id = ? AND kind = ?", signingID, kind))
+}
+
+// GetByContent returns the latest printed lease saying the same, laid out with the same margin
+func (r *documentRepository) GetByContent(ctx context.Context, leaseID uuid.UUID, contentSHA256 string, stampMarginMM int) (*model.Document, error) {
+	return r.first(r.db.WithContext(ctx).
+		Where("lease_id = ? AND kind = ? AND content_sha256 = ? AND stamp_margin_mm = ?", leaseID, model.DocumentKindLease, contentSHA256, stampMarginMM).
+		Order("created_at DESC"))
+}
+
+// GetByJournalEntry returns the receipt of a payment
+func (r *documentRepository) GetByJournalEntry(ctx context.Context, entryID uuid.UUID) (*model.Document, error) {
+	return r.first(r.db.WithContext(ctx).Where("journal_entry_id = ? AND kind = ?", entryID, model.DocumentKindReceipt))
+}
+
+func (r *documentRepository) first(query *gorm.DB) (*model.Document, error) {
+	var document model.Document
+	if err := query.First(&document).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return &document, nil
+}
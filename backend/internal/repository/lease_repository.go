@@ -0,0 +1,524 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrLeaseNotFound        = errors.New("lease not found")
+	ErrLeaseTenantNotFound  = errors.New("lease tenant not found")
+	ErrEStampNotFound       = errors.New("e-stamp not found")
+	ErrLeaseVersionNotFound = errors.New("lease version not found")
+	ErrVerificationNotFound = errors.New("tenant verification not found")
+	ErrRentDueNotFound      = errors.New("rent due not found")
+	// ErrLeaseStatusChanged means the lease left the expected status before the update
+	ErrLeaseStatusChanged = errors.New("lease status changed")
+	// ErrLeaseAlreadyRenewed means another renewal of the same lease was created first
+	ErrLeaseAlreadyRenewed = errors.New("lease already renewed")
+)
+
+type LeaseRepository interface {
+	Create(ctx context.Context, lease *model.Lease) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Lease, error)
+	List(ctx context.Context, limit, offset int) ([]model.Lease, int64, error)
+	ListAccessibleBy(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Lease, int64, error)
+	CountByProperty(ctx context.Context, propertyID uuid.UUID) (int64, error)
+	CountByPropertyAndStatus(ctx context.Context, propertyID uuid.UUID, statuses ...string) (int64, error)
+	Update(ctx context.Context, lease *model.Lease) error
+	UpdateStatus(ctx context.Context, lease *model.Lease, fromStatus string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddTenant(ctx context.Context, tenant *model.LeaseTenant) error
+	RemoveTenant(ctx context.Context, leaseID, userID uuid.UUID) error
+	CreateTransition(ctx context.Context, transition *model.LeaseTransition) error
+	ListTransitions(ctx context.Context, leaseID uuid.UUID) ([]model.LeaseTransition, error)
+	ListClauses(ctx context.Context, leaseID uuid.UUID) ([]model.LeaseClause, error)
+	ReplaceClauses(ctx context.Context, leaseID uuid.UUID, clauses []model.LeaseClause) error
+	GetEStamp(ctx context.Context, leaseID uuid.UUID) (*model.EStamp, error)
+	ReplaceEStamp(ctx context.Context, estamp *model.EStamp) error
+	DeleteEStamp(ctx context.Context, leaseID uuid.UUID) error
+	EStampNumberInUse(ctx context.Context, certificateNumber string, exceptLeaseID uuid.UUID) (bool, error)
+	CreateVersion(ctx context.Context, version *model.LeaseVersion) error
+	ListVersions(ctx context.Context, leaseID uuid.UUID) ([]model.LeaseVersion, error)
+	GetVersion(ctx context.Context, leaseID uuid.UUID, number int) (*model.LeaseVersion, error)
+	GetLatestVersion(ctx context.Context, leaseID uuid.UUID) (*model.LeaseVersion, error)
+	GetRenewal(ctx context.Context, leaseID uuid.UUID) (*model.Lease, error)
+	ListDueForRenewal(ctx context.Context, endingBy time.Time) ([]model.Lease, error)
+	ListByStatus(ctx context.Context, statuses ...string) ([]model.Lease, error)
+	ListByTenant(ctx context.Context, userID uuid.UUID) ([]model.Lease, error)
+	ListManagedBy(ctx context.Context, userID uuid.UUID) ([]model.Lease, error)
+	SetRenewalDrafted(ctx context.Context, id uuid.UUID, at time.Time) error
+	GetVerification(ctx context.Context, leaseID, userID uuid.UUID) (*model.TenantVerification, error)
+	ListVerifications(ctx context.Context, leaseID uuid.UUID) ([]model.TenantVerification, error)
+	SaveVerification(ctx context.Context, verification *model.TenantVerification) error
+	GetDue(ctx context.Context, leaseID, id uuid.UUID) (*model.RentDue, error)
+	ListDues(ctx context.Context, leaseID uuid.UUID) ([]model.RentDue, error)
+	SaveDues(ctx context.Context, dues []model.RentDue) error
+	DeleteDues(ctx context.Context, ids []uuid.UUID) error
+}
+
+type leaseRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaseRepository(db *gorm.DB) LeaseRepository {
+	return &leaseRepository{db: db}
+}
+
+// Create inserts the lease. A renewal of a lease that has one already is not
+// inserted, and ErrLeaseAlreadyRenewed is returned.
+func (r *leaseRepository) Create(ctx context.Context, lease *model.Lease) error {
+	db := r.db.WithContext(ctx).Omit("Property", "Tenants")
+	if lease.RenewalOfID == nil {
+		return db.Create(lease).Error
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(lease)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseAlreadyRenewed
+	}
+	return nil
+}
+
+// GetByID loads the lease with its property, the property's co-owners and the tenants
+func (r *leaseRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Lease, error) {
+	var lease model.Lease
+	if err := r.db.WithContext(ctx).
+		Preload("Property.CoOwners").
+		Preload("Tenants.User").
+		First(&lease, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLeaseNotFound
+		}
+		return nil, err
+	}
+	return &lease, nil
+}
+
+func (r *leaseRepository) List(ctx context.Context, limit, offset int) ([]model.Lease, int64, error) {
+	return r.paginate(r.db.WithContext(ctx).Model(&model.Lease{}), limit, offset)
+}
+
+// ListAccessibleBy returns leases on properties the user owns, co-owns or
+// manages, and leases the user is a tenant on
+func (r *leaseRepository) ListAccessibleBy(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Lease, int64, error) {
+	properties := r.db.Model(&model.Property{}).Select("id").
+		Where("owner_id = ? OR manager_id = ? OR id IN (?)",
+			userID, userID,
+			r.db.Model(&model.PropertyCoOwner{}).Select("property_id").Where("user_id = ?", userID),
+		)
+	tenancies := r.db.Model(&model.LeaseTenant{}).Select("lease_id").Where("user_id = ?", userID)
+	query := r.db.WithContext(ctx).Model(&model.Lease{}).Where("property_id IN (?) OR id IN (?)", properties, tenancies)
+	return r.paginate(query, limit, offset)
+}
+
+func (r *leaseRepository) paginate(query *gorm.DB, limit, offset int) ([]model.Lease, int64, error) {
+	var leases []model.Lease
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&leases).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return leases, total, nil
+}
+
+func (r *leaseRepository) CountByProperty(ctx context.Context, propertyID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.Lease{}).Where("property_id = ?", propertyID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *leaseRepository) CountByPropertyAndStatus(ctx context.Context, propertyID uuid.UUID, statuses ...string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.Lease{}).
+		Where("property_id = ? AND status IN ?", propertyID, statuses).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *leaseRepository) Update(ctx context.Context, lease *model.Lease) error {
+	result := r.db.WithContext(ctx).Omit("Property", "Tenants").Save(lease)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseNotFound
+	}
+	return nil
+}
+
+// UpdateStatus saves the lease's status and notice details, provided the
+// stored status is still fromStatus
+func (r *leaseRepository) UpdateStatus(ctx context.Context, lease *model.Lease, fromStatus string) error {
+	result := r.db.WithContext(ctx).Model(&model.Lease{}).
+		Where("id = ? AND status = ?", lease.ID, fromStatus).
+		Updates(map[string]any{
+			"status":          lease.Status,
+			"notice_given_at": lease.NoticeGivenAt,
+			"notice_given_by": lease.NoticeGivenBy,
+			"vacate_by":       lease.VacateBy,
+			"updated_at":      lease.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseStatusChanged
+	}
+	return nil
+}
+
+func (r *leaseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.Lease{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseNotFound
+	}
+	return nil
+}
+
+// ListClauses returns the lease's clauses in order, with the pinned wording
+func (r *leaseRepository) ListClauses(ctx context.Context, leaseID uuid.UUID) ([]model.LeaseClause, error) {
+	var clauses []model.LeaseClause
+	if err := r.db.WithContext(ctx).
+		Preload("Clause").
+		Preload("ClauseVersion").
+		Where("lease_id = ?", leaseID).
+		Order("position").
+		Find(&clauses).Error; err != nil {
+		return nil, err
+	}
+	return clauses, nil
+}
+
+// ReplaceClauses swaps the lease's clause list for the given one
+func (r *leaseRepository) ReplaceClauses(ctx context.Context, leaseID uuid.UUID, clauses []model.LeaseClause) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.LeaseClause{}, "lease_id = ?", leaseID).Error; err != nil {
+			return err
+		}
+		if len(clauses) == 0 {
+			return nil
+		}
+		return tx.Omit("Clause", "ClauseVersion").Create(&clauses).Error
+	})
+}
+
+func (r *leaseRepository) AddTenant(ctx context.Context, tenant *model.LeaseTenant) error {
+	return r.db.WithContext(ctx).Omit("User").Create(tenant).Error
+}
+
+func (r *leaseRepository) RemoveTenant(ctx context.Context, leaseID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.LeaseTenant{}, "lease_id = ? AND user_id = ?", leaseID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseTenantNotFound
+	}
+	return nil
+}
+
+func (r *leaseRepository) CreateTransition(ctx context.Context, transition *model.LeaseTransition) error {
+	return r.db.WithContext(ctx).Create(transition).Error
+}
+
+// ListTransitions returns the lease's status history, oldest first
+func (r *leaseRepository) ListTransitions(ctx context.Context, leaseID uuid.UUID) ([]model.LeaseTransition, error) {
+	var transitions []model.LeaseTransition
+	if err := r.db.WithContext(ctx).
+		Where("lease_id = ?", leaseID).
+		Order("created_at").
+		Find(&transitions).Error; err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+func (r *leaseRepository) GetEStamp(ctx context.Context, leaseID uuid.UUID) (*model.EStamp, error) {
+	var estamp model.EStamp
+	if err := r.db.WithContext(ctx).First(&estamp, "lease_id = ?", leaseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEStampNotFound
+		}
+		return nil, err
+	}
+	return &estamp, nil
+}
+
+// ReplaceEStamp attaches the e-stamp to its lease, replacing any earlier one
+func (r *leaseRepository) ReplaceEStamp(ctx context.Context, estamp *model.EStamp) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.EStamp{}, "lease_id = ?", estamp.LeaseID).Error; err != nil {
+			return err
+		}
+		return tx.Create(estamp).Error
+	})
+}
+
+func (r *leaseRepository) DeleteEStamp(ctx context.Context, leaseID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.EStamp{}, "lease_id = ?", leaseID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrEStampNotFound
+	}
+	return nil
+}
+
+// EStampNumberInUse reports whether the certificate is attached to a lease other than exceptLeaseID
+func (r *leaseRepository) EStampNumberInUse(ctx context.Context, certificateNumber string, exceptLeaseID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.EStamp{}).
+		Where("certificate_number = ? AND lease_id <> ?", certificateNumber, exceptLeaseID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateVersion saves the version with its tenants, clauses and their translations
+func (r *leaseRepository) CreateVersion(ctx context.Context, version *model.LeaseVersion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tenants", "Clauses", "ClauseTranslations").Create(version).Error; err != nil {
+			return err
+		}
+		for i := range version.Tenants {
+			version.Tenants[i].VersionID = version.ID
+		}
+		for i := range version.Clauses {
+			version.Clauses[i].VersionID = version.ID
+		}
+		for i := range version.ClauseTranslations {
+			version.ClauseTranslations[i].VersionID = version.ID
+		}
+		if len(version.Tenants) > 0 {
+			if err := tx.Create(&version.Tenants).Error; err != nil {
+				return err
+			}
+		}
+		if len(version.Clauses) > 0 {
+			if err := tx.Create(&version.Clauses).Error; err != nil {
+				return err
+			}
+		}
+		if len(version.ClauseTranslations) > 0 {
+			return tx.Create(&version.ClauseTranslations).Error
+		}
+		return nil
+	})
+}
+
+// ListVersions returns the lease's versions with their tenants, oldest first.
+// Clauses are left out.
+func (r *leaseRepository) ListVersions(ctx context.Context, leaseID uuid.UUID) ([]model.LeaseVersion, error) {
+	var versions []model.LeaseVersion
+	if err := r.db.WithContext(ctx).
+		Preload("Tenants").
+		Where("lease_id = ?", leaseID).
+		Order("number").
+		Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *leaseRepository) GetVersion(ctx context.Context, leaseID uuid.UUID, number int) (*model.LeaseVersion, error) {
+	return r.firstVersion(r.db.WithContext(ctx).Where("lease_id = ? AND number = ?", leaseID, number))
+}
+
+func (r *leaseRepository) GetLatestVersion(ctx context.Context, leaseID uuid.UUID) (*model.LeaseVersion, error) {
+	return r.firstVersion(r.db.WithContext(ctx).Where("lease_id = ?", leaseID).Order("number DESC"))
+}
+
+// firstVersion loads a version with its tenants and its clauses in order,
+// with their translations
+func (r *leaseRepository) firstVersion(query *gorm.DB) (*model.LeaseVersion, error) {
+	var version model.LeaseVersion
+	if err := query.
+		Preload("Tenants").
+		Preload("Clauses", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("ClauseTranslations", func(db *gorm.DB) *gorm.DB { return db.Order("position, lang") }).
+		First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLeaseVersionNotFound
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// GetRenewal returns the lease that renews the given one
+func (r *leaseRepository) GetRenewal(ctx context.Context, leaseID uuid.UUID) (*model.Lease, error) {
+	var lease model.Lease
+	if err := r.db.WithContext(ctx).First(&lease, "renewal_of_id = ?", leaseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLeaseNotFound
+		}
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// ListDueForRenewal returns active leases ending on or before endingBy that
+// have never had a renewal drafted, soonest ending first
+func (r *leaseRepository) ListDueForRenewal(ctx context.Context, endingBy time.Time) ([]model.Lease, error) {
+	var leases []model.Lease
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND renewal_drafted_at IS NULL AND end_date <= ?", model.LeaseStatusActive, endingBy).
+		Order("end_date").
+		Find(&leases).Error; err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+// ListByStatus returns the leases in any of the statuses with their tenants
+func (r *leaseRepository) ListByStatus(ctx context.Context, statuses ...string) ([]model.Lease, error) {
+	var leases []model.Lease
+	if err := r.db.WithContext(ctx).
+		Preload("Tenants").
+		Where("status IN ?", statuses).
+		Order("start_date").
+		Find(&leases).Error; err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+// ListByTenant returns the leases the user is a tenant on, oldest first, with
+// what GetByID preloads
+func (r *leaseRepository) ListByTenant(ctx context.Context, userID uuid.UUID) ([]model.Lease, error) {
+	var leases []model.Lease
+	if err := r.db.WithContext(ctx).
+		Preload("Property.CoOwners").
+		Preload("Tenants.User").
+		Where("id IN (?)", r.db.Model(&model.LeaseTenant{}).Select("lease_id").Where("user_id = ?", userID)).
+		Order("start_date").
+		Find(&leases).Error; err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+// ListManagedBy returns the leases on properties the user owns, co-owns or
+// manages, oldest first, with what GetByID preloads
+func (r *leaseRepository) ListManagedBy(ctx context.Context, userID uuid.UUID) ([]model.Lease, error) {
+	var leases []model.Lease
+	properties := r.db.Model(&model.Property{}).Select("id").
+		Where("owner_id = ? OR manager_id = ? OR id IN (?)",
+			userID, userID,
+			r.db.Model(&model.PropertyCoOwner{}).Select("property_id").Where("user_id = ?", userID),
+		)
+	if err := r.db.WithContext(ctx).
+		Preload("Property.CoOwners").
+		Preload("Tenants.User").
+		Where("property_id IN (?)", properties).
+		Order("start_date").
+		Find(&leases).Error; err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+func (r *leaseRepository) SetRenewalDrafted(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&model.Lease{}).Where("id = ?", id).Update("renewal_drafted_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseNotFound
+	}
+	return nil
+}
+
+func (r *leaseRepository) GetVerification(ctx context.Context, leaseID, userID uuid.UUID) (*model.TenantVerification, error) {
+	var verification model.TenantVerification
+	if err := r.db.WithContext(ctx).Preload("User").
+		First(&verification, "lease_id = ? AND user_id = ?", leaseID, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVerificationNotFound
+		}
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *leaseRepository) ListVerifications(ctx context.Context, leaseID uuid.UUID) ([]model.TenantVerification, error) {
+	var verifications []model.TenantVerification
+	if err := r.db.WithContext(ctx).Preload("User").
+		Where("lease_id = ?", leaseID).
+		Order("created_at ASC").
+		Find(&verifications).Error; err != nil {
+		return nil, err
+	}
+	return verifications, nil
+}
+
+// SaveVerification creates the verification or updates it in place
+func (r *leaseRepository) SaveVerification(ctx context.Context, verification *model.TenantVerification) error {
+	return r.db.WithContext(ctx).Omit("User").Save(verification).Error
+}
+
+// ListDues returns the rent dues of the lease in order of their periods
+func (r *leaseRepository) GetDue(ctx context.Context, leaseID, id uuid.UUID) (*model.RentDue, error) {
+	var due model.RentDue
+	if err := r.db.WithContext(ctx).First(&due, "id = ? AND lease_id = ?", id, leaseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRentDueNotFound
+		}
+		return nil, err
+	}
+	return &due, nil
+}
+
+func (r *leaseRepository) ListDues(ctx context.Context, leaseID uuid.UUID) ([]model.RentDue, error) {
+	var dues []model.RentDue
+	if err := r.db.WithContext(ctx).
+		Where("lease_id = ?", leaseID).
+		Order("period_start ASC").
+		Find(&dues).Error; err != nil {
+		return nil, err
+	}
+	return dues, nil
+}
+
+// SaveDues creates the dues or updates them in place
+func (r *leaseRepository) SaveDues(ctx context.Context, dues []model.RentDue) error {
+	if len(dues) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Save(&dues).Error
+}
+
+func (r *leaseRepository) DeleteDues(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Delete(&model.RentDue{}, "id IN ?", ids).Error
+}
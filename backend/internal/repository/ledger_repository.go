@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrJournalEntryNotFound = errors.New("journal entry not found")
+	// ErrDuplicatePaymentReference means the lease's ledger has a bank or
+	// gateway payment under the reference that has not been reversed
+	ErrDuplicatePaymentReference = errors.New("payment reference already recorded")
+	// ErrJournalEntryReversed means the entry was reversed before
+	ErrJournalEntryReversed = errors.New("journal entry already reversed")
+)
+
+type LedgerRepository interface {
+	EnsureAccounts(ctx context.Context, leaseID, ownerID uuid.UUID, tenantIDs []uuid.UUID) ([]model.LedgerAccount, error)
+	CreateEntry(ctx context.Context, entry *model.JournalEntry) error
+	GetEntry(ctx context.Context, leaseID, id uuid.UUID) (*model.JournalEntry, error)
+	GetReversal(ctx context.Context, id uuid.UUID) (*model.JournalEntry, error)
+	MarkReversed(ctx context.Context, id, reversalID uuid.UUID) error
+	ListEntries(ctx context.Context, leaseIDs []uuid.UUID) ([]model.JournalEntry, error)
+	GetPaymentByReference(ctx context.Context, leaseIDs []uuid.UUID, reference string) (*model.JournalEntry, error)
+}
+
+type ledgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+// EnsureAccounts opens any account the owner or the tenants do not have yet
+// on the lease and returns all of the lease's accounts, locked until the
+// transaction ends so that postings to the lease are made one after another
+func (r *ledgerRepository) EnsureAccounts(ctx context.Context, leaseID, ownerID uuid.UUID, tenantIDs []uuid.UUID) ([]model.LedgerAccount, error) {
+	accounts := make([]model.LedgerAccount, 0, len(model.LedgerAccountKinds)+len(tenantIDs)*len(model.TenantAccountKinds))
+	for _, kind := range model.LedgerAccountKinds {
+		if slices.Contains(model.TenantAccountKinds, kind) {
+			for _, tenantID := range tenantIDs {
+				accounts = append(accounts, model.LedgerAccount{ID: uuid.New(), LeaseID: leaseID, Kind: kind, PartyID: tenantID})
+			}
+			continue
+		}
+		accounts = append(accounts, model.LedgerAccount{ID: uuid.New(), LeaseID: leaseID, Kind: kind, PartyID: ownerID})
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&accounts).Error; err != nil {
+		return nil, err
+	}
+
+	accounts = nil
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("lease_id = ?", leaseID).
+		Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// CreateEntry inserts the entry and its postings. A bank or gateway payment
+// whose reference is on the lease's ledger already is not inserted, and
+// ErrDuplicatePaymentReference is returned.
+func (r *ledgerRepository) CreateEntry(ctx context.Context, entry *model.JournalEntry) error {
+	db := r.db.WithContext(ctx)
+	if entry.Kind == model.JournalPayment && entry.Source != "" && entry.Reference != "" && entry.ReversesID == nil {
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Omit("Postings").Create(entry)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDuplicatePaymentReference
+		}
+	} else if err := db.Omit("Postings").Create(entry).Error; err != nil {
+		return err
+	}
+	return db.Omit("Account").Create(&entry.Postings).Error
+}
+
+func (r *ledgerRepository) GetEntry(ctx context.Context, leaseID, id uuid.UUID) (*model.JournalEntry, error) {
+	return r.first(ctx, "id = ? AND lease_id = ?", id, leaseID)
+}
+
+// GetReversal returns the entry that reverses the entry with the given ID
+func (r *ledgerRepository) GetReversal(ctx context.Context, id uuid.UUID) (*model.JournalEntry, error) {
+	return r.first(ctx, "reverses_id = ?", id)
+}
+
+// MarkReversed stamps the entry with the reversal that undoes it
+func (r *ledgerRepository) MarkReversed(ctx context.Context, id, reversalID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&model.JournalEntry{}).
+		Where("id = ? AND reversed_by_id IS NULL", id).
+		Update("reversed_by_id", reversalID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrJournalEntryReversed
+	}
+	return nil
+}
+
+// GetPaymentByReference returns a payment to one of the leases recorded
+// under the reference that has not been reversed
+func (r *ledgerRepository) GetPaymentByReference(ctx context.Context, leaseIDs []uuid.UUID, reference string) (*model.JournalEntry, error) {
+	if len(leaseIDs) == 0 {
+		return nil, ErrJournalEntryNotFound
+	}
+	return r.first(ctx, "kind = ? AND reference = ? AND lease_id IN ? AND reverses_id IS NULL AND reversed_by_id IS NULL",
+		model.JournalPayment, reference, leaseIDs)
+}
+
+func (r *ledgerRepository) first(ctx context.Context, query string, args ...any) (*model.JournalEntry, error) {
+	var entry model.JournalEntry
+	if err := r.db.WithContext(ctx).Preload("Postings.Account").Where(query, args...).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJournalEntryNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListEntries returns every entry posting to an account of the leases, in the
+// order they occurred, with their postings and accounts
+func (r *ledgerRepository) ListEntries(ctx context.Context, leaseIDs []uuid.UUID) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+	if len(leaseIDs) == 0 {
+		return entries, nil
+	}
+	postings := r.db.Model(&model.LedgerPosting{}).Select("ledger_postings.entry_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_postings.account_id").
+		Where("ledger_accounts.lease_id IN ?", leaseIDs)
+	if err := r.db.WithContext(ctx).
+		Preload("Postings.Account").
+		Where("id IN (?)", postings).
+		Order("occurred_on ASC, created_at ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOTPChallengeNotFound = errors.New("otp challenge not found")
+	ErrOTPAlreadyConsumed   = errors.New("otp challenge already consumed")
+)
+
+type OTPRepository interface {
+	Create(ctx context.Context, challenge *model.OTPChallenge) error
+	GetLatestByPhone(ctx context.Context, phone, purpose string) (*model.OTPChallenge, error)
+	IncrementAttempts(ctx context.Context, id uuid.UUID) error
+	Consume(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type otpRepository struct {
+	db *gorm.DB
+}
+
+func NewOTPRepository(db *gorm.DB) OTPRepository {
+	return &otpRepository{db: db}
+}
+
+func (r *otpRepository) Create(ctx context.Context, challenge *model.OTPChallenge) error {
+	return r.db.WithContext(ctx).Create(challenge).Error
+}
+
+// GetLatestByPhone returns the last code sent to phone for purpose
+func (r *otpRepository) GetLatestByPhone(ctx context.Context, phone, purpose string) (*model.OTPChallenge, error) {
+	var challenge model.OTPChallenge
+	if err := r.db.WithContext(ctx).
+		Where("phone = ? AND purpose = ?", phone, purpose).
+		Order("created_at DESC").
+		First(&challenge).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOTPChallengeNotFound
+		}
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *otpRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.OTPChallenge{}).
+		Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOTPChallengeNotFound
+	}
+	return nil
+}
+
+// Consume marks the challenge as used; it fails if another request consumed it first
+func (r *otpRepository) Consume(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.OTPChallenge{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		UpdateColumn("consumed_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOTPAlreadyConsumed
+	}
+	return nil
+}
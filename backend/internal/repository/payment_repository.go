@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPayoutProfileNotFound = errors.New("payout profile not found")
+	ErrUPIRequestNotFound    = errors.New("UPI payment request not found")
+	ErrPaymentOrderNotFound  = errors.New("payment order not found")
+	ErrPaymentRefundNotFound = errors.New("payment refund not found")
+	ErrGatewayEventNotFound  = errors.New("gateway event not found")
+)
+
+type PaymentRepository interface {
+	GetPayoutProfile(ctx context.Context, userID uuid.UUID) (*model.PayoutProfile, error)
+	SavePayoutProfile(ctx context.Context, profile *model.PayoutProfile) error
+	CreateUPIRequest(ctx context.Context, request *model.UPIPaymentRequest) error
+	GetUPIRequest(ctx context.Context, leaseID, id uuid.UUID) (*model.UPIPaymentRequest, error)
+	GetUPIRequestByReference(ctx context.Context, ref string) (*model.UPIPaymentRequest, error)
+	GetLatestUPIRequest(ctx context.Context, rentDueID uuid.UUID) (*model.UPIPaymentRequest, error)
+	ListUPIRequests(ctx context.Context, leaseID uuid.UUID) ([]model.UPIPaymentRequest, error)
+	CreateOrder(ctx context.Context, order *model.PaymentOrder) error
+	SaveOrder(ctx context.Context, order *model.PaymentOrder) error
+	GetOrder(ctx context.Context, leaseID, id uuid.UUID) (*model.PaymentOrder, error)
+	LockOrder(ctx context.Context, leaseID, id uuid.UUID) (*model.PaymentOrder, error)
+	LockOrderByGatewayID(ctx context.Context, gateway, gatewayOrderID string) (*model.PaymentOrder, error)
+	LockOrderByPaymentID(ctx context.Context, gateway, gatewayPaymentID string) (*model.PaymentOrder, error)
+	GetLatestOrder(ctx context.Context, rentDueID uuid.UUID) (*model.PaymentOrder, error)
+	ListOrders(ctx context.Context, leaseID uuid.UUID) ([]model.PaymentOrder, error)
+	CreateRefund(ctx context.Context, refund *model.PaymentRefund) error
+	SaveRefund(ctx context.Context, refund *model.PaymentRefund) error
+	GetRefundByGatewayID(ctx context.Context, gatewayRefundID string) (*model.PaymentRefund, error)
+	LockRefund(ctx context.Context, orderID, id uuid.UUID) (*model.PaymentRefund, error)
+	GetPendingRefund(ctx context.Context, orderID uuid.UUID) (*model.PaymentRefund, error)
+	ClaimEvent(ctx context.Context, event *model.GatewayEvent) (bool, error)
+	GetEvent(ctx context.Context, gateway, eventID string) (*model.GatewayEvent, error)
+	CreateEvent(ctx context.Context, event *model.GatewayEvent) error
+	SaveEvent(ctx context.Context, event *model.GatewayEvent) error
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) GetPayoutProfile(ctx context.Context, userID uuid.UUID) (*model.PayoutProfile, error) {
+	var profile model.PayoutProfile
+	if err := r.db.WithContext(ctx).First(&profile, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPayoutProfileNotFound
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SavePayoutProfile creates the profile or replaces it
+func (r *paymentRepository) SavePayoutProfile(ctx context.Context, profile *model.PayoutProfile) error {
+	return r.db.WithContext(ctx).Save(profile).Error
+}
+
+func (r *paymentRepository) CreateUPIRequest(ctx context.Context, request *model.UPIPaymentRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+func (r *paymentRepository) GetUPIRequest(ctx context.Context, leaseID, id uuid.UUID) (*model.UPIPaymentRequest, error) {
+	return r.firstUPIRequest(r.db.WithContext(ctx).Where("id = ? AND lease_id = ?", id, leaseID))
+}
+
+func (r *paymentRepository) GetUPIRequestByReference(ctx context.Context, ref string) (*model.UPIPaymentRequest, error) {
+	return r.firstUPIRequest(r.db.WithContext(ctx).Where("transaction_ref = ?", ref))
+}
+
+// GetLatestUPIRequest returns the request last generated for the due
+func (r *paymentRepository) GetLatestUPIRequest(ctx context.Context, rentDueID uuid.UUID) (*model.UPIPaymentRequest, error) {
+	return r.firstUPIRequest(r.db.WithContext(ctx).Where("rent_due_id = ?", rentDueID).Order("created_at DESC"))
+}
+
+func (r *paymentRepository) firstUPIRequest(query *gorm.DB) (*model.UPIPaymentRequest, error) {
+	var request model.UPIPaymentRequest
+	if err := query.First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUPIRequestNotFound
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *paymentRepository) ListUPIRequests(ctx context.Context, leaseID uuid.UUID) ([]model.UPIPaymentRequest, error) {
+	var requests []model.UPIPaymentRequest
+	if err := r.db.WithContext(ctx).
+		Where("lease_id = ?", leaseID).
+		Order("created_at DESC").
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (r *paymentRepository) CreateOrder(ctx context.Context, order *model.PaymentOrder) error {
+	return r.db.WithContext(ctx).Omit("Refunds").Create(order).Error
+}
+
+func (r *paymentRepository) SaveOrder(ctx context.Context, order *model.PaymentOrder) error {
+	return r.db.WithContext(ctx).Omit("Refunds").Save(order).Error
+}
+
+func (r *paymentRepository) GetOrder(ctx context.Context, leaseID, id uuid.UUID) (*model.PaymentOrder, error) {
+	return r.firstOrder(r.db.WithContext(ctx).Preload("Refunds").Where("id = ? AND lease_id = ?", id, leaseID))
+}
+
+// LockOrder returns the order locked for update until the transaction ends
+func (r *paymentRepository) LockOrder(ctx context.Context, leaseID, id uuid.UUID) (*model.PaymentOrder, error) {
+	return r.firstOrder(r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND lease_id = ?", id, leaseID))
+}
+
+// LockOrderByGatewayID returns the order the gateway knows by gatewayOrderID, locked for update
+func (r *paymentRepository) LockOrderByGatewayID(ctx context.Context, gateway, gatewayOrderID string) (*model.PaymentOrder, error) {
+	return r.firstOrder(r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("gateway = ? AND gateway_order_id = ?", gateway, gatewayOrderID))
+}
+
+// LockOrderByPaymentID returns the order paid by the gateway's payment, locked for update
+func (r *paymentRepository) LockOrderByPaymentID(ctx context.Context, gateway, gatewayPaymentID string) (*model.PaymentOrder, error) {
+	return r.firstOrder(r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("gateway = ? AND gateway_payment_id = ?", gateway, gatewayPaymentID))
+}
+
+// GetLatestOrder returns the order last placed for the due
+func (r *paymentRepository) GetLatestOrder(ctx context.Context, rentDueID uuid.UUID) (*model.PaymentOrder, error) {
+	return r.firstOrder(r.db.WithContext(ctx).Where("rent_due_id = ?", rentDueID).Order("created_at DESC"))
+}
+
+func (r *paymentRepository) firstOrder(query *gorm.DB) (*model.PaymentOrder, error) {
+	var order model.PaymentOrder
+	if err := query.First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentOrderNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *paymentRepository) ListOrders(ctx context.Context, leaseID uuid.UUID) ([]model.PaymentOrder, error) {
+	var orders []model.PaymentOrder
+	if err := r.db.WithContext(ctx).
+		Preload("Refunds", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Where("lease_id = ?", leaseID).
+		Order("created_at DESC").
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *paymentRepository) CreateRefund(ctx context.Context, refund *model.PaymentRefund) error {
+	return r.db.WithContext(ctx).Create(refund).Error
+}
+
+func (r *paymentRepository) SaveRefund(ctx context.Context, refund *model.PaymentRefund) error {
+	return r.db.WithContext(ctx).Save(refund).Error
+}
+
+func (r *paymentRepository) GetRefundByGatewayID(ctx context.Context, gatewayRefundID string) (*model.PaymentRefund, error) {
+	return r.firstRefund(r.db.WithContext(ctx).Where("gateway_refund_id = ?", gatewayRefundID))
+}
+
+// LockRefund returns the order's refund locked for update until the transaction ends
+func (r *paymentRepository) LockRefund(ctx context.Context, orderID, id uuid.UUID) (*model.PaymentRefund, error) {
+	return r.firstRefund(r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND order_id = ?", id, orderID))
+}
+
+// GetPendingRefund returns the order's oldest refund the gateway has not confirmed
+func (r *paymentRepository) GetPendingRefund(ctx context.Context, orderID uuid.UUID) (*model.PaymentRefund, error) {
+	return r.firstRefund(r.db.WithContext(ctx).Where("order_id = ? AND status = ?", orderID, model.PaymentRefundPending).Order("created_at"))
+}
+
+func (r *paymentRepository) firstRefund(query *gorm.DB) (*model.PaymentRefund, error) {
+	var refund model.PaymentRefund
+	if err := query.First(&refund).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentRefundNotFound
+		}
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// ClaimEvent stores the first delivery of a gateway event. It reports false,
+// storing nothing, when the event has been stored before; a delivery racing
+// it waits for the other's transaction to end.
+func (r *paymentRepository) ClaimEvent(ctx context.Context, event *model.GatewayEvent) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "gateway"}, {Name: "event_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "duplicate_of IS NULL"}}},
+		DoNothing:   true,
+	}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetEvent returns the first delivery of a gateway event
+func (r *paymentRepository) GetEvent(ctx context.Context, gateway, eventID string) (*model.GatewayEvent, error) {
+	var event model.GatewayEvent
+	if err := r.db.WithContext(ctx).
+		Where("gateway = ? AND event_id = ? AND duplicate_of IS NULL", gateway, eventID).
+		First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGatewayEventNotFound
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *paymentRepository) CreateEvent(ctx context.Context, event *model.GatewayEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *paymentRepository) SaveEvent(ctx context.Context, event *model.GatewayEvent) error {
+	return r.db.WithContext(ctx).Save(event).Error
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPropertyNotFound = errors.New("property not found")
+	ErrCoOwnerNotFound  = errors.New("co-owner not found")
+)
+
+type PropertyRepository interface {
+	Create(ctx context.Context, property *model.Property) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Property, error)
+	List(ctx context.Context, limit, offset int) ([]model.Property, int64, error)
+	ListByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]model.Property, int64, error)
+	ListAccessibleBy(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Property, int64, error)
+	ListByBuilding(ctx context.Context, buildingID uuid.UUID, limit, offset int) ([]model.Property, int64, error)
+	UnitNumberExists(ctx context.Context, buildingID uuid.UUID, unitNumber string) (bool, error)
+	CountByOccupancy(ctx context.Context, buildingID uuid.UUID) (map[string]int64, error)
+	UpdateAddressByBuilding(ctx context.Context, buildingID uuid.UUID, address model.Address, updatedAt time.Time) error
+	Update(ctx context.Context, property *model.Property) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddCoOwner(ctx context.Context, coOwner *model.PropertyCoOwner) error
+	RemoveCoOwner(ctx context.Context, propertyID, userID uuid.UUID) error
+}
+
+type propertyRepository struct {
+	db *gorm.DB
+}
+
+func NewPropertyRepository(db *gorm.DB) PropertyRepository {
+	return &propertyRepository{db: db}
+}
+
+func (r *propertyRepository) Create(ctx context.Context, property *model.Property) error {
+	return r.db.WithContext(ctx).Omit("CoOwners").Create(property).Error
+}
+
+func (r *propertyRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Property, error) {
+	var property model.Property
+	if err := r.db.WithContext(ctx).Preload("CoOwners").First(&property, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPropertyNotFound
+		}
+		return nil, err
+	}
+	return &property, nil
+}
+
+func (r *propertyRepository) List(ctx context.Context, limit, offset int) ([]model.Property, int64, error) {
+	return r.paginate(r.db.WithContext(ctx).Model(&model.Property{}), limit, offset)
+}
+
+func (r *propertyRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]model.Property, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Property{}).Where("owner_id = ?", ownerID)
+	return r.paginate(query, limit, offset)
+}
+
+// ListAccessibleBy returns properties the user owns, co-owns or manages
+func (r *propertyRepository) ListAccessibleBy(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Property, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Property{}).
+		Where("owner_id = ? OR manager_id = ? OR id IN (?)",
+			userID, userID,
+			r.db.Model(&model.PropertyCoOwner{}).Select("property_id").Where("user_id = ?", userID),
+		)
+	return r.paginate(query, limit, offset)
+}
+
+func (r *propertyRepository) ListByBuilding(ctx context.Context, buildingID uuid.UUID, limit, offset int) ([]model.Property, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Property{}).Where("building_id = ?", buildingID)
+	return r.paginate(query, limit, offset)
+}
+
+func (r *propertyRepository) UnitNumberExists(ctx context.Context, buildingID uuid.UUID, unitNumber string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.Property{}).
+		Where("building_id = ? AND unit_number = ?", buildingID, unitNumber).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountByOccupancy returns the number of units in a building per occupancy status
+func (r *propertyRepository) CountByOccupancy(ctx context.Context, buildingID uuid.UUID) (map[string]int64, error) {
+	var rows []struct {
+		OccupancyStatus string
+		Count           int64
+	}
+	if err := r.db.WithContext(ctx).Model(&model.Property{}).
+		Select("occupancy_status, COUNT(*) AS count").
+		Where("building_id = ?", buildingID).
+		Group("occupancy_status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.OccupancyStatus] = row.Count
+	}
+	return counts, nil
+}
+
+// UpdateAddressByBuilding copies a building's address onto all of its units
+func (r *propertyRepository) UpdateAddressByBuilding(ctx context.Context, buildingID uuid.UUID, address model.Address, updatedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Property{}).
+		Where("building_id = ?", buildingID).
+		Updates(map[string]interface{}{
+			"address_line1": address.AddressLine1,
+			"address_line2": address.AddressLine2,
+			"locality":      address.Locality,
+			"city":          address.City,
+			"state":         address.State,
+			"pincode":       address.Pincode,
+			"updated_at":    updatedAt,
+		}).Error
+}
+
+func (r *propertyRepository) paginate(query *gorm.DB, limit, offset int) ([]model.Property, int64, error) {
+	var properties []model.Property
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Preload("CoOwners").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&properties).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return properties, total, nil
+}
+
+func (r *propertyRepository) Update(ctx context.Context, property *model.Property) error {
+	result := r.db.WithContext(ctx).Omit("CoOwners").Save(property)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPropertyNotFound
+	}
+	return nil
+}
+
+func (r *propertyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.Property{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPropertyNotFound
+	}
+	return nil
+}
+
+func (r *propertyRepository) AddCoOwner(ctx context.Context, coOwner *model.PropertyCoOwner) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(coOwner).Error
+}
+
+func (r *propertyRepository) RemoveCoOwner(ctx context.Context, propertyID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.PropertyCoOwner{}, "property_id = ? AND user_id = ?", propertyID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCoOwnerNotFound
+	}
+	return nil
+}
@@ -3,11 +3,33 @@ package repository
 import "gorm.io/gorm"
 
 type Repositories struct {
-	User UserRepository
+	User     UserRepository
+	OTP      OTPRepository
+	Session  SessionRepository
+	Property PropertyRepository
+	Building BuildingRepository
+	Clause   ClauseRepository
+	Lease    LeaseRepository
+	Signing  SigningRepository
+	Document DocumentRepository
+	Ledger   LedgerRepository
+	Payment  PaymentRepository
+	Bank     BankStatementRepository
 }
 
 func NewRepositories(db *gorm.DB) *Repositories {
 	return &Repositories{
-		User: NewUserRepository(db),
+		User:     NewUserRepository(db),
+		OTP:      NewOTPRepository(db),
+		Session:  NewSessionRepository(db),
+		Property: NewPropertyRepository(db),
+		Building: NewBuildingRepository(db),
+		Clause:   NewClauseRepository(db),
+		Lease:    NewLeaseRepository(db),
+		Signing:  NewSigningRepository(db),
+		Document: NewDocumentRepository(db),
+		Ledger:   NewLedgerRepository(db),
+		Payment:  NewPaymentRepository(db),
+		Bank:     NewBankStatementRepository(db),
 	}
 }
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSigningNotFound = errors.New("signing not found")
+	ErrSignerNotFound  = errors.New("signer not found")
+	// ErrSigningStatusChanged means the signing left the expected status before the update
+	ErrSigningStatusChanged = errors.New("signing status changed")
+	// ErrSigningLinkUsed means the link was used or replaced before the signature was saved
+	ErrSigningLinkUsed = errors.New("signing link already used")
+)
+
+type SigningRepository interface {
+	Create(ctx context.Context, signing *model.LeaseSigning) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.LeaseSigning, error)
+	Lock(ctx context.Context, id uuid.UUID) (*model.LeaseSigning, error)
+	GetActive(ctx context.Context, leaseID uuid.UUID) (*model.LeaseSigning, error)
+	GetLatest(ctx context.Context, leaseID uuid.UUID) (*model.LeaseSigning, error)
+	UpdateStatus(ctx context.Context, signing *model.LeaseSigning, fromStatus string) error
+	GetSignerByTokenHash(ctx context.Context, tokenHash string) (*model.LeaseSigner, error)
+	GetSignerByESignTxn(ctx context.Context, txn string) (*model.LeaseSigner, error)
+	SetSignerLink(ctx context.Context, signerID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	SetSignerESignTxn(ctx context.Context, signerID uuid.UUID, tokenHash, txn string) error
+	RecordSignature(ctx context.Context, signer *model.LeaseSigner, tokenHash string) error
+	ClearLinks(ctx context.Context, signingID uuid.UUID) error
+	CreateEvent(ctx context.Context, event *model.SigningEvent) error
+}
+
+type signingRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningRepository(db *gorm.DB) SigningRepository {
+	return &signingRepository{db: db}
+}
+
+// Create saves the signing with its signers
+func (r *signingRepository) Create(ctx context.Context, signing *model.LeaseSigning) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Signers", "Events").Create(signing).Error; err != nil {
+			return err
+		}
+		return tx.Create(&signing.Signers).Error
+	})
+}
+
+// GetByID loads the signing with its signers in signing order and its audit trail
+func (r *signingRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.LeaseSigning, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// Lock locks the signing for update until the transaction ends, then loads
+// it afresh so signatures saved while waiting for the lock are seen
+func (r *signingRepository) Lock(ctx context.Context, id uuid.UUID) (*model.LeaseSigning, error) {
+	var locked model.LeaseSigning
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSigningNotFound
+		}
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetActive returns the lease's signing that is still in progress
+func (r *signingRepository) GetActive(ctx context.Context, leaseID uuid.UUID) (*model.LeaseSigning, error) {
+	return r.first(r.db.WithContext(ctx).Where("lease_id = ? AND status = ?", leaseID, model.SigningStatusInProgress))
+}
+
+// GetLatest returns the lease's most recent signing in any status
+func (r *signingRepository) GetLatest(ctx context.Context, leaseID uuid.UUID) (*model.LeaseSigning, error) {
+	return r.first(r.db.WithContext(ctx).Where("lease_id = ?", leaseID).Order("created_at DESC"))
+}
+
+func (r *signingRepository) first(query *gorm.DB) (*model.LeaseSigning, error) {
+	var signing model.LeaseSigning
+	if err := query.
+		Preload("Signers", func(db *gorm.DB) *gorm.DB { return db.Order("signing_order, created_at") }).
+		Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		First(&signing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSigningNotFound
+		}
+		return nil, err
+	}
+	return &signing, nil
+}
+
+// UpdateStatus saves the signing's status and completion or cancellation
+// time, provided the stored status is still fromStatus
+func (r *signingRepository) UpdateStatus(ctx context.Context, signing *model.LeaseSigning, fromStatus string) error {
+	result := r.db.WithContext(ctx).Model(&model.LeaseSigning{}).
+		Where("id = ? AND status = ?", signing.ID, fromStatus).
+		Updates(map[string]any{
+			"status":       signing.Status,
+			"completed_at": signing.CompletedAt,
+			"cancelled_at": signing.CancelledAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSigningStatusChanged
+	}
+	return nil
+}
+
+func (r *signingRepository) GetSignerByTokenHash(ctx context.Context, tokenHash string) (*model.LeaseSigner, error) {
+	var signer model.LeaseSigner
+	if err := r.db.WithContext(ctx).First(&signer, "token_hash = ?", tokenHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSignerNotFound
+		}
+		return nil, err
+	}
+	return &signer, nil
+}
+
+func (r *signingRepository) GetSignerByESignTxn(ctx context.Context, txn string) (*model.LeaseSigner, error) {
+	var signer model.LeaseSigner
+	if err := r.db.WithContext(ctx).First(&signer, "esign_txn = ?", txn).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSignerNotFound
+		}
+		return nil, err
+	}
+	return &signer, nil
+}
+
+// SetSignerLink replaces the signer's link, invalidating any earlier one
+func (r *signingRepository) SetSignerLink(ctx context.Context, signerID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&model.LeaseSigner{}).
+		Where("id = ? AND status = ?", signerID, model.SignerStatusPending).
+		Updates(map[string]any{
+			"token_hash":      tokenHash,
+			"link_expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSignerNotFound
+	}
+	return nil
+}
+
+// SetSignerESignTxn records the eSign transaction started from the signer's
+// link, provided the signer is still pending on the link identified by tokenHash
+func (r *signingRepository) SetSignerESignTxn(ctx context.Context, signerID uuid.UUID, tokenHash, txn string) error {
+	result := r.db.WithContext(ctx).Model(&model.LeaseSigner{}).
+		Where("id = ? AND status = ? AND token_hash = ?", signerID, model.SignerStatusPending, tokenHash).
+		Update("esign_txn", txn)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSigningLinkUsed
+	}
+	return nil
+}
+
+// RecordSignature saves the signature and clears the link, provided the
+// signer is still pending on the link identified by tokenHash
+func (r *signingRepository) RecordSignature(ctx context.Context, signer *model.LeaseSigner, tokenHash string) error {
+	result := r.db.WithContext(ctx).Model(&model.LeaseSigner{}).
+		Where("id = ? AND status = ? AND token_hash = ?", signer.ID, model.SignerStatusPending, tokenHash).
+		Updates(map[string]any{
+			"status":          signer.Status,
+			"token_hash":      nil,
+			"link_expires_at": nil,
+			"signed_at":       signer.SignedAt,
+			"signature_type":  signer.SignatureType,
+			"signature_data":  signer.SignatureData,
+			"ip_address":      signer.IPAddress,
+			"user_agent":      signer.UserAgent,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSigningLinkUsed
+	}
+	return nil
+}
+
+// ClearLinks invalidates every outstanding link of the signing
+func (r *signingRepository) ClearLinks(ctx context.Context, signingID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&model.LeaseSigner{}).
+		Where("signing_id = ? AND token_hash IS NOT NULL", signingID).
+		Updates(map[string]any{
+			"token_hash":      nil,
+			"link_expires_at": nil,
+		}).Error
+}
+
+func (r *signingRepository) CreateEvent(ctx context.Context, event *model.SigningEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
@@ -11,14 +11,16 @@ import (
 )
 
 var (
-	ErrUserNotFound      = errors.New("user not found")
-	ErrUserAlreadyExists = errors.New("user with this email already exists")
+	ErrUserNotFound           = errors.New("user not found")
+	ErrUserAlreadyExists      = errors.New("user with this email already exists")
+	ErrUserPhoneAlreadyExists = errors.New("user with this phone already exists")
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	GetByPhone(ctx context.Context, phone string) (*model.User, error)
 	List(ctx context.Context, limit, offset int) ([]model.User, int64, error)
 	Update(ctx context.Context, user *model.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
@@ -33,12 +35,24 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *model.User) error {
-	existing, err := r.GetByEmail(ctx, user.Email)
-	if err != nil && !errors.Is(err, ErrUserNotFound) {
-		return err
+	if user.Email != nil {
+		existing, err := r.GetByEmail(ctx, *user.Email)
+		if err != nil && !errors.Is(err, ErrUserNotFound) {
+			return err
+		}
+		if existing != nil {
+			return ErrUserAlreadyExists
+		}
 	}
-	if existing != nil {
-		return ErrUserAlreadyExists
+
+	if user.Phone != nil {
+		existing, err := r.GetByPhone(ctx, *user.Phone)
+		if err != nil && !errors.Is(err, ErrUserNotFound) {
+			return err
+		}
+		if existing != nil {
+			return ErrUserPhoneAlreadyExists
+		}
 	}
 
 	return r.db.WithContext(ctx).Create(user).Error
@@ -66,6 +80,17 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.U
 	return &user, nil
 }
 
+func (r *userRepository) GetByPhone(ctx context.Context, phone string) (*model.User, error) {
+	var user model.User
+	if err := r.db.WithContext(ctx).First(&user, "phone = ?", phone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) List(ctx context.Context, limit, offset int) ([]model.User, int64, error) {
 	var users []model.User
 	var total int64
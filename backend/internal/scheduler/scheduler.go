@@ -0,0 +1,64 @@
+// Package scheduler runs background jobs at a fixed interval inside the API
+// process. Jobs must be safe to run on several instances at once.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is work repeated every Interval. A job with no interval never runs.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start runs each job straight away and then every interval. A failed run is
+// logged and the job runs again at its next tick.
+func Start(jobs ...Job) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{cancel: cancel}
+
+	for _, job := range jobs {
+		if job.Interval <= 0 {
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	return s
+}
+
+// Stop cancels the jobs and waits for runs in progress to return
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Job %s failed: %v", job.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
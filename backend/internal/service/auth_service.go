@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"backend/internal/auth"
+	"backend/internal/config"
+	"backend/internal/model"
+	"backend/internal/notify"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/phone"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, input LoginInput) (*model.TokenResponse, error)
+	RequestOTP(ctx context.Context, phoneNumber string) (*model.OTPRequestResponse, error)
+	VerifyOTP(ctx context.Context, input VerifyOTPInput) (*model.TokenResponse, error)
+	Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*model.TokenResponse, error)
+	Logout(ctx context.Context, sessionID uuid.UUID) error
+	ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]model.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	Authenticate(ctx context.Context, accessToken string) (*Principal, error)
+}
+
+// ClientInfo describes the device a session is opened from
+type ClientInfo struct {
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+}
+
+// Principal is the authenticated user and the session their token belongs to
+type Principal struct {
+	User      *model.User
+	SessionID uuid.UUID
+}
+
+type LoginInput struct {
+	Email    string
+	Password string
+	Client   ClientInfo
+}
+
+type VerifyOTPInput struct {
+	Phone  string
+	Code   string
+	Name   string // required only when the phone number has no account yet
+	Role   string // role for a new account, defaults to tenant
+	Client ClientInfo
+}
+
+type authService struct {
+	db          *gorm.DB
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	tokens      *auth.TokenManager
+	otp         *otpCodes
+	authCfg     config.AuthConfig
+}
+
+func NewAuthService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	otpRepo repository.OTPRepository,
+	sessionRepo repository.SessionRepository,
+	tokens *auth.TokenManager,
+	sms notify.SMSSender,
+	authCfg config.AuthConfig,
+	otpCfg config.OTPConfig,
+) AuthService {
+	return &authService{
+		db:          db,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		tokens:      tokens,
+		otp:         &otpCodes{repo: otpRepo, sms: sms, cfg: otpCfg},
+		authCfg:     authCfg,
+	}
+}
+
+func (s *authService) Login(ctx context.Context, input LoginInput) (*model.TokenResponse, error) {
+	user, err := s.userRepo.GetByEmail(ctx, input.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, apperr.Unauthorized("Invalid email or password", err)
+		}
+		return nil, apperr.Internal("Failed to fetch user", err)
+	}
+
+	if err := auth.CheckPassword(user.PasswordHash, input.Password); err != nil {
+		return nil, apperr.Unauthorized("Invalid email or password", err)
+	}
+
+	return s.startSession(ctx, user, input.Client)
+}
+
+func (s *authService) RequestOTP(ctx context.Context, phoneNumber string) (*model.OTPRequestResponse, error) {
+	normalized, err := phone.Normalize(phoneNumber)
+	if err != nil {
+		return nil, apperr.Invalid("Invalid phone number", err)
+	}
+
+	return s.otp.send(ctx, normalized, model.OTPPurposeLogin, nil,
+		"%s is your Rental App login code. It expires in %d minutes. Do not share it with anyone.")
+}
+
+func (s *authService) VerifyOTP(ctx context.Context, input VerifyOTPInput) (*model.TokenResponse, error) {
+	normalized, err := phone.Normalize(input.Phone)
+	if err != nil {
+		return nil, apperr.Invalid("Invalid phone number", err)
+	}
+
+	user, err := s.userRepo.GetByPhone(ctx, normalized)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, apperr.Internal("Failed to fetch user", err)
+	}
+	// Check before the code is consumed so a new user is not forced to request another OTP
+	if user == nil && input.Name == "" {
+		return nil, apperr.Invalid("Name is required to create a new account", nil)
+	}
+	if input.Role != "" && !slices.Contains(model.SignUpRoles, input.Role) {
+		return nil, apperr.Forbidden("Only the owner and tenant roles can be chosen at sign-up", nil)
+	}
+
+	if err := s.otp.consume(ctx, normalized, model.OTPPurposeLogin, nil, input.Code); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if user == nil {
+		role := input.Role
+		if role == "" {
+			role = model.RoleTenant
+		}
+
+		user = &model.User{
+			ID:        uuid.New(),
+			Name:      input.Name,
+			Phone:     &normalized,
+			Role:      role,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			if errors.Is(err, repository.ErrUserPhoneAlreadyExists) {
+				return nil, apperr.Conflict("User with this phone already exists", err)
+			}
+			return nil, apperr.Internal("Failed to create user", err)
+		}
+	}
+
+	return s.startSession(ctx, user, input.Client)
+}
+
+func (s *authService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*model.TokenResponse, error) {
+	sessionID, secret, err := auth.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, apperr.Unauthorized("Invalid refresh token", err)
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil, apperr.Unauthorized("Invalid refresh token", err)
+		}
+		return nil, apperr.Internal("Failed to fetch session", err)
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return nil, apperr.Unauthorized("Session has expired or been revoked", nil)
+	}
+
+	presentedHash := auth.HashRefreshSecret(secret)
+	if presentedHash != session.RefreshTokenHash {
+		return nil, s.revokeForReuse(ctx, session.ID, now)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, apperr.Unauthorized("User no longer exists", err)
+		}
+		return nil, apperr.Internal("Failed to fetch user", err)
+	}
+
+	newToken, newHash, err := auth.GenerateRefreshToken(session.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to generate refresh token", err)
+	}
+	expiresAt := now.Add(s.refreshTTL())
+
+	if err := s.sessionRepo.RotateRefreshToken(ctx, session.ID, presentedHash, newHash, now, expiresAt); err != nil {
+		if errors.Is(err, repository.ErrSessionTokenMismatch) {
+			// Another request rotated this token first, so it was presented twice
+			return nil, s.revokeForReuse(ctx, session.ID, now)
+		}
+		return nil, apperr.Internal("Failed to rotate refresh token", err)
+	}
+
+	return s.issueTokens(user, session.ID, newToken, expiresAt)
+}
+
+func (s *authService) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	if err := s.sessionRepo.Revoke(ctx, sessionID, model.SessionRevokedLogout, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return apperr.NotFound("Session not found", err)
+		}
+		return apperr.Internal("Failed to revoke session", err)
+	}
+	return nil
+}
+
+func (s *authService) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]model.Session, error) {
+	sessions, err := s.sessionRepo.ListActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch sessions", err)
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return apperr.NotFound("Session not found", err)
+		}
+		return apperr.Internal("Failed to fetch session", err)
+	}
+	// Do not reveal whether another user's session ID exists
+	if session.UserID != userID {
+		return apperr.NotFound("Session not found", nil)
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID, model.SessionRevokedByUser, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return apperr.NotFound("Session not found", err)
+		}
+		return apperr.Internal("Failed to revoke session", err)
+	}
+	return nil
+}
+
+func (s *authService) Authenticate(ctx context.Context, accessToken string) (*Principal, error) {
+	claims, err := s.tokens.Verify(accessToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrExpiredToken) {
+			return nil, apperr.Unauthorized("Access token has expired", err)
+		}
+		return nil, apperr.Unauthorized("Invalid access token", err)
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, apperr.Unauthorized("Invalid access token", err)
+	}
+	sessionID, err := claims.Session()
+	if err != nil {
+		return nil, apperr.Unauthorized("Invalid access token", err)
+	}
+
+	// Checked on every request so revocation applies before the access token expires
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil, apperr.Unauthorized("Session has expired or been revoked", err)
+		}
+		return nil, apperr.Internal("Failed to fetch session", err)
+	}
+	if session.UserID != userID || !session.IsActive(time.Now()) {
+		return nil, apperr.Unauthorized("Session has expired or been revoked", nil)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, apperr.Unauthorized("User no longer exists", err)
+		}
+		return nil, apperr.Internal("Failed to fetch user", err)
+	}
+
+	return &Principal{User: user, SessionID: session.ID}, nil
+}
+
+func (s *authService) startSession(ctx context.Context, user *model.User, client ClientInfo) (*model.TokenResponse, error) {
+	now := time.Now()
+	session := &model.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		DeviceName: client.DeviceName,
+		UserAgent:  truncate(client.UserAgent, 500),
+		IPAddress:  client.IPAddress,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(s.refreshTTL()),
+		CreatedAt:  now,
+	}
+
+	refreshToken, hash, err := auth.GenerateRefreshToken(session.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to generate refresh token", err)
+	}
+	session.RefreshTokenHash = hash
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, apperr.Internal("Failed to create session", err)
+	}
+
+	return s.issueTokens(user, session.ID, refreshToken, session.ExpiresAt)
+}
+
+func (s *authService) revokeForReuse(ctx context.Context, sessionID uuid.UUID, at time.Time) error {
+	if err := s.sessionRepo.Revoke(ctx, sessionID, model.SessionRevokedReuse, at); err != nil && !errors.Is(err, repository.ErrSessionNotFound) {
+		return apperr.Internal("Failed to revoke session", err)
+	}
+	return apperr.Unauthorized("Refresh token reuse detected, session has been revoked", nil)
+}
+
+func (s *authService) refreshTTL() time.Duration {
+	return time.Duration(s.authCfg.RefreshTokenTTL) * 24 * time.Hour
+}
+
+func (s *authService) issueTokens(user *model.User, sessionID uuid.UUID, refreshToken string, refreshExpiresAt time.Time) (*model.TokenResponse, error) {
+	accessToken, expiresAt, err := s.tokens.Issue(user.ID, user.Role, sessionID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to issue access token", err)
+	}
+
+	return &model.TokenResponse{
+		AccessToken:           accessToken,
+		TokenType:             "Bearer",
+		ExpiresAt:             expiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+		User:                  user,
+	}, nil
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
@@ -0,0 +1,576 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/internal/storage"
+	"backend/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+type BuildingService interface {
+	Create(ctx context.Context, actor *model.User, input CreateBuildingInput) (*model.Building, error)
+	GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Building, error)
+	List(ctx context.Context, actor *model.User, limit, offset int) ([]model.Building, int64, error)
+	Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdateBuildingInput) (*model.Building, error)
+	Delete(ctx context.Context, actor *model.User, id uuid.UUID) error
+	AssignManager(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Building, error)
+	RemoveManager(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Building, error)
+	AddUnit(ctx context.Context, actor *model.User, id uuid.UUID, input CreateUnitInput) (*model.Property, error)
+	ListUnits(ctx context.Context, actor *model.User, id uuid.UUID, limit, offset int) ([]model.Property, int64, error)
+	Occupancy(ctx context.Context, actor *model.User, id uuid.UUID) (*model.BuildingOccupancy, error)
+	AddCharge(ctx context.Context, actor *model.User, id uuid.UUID, input CreateBuildingChargeInput) (*model.BuildingCharge, error)
+	RemoveCharge(ctx context.Context, actor *model.User, id, chargeID uuid.UUID) error
+	UploadDocument(ctx context.Context, actor *model.User, id uuid.UUID, input UploadBuildingDocumentInput) (*model.BuildingDocument, error)
+	ListDocuments(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.BuildingDocument, error)
+	OpenDocument(ctx context.Context, actor *model.User, id, documentID uuid.UUID) (*model.BuildingDocument, io.ReadCloser, error)
+	DeleteDocument(ctx context.Context, actor *model.User, id, documentID uuid.UUID) error
+}
+
+type CreateBuildingInput struct {
+	OwnerID     *uuid.UUID // admin only; defaults to the actor
+	Name        string
+	SocietyName string
+	Address     model.Address
+	TotalFloors *int
+}
+
+type UpdateBuildingInput struct {
+	Name         *string
+	SocietyName  *string
+	AddressLine1 *string
+	AddressLine2 *string
+	Locality     *string
+	City         *string
+	State        *string
+	Pincode      *string
+	TotalFloors  *int
+}
+
+type CreateUnitInput struct {
+	UnitNumber      string
+	Name            string
+	PropertyType    string
+	BHK             int
+	CarpetAreaSqft  float64
+	BuiltUpAreaSqft *float64
+	Furnishing      string
+	Floor           int
+	Parking         string
+}
+
+type CreateBuildingChargeInput struct {
+	Name        string
+	AmountPaise int64
+	Frequency   string
+}
+
+type UploadBuildingDocumentInput struct {
+	Title        string
+	DocumentType string
+	FileName     string
+	ContentType  string
+	Size         int64
+	Content      io.Reader
+}
+
+// documentExtensions lists the accepted upload content types and the file
+// extension used when storing them
+var documentExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+type buildingService struct {
+	services     *Services
+	buildingRepo repository.BuildingRepository
+	propertyRepo repository.PropertyRepository
+	userRepo     repository.UserRepository
+	storage      storage.Storage
+	maxUpload    int64
+}
+
+func NewBuildingService(
+	services *Services,
+	buildingRepo repository.BuildingRepository,
+	propertyRepo repository.PropertyRepository,
+	userRepo repository.UserRepository,
+	store storage.Storage,
+	cfg config.StorageConfig,
+) BuildingService {
+	return &buildingService{
+		services:     services,
+		buildingRepo: buildingRepo,
+		propertyRepo: propertyRepo,
+		userRepo:     userRepo,
+		storage:      store,
+		maxUpload:    int64(cfg.MaxUploadMB) << 20,
+	}
+}
+
+func (s *buildingService) Create(ctx context.Context, actor *model.User, input CreateBuildingInput) (*model.Building, error) {
+	if err := policy.AuthorizeCreate(actor, policy.ResourceBuilding); err != nil {
+		return nil, err
+	}
+
+	ownerID := actor.ID
+	if input.OwnerID != nil && actor.IsAdmin() {
+		if _, err := s.userRepo.GetByID(ctx, *input.OwnerID); err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return nil, apperr.NotFound("Owner not found", err)
+			}
+			return nil, apperr.Internal("Failed to verify owner", err)
+		}
+		ownerID = *input.OwnerID
+	}
+
+	address := input.Address
+	address.State = strings.ToUpper(address.State)
+
+	building := &model.Building{
+		ID:          uuid.New(),
+		OwnerID:     ownerID,
+		Name:        input.Name,
+		SocietyName: input.SocietyName,
+		Address:     address,
+		TotalFloors: input.TotalFloors,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.buildingRepo.Create(ctx, building); err != nil {
+		return nil, apperr.Internal("Failed to create building", err)
+	}
+
+	return building, nil
+}
+
+func (s *buildingService) GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Building, error) {
+	return s.authorized(ctx, actor, policy.ActionRead, id)
+}
+
+// List returns every building for admins, and otherwise only the buildings
+// the actor owns or manages
+func (s *buildingService) List(ctx context.Context, actor *model.User, limit, offset int) ([]model.Building, int64, error) {
+	var (
+		buildings []model.Building
+		total     int64
+		err       error
+	)
+
+	if actor.IsAdmin() {
+		buildings, total, err = s.buildingRepo.List(ctx, limit, offset)
+	} else {
+		buildings, total, err = s.buildingRepo.ListAccessibleBy(ctx, actor.ID, limit, offset)
+	}
+	if err != nil {
+		return nil, 0, apperr.Internal("Failed to fetch buildings", err)
+	}
+
+	return buildings, total, nil
+}
+
+// Update changes the building and copies any address change onto its units
+func (s *buildingService) Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdateBuildingInput) (*model.Building, error) {
+	building, err := s.authorized(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		building.Name = *input.Name
+	}
+	if input.SocietyName != nil {
+		building.SocietyName = *input.SocietyName
+	}
+	if input.AddressLine1 != nil {
+		building.AddressLine1 = *input.AddressLine1
+	}
+	if input.AddressLine2 != nil {
+		building.AddressLine2 = *input.AddressLine2
+	}
+	if input.Locality != nil {
+		building.Locality = *input.Locality
+	}
+	if input.City != nil {
+		building.City = *input.City
+	}
+	if input.State != nil {
+		building.State = strings.ToUpper(*input.State)
+	}
+	if input.Pincode != nil {
+		building.Pincode = *input.Pincode
+	}
+	if input.TotalFloors != nil {
+		building.TotalFloors = input.TotalFloors
+	}
+	building.UpdatedAt = time.Now()
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Building.Update(ctx, building); err != nil {
+			return apperr.Internal("Failed to update building", err)
+		}
+		if err := tx.repos.Property.UpdateAddressByBuilding(ctx, building.ID, building.Address, building.UpdatedAt); err != nil {
+			return apperr.Internal("Failed to update unit addresses", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return building, nil
+}
+
+func (s *buildingService) Delete(ctx context.Context, actor *model.User, id uuid.UUID) error {
+	if _, err := s.authorized(ctx, actor, policy.ActionDelete, id); err != nil {
+		return err
+	}
+
+	_, units, err := s.propertyRepo.ListByBuilding(ctx, id, 1, 0)
+	if err != nil {
+		return apperr.Internal("Failed to check building units", err)
+	}
+	if units > 0 {
+		return apperr.Conflict("Remove the building's units before deleting it", nil)
+	}
+
+	documents, err := s.buildingRepo.ListDocuments(ctx, id)
+	if err != nil {
+		return apperr.Internal("Failed to fetch building documents", err)
+	}
+
+	if err := s.buildingRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrBuildingNotFound) {
+			return apperr.NotFound("Building not found", err)
+		}
+		return apperr.Internal("Failed to delete building", err)
+	}
+
+	for _, document := range documents {
+		s.removeFile(ctx, document.StorageKey)
+	}
+	return nil
+}
+
+func (s *buildingService) AssignManager(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Building, error) {
+	building, err := s.authorized(ctx, actor, policy.ActionManage, id)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, apperr.NotFound("User not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch user", err)
+	}
+	if manager.Role != model.RoleManager && manager.Role != model.RoleTenant {
+		return nil, apperr.Invalid("User must have one of the roles: manager, tenant", nil)
+	}
+
+	building.ManagerID = &userID
+	building.UpdatedAt = time.Now()
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := grantRole(ctx, tx.repos, manager, model.RoleManager); err != nil {
+			return err
+		}
+		if err := tx.repos.Building.Update(ctx, building); err != nil {
+			return apperr.Internal("Failed to assign manager", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return building, nil
+}
+
+func (s *buildingService) RemoveManager(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Building, error) {
+	building, err := s.authorized(ctx, actor, policy.ActionManage, id)
+	if err != nil {
+		return nil, err
+	}
+
+	building.ManagerID = nil
+	building.UpdatedAt = time.Now()
+
+	if err := s.buildingRepo.Update(ctx, building); err != nil {
+		return nil, apperr.Internal("Failed to remove manager", err)
+	}
+
+	return building, nil
+}
+
+// AddUnit creates a rentable unit in the building. The unit inherits the
+// building's owner, manager and address.
+func (s *buildingService) AddUnit(ctx context.Context, actor *model.User, id uuid.UUID, input CreateUnitInput) (*model.Property, error) {
+	building, err := s.authorized(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.propertyRepo.UnitNumberExists(ctx, building.ID, input.UnitNumber)
+	if err != nil {
+		return nil, apperr.Internal("Failed to check unit number", err)
+	}
+	if exists {
+		return nil, apperr.Conflict("Unit number already exists in this building", nil)
+	}
+
+	name := input.Name
+	if name == "" {
+		name = building.Name + " - " + input.UnitNumber
+	}
+	parking := input.Parking
+	if parking == "" {
+		parking = model.ParkingNone
+	}
+
+	unit := &model.Property{
+		ID:              uuid.New(),
+		OwnerID:         building.OwnerID,
+		ManagerID:       building.ManagerID,
+		BuildingID:      &building.ID,
+		UnitNumber:      input.UnitNumber,
+		Name:            name,
+		PropertyType:    input.PropertyType,
+		Address:         building.Address,
+		BHK:             input.BHK,
+		CarpetAreaSqft:  input.CarpetAreaSqft,
+		BuiltUpAreaSqft: input.BuiltUpAreaSqft,
+		Furnishing:      input.Furnishing,
+		Floor:           input.Floor,
+		TotalFloors:     building.TotalFloors,
+		Parking:         parking,
+		OccupancyStatus: model.OccupancyVacant,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	if err := validatePropertyLayout(unit); err != nil {
+		return nil, err
+	}
+
+	if err := s.propertyRepo.Create(ctx, unit); err != nil {
+		return nil, apperr.Internal("Failed to create unit", err)
+	}
+
+	return unit, nil
+}
+
+func (s *buildingService) ListUnits(ctx context.Context, actor *model.User, id uuid.UUID, limit, offset int) ([]model.Property, int64, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, 0, err
+	}
+
+	units, total, err := s.propertyRepo.ListByBuilding(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, apperr.Internal("Failed to fetch units", err)
+	}
+
+	return units, total, nil
+}
+
+func (s *buildingService) Occupancy(ctx context.Context, actor *model.User, id uuid.UUID) (*model.BuildingOccupancy, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.propertyRepo.CountByOccupancy(ctx, id)
+	if err != nil {
+		return nil, apperr.Internal("Failed to count units", err)
+	}
+
+	occupancy := &model.BuildingOccupancy{
+		BuildingID: id,
+		Occupied:   counts[model.OccupancyOccupied],
+		Vacant:     counts[model.OccupancyVacant],
+	}
+	occupancy.TotalUnits = occupancy.Occupied + occupancy.Vacant
+	if occupancy.TotalUnits > 0 {
+		occupancy.OccupancyRate = float64(occupancy.Occupied) * 100 / float64(occupancy.TotalUnits)
+	}
+
+	return occupancy, nil
+}
+
+func (s *buildingService) AddCharge(ctx context.Context, actor *model.User, id uuid.UUID, input CreateBuildingChargeInput) (*model.BuildingCharge, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionUpdate, id); err != nil {
+		return nil, err
+	}
+
+	charge := &model.BuildingCharge{
+		ID:          uuid.New(),
+		BuildingID:  id,
+		Name:        input.Name,
+		AmountPaise: input.AmountPaise,
+		Frequency:   input.Frequency,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.buildingRepo.CreateCharge(ctx, charge); err != nil {
+		return nil, apperr.Internal("Failed to add charge", err)
+	}
+
+	return charge, nil
+}
+
+func (s *buildingService) RemoveCharge(ctx context.Context, actor *model.User, id, chargeID uuid.UUID) error {
+	if _, err := s.authorized(ctx, actor, policy.ActionUpdate, id); err != nil {
+		return err
+	}
+
+	if err := s.buildingRepo.DeleteCharge(ctx, id, chargeID); err != nil {
+		if errors.Is(err, repository.ErrBuildingChargeNotFound) {
+			return apperr.NotFound("Charge not found", err)
+		}
+		return apperr.Internal("Failed to remove charge", err)
+	}
+	return nil
+}
+
+func (s *buildingService) UploadDocument(ctx context.Context, actor *model.User, id uuid.UUID, input UploadBuildingDocumentInput) (*model.BuildingDocument, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionUpdate, id); err != nil {
+		return nil, err
+	}
+
+	ext, ok := documentExtensions[input.ContentType]
+	if !ok {
+		return nil, apperr.Invalid("Only PDF, JPEG and PNG documents are accepted", nil)
+	}
+	if input.Size > s.maxUpload {
+		return nil, apperr.Invalid(fmt.Sprintf("Document must not exceed %d MB", s.maxUpload>>20), nil)
+	}
+
+	document := &model.BuildingDocument{
+		ID:           uuid.New(),
+		BuildingID:   id,
+		Title:        input.Title,
+		DocumentType: input.DocumentType,
+		FileName:     input.FileName,
+		ContentType:  input.ContentType,
+		SizeBytes:    input.Size,
+		UploadedBy:   actor.ID,
+		CreatedAt:    time.Now(),
+	}
+	document.StorageKey = fmt.Sprintf("buildings/%s/documents/%s%s", id, document.ID, ext)
+
+	if err := s.storage.Put(ctx, document.StorageKey, input.Content); err != nil {
+		return nil, apperr.Internal("Failed to store document", err)
+	}
+
+	if err := s.buildingRepo.CreateDocument(ctx, document); err != nil {
+		s.removeFile(ctx, document.StorageKey)
+		return nil, apperr.Internal("Failed to save document", err)
+	}
+
+	return document, nil
+}
+
+func (s *buildingService) ListDocuments(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.BuildingDocument, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+
+	documents, err := s.buildingRepo.ListDocuments(ctx, id)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch documents", err)
+	}
+
+	return documents, nil
+}
+
+// OpenDocument returns the document and its content. The caller must close the reader.
+func (s *buildingService) OpenDocument(ctx context.Context, actor *model.User, id, documentID uuid.UUID) (*model.BuildingDocument, io.ReadCloser, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, nil, err
+	}
+
+	document, err := s.fetchDocument(ctx, id, documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.storage.Open(ctx, document.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, nil, apperr.NotFound("Document file not found", err)
+		}
+		return nil, nil, apperr.Internal("Failed to open document", err)
+	}
+
+	return document, content, nil
+}
+
+func (s *buildingService) DeleteDocument(ctx context.Context, actor *model.User, id, documentID uuid.UUID) error {
+	if _, err := s.authorized(ctx, actor, policy.ActionUpdate, id); err != nil {
+		return err
+	}
+
+	document, err := s.fetchDocument(ctx, id, documentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.buildingRepo.DeleteDocument(ctx, id, documentID); err != nil {
+		if errors.Is(err, repository.ErrBuildingDocumentNotFound) {
+			return apperr.NotFound("Document not found", err)
+		}
+		return apperr.Internal("Failed to delete document", err)
+	}
+
+	s.removeFile(ctx, document.StorageKey)
+	return nil
+}
+
+// authorized loads the building and checks the actor may perform the action on it
+func (s *buildingService) authorized(ctx context.Context, actor *model.User, action policy.Action, id uuid.UUID) (*model.Building, error) {
+	building, err := s.buildingRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrBuildingNotFound) {
+			return nil, apperr.NotFound("Building not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch building", err)
+	}
+
+	if err := policy.Authorize(actor, action, policy.ForBuilding(building)); err != nil {
+		return nil, err
+	}
+
+	return building, nil
+}
+
+func (s *buildingService) fetchDocument(ctx context.Context, id, documentID uuid.UUID) (*model.BuildingDocument, error) {
+	document, err := s.buildingRepo.GetDocument(ctx, id, documentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrBuildingDocumentNotFound) {
+			return nil, apperr.NotFound("Document not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch document", err)
+	}
+	return document, nil
+}
+
+// removeFile deletes a stored file on a best-effort basis; the database row is the source of truth
+func (s *buildingService) removeFile(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("Failed to delete stored file %s: %v", key, err)
+	}
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/internal/clausetext"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/india"
+
+	"github.com/google/uuid"
+)
+
+type ClauseService interface {
+	Create(ctx context.Context, actor *model.User, input CreateClauseInput) (*model.Clause, error)
+	GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Clause, error)
+	List(ctx context.Context, actor *model.User, category string, limit, offset int) ([]model.Clause, int64, error)
+	Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdateClauseInput) (*model.Clause, error)
+	Archive(ctx context.Context, actor *model.User, id uuid.UUID) error
+	ListVersions(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.ClauseVersion, error)
+	Translate(ctx context.Context, actor *model.User, id uuid.UUID, lang string, input TranslateClauseInput) (*model.ClauseTranslation, error)
+	ListTranslations(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.ClauseTranslation, error)
+	DeleteTranslation(ctx context.Context, actor *model.User, id uuid.UUID, lang string) error
+}
+
+type CreateClauseInput struct {
+	Category    string
+	Title       string
+	Body        string
+	IsMandatory bool
+}
+
+type UpdateClauseInput struct {
+	Category    *string
+	Title       *string
+	Body        *string
+	IsMandatory *bool
+}
+
+type TranslateClauseInput struct {
+	Title string
+	Body  string
+}
+
+type clauseService struct {
+	services   *Services
+	clauseRepo repository.ClauseRepository
+}
+
+func NewClauseService(services *Services, clauseRepo repository.ClauseRepository) ClauseService {
+	return &clauseService{
+		services:   services,
+		clauseRepo: clauseRepo,
+	}
+}
+
+// Create adds a clause to the library. Clauses created by admins are system
+// clauses available to everyone; anyone else creates a custom clause of their own.
+func (s *clauseService) Create(ctx context.Context, actor *model.User, input CreateClauseInput) (*model.Clause, error) {
+	if err := policy.AuthorizeCreate(actor, policy.ResourceClause); err != nil {
+		return nil, err
+	}
+	if err := validateClauseText(input.Body); err != nil {
+		return nil, err
+	}
+
+	clause := &model.Clause{
+		ID:          uuid.New(),
+		Category:    input.Category,
+		Title:       input.Title,
+		IsMandatory: input.IsMandatory,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if !actor.IsAdmin() {
+		if input.IsMandatory {
+			return nil, apperr.Invalid("Only system clauses can be mandatory", nil)
+		}
+		clause.OwnerID = &actor.ID
+	}
+
+	err := s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Clause.Create(ctx, clause); err != nil {
+			return apperr.Internal("Failed to create clause", err)
+		}
+		return s.addVersion(ctx, tx, actor, clause, input.Body)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clause, nil
+}
+
+func (s *clauseService) GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Clause, error) {
+	return s.authorized(ctx, actor, policy.ActionRead, id)
+}
+
+// List returns the system clauses plus the actor's own custom clauses, or every clause for admins
+func (s *clauseService) List(ctx context.Context, actor *model.User, category string, limit, offset int) ([]model.Clause, int64, error) {
+	filter := repository.ClauseFilter{Category: category}
+	if !actor.IsAdmin() {
+		filter.OwnerID = &actor.ID
+	}
+
+	clauses, total, err := s.clauseRepo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, apperr.Internal("Failed to fetch clauses", err)
+	}
+
+	return clauses, total, nil
+}
+
+// Update changes the clause. A new body is stored as a new version; leases
+// keep the version they were drafted with.
+func (s *clauseService) Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdateClauseInput) (*model.Clause, error) {
+	clause, err := s.authorized(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if clause.ArchivedAt != nil {
+		return nil, apperr.Invalid("Archived clauses cannot be changed", nil)
+	}
+	if input.Body != nil {
+		if err := validateClauseText(*input.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Category != nil {
+		clause.Category = *input.Category
+	}
+	if input.Title != nil {
+		clause.Title = *input.Title
+	}
+	if input.IsMandatory != nil {
+		if *input.IsMandatory && !clause.IsSystem() {
+			return nil, apperr.Invalid("Only system clauses can be mandatory", nil)
+		}
+		clause.IsMandatory = *input.IsMandatory
+	}
+	clause.UpdatedAt = time.Now()
+
+	bodyChanged := input.Body != nil && (clause.CurrentVersion == nil || clause.CurrentVersion.Body != *input.Body)
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if bodyChanged {
+			return s.addVersion(ctx, tx, actor, clause, *input.Body)
+		}
+		if err := tx.repos.Clause.Update(ctx, clause); err != nil {
+			return apperr.Internal("Failed to update clause", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clause, nil
+}
+
+// Archive hides the clause from the library. Versions already attached to leases are kept.
+func (s *clauseService) Archive(ctx context.Context, actor *model.User, id uuid.UUID) error {
+	clause, err := s.authorized(ctx, actor, policy.ActionDelete, id)
+	if err != nil {
+		return err
+	}
+	if clause.ArchivedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	clause.ArchivedAt = &now
+	clause.UpdatedAt = now
+
+	if err := s.clauseRepo.Update(ctx, clause); err != nil {
+		return apperr.Internal("Failed to archive clause", err)
+	}
+	return nil
+}
+
+func (s *clauseService) ListVersions(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.ClauseVersion, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.clauseRepo.ListVersions(ctx, id)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch clause versions", err)
+	}
+
+	return versions, nil
+}
+
+// Translate sets the wording of the clause's current version in lang. It is
+// added as the next revision of the translation; earlier revisions are kept,
+// as lease versions may quote them.
+func (s *clauseService) Translate(ctx context.Context, actor *model.User, id uuid.UUID, lang string, input TranslateClauseInput) (*model.ClauseTranslation, error) {
+	clause, err := s.translatable(ctx, actor, id, lang)
+	if err != nil {
+		return nil, err
+	}
+	if clause.ArchivedAt != nil {
+		return nil, apperr.Invalid("Archived clauses cannot be changed", nil)
+	}
+	if err := validateClauseText(input.Body); err != nil {
+		return nil, err
+	}
+
+	translation := &model.ClauseTranslation{
+		ID:              uuid.New(),
+		ClauseVersionID: clause.CurrentVersion.ID,
+		Lang:            lang,
+		Title:           input.Title,
+		Body:            input.Body,
+		CreatedBy:       &actor.ID,
+		CreatedAt:       time.Now(),
+		Version:         clause.CurrentVersion.Version,
+	}
+	if err := s.clauseRepo.CreateTranslation(ctx, translation); err != nil {
+		return nil, apperr.Internal("Failed to save clause translation", err)
+	}
+	return translation, nil
+}
+
+// ListTranslations returns every revision of the translations of every
+// version of the clause
+func (s *clauseService) ListTranslations(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.ClauseTranslation, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+
+	translations, err := s.clauseRepo.ListTranslations(ctx, id)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch clause translations", err)
+	}
+	return translations, nil
+}
+
+// DeleteTranslation withdraws the translation of the clause's current
+// version in lang by adding a withdrawn revision; leases then render that
+// clause in English
+func (s *clauseService) DeleteTranslation(ctx context.Context, actor *model.User, id uuid.UUID, lang string) error {
+	clause, err := s.translatable(ctx, actor, id, lang)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.clauseRepo.GetTranslations(ctx, []uuid.UUID{clause.CurrentVersion.ID}, lang)
+	if err != nil {
+		return apperr.Internal("Failed to fetch clause translation", err)
+	}
+	if len(current) == 0 {
+		return apperr.NotFound("The clause has no "+india.LanguageName(lang)+" translation", repository.ErrClauseTranslationNotFound)
+	}
+
+	if err := s.clauseRepo.CreateTranslation(ctx, &model.ClauseTranslation{
+		ID:              uuid.New(),
+		ClauseVersionID: clause.CurrentVersion.ID,
+		Lang:            lang,
+		Withdrawn:       true,
+		CreatedBy:       &actor.ID,
+		CreatedAt:       time.Now(),
+	}); err != nil {
+		return apperr.Internal("Failed to delete clause translation", err)
+	}
+	return nil
+}
+
+// translatable fetches a clause the actor may change and checks lang is one
+// it can be translated into
+func (s *clauseService) translatable(ctx context.Context, actor *model.User, id uuid.UUID, lang string) (*model.Clause, error) {
+	if lang == india.English {
+		return nil, apperr.Invalid("English is the clause's own wording; update the clause instead", nil)
+	}
+	if !india.IsLanguageCode(lang) {
+		return nil, apperr.Invalid("Unsupported language "+lang, nil)
+	}
+
+	clause, err := s.authorized(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if clause.CurrentVersion == nil {
+		return nil, apperr.Invalid("The clause has no wording to translate", nil)
+	}
+	return clause, nil
+}
+
+// addVersion stores body as the next version of the clause and makes it current
+func (s *clauseService) addVersion(ctx context.Context, tx *Services, actor *model.User, clause *model.Clause, body string) error {
+	next := 1
+	if clause.CurrentVersion != nil {
+		next = clause.CurrentVersion.Version + 1
+	}
+
+	version := &model.ClauseVersion{
+		ID:        uuid.New(),
+		ClauseID:  clause.ID,
+		Version:   next,
+		Body:      body,
+		CreatedBy: &actor.ID,
+		CreatedAt: time.Now(),
+	}
+	if err := tx.repos.Clause.CreateVersion(ctx, version); err != nil {
+		return apperr.Internal("Failed to save clause text", err)
+	}
+
+	clause.CurrentVersionID = &version.ID
+	clause.CurrentVersion = version
+	if err := tx.repos.Clause.Update(ctx, clause); err != nil {
+		return apperr.Internal("Failed to update clause", err)
+	}
+	return nil
+}
+
+// validateClauseText rejects bodies with unknown variables or malformed placeholders
+func validateClauseText(body string) error {
+	if _, err := clausetext.Parse(body); err != nil {
+		return apperr.Invalid("Invalid clause text: "+err.Error(), err)
+	}
+	return nil
+}
+
+func (s *clauseService) authorized(ctx context.Context, actor *model.User, action policy.Action, id uuid.UUID) (*model.Clause, error) {
+	clause, err := s.clauseRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrClauseNotFound) {
+			return nil, apperr.NotFound("Clause not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch clause", err)
+	}
+
+	if err := policy.Authorize(actor, action, policy.ForClause(clause)); err != nil {
+		return nil, err
+	}
+
+	return clause, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"backend/internal/compliance"
+	"backend/internal/model"
+	"backend/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// checkCompliance checks lease against the tenancy law of its property's
+// state. clauseIDs are the clauses the lease has or is about to have.
+func (s *leaseService) checkCompliance(ctx context.Context, lease *model.Lease, clauseIDs []uuid.UUID) ([]model.ComplianceFinding, error) {
+	mandatory, err := s.clauseRepo.ListMandatory(ctx)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch mandatory clauses", err)
+	}
+	var missing []string
+	for _, clause := range mandatory {
+		if !slices.Contains(clauseIDs, clause.ID) {
+			missing = append(missing, clause.Title)
+		}
+	}
+
+	return s.tenancyLaw.Check(compliance.Input{
+		State:            lease.Property.State,
+		PremisesUse:      lease.PremisesUse,
+		MonthlyRentPaise: lease.MonthlyRentPaise,
+		DepositPaise:     lease.SecurityDepositPaise,
+		NoticePeriodDays: lease.NoticePeriodDays,
+		TermMonths:       lease.TermMonths,
+		MissingClauses:   missing,
+	}), nil
+}
+
+// withCompliance sets the compliance findings of a stored lease on it
+func (s *leaseService) withCompliance(ctx context.Context, lease *model.Lease) (*model.Lease, error) {
+	clauses, err := s.leaseRepo.ListClauses(ctx, lease.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch lease clauses", err)
+	}
+	clauseIDs := make([]uuid.UUID, 0, len(clauses))
+	for _, lc := range clauses {
+		clauseIDs = append(clauseIDs, lc.ClauseID)
+	}
+
+	if lease.Compliance, err = s.checkCompliance(ctx, lease, clauseIDs); err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// complianceError refuses a lease with findings of error severity, naming
+// each of them; warnings do not block
+func complianceError(findings []model.ComplianceFinding) error {
+	errs := compliance.Errors(findings)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, f := range errs {
+		messages = append(messages, f.Message)
+	}
+	return apperr.Invalid("The lease does not comply with the "+errs[0].Act+": "+strings.Join(messages, "; "), nil)
+}
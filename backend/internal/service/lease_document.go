@@ -0,0 +1,417 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"backend/internal/leasepdf"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/india"
+
+	"github.com/google/uuid"
+)
+
+const documentCodeLength = 12
+
+// documentCodeAlphabet leaves out 0, 1, I and O, which are easily misread from paper
+const documentCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// VerifyDocument returns what anyone holding a document's verification code
+// may see: the parties' names, the lease dates and status, and whether the
+// document has since been superseded
+func (s *leaseService) VerifyDocument(ctx context.Context, code string) (*model.DocumentVerification, error) {
+	document, err := s.documentRepo.GetByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		if errors.Is(err, repository.ErrDocumentNotFound) {
+			return nil, apperr.NotFound("No document has this verification code", err)
+		}
+		return nil, apperr.Internal("Failed to fetch document", err)
+	}
+
+	lease, err := s.fetch(ctx, document.LeaseID)
+	if err != nil {
+		return nil, err
+	}
+	latestSigning, err := s.signingRepo.GetLatest(ctx, lease.ID)
+	if err != nil && !errors.Is(err, repository.ErrSigningNotFound) {
+		return nil, apperr.Internal("Failed to fetch signing", err)
+	}
+
+	v := &model.DocumentVerification{
+		Code:          document.Code,
+		Kind:          document.Kind,
+		SHA256:        document.SHA256,
+		GeneratedAt:   document.CreatedAt,
+		LeaseStatus:   lease.Status,
+		StartDate:     lease.StartDate,
+		EndDate:       lease.EndDate,
+		PropertyCity:  lease.Property.City,
+		PropertyState: lease.Property.State,
+	}
+
+	if document.SigningID == nil {
+		owners, err := s.owners(ctx, lease)
+		if err != nil {
+			return nil, err
+		}
+		for _, owner := range owners {
+			v.Parties = append(v.Parties, model.DocumentParty{Name: owner.Name, Role: model.SignerRoleOwner})
+		}
+		for _, tenant := range tenantUsers(lease) {
+			v.Parties = append(v.Parties, model.DocumentParty{Name: tenant.Name, Role: model.SignerRoleTenant})
+		}
+
+		if document.Kind == model.DocumentKindReceipt {
+			payment, err := s.services.repos.Ledger.GetEntry(ctx, lease.ID, *document.JournalEntryID)
+			if err != nil {
+				return nil, apperr.Internal("Failed to fetch payment", err)
+			}
+			v.AmountPaise = paymentAmount(payment)
+			v.PaidOn = &payment.OccurredOn
+			v.Superseded = payment.ReversedByID != nil
+			return v, nil
+		}
+
+		// Printouts of the same content with another margin stay current
+		latest, err := s.documentRepo.GetLatest(ctx, lease.ID, model.DocumentKindLease)
+		if err != nil {
+			return nil, apperr.Internal("Failed to fetch document", err)
+		}
+		v.Superseded = latest.ContentSHA256 != document.ContentSHA256 ||
+			(latestSigning != nil && latestSigning.CreatedAt.After(document.CreatedAt))
+		return v, nil
+	}
+
+	signing, err := s.fetchSigning(ctx, *document.SigningID)
+	if err != nil {
+		return nil, err
+	}
+	for _, signer := range signing.Signers {
+		v.Parties = append(v.Parties, model.DocumentParty{Name: signer.Name, Role: signer.Role, SignedAt: signer.SignedAt})
+	}
+	v.SignedAt = signing.CompletedAt
+	v.Superseded = signing.Status == model.SigningStatusCancelled || latestSigning.ID != signing.ID
+	return v, nil
+}
+
+// leaseDocument renders the agreement before it is signed online. Each
+// distinct version printed with a given stamp margin gets its own
+// verification code; rendering an unchanged lease again with the same margin
+// gives the same bytes under the same code. The margin only changes the
+// layout, so printouts with another margin are not superseded by it.
+func (s *leaseService) leaseDocument(ctx context.Context, actor *model.User, lease *model.Lease, stampMarginMM *int) ([]byte, error) {
+	doc, err := s.document(ctx, lease, nil, india.English)
+	if err != nil {
+		return nil, err
+	}
+	margin := s.pdfConfig.StampMarginMM
+	if stampMarginMM != nil {
+		margin = *stampMarginMM
+	}
+	contentSum, err := s.contentSum(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.documentRepo.GetByContent(ctx, lease.ID, contentSum, margin)
+	if err != nil && !errors.Is(err, repository.ErrDocumentNotFound) {
+		return nil, apperr.Internal("Failed to fetch document", err)
+	}
+	if existing != nil {
+		content, sum, err := s.renderDocument(doc, existing, &margin)
+		if err != nil {
+			return nil, err
+		}
+		if sum == existing.SHA256 {
+			return content, nil
+		}
+	}
+
+	document, err := newDocument(model.DocumentKindLease, lease.ID, nil, &actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	content, sum, err := s.renderDocument(doc, document, &margin)
+	if err != nil {
+		return nil, err
+	}
+	document.SHA256 = sum
+	document.ContentSHA256 = contentSum
+	document.StampMarginMM = &margin
+	if err := s.documentRepo.Create(ctx, document); err != nil {
+		return nil, apperr.Internal("Failed to record document", err)
+	}
+	return content, nil
+}
+
+// contentSum returns the SHA-256 of the agreement rendered with the
+// configured layout and no verification code or date, which identifies what
+// it says however it is printed
+func (s *leaseService) contentSum(doc *leasepdf.Document) (string, error) {
+	canonical := *doc
+	canonical.Verification = nil
+	canonical.GeneratedAt = time.Unix(0, 0)
+	content, err := s.renderPDF(&canonical, nil)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ReceiptPDF returns the rent receipt of a payment on the lease's ledger. It
+// is rendered once with its own verification code and from then on served as
+// stored, so its hash never changes; once the payment is reversed the code
+// verifies as superseded.
+func (s *leaseService) ReceiptPDF(ctx context.Context, actor *model.User, leaseID, entryID uuid.UUID) ([]byte, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, err
+	}
+	payment, err := s.services.repos.Ledger.GetEntry(ctx, lease.ID, entryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrJournalEntryNotFound) {
+			return nil, apperr.NotFound("Journal entry not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch journal entry", err)
+	}
+	if payment.Kind != model.JournalPayment || payment.ReversesID != nil {
+		return nil, apperr.Invalid("Receipts are issued for payments only", nil)
+	}
+
+	document, err := s.documentRepo.GetByJournalEntry(ctx, payment.ID)
+	if err == nil {
+		return s.readDocument(ctx, document)
+	}
+	if !errors.Is(err, repository.ErrDocumentNotFound) {
+		return nil, apperr.Internal("Failed to fetch document", err)
+	}
+
+	owners, err := s.owners(ctx, lease)
+	if err != nil {
+		return nil, err
+	}
+	document, err = newDocument(model.DocumentKindReceipt, lease.ID, nil, &actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	document.JournalEntryID = &payment.ID
+	content, err := leasepdf.RenderReceipt(&leasepdf.Receipt{
+		Lease:        lease,
+		Property:     lease.Property,
+		Owners:       owners,
+		Tenants:      tenantUsers(lease),
+		Payment:      payment,
+		AmountPaise:  paymentAmount(payment),
+		Verification: s.verification(document),
+		GeneratedAt:  document.CreatedAt,
+	}, leasepdf.Options{PaperSize: s.pdfConfig.PaperSize})
+	if err != nil {
+		return nil, apperr.Internal("Failed to generate receipt PDF", err)
+	}
+	sum := sha256.Sum256(content)
+	document.SHA256 = hex.EncodeToString(sum[:])
+	document.StorageKey = fmt.Sprintf("leases/%s/documents/%s.pdf", lease.ID, document.ID)
+
+	if err := s.storage.Put(ctx, document.StorageKey, bytes.NewReader(content)); err != nil {
+		return nil, apperr.Internal("Failed to store receipt", err)
+	}
+	if err := s.documentRepo.Create(ctx, document); err != nil {
+		s.removeFile(ctx, document.StorageKey)
+		// Another request recorded the receipt first; serve that one
+		if existing, getErr := s.documentRepo.GetByJournalEntry(ctx, payment.ID); getErr == nil {
+			return s.readDocument(ctx, existing)
+		}
+		return nil, apperr.Internal("Failed to record document", err)
+	}
+	return content, nil
+}
+
+// paymentAmount is what a payment entry brought into the owner's cash
+func paymentAmount(payment *model.JournalEntry) int64 {
+	var amount int64
+	for _, posting := range payment.Postings {
+		amount += posting.DebitPaise
+	}
+	return amount
+}
+
+// translatedDocument renders a copy of the agreement with its clauses in
+// another language. The translations are those kept with the lease's latest
+// version, so a signed lease reads the same however its clauses are
+// translated later; a draft's version is brought up to date first.
+// Translations are for reference, so the copy is not recorded as a document
+// and carries no verification code; its footer says the English agreement
+// prevails.
+func (s *leaseService) translatedDocument(ctx context.Context, actor *model.User, lease *model.Lease, input LeasePDFInput) ([]byte, error) {
+	if !s.fonts.Supports(india.Languages[input.Lang].Script) {
+		return nil, apperr.Invalid("Leases cannot be printed in "+india.LanguageName(input.Lang)+" on this server: its fonts are not installed", nil)
+	}
+
+	version, err := s.leaseRepo.GetLatestVersion(ctx, lease.ID)
+	if err != nil && !errors.Is(err, repository.ErrLeaseVersionNotFound) {
+		return nil, apperr.Internal("Failed to fetch lease version", err)
+	}
+	if version == nil || lease.Status == model.LeaseStatusDraft {
+		if version, err = s.currentVersion(ctx, lease.ID, &actor.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	doc, err := s.document(ctx, lease, versionTranslationsIn(version, input.Lang), input.Lang)
+	if err != nil {
+		return nil, err
+	}
+	doc.Bilingual = input.Bilingual
+	doc.GeneratedAt = lease.UpdatedAt
+
+	return s.renderPDF(doc, input.StampMarginMM)
+}
+
+// versionTranslationsIn returns the translations into lang kept with the
+// version, by the clause version they translate
+func versionTranslationsIn(version *model.LeaseVersion, lang string) map[uuid.UUID]model.ClauseTranslation {
+	clauseVersions := map[int]uuid.UUID{}
+	for _, clause := range version.Clauses {
+		clauseVersions[clause.Position] = clause.ClauseVersionID
+	}
+	translations := map[uuid.UUID]model.ClauseTranslation{}
+	for _, t := range version.ClauseTranslations {
+		if t.Lang != lang {
+			continue
+		}
+		translations[clauseVersions[t.Position]] = model.ClauseTranslation{
+			ID:              t.TranslationID,
+			ClauseVersionID: clauseVersions[t.Position],
+			Lang:            t.Lang,
+			Title:           t.Title,
+			Body:            t.Text,
+		}
+	}
+	return translations
+}
+
+// signedDocument returns the agreement of a completed signing round with its
+// audit certificate. It is rendered once and from then on served as stored,
+// so its hash never changes.
+func (s *leaseService) signedDocument(ctx context.Context, lease *model.Lease, signing *model.LeaseSigning) ([]byte, error) {
+	document, err := s.documentRepo.GetBySigning(ctx, signing.ID, model.DocumentKindLeaseSigned)
+	if err == nil {
+		return s.readDocument(ctx, document)
+	}
+	if !errors.Is(err, repository.ErrDocumentNotFound) {
+		return nil, apperr.Internal("Failed to fetch document", err)
+	}
+
+	doc, err := s.document(ctx, lease, nil, india.English)
+	if err != nil {
+		return nil, err
+	}
+	doc.Signing = signing
+	for _, signer := range signing.Signers {
+		if signer.Role == model.SignerRoleWitness {
+			doc.Witnesses = append(doc.Witnesses, signer.Name)
+		}
+	}
+
+	document, err = newDocument(model.DocumentKindLeaseSigned, lease.ID, &signing.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	content, sum, err := s.renderDocument(doc, document, nil)
+	if err != nil {
+		return nil, err
+	}
+	document.SHA256 = sum
+	document.StorageKey = fmt.Sprintf("leases/%s/documents/%s.pdf", lease.ID, document.ID)
+
+	if err := s.storage.Put(ctx, document.StorageKey, bytes.NewReader(content)); err != nil {
+		return nil, apperr.Internal("Failed to store signed document", err)
+	}
+	if err := s.documentRepo.Create(ctx, document); err != nil {
+		s.removeFile(ctx, document.StorageKey)
+		// Another request froze the signed version first; serve that one
+		if existing, getErr := s.documentRepo.GetBySigning(ctx, signing.ID, model.DocumentKindLeaseSigned); getErr == nil {
+			return s.readDocument(ctx, existing)
+		}
+		return nil, apperr.Internal("Failed to record document", err)
+	}
+	return content, nil
+}
+
+// renderDocument renders the agreement carrying the document's verification
+// code and returns it with its SHA-256
+func (s *leaseService) renderDocument(doc *leasepdf.Document, document *model.Document, stampMarginMM *int) ([]byte, string, error) {
+	doc.Verification = s.verification(document)
+	doc.GeneratedAt = document.CreatedAt
+
+	content, err := s.renderPDF(doc, stampMarginMM)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(content)
+	return content, hex.EncodeToString(sum[:]), nil
+}
+
+// verification is what the document's QR code points at
+func (s *leaseService) verification(document *model.Document) *leasepdf.Verification {
+	return &leasepdf.Verification{
+		Code: document.Code,
+		URL:  strings.TrimRight(s.documentCfg.VerifyBaseURL, "/") + "/" + document.Code,
+	}
+}
+
+func (s *leaseService) readDocument(ctx context.Context, document *model.Document) ([]byte, error) {
+	content, err := s.storage.Open(ctx, document.StorageKey)
+	if err != nil {
+		return nil, apperr.Internal("Failed to open document", err)
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, apperr.Internal("Failed to read document", err)
+	}
+	return data, nil
+}
+
+// newDocument starts a document record with a fresh verification code. The
+// creation time is kept to the second, as it is printed in the PDF.
+func newDocument(kind string, leaseID uuid.UUID, signingID, createdBy *uuid.UUID) (*model.Document, error) {
+	code, err := newDocumentCode()
+	if err != nil {
+		return nil, apperr.Internal("Failed to generate verification code", err)
+	}
+	return &model.Document{
+		ID:        uuid.New(),
+		Code:      code,
+		Kind:      kind,
+		LeaseID:   leaseID,
+		SigningID: signingID,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().Truncate(time.Second),
+	}, nil
+}
+
+func newDocumentCode() (string, error) {
+	random := make([]byte, documentCodeLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	code := make([]byte, documentCodeLength)
+	for i, b := range random {
+		code[i] = documentCodeAlphabet[int(b)%len(documentCodeAlphabet)]
+	}
+	return string(code), nil
+}
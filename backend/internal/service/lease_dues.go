@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// ListDues returns the lease's unpaid rent: what is overdue and what is still
+// to fall due, and what the tenants have paid beyond every due
+func (s *leaseService) ListDues(ctx context.Context, actor *model.User, id uuid.UUID) (*model.RentDueSchedule, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dues, err := s.leaseRepo.ListDues(ctx, lease.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch rent dues", err)
+	}
+	entries, err := s.services.repos.Ledger.ListEntries(ctx, []uuid.UUID{lease.ID})
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch ledger", err)
+	}
+
+	schedule := &model.RentDueSchedule{LeaseID: lease.ID, Overdue: []model.RentDue{}, Upcoming: []model.RentDue{}}
+	_, schedule.CreditPaise = ledgerOf(lease.ID, entries).allocate(dues)
+	now := today()
+	for _, due := range dues {
+		switch {
+		case due.OutstandingPaise() == 0:
+		case due.DueDate.Before(now):
+			schedule.Overdue = append(schedule.Overdue, due)
+			schedule.OverduePaise += due.OutstandingPaise()
+		default:
+			schedule.Upcoming = append(schedule.Upcoming, due)
+		}
+	}
+	if lease.EscalationType != model.EscalationNone && !lease.IsClosed() {
+		renewsOn := lease.EndDate.AddDate(0, 0, 1)
+		schedule.NextEscalationDate = &renewsOn
+	}
+	return schedule, nil
+}
+
+// reconcileDues brings the stored dues of the lease in line with its terms
+// within tx. Running it again changes nothing. A lease that has ended stops
+// accruing from the day after it ended; dues already charged to the ledger
+// or paid are kept even when they fall outside the lease.
+func (s *leaseService) reconcileDues(ctx context.Context, tx *Services, lease *model.Lease) error {
+	end := lease.EndDate
+	if lease.Status == model.LeaseStatusTerminated {
+		if now := today(); now.Before(end) {
+			end = now
+		}
+	}
+
+	escalated := false
+	if lease.RenewalOfID != nil {
+		previous, err := tx.repos.Lease.GetByID(ctx, *lease.RenewalOfID)
+		if err != nil && !errors.Is(err, repository.ErrLeaseNotFound) {
+			return apperr.Internal("Failed to fetch renewed lease", err)
+		}
+		escalated = previous != nil && lease.MonthlyRentPaise > previous.MonthlyRentPaise
+	}
+
+	entries, err := tx.repos.Ledger.ListEntries(ctx, []uuid.UUID{lease.ID})
+	if err != nil {
+		return apperr.Internal("Failed to fetch ledger", err)
+	}
+	charged := ledgerOf(lease.ID, entries).charged
+
+	existing, err := tx.repos.Lease.ListDues(ctx, lease.ID)
+	if err != nil {
+		return apperr.Internal("Failed to fetch rent dues", err)
+	}
+	save, stale := mergeDues(existing, rentSchedule(lease, end, escalated), charged)
+	if err := tx.repos.Lease.DeleteDues(ctx, stale); err != nil {
+		return apperr.Internal("Failed to remove rent dues", err)
+	}
+	if err := tx.repos.Lease.SaveDues(ctx, save); err != nil {
+		return apperr.Internal("Failed to save rent dues", err)
+	}
+	return nil
+}
+
+// mergeDues works out which stored dues to save and which to remove so that
+// they match the schedule. A due outside the schedule is kept when it has been
+// charged or paid: a charged one as it stands, a paid one with nothing left to
+// pay. What is paid beyond a due's amount is carried forward to the unpaid
+// dues, oldest first; anything left stays on the last due and shows up as the
+// lease's credit.
+func mergeDues(existing, schedule []model.RentDue, charged map[uuid.UUID]dueCharge) (save []model.RentDue, stale []uuid.UUID) {
+	stored := make(map[string]model.RentDue, len(existing))
+	for _, due := range existing {
+		stored[due.PeriodStart.Format(time.DateOnly)] = due
+	}
+
+	now := time.Now()
+	dues := make([]model.RentDue, 0, len(schedule)+len(stored))
+	changed := map[uuid.UUID]bool{}
+	for _, due := range schedule {
+		key := due.PeriodStart.Format(time.DateOnly)
+		current, ok := stored[key]
+		delete(stored, key)
+		switch {
+		case ok && sameDue(current, due):
+			due = current
+		case ok:
+			due.ID, due.PaidPaise, due.CreatedAt, due.UpdatedAt = current.ID, current.PaidPaise, current.CreatedAt, now
+			changed[due.ID] = true
+		default:
+			due.ID, due.CreatedAt, due.UpdatedAt = uuid.New(), now, now
+			changed[due.ID] = true
+		}
+		dues = append(dues, due)
+	}
+
+	for _, due := range stored {
+		_, isCharged := charged[due.ID]
+		switch {
+		case isCharged:
+		case due.PaidPaise > 0:
+			if due.AmountPaise != 0 {
+				due.RentPaise, due.MaintenancePaise, due.AmountPaise, due.UpdatedAt = 0, 0, 0, now
+				changed[due.ID] = true
+			}
+		default:
+			stale = append(stale, due.ID)
+			continue
+		}
+		dues = append(dues, due)
+	}
+	sort.SliceStable(dues, func(i, j int) bool { return dues[i].DueDate.Before(dues[j].DueDate) })
+
+	var excess int64
+	for i := range dues {
+		if over := dues[i].PaidPaise - dues[i].AmountPaise; over > 0 {
+			dues[i].PaidPaise -= over
+			excess += over
+			changed[dues[i].ID] = true
+		}
+	}
+	for i := range dues {
+		if share := min(excess, dues[i].OutstandingPaise()); share > 0 {
+			dues[i].PaidPaise += share
+			excess -= share
+			changed[dues[i].ID] = true
+		}
+	}
+	if excess > 0 && len(dues) > 0 {
+		dues[len(dues)-1].PaidPaise += excess
+	}
+
+	for _, due := range dues {
+		if changed[due.ID] {
+			due.UpdatedAt = now
+			save = append(save, due)
+		}
+	}
+	return save, stale
+}
+
+// rentSchedule lays out the dues of the lease up to end. Periods start on the
+// rent due day and run for the lease's rent cycle; rent is due in advance on
+// the first day of each period. When the lease starts on another day the
+// days up to the first due day form a shorter, pro-rated first period, and
+// the last period is pro-rated when the lease ends before the cycle does.
+func rentSchedule(lease *model.Lease, end time.Time, escalated bool) []model.RentDue {
+	cycle := max(lease.RentCycleMonths, 1)
+	start := lease.StartDate
+
+	boundary := time.Date(start.Year(), start.Month(), lease.RentDueDay, 0, 0, 0, 0, time.UTC)
+	if boundary.Before(start) {
+		boundary = boundary.AddDate(0, 1, 0)
+	}
+
+	var dues []model.RentDue
+	add := func(from, to, cycleFrom, cycleTo time.Time) {
+		due := model.RentDue{
+			LeaseID:          lease.ID,
+			PeriodStart:      from,
+			PeriodEnd:        to,
+			DueDate:          from,
+			RentPaise:        lease.MonthlyRentPaise * int64(cycle),
+			MaintenancePaise: lease.MaintenancePaise * int64(cycle),
+		}
+		if days, cycleDays := daysBetween(from, to), daysBetween(cycleFrom, cycleTo); days < cycleDays {
+			due.RentPaise = prorate(due.RentPaise, days, cycleDays)
+			due.MaintenancePaise = prorate(due.MaintenancePaise, days, cycleDays)
+			due.Prorated = true
+		}
+		due.AmountPaise = due.RentPaise + due.MaintenancePaise
+		dues = append(dues, due)
+	}
+
+	if boundary.After(start) && !start.After(end) {
+		add(start, minDate(boundary.AddDate(0, 0, -1), end), boundary.AddDate(0, -cycle, 0), boundary.AddDate(0, 0, -1))
+	}
+	for from := boundary; !from.After(end); from = from.AddDate(0, cycle, 0) {
+		to := from.AddDate(0, cycle, -1)
+		add(from, minDate(to, end), from, to)
+	}
+
+	if len(dues) > 0 {
+		dues[0].Escalated = escalated
+	}
+	return dues
+}
+
+// sameDue reports whether a stored due already matches the generated one
+func sameDue(stored, generated model.RentDue) bool {
+	return stored.PeriodEnd.Equal(generated.PeriodEnd) &&
+		stored.DueDate.Equal(generated.DueDate) &&
+		stored.RentPaise == generated.RentPaise &&
+		stored.MaintenancePaise == generated.MaintenancePaise &&
+		stored.AmountPaise == generated.AmountPaise &&
+		stored.Prorated == generated.Prorated &&
+		stored.Escalated == generated.Escalated
+}
+
+// daysBetween counts the days from from to to, both included
+func daysBetween(from, to time.Time) int64 {
+	return int64(to.Sub(from).Hours()/24) + 1
+}
+
+// prorate returns amount for days out of total, rounded to the nearest paisa
+func prorate(amount, days, total int64) int64 {
+	return (2*amount*days + total) / (2 * total)
+}
+
+func minDate(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+)
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		amount, days, total int64
+		want                int64
+	}{
+		{1500000, 30, 30, 1500000},
+		{1500000, 15, 30, 750000},
+		{1500000, 15, 31, 725806},
+		{100, 1, 3, 33},
+		{200, 1, 3, 67},
+		{1, 1, 2, 1},
+		{0, 10, 31, 0},
+	}
+
+	for _, tt := range tests {
+		if got := prorate(tt.amount, tt.days, tt.total); got != tt.want {
+			t.Errorf("prorate(%d, %d, %d) = %d, want %d", tt.amount, tt.days, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestRentSchedule(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	// period is the part of a due the schedule decides
+	type period struct {
+		From, To          time.Time
+		Rent, Maintenance int64
+		Prorated          bool
+	}
+	full := func(from, to time.Time) period {
+		return period{From: from, To: to, Rent: 1500000, Maintenance: 100000}
+	}
+
+	tests := []struct {
+		name      string
+		start     time.Time
+		end       time.Time
+		dueDay    int
+		cycle     int
+		escalated bool
+		want      []period
+	}{
+		{
+			name:   "starts on the due day",
+			start:  date(2024, time.April, 1),
+			end:    date(2024, time.June, 30),
+			dueDay: 1,
+			cycle:  1,
+			want: []period{
+				full(date(2024, time.April, 1), date(2024, time.April, 30)),
+				full(date(2024, time.May, 1), date(2024, time.May, 31)),
+				full(date(2024, time.June, 1), date(2024, time.June, 30)),
+			},
+		},
+		{
+			name:   "mid-month start and a last partial period",
+			start:  date(2024, time.April, 16),
+			end:    date(2024, time.July, 15),
+			dueDay: 1,
+			cycle:  1,
+			want: []period{
+				{From: date(2024, time.April, 16), To: date(2024, time.April, 30), Rent: 750000, Maintenance: 50000, Prorated: true},
+				full(date(2024, time.May, 1), date(2024, time.May, 31)),
+				full(date(2024, time.June, 1), date(2024, time.June, 30)),
+				{From: date(2024, time.July, 1), To: date(2024, time.July, 15), Rent: 725806, Maintenance: 48387, Prorated: true},
+			},
+		},
+		{
+			name:   "due day later in the month",
+			start:  date(2024, time.April, 1),
+			end:    date(2024, time.May, 31),
+			dueDay: 5,
+			cycle:  1,
+			want: []period{
+				{From: date(2024, time.April, 1), To: date(2024, time.April, 4), Rent: 193548, Maintenance: 12903, Prorated: true},
+				full(date(2024, time.April, 5), date(2024, time.May, 4)),
+				{From: date(2024, time.May, 5), To: date(2024, time.May, 31), Rent: 1306452, Maintenance: 87097, Prorated: true},
+			},
+		},
+		{
+			name:   "February start",
+			start:  date(2025, time.February, 10),
+			end:    date(2025, time.March, 31),
+			dueDay: 1,
+			cycle:  1,
+			want: []period{
+				{From: date(2025, time.February, 10), To: date(2025, time.February, 28), Rent: 1017857, Maintenance: 67857, Prorated: true},
+				full(date(2025, time.March, 1), date(2025, time.March, 31)),
+			},
+		},
+		{
+			name:   "February start in a leap year",
+			start:  date(2024, time.February, 10),
+			end:    date(2024, time.March, 31),
+			dueDay: 1,
+			cycle:  1,
+			want: []period{
+				{From: date(2024, time.February, 10), To: date(2024, time.February, 29), Rent: 1034483, Maintenance: 68966, Prorated: true},
+				full(date(2024, time.March, 1), date(2024, time.March, 31)),
+			},
+		},
+		{
+			name:   "quarterly cycle ending early",
+			start:  date(2024, time.April, 1),
+			end:    date(2024, time.November, 30),
+			dueDay: 1,
+			cycle:  3,
+			want: []period{
+				{From: date(2024, time.April, 1), To: date(2024, time.June, 30), Rent: 4500000, Maintenance: 300000},
+				{From: date(2024, time.July, 1), To: date(2024, time.September, 30), Rent: 4500000, Maintenance: 300000},
+				{From: date(2024, time.October, 1), To: date(2024, time.November, 30), Rent: 2983696, Maintenance: 198913, Prorated: true},
+			},
+		},
+		{
+			name:   "ended before the first due day",
+			start:  date(2024, time.April, 16),
+			end:    date(2024, time.April, 20),
+			dueDay: 1,
+			cycle:  1,
+			want: []period{
+				{From: date(2024, time.April, 16), To: date(2024, time.April, 20), Rent: 250000, Maintenance: 16667, Prorated: true},
+			},
+		},
+		{
+			name:   "ended before it started",
+			start:  date(2024, time.May, 1),
+			end:    date(2024, time.April, 30),
+			dueDay: 1,
+			cycle:  1,
+		},
+		{
+			name:      "escalated renewal",
+			start:     date(2024, time.April, 1),
+			end:       date(2024, time.May, 31),
+			dueDay:    1,
+			cycle:     1,
+			escalated: true,
+			want: []period{
+				full(date(2024, time.April, 1), date(2024, time.April, 30)),
+				full(date(2024, time.May, 1), date(2024, time.May, 31)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lease := &model.Lease{
+				StartDate:        tt.start,
+				EndDate:          tt.end,
+				RentDueDay:       tt.dueDay,
+				RentCycleMonths:  tt.cycle,
+				MonthlyRentPaise: 1500000,
+				MaintenancePaise: 100000,
+			}
+
+			dues := rentSchedule(lease, tt.end, tt.escalated)
+			var got []period
+			for i, due := range dues {
+				got = append(got, period{
+					From: due.PeriodStart, To: due.PeriodEnd,
+					Rent: due.RentPaise, Maintenance: due.MaintenancePaise, Prorated: due.Prorated,
+				})
+				if !due.DueDate.Equal(due.PeriodStart) {
+					t.Errorf("due %d falls due on %s, want the first day of its period", i, due.DueDate.Format(time.DateOnly))
+				}
+				if due.AmountPaise != due.RentPaise+due.MaintenancePaise {
+					t.Errorf("due %d amounts to %d, want rent plus maintenance", i, due.AmountPaise)
+				}
+				if due.Escalated != (tt.escalated && i == 0) {
+					t.Errorf("due %d escalated = %v", i, due.Escalated)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rentSchedule =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeDues(t *testing.T) {
+	month := func(m time.Month) time.Time { return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC) }
+	due := func(m time.Month, amount, paid int64) model.RentDue {
+		return model.RentDue{
+			ID:          uuid.New(),
+			PeriodStart: month(m),
+			PeriodEnd:   month(m+1).AddDate(0, 0, -1),
+			DueDate:     month(m),
+			RentPaise:   amount,
+			AmountPaise: amount,
+			PaidPaise:   paid,
+		}
+	}
+	const rent = 1500000
+	// row is what mergeDues saves of a due
+	type row struct {
+		amount, paid int64
+	}
+
+	tests := []struct {
+		name      string
+		existing  []model.RentDue
+		schedule  []model.RentDue
+		charged   []int // indexes into existing charged to the ledger
+		wantSave  map[time.Month]row
+		wantStale []int // indexes into existing removed
+	}{
+		{
+			name:     "new lease",
+			schedule: []model.RentDue{due(time.April, rent, 0), due(time.May, rent, 0)},
+			wantSave: map[time.Month]row{time.April: {rent, 0}, time.May: {rent, 0}},
+		},
+		{
+			name:     "nothing changed",
+			existing: []model.RentDue{due(time.April, rent, rent), due(time.May, rent, 0)},
+			schedule: []model.RentDue{due(time.April, rent, 0), due(time.May, rent, 0)},
+			wantSave: map[time.Month]row{},
+		},
+		{
+			name:      "unpaid due after termination is removed",
+			existing:  []model.RentDue{due(time.April, rent, rent), due(time.May, rent, 0), due(time.June, rent, 0)},
+			schedule:  []model.RentDue{due(time.April, rent, 0), due(time.May, rent, 0)},
+			wantSave:  map[time.Month]row{},
+			wantStale: []int{2},
+		},
+		{
+			name:     "charged due after termination is kept",
+			existing: []model.RentDue{due(time.April, rent, rent), due(time.May, rent, 0)},
+			schedule: []model.RentDue{due(time.April, rent, 0)},
+			charged:  []int{1},
+			wantSave: map[time.Month]row{},
+		},
+		{
+			name:     "paid due after termination is kept and its payment carried forward",
+			existing: []model.RentDue{due(time.April, rent, rent), due(time.May, rent, 0), due(time.June, rent, 1000000)},
+			schedule: []model.RentDue{due(time.April, rent, 0), due(time.May, rent, 0)},
+			wantSave: map[time.Month]row{time.May: {rent, 1000000}, time.June: {0, 0}},
+		},
+		{
+			name:     "payment beyond the remaining dues stays on the last due",
+			existing: []model.RentDue{due(time.April, rent, rent), due(time.May, rent, rent)},
+			schedule: []model.RentDue{due(time.April, rent, 0)},
+			wantSave: map[time.Month]row{time.May: {0, rent}},
+		},
+		{
+			name:     "shrunk due passes its overpayment to the next",
+			existing: []model.RentDue{due(time.April, rent, rent), due(time.May, rent, 0)},
+			schedule: []model.RentDue{due(time.April, 750000, 0), due(time.May, rent, 0)},
+			wantSave: map[time.Month]row{time.April: {750000, 750000}, time.May: {rent, 750000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charged := map[uuid.UUID]dueCharge{}
+			for _, i := range tt.charged {
+				charged[tt.existing[i].ID] = dueCharge{rent: tt.existing[i].RentPaise}
+			}
+
+			save, stale := mergeDues(tt.existing, tt.schedule, charged)
+			got := map[time.Month]row{}
+			for _, d := range save {
+				got[d.PeriodStart.Month()] = row{d.AmountPaise, d.PaidPaise}
+			}
+			if !reflect.DeepEqual(got, tt.wantSave) {
+				t.Errorf("saved %v, want %v", got, tt.wantSave)
+			}
+			var wantStale []uuid.UUID
+			for _, i := range tt.wantStale {
+				wantStale = append(wantStale, tt.existing[i].ID)
+			}
+			if !reflect.DeepEqual(stale, wantStale) {
+				t.Errorf("removed %v, want %v", stale, wantStale)
+			}
+		})
+	}
+}
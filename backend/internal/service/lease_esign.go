@@ -0,0 +1,159 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"backend/internal/auth"
+	"backend/internal/esign"
+	"backend/internal/model"
+	"backend/internal/repository"
+	"backend/internal/storage"
+	"backend/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+// StartESign starts an Aadhaar eSign of the signing document for the holder
+// of a signing link. Their browser posts the returned request to the ESP,
+// which posts the result back to CompleteESign.
+func (s *leaseService) StartESign(ctx context.Context, token string, client ClientInfo) (*esign.Transaction, error) {
+	signer, signing, err := s.signerByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSigningVersion(ctx, s.services, signing); err != nil {
+		return nil, err
+	}
+	lease, err := s.fetch(ctx, signing.LeaseID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := s.openSigningDocument(ctx, signing)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	document, err := io.ReadAll(content)
+	if err != nil {
+		return nil, apperr.Internal("Failed to read signing document", err)
+	}
+
+	txn, err := s.esigner.Initiate(ctx, esign.Request{
+		Document:   document,
+		SignerName: signer.Name,
+		Reason:     "Rent agreement signed as " + signer.Role,
+		Location:   lease.Property.City,
+		DocInfo:    "Rent agreement for " + lease.Property.Name,
+	})
+	if err != nil {
+		return nil, apperr.Internal("Failed to start eSign", err)
+	}
+
+	tokenHash := auth.HashLinkToken(token)
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Signing.SetSignerESignTxn(ctx, signer.ID, tokenHash, txn.Txn); err != nil {
+			if errors.Is(err, repository.ErrSigningLinkUsed) {
+				return apperr.Conflict("This signing link has already been used", err)
+			}
+			return apperr.Internal("Failed to start eSign", err)
+		}
+		return s.recordSigningEvent(ctx, tx, signing, &signer.ID, nil, model.SigningEventESign, client, "Transaction "+txn.Txn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txn, nil
+}
+
+// CompleteESign verifies the response the ESP posted back and records the
+// eSign as the signer's signature. The signed copy of the document is kept
+// alongside the original.
+func (s *leaseService) CompleteESign(ctx context.Context, responseXML string, client ClientInfo) (*model.LeaseSigner, error) {
+	result, err := s.esigner.Verify(ctx, []byte(responseXML))
+	if err != nil {
+		switch {
+		case errors.Is(err, esign.ErrSignatureRejected):
+			return nil, apperr.Invalid("Aadhaar eSign was not completed", err)
+		case errors.Is(err, esign.ErrTransactionNotFound):
+			return nil, apperr.NotFound("This eSign has expired; start it again from your signing link", err)
+		case errors.Is(err, esign.ErrInvalidResponse):
+			return nil, apperr.Invalid("The eSign response could not be verified", err)
+		}
+		return nil, apperr.Internal("Failed to verify eSign", err)
+	}
+
+	found, err := s.signingRepo.GetSignerByESignTxn(ctx, result.Txn)
+	if err != nil {
+		if errors.Is(err, repository.ErrSignerNotFound) {
+			return nil, apperr.NotFound("eSign transaction not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch signer", err)
+	}
+	signing, err := s.fetchSigning(ctx, found.SigningID)
+	if err != nil {
+		return nil, err
+	}
+	signer := signing.Signer(found.ID)
+	if signing.Status != model.SigningStatusInProgress || signer == nil || signer.TokenHash == nil || !isNextSigner(signing, signer) {
+		return nil, apperr.Conflict("This signing link has already been used", nil)
+	}
+	tokenHash := *signer.TokenHash
+
+	signed, err := s.esigner.SignedPDF(ctx, result.Txn)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch eSigned document", err)
+	}
+	key := fmt.Sprintf("leases/%s/signings/%s/%s.pdf", signing.LeaseID, signing.ID, signer.ID)
+	if err := s.storage.Put(ctx, key, bytes.NewReader(signed)); err != nil {
+		return nil, apperr.Internal("Failed to store eSigned document", err)
+	}
+
+	signer.Status = model.SignerStatusSigned
+	signer.SignedAt = &result.SignedAt
+	signer.TokenHash = nil
+	signer.LinkExpiresAt = nil
+	signer.SignatureType = model.SignatureTypeESign
+	signer.SignatureData = fmt.Sprintf("%s, certificate %s issued by %s", result.SignerName, result.CertificateSerial, result.CertificateIssuer)
+	signer.SignedDocumentKey = key
+	signer.IPAddress = client.IPAddress
+	signer.UserAgent = truncate(client.UserAgent, 500)
+
+	detail := fmt.Sprintf("Aadhaar eSign as %s, certificate %s", result.SignerName, result.CertificateSerial)
+	if err := s.recordSignature(ctx, signing, signer, tokenHash, client, detail); err != nil {
+		s.removeFile(ctx, key)
+		return nil, err
+	}
+	return signer, nil
+}
+
+// OpenSignedDocument returns the copy of the signing document carrying a
+// signer's eSign, from the lease's latest signing round. The caller must close the reader.
+func (s *leaseService) OpenSignedDocument(ctx context.Context, actor *model.User, id, signerID uuid.UUID) (*model.LeaseSigner, io.ReadCloser, error) {
+	signing, err := s.GetSigning(ctx, actor, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signer := signing.Signer(signerID)
+	if signer == nil {
+		return nil, nil, apperr.NotFound("Signer not found", nil)
+	}
+	if signer.SignedDocumentKey == "" {
+		return nil, nil, apperr.NotFound(signer.Name+" has not signed with eSign", nil)
+	}
+
+	content, err := s.storage.Open(ctx, signer.SignedDocumentKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, nil, apperr.NotFound("eSigned document not found", err)
+		}
+		return nil, nil, apperr.Internal("Failed to open eSigned document", err)
+	}
+	return signer, content, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"backend/internal/estamp"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/internal/storage"
+	"backend/pkg/apperr"
+	"backend/pkg/india"
+	"backend/pkg/inr"
+
+	"github.com/google/uuid"
+)
+
+// estampValidityMonths is how long after issue a stamp may be used to execute an agreement
+const estampValidityMonths = 6
+
+type AttachEStampInput struct {
+	CertificateNumber string
+	State             string
+	DenominationPaise int64
+	Purchaser         string
+	IssueDate         time.Time
+	FileName          string
+	ContentType       string
+	Size              int64
+	Content           io.Reader
+}
+
+// AttachEStamp verifies the certificate with the e-stamp provider and
+// attaches it with its scan to a draft lease, replacing any earlier one
+func (s *leaseService) AttachEStamp(ctx context.Context, actor *model.User, id uuid.UUID, input AttachEStampInput) (*model.EStamp, error) {
+	lease, err := s.authorizedDraft(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, ok := documentExtensions[input.ContentType]
+	if !ok {
+		return nil, apperr.Invalid("Only PDF, JPEG and PNG scans are accepted", nil)
+	}
+	if input.Size > s.maxUpload {
+		return nil, apperr.Invalid(fmt.Sprintf("Scan must not exceed %d MB", s.maxUpload>>20), nil)
+	}
+
+	stamp := &model.EStamp{
+		ID:                uuid.New(),
+		LeaseID:           lease.ID,
+		CertificateNumber: strings.ToUpper(strings.TrimSpace(input.CertificateNumber)),
+		State:             input.State,
+		DenominationPaise: input.DenominationPaise,
+		Purchaser:         strings.TrimSpace(input.Purchaser),
+		IssueDate:         input.IssueDate,
+		FileName:          input.FileName,
+		ContentType:       input.ContentType,
+		SizeBytes:         input.Size,
+		CreatedBy:         actor.ID,
+		CreatedAt:         time.Now(),
+	}
+	if !estamp.ValidNumber(stamp.CertificateNumber) {
+		return nil, apperr.Invalid("Certificate number must look like IN-MH12345678901234X", nil)
+	}
+	if err := s.checkEStamp(lease, stamp); err != nil {
+		return nil, err
+	}
+
+	inUse, err := s.leaseRepo.EStampNumberInUse(ctx, stamp.CertificateNumber, lease.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to check certificate number", err)
+	}
+	if inUse {
+		return nil, apperr.Conflict("This e-stamp certificate is already attached to another lease", nil)
+	}
+
+	if err := s.estamps.Verify(ctx, estamp.Certificate{
+		Number:            stamp.CertificateNumber,
+		State:             stamp.State,
+		DenominationPaise: stamp.DenominationPaise,
+		Purchaser:         stamp.Purchaser,
+		IssueDate:         stamp.IssueDate,
+	}); err != nil {
+		if errors.Is(err, estamp.ErrCertificateNotFound) || errors.Is(err, estamp.ErrDetailsMismatch) {
+			return nil, apperr.Invalid("E-stamp certificate could not be verified: "+err.Error(), err)
+		}
+		return nil, apperr.Internal("Failed to verify e-stamp certificate", err)
+	}
+	stamp.VerifiedAt = time.Now()
+
+	previous, err := s.leaseRepo.GetEStamp(ctx, lease.ID)
+	if err != nil && !errors.Is(err, repository.ErrEStampNotFound) {
+		return nil, apperr.Internal("Failed to fetch e-stamp", err)
+	}
+
+	stamp.StorageKey = fmt.Sprintf("leases/%s/estamps/%s%s", lease.ID, stamp.ID, ext)
+	if err := s.storage.Put(ctx, stamp.StorageKey, input.Content); err != nil {
+		return nil, apperr.Internal("Failed to store e-stamp scan", err)
+	}
+
+	if err := s.leaseRepo.ReplaceEStamp(ctx, stamp); err != nil {
+		s.removeFile(ctx, stamp.StorageKey)
+		return nil, apperr.Internal("Failed to save e-stamp", err)
+	}
+	if previous != nil {
+		s.removeFile(ctx, previous.StorageKey)
+	}
+
+	return stamp, nil
+}
+
+func (s *leaseService) GetEStamp(ctx context.Context, actor *model.User, id uuid.UUID) (*model.EStamp, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.fetchEStamp(ctx, lease.ID)
+}
+
+// OpenEStamp returns the e-stamp and its scan. The caller must close the reader.
+func (s *leaseService) OpenEStamp(ctx context.Context, actor *model.User, id uuid.UUID) (*model.EStamp, io.ReadCloser, error) {
+	stamp, err := s.GetEStamp(ctx, actor, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.storage.Open(ctx, stamp.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, nil, apperr.NotFound("E-stamp scan not found", err)
+		}
+		return nil, nil, apperr.Internal("Failed to open e-stamp scan", err)
+	}
+
+	return stamp, content, nil
+}
+
+func (s *leaseService) RemoveEStamp(ctx context.Context, actor *model.User, id uuid.UUID) error {
+	lease, err := s.authorizedDraft(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return err
+	}
+
+	stamp, err := s.fetchEStamp(ctx, lease.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.leaseRepo.DeleteEStamp(ctx, lease.ID); err != nil {
+		if errors.Is(err, repository.ErrEStampNotFound) {
+			return apperr.NotFound("No e-stamp is attached to this lease", err)
+		}
+		return apperr.Internal("Failed to remove e-stamp", err)
+	}
+	s.removeFile(ctx, stamp.StorageKey)
+
+	return nil
+}
+
+// checkEStamp validates the stamp against the lease: it must be issued in the
+// property's state, still be within its validity and cover the stamp duty
+func (s *leaseService) checkEStamp(lease *model.Lease, stamp *model.EStamp) error {
+	if stamp.State != lease.Property.State {
+		return apperr.Invalid("E-stamp must be issued in "+india.StateName(lease.Property.State)+", where the property is", nil)
+	}
+
+	now := today()
+	if stamp.IssueDate.After(now) {
+		return apperr.Invalid("E-stamp issue date cannot be in the future", nil)
+	}
+	if stamp.IssueDate.AddDate(0, estampValidityMonths, 0).Before(now) {
+		return apperr.Invalid(fmt.Sprintf("E-stamp was issued more than %d months ago and can no longer be used", estampValidityMonths), nil)
+	}
+
+	estimate, err := s.estimateStampDuty(lease)
+	if err == nil && stamp.DenominationPaise < estimate.StampDutyPaise {
+		return apperr.Invalid("E-stamp of "+inr.FormatWithSymbol(stamp.DenominationPaise)+
+			" does not cover the stamp duty of "+inr.FormatWithSymbol(estimate.StampDutyPaise), nil)
+	}
+
+	return nil
+}
+
+func (s *leaseService) fetchEStamp(ctx context.Context, leaseID uuid.UUID) (*model.EStamp, error) {
+	stamp, err := s.leaseRepo.GetEStamp(ctx, leaseID)
+	if err != nil {
+		if errors.Is(err, repository.ErrEStampNotFound) {
+			return nil, apperr.NotFound("No e-stamp is attached to this lease", err)
+		}
+		return nil, apperr.Internal("Failed to fetch e-stamp", err)
+	}
+	return stamp, nil
+}
+
+// removeFile deletes a stored file on a best-effort basis; the database row is the source of truth
+func (s *leaseService) removeFile(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("Failed to delete stored file %s: %v", key, err)
+	}
+}
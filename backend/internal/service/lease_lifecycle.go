@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/india"
+
+	"github.com/google/uuid"
+)
+
+// Lease lifecycle events, recorded on each transition
+const (
+	LeaseEventSubmit    = "submit"
+	LeaseEventWithdraw  = "withdraw"
+	LeaseEventSign      = "sign"
+	LeaseEventActivate  = "activate"
+	LeaseEventNotice    = "notice"
+	LeaseEventTerminate = "terminate"
+	LeaseEventExpire    = "expire"
+	LeaseEventRenew     = "renew"
+)
+
+type leaseTransition struct {
+	from   []string
+	to     string
+	action policy.Action
+}
+
+// leaseTransitions is the lease state machine: the statuses each event may
+// be applied in and the status it leads to
+var leaseTransitions = map[string]leaseTransition{
+	LeaseEventSubmit: {
+		from:   []string{model.LeaseStatusDraft},
+		to:     model.LeaseStatusPendingSignatures,
+		action: policy.ActionUpdate,
+	},
+	LeaseEventWithdraw: {
+		from:   []string{model.LeaseStatusPendingSignatures},
+		to:     model.LeaseStatusDraft,
+		action: policy.ActionUpdate,
+	},
+	LeaseEventSign: {
+		from:   []string{model.LeaseStatusPendingSignatures},
+		to:     model.LeaseStatusSigned,
+		action: policy.ActionUpdate,
+	},
+	LeaseEventActivate: {
+		from:   []string{model.LeaseStatusSigned},
+		to:     model.LeaseStatusActive,
+		action: policy.ActionUpdate,
+	},
+	LeaseEventNotice: {
+		from:   []string{model.LeaseStatusActive},
+		to:     model.LeaseStatusNoticePeriod,
+		action: policy.ActionNotice,
+	},
+	LeaseEventTerminate: {
+		from:   []string{model.LeaseStatusActive, model.LeaseStatusNoticePeriod},
+		to:     model.LeaseStatusTerminated,
+		action: policy.ActionUpdate,
+	},
+	LeaseEventExpire: {
+		from:   []string{model.LeaseStatusActive, model.LeaseStatusNoticePeriod},
+		to:     model.LeaseStatusExpired,
+		action: policy.ActionUpdate,
+	},
+	// LeaseEventRenew is applied only when the lease's renewal activates
+	LeaseEventRenew: {
+		from:   []string{model.LeaseStatusActive, model.LeaseStatusNoticePeriod},
+		to:     model.LeaseStatusRenewed,
+		action: policy.ActionUpdate,
+	},
+}
+
+// Submit sends a draft for signatures once it has tenants, every clause
+// renders and a valid e-stamp is attached
+func (s *leaseService) Submit(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error) {
+	return s.transition(ctx, actor, id, LeaseEventSubmit, reason)
+}
+
+// Withdraw returns a lease awaiting signatures to draft so it can be revised
+func (s *leaseService) Withdraw(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error) {
+	return s.transition(ctx, actor, id, LeaseEventWithdraw, reason)
+}
+
+// MarkSigned records signatures collected on paper. Leases signed online
+// advance on their own when the last party signs.
+func (s *leaseService) MarkSigned(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error) {
+	return s.transition(ctx, actor, id, LeaseEventSign, reason)
+}
+
+// Activate starts a signed lease on or after its start date and marks the property occupied
+func (s *leaseService) Activate(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error) {
+	return s.transition(ctx, actor, id, LeaseEventActivate, reason)
+}
+
+// GiveNotice starts the notice period. Either party may serve notice.
+func (s *leaseService) GiveNotice(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error) {
+	return s.transition(ctx, actor, id, LeaseEventNotice, reason)
+}
+
+// Terminate ends the lease early and marks the property vacant
+func (s *leaseService) Terminate(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error) {
+	return s.transition(ctx, actor, id, LeaseEventTerminate, reason)
+}
+
+// Expire closes a lease whose end date has passed and marks the property vacant
+func (s *leaseService) Expire(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error) {
+	return s.transition(ctx, actor, id, LeaseEventExpire, reason)
+}
+
+func (s *leaseService) ListTransitions(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.LeaseTransition, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.leaseRepo.ListTransitions(ctx, id)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch lease history", err)
+	}
+
+	return transitions, nil
+}
+
+// transition applies event to the lease. The status change, its history
+// record and any side-effects on the property commit together.
+func (s *leaseService) transition(ctx context.Context, actor *model.User, id uuid.UUID, event, reason string) (*model.Lease, error) {
+	t := leaseTransitions[event]
+
+	lease, err := s.authorized(ctx, actor, t.action, id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(t.from, lease.Status) {
+		return nil, apperr.Invalid("Cannot "+event+" a lease that is "+lease.Status, nil)
+	}
+	if err := s.checkTransition(ctx, lease, event); err != nil {
+		return nil, err
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		return s.applyEvent(ctx, tx, lease, event, &actor.ID, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// applyEvent moves the lease to the event's status within tx. actorID is nil
+// when the system applies the event, such as when the last signature lands.
+func (s *leaseService) applyEvent(ctx context.Context, tx *Services, lease *model.Lease, event string, actorID *uuid.UUID, reason string) error {
+	from := lease.Status
+	now := time.Now()
+	lease.Status = leaseTransitions[event].to
+	lease.UpdatedAt = now
+	if event == LeaseEventNotice {
+		vacateBy := today().AddDate(0, 0, lease.NoticePeriodDays)
+		if vacateBy.After(lease.EndDate) {
+			vacateBy = lease.EndDate
+		}
+		lease.NoticeGivenAt = &now
+		lease.NoticeGivenBy = actorID
+		lease.VacateBy = &vacateBy
+	}
+
+	if err := tx.repos.Lease.UpdateStatus(ctx, lease, from); err != nil {
+		if errors.Is(err, repository.ErrLeaseStatusChanged) {
+			return apperr.Conflict("Lease status was changed by someone else, please retry", err)
+		}
+		return apperr.Internal("Failed to update lease status", err)
+	}
+	if err := tx.repos.Lease.CreateTransition(ctx, &model.LeaseTransition{
+		ID:         uuid.New(),
+		LeaseID:    lease.ID,
+		Event:      event,
+		FromStatus: from,
+		ToStatus:   lease.Status,
+		ActorID:    actorID,
+		Reason:     reason,
+		CreatedAt:  now,
+	}); err != nil {
+		return apperr.Internal("Failed to record lease transition", err)
+	}
+	return s.applyTransition(ctx, tx, lease, event, actorID)
+}
+
+// checkTransition enforces the preconditions of an event beyond the current status
+func (s *leaseService) checkTransition(ctx context.Context, lease *model.Lease, event string) error {
+	switch event {
+	case LeaseEventSubmit:
+		if len(lease.Tenants) == 0 {
+			return apperr.Invalid("Add at least one tenant before sending the lease for signatures", nil)
+		}
+		if _, err := s.render(ctx, lease, india.English); err != nil {
+			return err
+		}
+		if _, err := s.withCompliance(ctx, lease); err != nil {
+			return err
+		}
+		if err := complianceError(lease.Compliance); err != nil {
+			return err
+		}
+		stamp, err := s.leaseRepo.GetEStamp(ctx, lease.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrEStampNotFound) {
+				return apperr.Invalid("Attach the e-stamp certificate before sending the lease for signatures", nil)
+			}
+			return apperr.Internal("Failed to fetch e-stamp", err)
+		}
+		return s.checkEStamp(lease, stamp)
+	case LeaseEventSign:
+		_, err := s.signingRepo.GetActive(ctx, lease.ID)
+		if err == nil {
+			return apperr.Conflict("Signatures are being collected online; the lease is marked signed when the last party signs", nil)
+		}
+		if !errors.Is(err, repository.ErrSigningNotFound) {
+			return apperr.Internal("Failed to fetch signing", err)
+		}
+	case LeaseEventActivate:
+		if today().Before(lease.StartDate) {
+			return apperr.Invalid("Lease cannot be activated before its start date", nil)
+		}
+		count, err := s.leaseRepo.CountByPropertyAndStatus(ctx, lease.PropertyID, model.LeaseStatusActive, model.LeaseStatusNoticePeriod)
+		if err != nil {
+			return apperr.Internal("Failed to check property leases", err)
+		}
+		// A renewal takes over from the lease it renews, which is still running
+		if previous, err := s.renewedLease(ctx, s.leaseRepo, lease); err != nil {
+			return err
+		} else if previous != nil && previous.PropertyID == lease.PropertyID {
+			count--
+		}
+		if count > 0 {
+			return apperr.Conflict("Property already has an active lease", nil)
+		}
+	case LeaseEventExpire:
+		if !today().After(lease.EndDate) {
+			return apperr.Invalid("Lease cannot expire before its end date", nil)
+		}
+	}
+	return nil
+}
+
+// applyTransition carries out the side-effects of an event within the transaction
+func (s *leaseService) applyTransition(ctx context.Context, tx *Services, lease *model.Lease, event string, actorID *uuid.UUID) error {
+	switch event {
+	case LeaseEventActivate, LeaseEventRenew, LeaseEventTerminate:
+		if err := s.reconcileDues(ctx, tx, lease); err != nil {
+			return err
+		}
+	}
+	switch event {
+	case LeaseEventActivate, LeaseEventRenew, LeaseEventTerminate, LeaseEventExpire:
+		if _, err := tx.Ledger.SyncLease(ctx, lease); err != nil {
+			return err
+		}
+	}
+
+	var occupancy string
+	switch event {
+	case LeaseEventWithdraw:
+		return s.cancelSigning(ctx, tx, lease.ID, actorID, "Lease withdrawn for revision")
+	case LeaseEventActivate:
+		previous, err := s.renewedLease(ctx, tx.repos.Lease, lease)
+		if err != nil {
+			return err
+		}
+		if previous != nil {
+			if err := s.applyEvent(ctx, tx, previous, LeaseEventRenew, actorID, "Renewed by lease "+lease.ID.String()); err != nil {
+				return err
+			}
+		}
+		occupancy = model.OccupancyOccupied
+	case LeaseEventTerminate, LeaseEventExpire:
+		occupancy = model.OccupancyVacant
+	default:
+		return nil
+	}
+
+	lease.Property.OccupancyStatus = occupancy
+	lease.Property.UpdatedAt = lease.UpdatedAt
+	if err := tx.repos.Property.Update(ctx, lease.Property); err != nil {
+		return apperr.Internal("Failed to update property occupancy", err)
+	}
+	return nil
+}
+
+// renewedLease returns the lease that lease renews when that one is still
+// running, and nil otherwise
+func (s *leaseService) renewedLease(ctx context.Context, repo repository.LeaseRepository, lease *model.Lease) (*model.Lease, error) {
+	if lease.RenewalOfID == nil {
+		return nil, nil
+	}
+	previous, err := repo.GetByID(ctx, *lease.RenewalOfID)
+	if err != nil {
+		if errors.Is(err, repository.ErrLeaseNotFound) {
+			return nil, nil
+		}
+		return nil, apperr.Internal("Failed to fetch renewed lease", err)
+	}
+	if !slices.Contains(leaseTransitions[LeaseEventRenew].from, previous.Status) {
+		return nil, nil
+	}
+	return previous, nil
+}
+
+// today returns the current date at midnight UTC, matching how date columns are loaded
+func today() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/clausetext"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/inr"
+
+	"github.com/google/uuid"
+)
+
+type RenewLeaseInput struct {
+	// TermMonths defaults to the term of the lease being renewed
+	TermMonths *int
+}
+
+// DraftRenewal drafts a lease renewing an active one from the day after it
+// ends, with the same parties, terms and clause wording. The rent is
+// escalated as the lease provides and the deposit is carried forward.
+func (s *leaseService) DraftRenewal(ctx context.Context, actor *model.User, id uuid.UUID, input RenewLeaseInput) (*model.Lease, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+
+	termMonths := lease.TermMonths
+	if input.TermMonths != nil {
+		termMonths = *input.TermMonths
+	}
+	renewal, err := s.draftRenewal(ctx, lease, termMonths, actor.ID, &actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withCompliance(ctx, renewal)
+}
+
+// GetRenewal returns the draft or lease renewing the given one
+func (s *leaseService) GetRenewal(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Lease, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+
+	renewal, err := s.leaseRepo.GetRenewal(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrLeaseNotFound) {
+			return nil, apperr.NotFound("This lease has not been renewed", err)
+		}
+		return nil, apperr.Internal("Failed to fetch renewal", err)
+	}
+	lease, err := s.fetch(ctx, renewal.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withCompliance(ctx, lease)
+}
+
+// DraftDueRenewals drafts a renewal of every active lease ending within the
+// configured lead time that has not had one drafted yet, and tells the owner
+// by SMS. It returns how many were drafted.
+func (s *leaseService) DraftDueRenewals(ctx context.Context) (int, error) {
+	if s.renewalCfg.LeadDays <= 0 {
+		return 0, nil
+	}
+
+	due, err := s.leaseRepo.ListDueForRenewal(ctx, today().AddDate(0, 0, s.renewalCfg.LeadDays))
+	if err != nil {
+		return 0, fmt.Errorf("list leases due for renewal: %w", err)
+	}
+
+	var (
+		drafted int
+		errs    []error
+	)
+	for _, candidate := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		lease, err := s.fetch(ctx, candidate.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		renewal, err := s.draftRenewal(ctx, lease, lease.TermMonths, lease.OwnerID, nil)
+		if errors.Is(err, repository.ErrLeaseAlreadyRenewed) {
+			// Another instance or the owner drafted it first
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lease %s: %w", lease.ID, err))
+			continue
+		}
+		drafted++
+		if err := s.notifyRenewal(ctx, renewal); err != nil {
+			log.Printf("Failed to tell the owner of lease %s about its renewal: %v", lease.ID, err)
+		}
+	}
+	return drafted, errors.Join(errs...)
+}
+
+// draftRenewal creates the renewal of lease as a draft. actorID is nil when
+// the renewal job drafts it; the owner is then recorded as its creator.
+func (s *leaseService) draftRenewal(ctx context.Context, lease *model.Lease, termMonths int, createdBy uuid.UUID, actorID *uuid.UUID) (*model.Lease, error) {
+	if lease.Status != model.LeaseStatusActive {
+		return nil, apperr.Invalid("Only an active lease can be renewed", nil)
+	}
+	_, err := s.leaseRepo.GetRenewal(ctx, lease.ID)
+	if err == nil {
+		return nil, apperr.Conflict("This lease already has a renewal", repository.ErrLeaseAlreadyRenewed)
+	}
+	if !errors.Is(err, repository.ErrLeaseNotFound) {
+		return nil, apperr.Internal("Failed to fetch renewal", err)
+	}
+
+	rent, err := s.escalatedRent(ctx, lease)
+	if err != nil {
+		return nil, err
+	}
+	clauses, err := s.leaseRepo.ListClauses(ctx, lease.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch lease clauses", err)
+	}
+
+	now := time.Now()
+	renewal := &model.Lease{
+		ID:                    uuid.New(),
+		PropertyID:            lease.PropertyID,
+		OwnerID:               lease.OwnerID,
+		Status:                model.LeaseStatusDraft,
+		StartDate:             lease.EndDate.AddDate(0, 0, 1),
+		TermMonths:            termMonths,
+		MonthlyRentPaise:      rent,
+		SecurityDepositPaise:  lease.SecurityDepositPaise,
+		MaintenancePaise:      lease.MaintenancePaise,
+		RentDueDay:            lease.RentDueDay,
+		RentCycleMonths:       lease.RentCycleMonths,
+		NoticePeriodDays:      lease.NoticePeriodDays,
+		LockInMonths:          min(lease.LockInMonths, termMonths),
+		PremisesUse:           lease.PremisesUse,
+		EscalationType:        lease.EscalationType,
+		EscalationBasisPoints: lease.EscalationBasisPoints,
+		EscalationAmountPaise: lease.EscalationAmountPaise,
+		EscalationCompounding: lease.EscalationCompounding,
+		RenewalOfID:           &lease.ID,
+		CreatedBy:             createdBy,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	if err := applyLeaseTerm(renewal); err != nil {
+		return nil, err
+	}
+
+	// The clause wording agreed last time is kept; the owner may bring clauses up to date in the draft
+	copied := make([]model.LeaseClause, 0, len(clauses))
+	for _, lc := range clauses {
+		copied = append(copied, model.LeaseClause{
+			ID:              uuid.New(),
+			LeaseID:         renewal.ID,
+			ClauseID:        lc.ClauseID,
+			ClauseVersionID: lc.ClauseVersionID,
+			Position:        lc.Position,
+			CreatedAt:       now,
+		})
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Lease.Create(ctx, renewal); err != nil {
+			if errors.Is(err, repository.ErrLeaseAlreadyRenewed) {
+				return apperr.Conflict("This lease already has a renewal", err)
+			}
+			return apperr.Internal("Failed to create renewal", err)
+		}
+		if err := tx.repos.Lease.ReplaceClauses(ctx, renewal.ID, copied); err != nil {
+			return apperr.Internal("Failed to copy lease clauses", err)
+		}
+		for _, tenantID := range lease.TenantIDs() {
+			if err := tx.repos.Lease.AddTenant(ctx, &model.LeaseTenant{
+				LeaseID:   renewal.ID,
+				UserID:    tenantID,
+				CreatedAt: now,
+			}); err != nil {
+				return apperr.Internal("Failed to copy tenants", err)
+			}
+		}
+		if err := tx.repos.Lease.SetRenewalDrafted(ctx, lease.ID, now); err != nil {
+			return apperr.Internal("Failed to link renewal", err)
+		}
+		_, err := s.recordVersion(ctx, tx, renewal.ID, actorID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fetch(ctx, renewal.ID)
+}
+
+// escalatedRent is the rent of the next renewal of lease. A fixed escalation
+// is added to the current rent. A percentage applies to the current rent when
+// compounding, and otherwise to the rent of the first lease in the chain once
+// for every renewal. The result is rounded to the rupee.
+func (s *leaseService) escalatedRent(ctx context.Context, lease *model.Lease) (int64, error) {
+	switch lease.EscalationType {
+	case model.EscalationFixed:
+		return lease.MonthlyRentPaise + lease.EscalationAmountPaise, nil
+	case model.EscalationPercent:
+		if lease.EscalationCompounding {
+			return escalate(lease.MonthlyRentPaise, lease.EscalationBasisPoints, 1), nil
+		}
+
+		base, renewals := lease.MonthlyRentPaise, 1
+		for previousID := lease.RenewalOfID; previousID != nil; renewals++ {
+			previous, err := s.leaseRepo.GetByID(ctx, *previousID)
+			if err != nil {
+				return 0, apperr.Internal("Failed to fetch renewed lease", err)
+			}
+			base, previousID = previous.MonthlyRentPaise, previous.RenewalOfID
+		}
+		return escalate(base, lease.EscalationBasisPoints, renewals), nil
+	}
+	return lease.MonthlyRentPaise, nil
+}
+
+// escalate raises rent by basisPoints the given number of times without
+// compounding, rounding half up to the rupee
+func escalate(rentPaise int64, basisPoints, times int) int64 {
+	scaled := rentPaise * (10_000 + int64(basisPoints)*int64(times))
+	return (scaled + 500_000) / 1_000_000 * 100
+}
+
+// checkEscalation clears the amounts that do not apply to the lease's
+// escalation type and requires the one that does
+func checkEscalation(lease *model.Lease) error {
+	switch lease.EscalationType {
+	case "", model.EscalationNone:
+		lease.EscalationType = model.EscalationNone
+		lease.EscalationBasisPoints, lease.EscalationAmountPaise, lease.EscalationCompounding = 0, 0, false
+	case model.EscalationPercent:
+		if lease.EscalationBasisPoints <= 0 {
+			return apperr.Invalid("Give the escalation percentage in basis points, e.g. 500 for 5%", nil)
+		}
+		lease.EscalationAmountPaise = 0
+	case model.EscalationFixed:
+		if lease.EscalationAmountPaise <= 0 {
+			return apperr.Invalid("Give the escalation amount", nil)
+		}
+		lease.EscalationBasisPoints, lease.EscalationCompounding = 0, false
+	default:
+		return apperr.Invalid("Unknown escalation type "+lease.EscalationType, nil)
+	}
+	return nil
+}
+
+func (s *leaseService) notifyRenewal(ctx context.Context, renewal *model.Lease) error {
+	owner, err := s.userRepo.GetByID(ctx, renewal.OwnerID)
+	if err != nil {
+		return err
+	}
+	if owner.Phone == nil {
+		return nil
+	}
+
+	message := fmt.Sprintf("%s, a renewal of the rent agreement for %s has been drafted: %s a month from %s. Review it and send it to your tenants.",
+		owner.Name, renewal.Property.Name, inr.FormatWithSymbol(renewal.MonthlyRentPaise), renewal.StartDate.Format(clausetext.DateLayout))
+	return s.sms.SendSMS(ctx, *owner.Phone, message)
+}
@@ -0,0 +1,912 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"backend/internal/clausetext"
+	"backend/internal/compliance"
+	"backend/internal/config"
+	"backend/internal/esign"
+	"backend/internal/estamp"
+	"backend/internal/leasepdf"
+	"backend/internal/model"
+	"backend/internal/notify"
+	"backend/internal/policeform"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/internal/stampduty"
+	"backend/internal/storage"
+	"backend/pkg/apperr"
+	"backend/pkg/india"
+
+	"github.com/google/uuid"
+)
+
+type LeaseService interface {
+	Create(ctx context.Context, actor *model.User, input CreateLeaseInput) (*model.Lease, error)
+	GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Lease, error)
+	List(ctx context.Context, actor *model.User, limit, offset int) ([]model.Lease, int64, error)
+	Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdateLeaseInput) (*model.Lease, error)
+	Delete(ctx context.Context, actor *model.User, id uuid.UUID) error
+	ListClauses(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.LeaseClause, error)
+	SetClauses(ctx context.Context, actor *model.User, id uuid.UUID, clauseIDs []uuid.UUID) ([]model.LeaseClause, error)
+	Preview(ctx context.Context, actor *model.User, id uuid.UUID, lang string) ([]model.RenderedClause, error)
+	PDF(ctx context.Context, actor *model.User, id uuid.UUID, input LeasePDFInput) ([]byte, error)
+	ReceiptPDF(ctx context.Context, actor *model.User, leaseID, entryID uuid.UUID) ([]byte, error)
+	StampDuty(ctx context.Context, actor *model.User, id uuid.UUID) (*stampduty.Estimate, error)
+	AddTenant(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Lease, error)
+	RemoveTenant(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Lease, error)
+
+	Submit(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error)
+	Withdraw(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error)
+	MarkSigned(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error)
+	Activate(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error)
+	GiveNotice(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error)
+	Terminate(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error)
+	Expire(ctx context.Context, actor *model.User, id uuid.UUID, reason string) (*model.Lease, error)
+	ListTransitions(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.LeaseTransition, error)
+
+	AttachEStamp(ctx context.Context, actor *model.User, id uuid.UUID, input AttachEStampInput) (*model.EStamp, error)
+	GetEStamp(ctx context.Context, actor *model.User, id uuid.UUID) (*model.EStamp, error)
+	OpenEStamp(ctx context.Context, actor *model.User, id uuid.UUID) (*model.EStamp, io.ReadCloser, error)
+	RemoveEStamp(ctx context.Context, actor *model.User, id uuid.UUID) error
+
+	StartSigning(ctx context.Context, actor *model.User, id uuid.UUID, input StartSigningInput) (*model.LeaseSigning, error)
+	GetSigning(ctx context.Context, actor *model.User, id uuid.UUID) (*model.LeaseSigning, error)
+	OpenSigningDocument(ctx context.Context, actor *model.User, id uuid.UUID) (*model.LeaseSigning, io.ReadCloser, error)
+	ResendSigningLink(ctx context.Context, actor *model.User, id, signerID uuid.UUID) (*model.LeaseSigner, error)
+	ViewSigning(ctx context.Context, token string, client ClientInfo) (*model.SigningView, error)
+	OpenSigningDocumentByToken(ctx context.Context, token string) (*model.LeaseSigning, io.ReadCloser, error)
+	Sign(ctx context.Context, token string, input SignInput) (*model.LeaseSigner, error)
+	StartESign(ctx context.Context, token string, client ClientInfo) (*esign.Transaction, error)
+	CompleteESign(ctx context.Context, responseXML string, client ClientInfo) (*model.LeaseSigner, error)
+	OpenSignedDocument(ctx context.Context, actor *model.User, id, signerID uuid.UUID) (*model.LeaseSigner, io.ReadCloser, error)
+	VerifyDocument(ctx context.Context, code string) (*model.DocumentVerification, error)
+
+	DraftRenewal(ctx context.Context, actor *model.User, id uuid.UUID, input RenewLeaseInput) (*model.Lease, error)
+	GetRenewal(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Lease, error)
+	DraftDueRenewals(ctx context.Context) (int, error)
+
+	ListVersions(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.LeaseVersion, error)
+	GetVersion(ctx context.Context, actor *model.User, id uuid.UUID, number int) (*model.LeaseVersion, error)
+	DiffVersions(ctx context.Context, actor *model.User, id uuid.UUID, from, to int) (*model.LeaseVersionDiff, error)
+
+	ListVerifications(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.TenantVerification, error)
+	GetVerification(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.TenantVerification, error)
+	SaveVerification(ctx context.Context, actor *model.User, id, userID uuid.UUID, input SaveVerificationInput) (*model.TenantVerification, error)
+	UploadVerificationPhoto(ctx context.Context, actor *model.User, id, userID uuid.UUID, input VerificationPhotoInput) (*model.TenantVerification, error)
+	OpenVerificationPhoto(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.TenantVerification, io.ReadCloser, error)
+	VerificationForm(ctx context.Context, actor *model.User, id, userID uuid.UUID) ([]byte, error)
+	SetVerificationStatus(ctx context.Context, actor *model.User, id, userID uuid.UUID, input SetVerificationStatusInput) (*model.TenantVerification, error)
+
+	ListDues(ctx context.Context, actor *model.User, id uuid.UUID) (*model.RentDueSchedule, error)
+}
+
+type CreateLeaseInput struct {
+	PropertyID           uuid.UUID
+	StartDate            time.Time
+	TermMonths           int
+	MonthlyRentPaise     int64
+	SecurityDepositPaise int64
+	MaintenancePaise     int64
+	RentDueDay           int
+	RentCycleMonths      int
+	NoticePeriodDays     int
+	LockInMonths         int
+	PremisesUse          string
+	// Rent escalation on renewal
+	EscalationType        string
+	EscalationBasisPoints int
+	EscalationAmountPaise int64
+	EscalationCompounding bool
+}
+
+type UpdateLeaseInput struct {
+	StartDate            *time.Time
+	TermMonths           *int
+	MonthlyRentPaise     *int64
+	SecurityDepositPaise *int64
+	MaintenancePaise     *int64
+	RentDueDay           *int
+	RentCycleMonths      *int
+	NoticePeriodDays     *int
+	LockInMonths         *int
+	PremisesUse          *string
+	// Rent escalation on renewal
+	EscalationType        *string
+	EscalationBasisPoints *int
+	EscalationAmountPaise *int64
+	EscalationCompounding *bool
+}
+
+type leaseService struct {
+	services     *Services
+	leaseRepo    repository.LeaseRepository
+	propertyRepo repository.PropertyRepository
+	clauseRepo   repository.ClauseRepository
+	userRepo     repository.UserRepository
+	signingRepo  repository.SigningRepository
+	documentRepo repository.DocumentRepository
+	stampDuty    *stampduty.Calculator
+	tenancyLaw   *compliance.Checker
+	fonts        *leasepdf.Fonts
+	policeForms  *policeform.Formats
+	estamps      estamp.EStampProvider
+	esigner      esign.ESignProvider
+	storage      storage.Storage
+	sms          notify.SMSSender
+	maxUpload    int64
+	pdfConfig    config.LeasePDFConfig
+	signingCfg   config.SigningConfig
+	documentCfg  config.DocumentConfig
+	renewalCfg   config.RenewalConfig
+}
+
+func NewLeaseService(
+	services *Services,
+	leaseRepo repository.LeaseRepository,
+	propertyRepo repository.PropertyRepository,
+	clauseRepo repository.ClauseRepository,
+	userRepo repository.UserRepository,
+	signingRepo repository.SigningRepository,
+	documentRepo repository.DocumentRepository,
+	stampDuty *stampduty.Calculator,
+	tenancyLaw *compliance.Checker,
+	fonts *leasepdf.Fonts,
+	policeForms *policeform.Formats,
+	estamps estamp.EStampProvider,
+	esigner esign.ESignProvider,
+	store storage.Storage,
+	sms notify.SMSSender,
+	storageCfg config.StorageConfig,
+	pdfConfig config.LeasePDFConfig,
+	signingCfg config.SigningConfig,
+	documentCfg config.DocumentConfig,
+	renewalCfg config.RenewalConfig,
+) LeaseService {
+	return &leaseService{
+		services:     services,
+		leaseRepo:    leaseRepo,
+		propertyRepo: propertyRepo,
+		clauseRepo:   clauseRepo,
+		userRepo:     userRepo,
+		signingRepo:  signingRepo,
+		documentRepo: documentRepo,
+		stampDuty:    stampDuty,
+		tenancyLaw:   tenancyLaw,
+		fonts:        fonts,
+		policeForms:  policeForms,
+		estamps:      estamps,
+		esigner:      esigner,
+		storage:      store,
+		sms:          sms,
+		maxUpload:    int64(storageCfg.MaxUploadMB) << 20,
+		pdfConfig:    pdfConfig,
+		signingCfg:   signingCfg,
+		documentCfg:  documentCfg,
+		renewalCfg:   renewalCfg,
+	}
+}
+
+// Create drafts a lease for a property, attaches the mandatory clauses and
+// records the draft as version 1
+func (s *leaseService) Create(ctx context.Context, actor *model.User, input CreateLeaseInput) (*model.Lease, error) {
+	if err := policy.AuthorizeCreate(actor, policy.ResourceLease); err != nil {
+		return nil, err
+	}
+
+	property, err := s.propertyRepo.GetByID(ctx, input.PropertyID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPropertyNotFound) {
+			return nil, apperr.NotFound("Property not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch property", err)
+	}
+	if err := policy.Authorize(actor, policy.ActionUpdate, policy.ForProperty(property)); err != nil {
+		return nil, err
+	}
+
+	lease := &model.Lease{
+		ID:                    uuid.New(),
+		PropertyID:            property.ID,
+		OwnerID:               property.OwnerID,
+		Status:                model.LeaseStatusDraft,
+		StartDate:             input.StartDate,
+		TermMonths:            input.TermMonths,
+		MonthlyRentPaise:      input.MonthlyRentPaise,
+		SecurityDepositPaise:  input.SecurityDepositPaise,
+		MaintenancePaise:      input.MaintenancePaise,
+		RentDueDay:            input.RentDueDay,
+		RentCycleMonths:       input.RentCycleMonths,
+		NoticePeriodDays:      input.NoticePeriodDays,
+		LockInMonths:          input.LockInMonths,
+		PremisesUse:           input.PremisesUse,
+		EscalationType:        input.EscalationType,
+		EscalationBasisPoints: input.EscalationBasisPoints,
+		EscalationAmountPaise: input.EscalationAmountPaise,
+		EscalationCompounding: input.EscalationCompounding,
+		CreatedBy:             actor.ID,
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
+	}
+	if err := applyLeaseTerm(lease); err != nil {
+		return nil, err
+	}
+	if err := checkEscalation(lease); err != nil {
+		return nil, err
+	}
+	if lease.PremisesUse == "" {
+		lease.PremisesUse = model.PremisesResidential
+	}
+	if lease.RentCycleMonths == 0 {
+		lease.RentCycleMonths = 1
+	}
+
+	mandatory, err := s.clauseRepo.ListMandatory(ctx)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch mandatory clauses", err)
+	}
+	sortClauses(mandatory)
+
+	lease.Property = property
+	clauseIDs := make([]uuid.UUID, 0, len(mandatory))
+	for _, clause := range mandatory {
+		clauseIDs = append(clauseIDs, clause.ID)
+	}
+	if lease.Compliance, err = s.checkCompliance(ctx, lease, clauseIDs); err != nil {
+		return nil, err
+	}
+	if err := complianceError(lease.Compliance); err != nil {
+		return nil, err
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Lease.Create(ctx, lease); err != nil {
+			return apperr.Internal("Failed to create lease", err)
+		}
+		if err := tx.repos.Lease.ReplaceClauses(ctx, lease.ID, leaseClauses(lease.ID, mandatory)); err != nil {
+			return apperr.Internal("Failed to attach mandatory clauses", err)
+		}
+		_, err := s.recordVersion(ctx, tx, lease.ID, &actor.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// GetByID returns the lease with its compliance findings
+func (s *leaseService) GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Lease, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withCompliance(ctx, lease)
+}
+
+// List returns every lease for admins, and otherwise the leases on properties
+// the actor owns, co-owns or manages plus the leases they are a tenant on
+func (s *leaseService) List(ctx context.Context, actor *model.User, limit, offset int) ([]model.Lease, int64, error) {
+	var (
+		leases []model.Lease
+		total  int64
+		err    error
+	)
+
+	if actor.IsAdmin() {
+		leases, total, err = s.leaseRepo.List(ctx, limit, offset)
+	} else {
+		leases, total, err = s.leaseRepo.ListAccessibleBy(ctx, actor.ID, limit, offset)
+	}
+	if err != nil {
+		return nil, 0, apperr.Internal("Failed to fetch leases", err)
+	}
+
+	return leases, total, nil
+}
+
+// Update changes the terms of a draft lease, recording a new version when
+// anything changed
+func (s *leaseService) Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdateLeaseInput) (*model.Lease, error) {
+	lease, err := s.authorizedDraft(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.StartDate != nil {
+		lease.StartDate = *input.StartDate
+	}
+	if input.TermMonths != nil {
+		lease.TermMonths = *input.TermMonths
+	}
+	if input.MonthlyRentPaise != nil {
+		lease.MonthlyRentPaise = *input.MonthlyRentPaise
+	}
+	if input.SecurityDepositPaise != nil {
+		lease.SecurityDepositPaise = *input.SecurityDepositPaise
+	}
+	if input.MaintenancePaise != nil {
+		lease.MaintenancePaise = *input.MaintenancePaise
+	}
+	if input.RentDueDay != nil {
+		lease.RentDueDay = *input.RentDueDay
+	}
+	if input.RentCycleMonths != nil {
+		lease.RentCycleMonths = *input.RentCycleMonths
+	}
+	if input.NoticePeriodDays != nil {
+		lease.NoticePeriodDays = *input.NoticePeriodDays
+	}
+	if input.LockInMonths != nil {
+		lease.LockInMonths = *input.LockInMonths
+	}
+	if input.PremisesUse != nil {
+		lease.PremisesUse = *input.PremisesUse
+	}
+	if input.EscalationType != nil {
+		lease.EscalationType = *input.EscalationType
+	}
+	if input.EscalationBasisPoints != nil {
+		lease.EscalationBasisPoints = *input.EscalationBasisPoints
+	}
+	if input.EscalationAmountPaise != nil {
+		lease.EscalationAmountPaise = *input.EscalationAmountPaise
+	}
+	if input.EscalationCompounding != nil {
+		lease.EscalationCompounding = *input.EscalationCompounding
+	}
+	if err := applyLeaseTerm(lease); err != nil {
+		return nil, err
+	}
+	if err := checkEscalation(lease); err != nil {
+		return nil, err
+	}
+	if _, err := s.withCompliance(ctx, lease); err != nil {
+		return nil, err
+	}
+	if err := complianceError(lease.Compliance); err != nil {
+		return nil, err
+	}
+	lease.UpdatedAt = time.Now()
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Lease.Update(ctx, lease); err != nil {
+			return apperr.Internal("Failed to update lease", err)
+		}
+		_, err := s.recordVersion(ctx, tx, lease.ID, &actor.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+func (s *leaseService) Delete(ctx context.Context, actor *model.User, id uuid.UUID) error {
+	if _, err := s.authorizedDraft(ctx, actor, policy.ActionDelete, id); err != nil {
+		return err
+	}
+
+	stamp, err := s.leaseRepo.GetEStamp(ctx, id)
+	if err != nil && !errors.Is(err, repository.ErrEStampNotFound) {
+		return apperr.Internal("Failed to fetch e-stamp", err)
+	}
+
+	if err := s.leaseRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrLeaseNotFound) {
+			return apperr.NotFound("Lease not found", err)
+		}
+		return apperr.Internal("Failed to delete lease", err)
+	}
+	if stamp != nil {
+		s.removeFile(ctx, stamp.StorageKey)
+	}
+	return nil
+}
+
+func (s *leaseService) ListClauses(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.LeaseClause, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+
+	clauses, err := s.leaseRepo.ListClauses(ctx, id)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch lease clauses", err)
+	}
+
+	return clauses, nil
+}
+
+// SetClauses replaces the clauses of a draft lease with the given ordered
+// list, pinning the current version of each, and records a new lease version.
+// Every mandatory clause must be included.
+func (s *leaseService) SetClauses(ctx context.Context, actor *model.User, id uuid.UUID, clauseIDs []uuid.UUID) ([]model.LeaseClause, error) {
+	lease, err := s.authorizedDraft(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, clauseID := range clauseIDs {
+		if slices.Contains(clauseIDs[:i], clauseID) {
+			return nil, apperr.Invalid("Each clause can be added only once", nil)
+		}
+	}
+
+	found, err := s.clauseRepo.GetByIDs(ctx, clauseIDs)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch clauses", err)
+	}
+	if len(found) != len(clauseIDs) {
+		return nil, apperr.NotFound("Clause not found", nil)
+	}
+
+	byID := make(map[uuid.UUID]model.Clause, len(found))
+	for _, clause := range found {
+		byID[clause.ID] = clause
+	}
+
+	ordered := make([]model.Clause, 0, len(clauseIDs))
+	for _, clauseID := range clauseIDs {
+		clause := byID[clauseID]
+		if clause.ArchivedAt != nil {
+			return nil, apperr.Invalid("Clause \""+clause.Title+"\" has been archived", nil)
+		}
+		if clause.CurrentVersionID == nil {
+			return nil, apperr.Invalid("Clause \""+clause.Title+"\" has no text", nil)
+		}
+		if !clause.IsSystem() && *clause.OwnerID != lease.OwnerID && *clause.OwnerID != actor.ID && !actor.IsAdmin() {
+			return nil, apperr.Forbidden("Clause \""+clause.Title+"\" belongs to another owner", nil)
+		}
+		ordered = append(ordered, clause)
+	}
+
+	mandatory, err := s.clauseRepo.ListMandatory(ctx)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch mandatory clauses", err)
+	}
+	for _, clause := range mandatory {
+		if !slices.Contains(clauseIDs, clause.ID) {
+			return nil, apperr.Invalid("Mandatory clause \""+clause.Title+"\" must be included", nil)
+		}
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Lease.ReplaceClauses(ctx, lease.ID, leaseClauses(lease.ID, ordered)); err != nil {
+			return apperr.Internal("Failed to update lease clauses", err)
+		}
+		_, err := s.recordVersion(ctx, tx, lease.ID, &actor.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	clauses, err := s.leaseRepo.ListClauses(ctx, lease.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch lease clauses", err)
+	}
+
+	return clauses, nil
+}
+
+// Preview renders the lease clauses with the lease terms filled in, along
+// with their translations into lang where they have one. It fails with the
+// list of variables that have no value yet.
+func (s *leaseService) Preview(ctx context.Context, actor *model.User, id uuid.UUID, lang string) ([]model.RenderedClause, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.render(ctx, lease, lang)
+}
+
+// LeasePDFInput chooses how the agreement is printed
+type LeasePDFInput struct {
+	// StampMarginMM overrides the configured blank space at the top of page one
+	StampMarginMM *int
+	// Lang, other than English, prints the clauses translated into it
+	Lang string
+	// Bilingual prints the translated clauses beside the English wording
+	Bilingual bool
+}
+
+// PDF lays out the lease as a printable agreement carrying a verification
+// code. Once signed online, the signed version with the audit certificate is
+// returned as stored and the stamp margin is ignored. A copy in another
+// language is rendered afresh whether or not the lease is signed.
+func (s *leaseService) PDF(ctx context.Context, actor *model.User, id uuid.UUID, input LeasePDFInput) ([]byte, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Lang != "" && input.Lang != india.English {
+		return s.translatedDocument(ctx, actor, lease, input)
+	}
+
+	signing, err := s.signingRepo.GetLatest(ctx, lease.ID)
+	if err != nil && !errors.Is(err, repository.ErrSigningNotFound) {
+		return nil, apperr.Internal("Failed to fetch signing", err)
+	}
+	if signing != nil && signing.Status == model.SigningStatusCompleted {
+		return s.signedDocument(ctx, lease, signing)
+	}
+
+	return s.leaseDocument(ctx, actor, lease, input.StampMarginMM)
+}
+
+// document collects everything printed in the agreement, with its clauses
+// in lang where translations gives them one
+func (s *leaseService) document(ctx context.Context, lease *model.Lease, translations map[uuid.UUID]model.ClauseTranslation, lang string) (*leasepdf.Document, error) {
+	clauses, err := s.renderWith(ctx, lease, translations)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := s.owners(ctx, lease)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &leasepdf.Document{
+		Lease:    lease,
+		Property: lease.Property,
+		Owners:   owners,
+		Tenants:  tenantUsers(lease),
+		Clauses:  clauses,
+		Lang:     lang,
+	}
+	// The figure is left out for states without rules rather than failing the document
+	if estimate, err := s.estimateStampDuty(lease); err == nil {
+		doc.StampDuty = estimate
+	}
+	stamp, err := s.leaseRepo.GetEStamp(ctx, lease.ID)
+	if err != nil && !errors.Is(err, repository.ErrEStampNotFound) {
+		return nil, apperr.Internal("Failed to fetch e-stamp", err)
+	}
+	doc.EStamp = stamp
+	version, err := s.leaseRepo.GetLatestVersion(ctx, lease.ID)
+	if err != nil && !errors.Is(err, repository.ErrLeaseVersionNotFound) {
+		return nil, apperr.Internal("Failed to fetch lease version", err)
+	}
+	if version != nil {
+		doc.Version = version.Number
+	}
+
+	return doc, nil
+}
+
+func (s *leaseService) renderPDF(doc *leasepdf.Document, stampMarginMM *int) ([]byte, error) {
+	opts := leasepdf.Options{
+		PaperSize:     s.pdfConfig.PaperSize,
+		StampMarginMM: float64(s.pdfConfig.StampMarginMM),
+		Fonts:         s.fonts,
+	}
+	if stampMarginMM != nil {
+		opts.StampMarginMM = float64(*stampMarginMM)
+	}
+
+	content, err := leasepdf.Render(doc, opts)
+	if err != nil {
+		return nil, apperr.Internal("Failed to generate lease PDF", err)
+	}
+
+	return content, nil
+}
+
+// StampDuty estimates the stamp duty and registration fee for the lease in
+// the property's state
+func (s *leaseService) StampDuty(ctx context.Context, actor *model.User, id uuid.UUID) (*stampduty.Estimate, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, id)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := s.estimateStampDuty(lease)
+	if err != nil {
+		if errors.Is(err, stampduty.ErrUnsupportedState) || errors.Is(err, stampduty.ErrUnsupportedTerm) {
+			return nil, apperr.Invalid("Stamp duty cannot be calculated: "+err.Error(), err)
+		}
+		return nil, apperr.Internal("Failed to calculate stamp duty", err)
+	}
+
+	return estimate, nil
+}
+
+func (s *leaseService) estimateStampDuty(lease *model.Lease) (*stampduty.Estimate, error) {
+	return s.stampDuty.Calculate(stampduty.Input{
+		State:            lease.Property.State,
+		MonthlyRentPaise: lease.MonthlyRentPaise,
+		DepositPaise:     lease.SecurityDepositPaise,
+		TermMonths:       lease.TermMonths,
+	})
+}
+
+// AddTenant adds a user with the tenant role as a party to a draft lease
+func (s *leaseService) AddTenant(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Lease, error) {
+	lease, err := s.authorizedDraft(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(lease.TenantIDs(), userID) {
+		return nil, apperr.Conflict("User is already a tenant on this lease", nil)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, apperr.NotFound("User not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch user", err)
+	}
+	if user.Role != model.RoleTenant {
+		return nil, apperr.Invalid("User must have the tenant role", nil)
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Lease.AddTenant(ctx, &model.LeaseTenant{
+			LeaseID:   lease.ID,
+			UserID:    user.ID,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return apperr.Internal("Failed to add tenant", err)
+		}
+		_, err := s.recordVersion(ctx, tx, lease.ID, &actor.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fetch(ctx, id)
+}
+
+func (s *leaseService) RemoveTenant(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Lease, error) {
+	lease, err := s.authorizedDraft(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Lease.RemoveTenant(ctx, lease.ID, userID); err != nil {
+			if errors.Is(err, repository.ErrLeaseTenantNotFound) {
+				return apperr.NotFound("Tenant not found", err)
+			}
+			return apperr.Internal("Failed to remove tenant", err)
+		}
+		_, err := s.recordVersion(ctx, tx, lease.ID, &actor.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fetch(ctx, id)
+}
+
+// render fills in the lease's clauses with its terms and parties. Unless
+// lang is English, clauses translated into it carry their current
+// translation too.
+func (s *leaseService) render(ctx context.Context, lease *model.Lease, lang string) ([]model.RenderedClause, error) {
+	translations := map[uuid.UUID]model.ClauseTranslation{}
+	if lang != "" && lang != india.English {
+		clauses, err := s.leaseRepo.ListClauses(ctx, lease.ID)
+		if err != nil {
+			return nil, apperr.Internal("Failed to fetch lease clauses", err)
+		}
+		versionIDs := make([]uuid.UUID, 0, len(clauses))
+		for _, lc := range clauses {
+			versionIDs = append(versionIDs, lc.ClauseVersionID)
+		}
+		if len(versionIDs) > 0 {
+			found, err := s.clauseRepo.GetTranslations(ctx, versionIDs, lang)
+			if err != nil {
+				return nil, apperr.Internal("Failed to fetch clause translations", err)
+			}
+			for _, t := range found {
+				translations[t.ClauseVersionID] = t
+			}
+		}
+	}
+
+	return s.renderWith(ctx, lease, translations)
+}
+
+// renderWith fills in the lease's clauses with its terms and parties, along
+// with the given translations by clause version
+func (s *leaseService) renderWith(ctx context.Context, lease *model.Lease, translations map[uuid.UUID]model.ClauseTranslation) ([]model.RenderedClause, error) {
+	clauses, err := s.leaseRepo.ListClauses(ctx, lease.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch lease clauses", err)
+	}
+
+	data, err := s.templateData(ctx, lease)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderClauses(clauses, translations, data)
+}
+
+// templateData collects the values clause variables are resolved from
+func (s *leaseService) templateData(ctx context.Context, lease *model.Lease) (*clausetext.Data, error) {
+	owner, err := s.userRepo.GetByID(ctx, lease.OwnerID)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, apperr.Internal("Failed to fetch owner", err)
+	}
+
+	data := &clausetext.Data{
+		Lease:    lease,
+		Property: lease.Property,
+		Owner:    owner,
+	}
+	data.Tenants = tenantUsers(lease)
+	return data, nil
+}
+
+// owners loads the owner followed by the property's co-owners
+func (s *leaseService) owners(ctx context.Context, lease *model.Lease) ([]model.User, error) {
+	ids := append([]uuid.UUID{lease.OwnerID}, lease.Property.CoOwnerIDs()...)
+
+	owners := make([]model.User, 0, len(ids))
+	for _, userID := range ids {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, apperr.Internal("Failed to fetch owner", err)
+		}
+		owners = append(owners, *user)
+	}
+	return owners, nil
+}
+
+// tenantUsers returns the preloaded users of the lease's tenants
+func tenantUsers(lease *model.Lease) []model.User {
+	var users []model.User
+	for _, tenant := range lease.Tenants {
+		if tenant.User != nil {
+			users = append(users, *tenant.User)
+		}
+	}
+	return users
+}
+
+// renderClauses fills in the pinned wording of each clause and its
+// translation, collecting every missing variable across clauses into one error
+func renderClauses(clauses []model.LeaseClause, translations map[uuid.UUID]model.ClauseTranslation, data *clausetext.Data) ([]model.RenderedClause, error) {
+	var (
+		rendered = make([]model.RenderedClause, 0, len(clauses))
+		missing  []string
+	)
+
+	for _, lc := range clauses {
+		text, ok, err := fillClause(lc.ClauseVersion.Body, data, &missing)
+		if err != nil {
+			return nil, apperr.Invalid("Clause \""+lc.Clause.Title+"\" cannot be rendered: "+err.Error(), err)
+		}
+		if !ok {
+			continue
+		}
+
+		clause := model.RenderedClause{
+			Position: lc.Position,
+			ClauseID: lc.ClauseID,
+			Category: lc.Clause.Category,
+			Title:    lc.Clause.Title,
+			Version:  lc.ClauseVersion.Version,
+			Text:     text,
+		}
+		if t, found := translations[lc.ClauseVersionID]; found {
+			text, ok, err := fillClause(t.Body, data, &missing)
+			if err != nil {
+				return nil, apperr.Invalid("The "+india.LanguageName(t.Lang)+" translation of clause \""+lc.Clause.Title+"\" cannot be rendered: "+err.Error(), err)
+			}
+			if !ok {
+				continue
+			}
+			clause.Translation = &model.TranslatedClause{Lang: t.Lang, Title: t.Title, Text: text}
+		}
+		rendered = append(rendered, clause)
+	}
+
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, apperr.Invalid("Missing values for: "+strings.Join(missing, ", "), nil)
+	}
+	return rendered, nil
+}
+
+// fillClause renders a clause body, adding the variables that have no value
+// to missing instead of failing; ok is false when there were any
+func fillClause(body string, data *clausetext.Data, missing *[]string) (string, bool, error) {
+	text, err := clausetext.Render(body, data)
+	if err != nil {
+		var unresolved *clausetext.UnresolvedError
+		if errors.As(err, &unresolved) {
+			for _, name := range unresolved.Names {
+				if !slices.Contains(*missing, name) {
+					*missing = append(*missing, name)
+				}
+			}
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return text, true, nil
+}
+
+func (s *leaseService) fetch(ctx context.Context, id uuid.UUID) (*model.Lease, error) {
+	lease, err := s.leaseRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrLeaseNotFound) {
+			return nil, apperr.NotFound("Lease not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch lease", err)
+	}
+	return lease, nil
+}
+
+func (s *leaseService) authorized(ctx context.Context, actor *model.User, action policy.Action, id uuid.UUID) (*model.Lease, error) {
+	lease, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.Authorize(actor, action, policy.ForLease(lease)); err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// authorizedDraft is authorized for operations that are only allowed while the lease is a draft
+func (s *leaseService) authorizedDraft(ctx context.Context, actor *model.User, action policy.Action, id uuid.UUID) (*model.Lease, error) {
+	lease, err := s.authorized(ctx, actor, action, id)
+	if err != nil {
+		return nil, err
+	}
+	if lease.Status != model.LeaseStatusDraft {
+		return nil, apperr.Invalid("Only draft leases can be changed", nil)
+	}
+	return lease, nil
+}
+
+// applyLeaseTerm validates the term and sets the end date to the day before
+// the same date TermMonths later
+func applyLeaseTerm(lease *model.Lease) error {
+	if lease.LockInMonths > lease.TermMonths {
+		return apperr.Invalid("Lock-in period cannot be longer than the lease term", nil)
+	}
+	lease.EndDate = lease.StartDate.AddDate(0, lease.TermMonths, -1)
+	return nil
+}
+
+// sortClauses orders clauses by category as they appear in a lease
+func sortClauses(clauses []model.Clause) {
+	slices.SortStableFunc(clauses, func(a, b model.Clause) int {
+		return slices.Index(model.ClauseCategories, a.Category) - slices.Index(model.ClauseCategories, b.Category)
+	})
+}
+
+// leaseClauses pins the current version of each clause, in order
+func leaseClauses(leaseID uuid.UUID, clauses []model.Clause) []model.LeaseClause {
+	result := make([]model.LeaseClause, 0, len(clauses))
+	for i, clause := range clauses {
+		result = append(result, model.LeaseClause{
+			ID:              uuid.New(),
+			LeaseID:         leaseID,
+			ClauseID:        clause.ID,
+			ClauseVersionID: *clause.CurrentVersionID,
+			Position:        i + 1,
+			CreatedAt:       time.Now(),
+		})
+	}
+	return result
+}
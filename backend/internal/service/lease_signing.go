@@ -0,0 +1,666 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"backend/internal/auth"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/internal/storage"
+	"backend/pkg/apperr"
+	"backend/pkg/india"
+	"backend/pkg/phone"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxSignatureImageBytes = 200 << 10
+	maxSignatureImageSide  = 2000
+)
+
+type WitnessInput struct {
+	Name  string
+	Phone string
+}
+
+type StartSigningInput struct {
+	Sequential bool
+	Witnesses  []WitnessInput
+	// Version, when set, is the lease version the owner reviewed; signing
+	// does not start if the lease has moved on since
+	Version int
+}
+
+type SignInput struct {
+	SignatureType string
+	Signature     string
+	Consent       bool
+	Client        ClientInfo
+}
+
+// signingLink is a link issued to a signer, sent once the database changes commit
+type signingLink struct {
+	signer    *model.LeaseSigner
+	url       string
+	expiresAt time.Time
+}
+
+// StartSigning renders the lease as it will be signed, stores it with its
+// SHA-256 and sends signing links to the parties: owners, then tenants, then
+// witnesses. In sequential signing only the first party gets a link now.
+func (s *leaseService) StartSigning(ctx context.Context, actor *model.User, id uuid.UUID, input StartSigningInput) (*model.LeaseSigning, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if lease.Status != model.LeaseStatusPendingSignatures {
+		return nil, apperr.Invalid("Send the lease for signatures before collecting them", nil)
+	}
+
+	_, err = s.signingRepo.GetActive(ctx, lease.ID)
+	if err == nil {
+		return nil, apperr.Conflict("Signatures are already being collected on this lease", nil)
+	}
+	if !errors.Is(err, repository.ErrSigningNotFound) {
+		return nil, apperr.Internal("Failed to fetch signing", err)
+	}
+
+	version, err := s.currentVersion(ctx, lease.ID, &actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Version != 0 && input.Version != version.Number {
+		return nil, apperr.Conflict(fmt.Sprintf("The lease is now at version %d; review it before collecting signatures", version.Number), nil)
+	}
+
+	now := time.Now()
+	signing := &model.LeaseSigning{
+		ID:         uuid.New(),
+		LeaseID:    lease.ID,
+		Status:     model.SigningStatusInProgress,
+		Sequential: input.Sequential,
+		VersionID:  &version.ID,
+		CreatedBy:  actor.ID,
+		CreatedAt:  now,
+	}
+	if err := s.addSigners(ctx, signing, lease, input.Witnesses); err != nil {
+		return nil, err
+	}
+
+	doc, err := s.document(ctx, lease, nil, india.English)
+	if err != nil {
+		return nil, err
+	}
+	for _, signer := range signing.Signers {
+		if signer.Role == model.SignerRoleWitness {
+			doc.Witnesses = append(doc.Witnesses, signer.Name)
+		}
+	}
+	document, err := newDocument(model.DocumentKindLeaseSigning, lease.ID, &signing.ID, &actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	content, sum, err := s.renderDocument(doc, document, nil)
+	if err != nil {
+		return nil, err
+	}
+	signing.DocumentSHA256 = sum
+	signing.DocumentKey = fmt.Sprintf("leases/%s/signings/%s.pdf", lease.ID, signing.ID)
+	document.SHA256 = sum
+	document.StorageKey = signing.DocumentKey
+
+	if err := s.storage.Put(ctx, signing.DocumentKey, bytes.NewReader(content)); err != nil {
+		return nil, apperr.Internal("Failed to store signing document", err)
+	}
+
+	// Links are sent inside the transaction so a failed message leaves nothing behind
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Signing.Create(ctx, signing); err != nil {
+			return apperr.Internal("Failed to start signing", err)
+		}
+		if err := tx.repos.Document.Create(ctx, document); err != nil {
+			return apperr.Internal("Failed to record document", err)
+		}
+		mode := "parallel"
+		if signing.Sequential {
+			mode = "sequential"
+		}
+		if err := s.recordSigningEvent(ctx, tx, signing, nil, &actor.ID, model.SigningEventStarted, ClientInfo{},
+			fmt.Sprintf("%d parties, %s signing of version %d", len(signing.Signers), mode, version.Number)); err != nil {
+			return err
+		}
+
+		links, err := s.issueLinks(ctx, tx, signing, signing.NextSigners(), &actor.ID)
+		if err != nil {
+			return err
+		}
+		for _, link := range links {
+			if err := s.sendLink(ctx, lease, link); err != nil {
+				return apperr.Internal("Failed to send signing link to "+link.signer.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.removeFile(ctx, signing.DocumentKey)
+		return nil, err
+	}
+
+	return s.fetchSigning(ctx, signing.ID)
+}
+
+// GetSigning returns the lease's latest signing round with its audit trail
+func (s *leaseService) GetSigning(ctx context.Context, actor *model.User, id uuid.UUID) (*model.LeaseSigning, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, id)
+	if err != nil {
+		return nil, err
+	}
+
+	signing, err := s.signingRepo.GetLatest(ctx, lease.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSigningNotFound) {
+			return nil, apperr.NotFound("Signatures have not been requested on this lease", err)
+		}
+		return nil, apperr.Internal("Failed to fetch signing", err)
+	}
+	return signing, nil
+}
+
+// OpenSigningDocument returns the document of the lease's latest signing
+// round, exactly as it was presented for signature. The caller must close the reader.
+func (s *leaseService) OpenSigningDocument(ctx context.Context, actor *model.User, id uuid.UUID) (*model.LeaseSigning, io.ReadCloser, error) {
+	signing, err := s.GetSigning(ctx, actor, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.openSigningDocument(ctx, signing)
+	if err != nil {
+		return nil, nil, err
+	}
+	return signing, content, nil
+}
+
+// ResendSigningLink replaces a pending signer's link with a new one, for when
+// the first was lost or has expired
+func (s *leaseService) ResendSigningLink(ctx context.Context, actor *model.User, id, signerID uuid.UUID) (*model.LeaseSigner, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+
+	signing, err := s.signingRepo.GetActive(ctx, lease.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSigningNotFound) {
+			return nil, apperr.NotFound("Signatures are not being collected on this lease", err)
+		}
+		return nil, apperr.Internal("Failed to fetch signing", err)
+	}
+
+	signer := signing.Signer(signerID)
+	if signer == nil {
+		return nil, apperr.NotFound("Signer not found", nil)
+	}
+	if signer.Status == model.SignerStatusSigned {
+		return nil, apperr.Invalid(signer.Name+" has already signed", nil)
+	}
+	if !isNextSigner(signing, signer) {
+		return nil, apperr.Invalid(signer.Name+" signs after the parties before them; their link is sent when it is their turn", nil)
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		links, err := s.issueLinks(ctx, tx, signing, []*model.LeaseSigner{signer}, &actor.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.sendLink(ctx, lease, links[0]); err != nil {
+			return apperr.Internal("Failed to send signing link to "+signer.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+// ViewSigning returns what the holder of a signing link is asked to sign and
+// records that they opened it
+func (s *leaseService) ViewSigning(ctx context.Context, token string, client ClientInfo) (*model.SigningView, error) {
+	signer, signing, err := s.signerByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	lease, err := s.fetch(ctx, signing.LeaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordSigningEvent(ctx, s.services, signing, &signer.ID, nil, model.SigningEventViewed, client, ""); err != nil {
+		return nil, err
+	}
+
+	view := &model.SigningView{
+		SignerName:     signer.Name,
+		Role:           signer.Role,
+		LinkExpiresAt:  *signer.LinkExpiresAt,
+		DocumentSHA256: signing.DocumentSHA256,
+		Lease:          lease,
+	}
+	for _, other := range signing.Signers {
+		view.Signers = append(view.Signers, model.SignerStatus{
+			Name:         other.Name,
+			Role:         other.Role,
+			SigningOrder: other.SigningOrder,
+			Status:       other.Status,
+			SignedAt:     other.SignedAt,
+		})
+	}
+	return view, nil
+}
+
+// OpenSigningDocumentByToken returns the document the holder of a signing
+// link is asked to sign. The caller must close the reader.
+func (s *leaseService) OpenSigningDocumentByToken(ctx context.Context, token string) (*model.LeaseSigning, io.ReadCloser, error) {
+	_, signing, err := s.signerByToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.openSigningDocument(ctx, signing)
+	if err != nil {
+		return nil, nil, err
+	}
+	return signing, content, nil
+}
+
+// Sign records the signature of the holder of a signing link
+func (s *leaseService) Sign(ctx context.Context, token string, input SignInput) (*model.LeaseSigner, error) {
+	signer, signing, err := s.signerByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !input.Consent {
+		return nil, apperr.Invalid("Confirm that you have read the agreement and agree to sign it electronically", nil)
+	}
+
+	data, err := signatureData(input.SignatureType, input.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	signer.Status = model.SignerStatusSigned
+	signer.SignedAt = &now
+	signer.TokenHash = nil
+	signer.LinkExpiresAt = nil
+	signer.SignatureType = input.SignatureType
+	signer.SignatureData = data
+	signer.IPAddress = input.Client.IPAddress
+	signer.UserAgent = truncate(input.Client.UserAgent, 500)
+
+	if err := s.recordSignature(ctx, signing, signer, auth.HashLinkToken(token), input.Client, "Signature "+signer.SignatureType); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// recordSignature saves a signature given on the link identified by
+// tokenHash. In sequential signing the next party is then sent their link;
+// when the last party signs the round completes and the lease is marked signed.
+// The signing is locked and reloaded first, so of two parties signing at once
+// the second sees the first's signature and completes the round.
+func (s *leaseService) recordSignature(ctx context.Context, signing *model.LeaseSigning, signer *model.LeaseSigner, tokenHash string, client ClientInfo, detail string) error {
+	now := *signer.SignedAt
+	var (
+		links     []signingLink
+		lease     *model.Lease
+		completed bool
+		err       error
+	)
+	err = s.services.Transaction(func(tx *Services) error {
+		locked, err := tx.repos.Signing.Lock(ctx, signing.ID)
+		if err != nil {
+			return apperr.Internal("Failed to fetch signing", err)
+		}
+		if locked.Status != model.SigningStatusInProgress {
+			return apperr.Conflict("Signing was completed or cancelled", nil)
+		}
+		current := locked.Signer(signer.ID)
+		if current == nil {
+			return apperr.NotFound("Signer not found", nil)
+		}
+		*signing = *locked
+
+		if err := s.checkSigningVersion(ctx, tx, signing); err != nil {
+			return err
+		}
+		if err := tx.repos.Signing.RecordSignature(ctx, signer, tokenHash); err != nil {
+			if errors.Is(err, repository.ErrSigningLinkUsed) {
+				return apperr.Conflict("This signing link has already been used", err)
+			}
+			return apperr.Internal("Failed to record signature", err)
+		}
+		*current = *signer
+		if err := s.recordSigningEvent(ctx, tx, signing, &signer.ID, nil, model.SigningEventSigned, client, detail); err != nil {
+			return err
+		}
+
+		next := signing.NextSigners()
+		if len(next) > 0 {
+			var unsent []*model.LeaseSigner
+			for _, n := range next {
+				if n.TokenHash == nil {
+					unsent = append(unsent, n)
+				}
+			}
+			links, err = s.issueLinks(ctx, tx, signing, unsent, nil)
+			return err
+		}
+
+		signing.Status = model.SigningStatusCompleted
+		signing.CompletedAt = &now
+		if err := tx.repos.Signing.UpdateStatus(ctx, signing, model.SigningStatusInProgress); err != nil {
+			if errors.Is(err, repository.ErrSigningStatusChanged) {
+				return apperr.Conflict("Signing was cancelled", err)
+			}
+			return apperr.Internal("Failed to complete signing", err)
+		}
+		if err := s.recordSigningEvent(ctx, tx, signing, nil, nil, model.SigningEventCompleted, ClientInfo{},
+			"All parties signed document "+signing.DocumentSHA256); err != nil {
+			return err
+		}
+
+		lease, err = tx.repos.Lease.GetByID(ctx, signing.LeaseID)
+		if err != nil {
+			return apperr.Internal("Failed to fetch lease", err)
+		}
+		completed = true
+		return s.applyEvent(ctx, tx, lease, LeaseEventSign, nil, "Signed online by all parties")
+	})
+	if err != nil {
+		return err
+	}
+
+	// Freezing the signed version now fixes its hash; a failure is retried on the next download
+	if completed {
+		if _, err := s.signedDocument(ctx, lease, signing); err != nil {
+			log.Printf("Failed to store signed document of signing %s: %v", signing.ID, err)
+		}
+	}
+
+	// The signature is saved; a link that fails to send can be resent by the owner
+	if len(links) > 0 {
+		if lease, err = s.leaseRepo.GetByID(ctx, signing.LeaseID); err == nil {
+			for _, link := range links {
+				if err := s.sendLink(ctx, lease, link); err != nil {
+					log.Printf("Failed to send signing link to signer %s: %v", link.signer.ID, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSigningVersion fails when the lease has a newer version than the one
+// the signing round was started on. Rounds started before versions were kept
+// are not checked.
+func (s *leaseService) checkSigningVersion(ctx context.Context, tx *Services, signing *model.LeaseSigning) error {
+	if signing.VersionID == nil {
+		return nil
+	}
+	latest, err := tx.repos.Lease.GetLatestVersion(ctx, signing.LeaseID)
+	if err != nil {
+		return apperr.Internal("Failed to fetch lease version", err)
+	}
+	if latest.ID != *signing.VersionID {
+		return apperr.Conflict("The lease has changed since it was sent for signature", nil)
+	}
+	return nil
+}
+
+// addSigners lists the owners, tenants and witnesses as signers in signing order
+func (s *leaseService) addSigners(ctx context.Context, signing *model.LeaseSigning, lease *model.Lease, witnesses []WitnessInput) error {
+	owners, err := s.owners(ctx, lease)
+	if err != nil {
+		return err
+	}
+
+	add := func(role string, userID *uuid.UUID, name, number string) error {
+		normalized, err := phone.Normalize(number)
+		if err != nil {
+			return apperr.Invalid(name+" needs a valid phone number to receive the signing link", err)
+		}
+		signing.Signers = append(signing.Signers, model.LeaseSigner{
+			ID:           uuid.New(),
+			SigningID:    signing.ID,
+			Role:         role,
+			UserID:       userID,
+			Name:         name,
+			Phone:        normalized,
+			SigningOrder: len(signing.Signers) + 1,
+			Status:       model.SignerStatusPending,
+			CreatedAt:    signing.CreatedAt,
+		})
+		return nil
+	}
+	addUser := func(role string, user model.User) error {
+		var number string
+		if user.Phone != nil {
+			number = *user.Phone
+		}
+		return add(role, &user.ID, user.Name, number)
+	}
+
+	for _, owner := range owners {
+		if err := addUser(model.SignerRoleOwner, owner); err != nil {
+			return err
+		}
+	}
+	for _, tenant := range tenantUsers(lease) {
+		if err := addUser(model.SignerRoleTenant, tenant); err != nil {
+			return err
+		}
+	}
+	for _, witness := range witnesses {
+		if err := add(model.SignerRoleWitness, nil, strings.TrimSpace(witness.Name), witness.Phone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueLinks gives each signer a new link, replacing any earlier one
+func (s *leaseService) issueLinks(ctx context.Context, tx *Services, signing *model.LeaseSigning, signers []*model.LeaseSigner, actorID *uuid.UUID) ([]signingLink, error) {
+	links := make([]signingLink, 0, len(signers))
+	for _, signer := range signers {
+		token, hash, err := auth.GenerateLinkToken()
+		if err != nil {
+			return nil, apperr.Internal("Failed to generate signing link", err)
+		}
+		expiresAt := time.Now().Add(time.Duration(s.signingCfg.LinkTTL) * time.Hour)
+
+		if err := tx.repos.Signing.SetSignerLink(ctx, signer.ID, hash, expiresAt); err != nil {
+			return nil, apperr.Internal("Failed to save signing link", err)
+		}
+		if err := s.recordSigningEvent(ctx, tx, signing, &signer.ID, actorID, model.SigningEventLinkSent, ClientInfo{},
+			"Link sent to "+phone.Mask(signer.Phone)); err != nil {
+			return nil, err
+		}
+
+		signer.TokenHash = &hash
+		signer.LinkExpiresAt = &expiresAt
+		links = append(links, signingLink{
+			signer:    signer,
+			url:       strings.TrimRight(s.signingCfg.LinkBaseURL, "/") + "/" + token,
+			expiresAt: expiresAt,
+		})
+	}
+	return links, nil
+}
+
+func (s *leaseService) sendLink(ctx context.Context, lease *model.Lease, link signingLink) error {
+	message := fmt.Sprintf("%s, please review and sign the rent agreement for %s: %s (valid until %s)",
+		link.signer.Name, lease.Property.Name, link.url, link.expiresAt.Format("2 Jan 2006 15:04"))
+	return s.sms.SendSMS(ctx, link.signer.Phone, message)
+}
+
+// cancelSigning stops the lease's signing round, if one is in progress, and
+// invalidates its links. Signatures already given are kept in the audit trail.
+func (s *leaseService) cancelSigning(ctx context.Context, tx *Services, leaseID uuid.UUID, actorID *uuid.UUID, reason string) error {
+	signing, err := tx.repos.Signing.GetActive(ctx, leaseID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSigningNotFound) {
+			return nil
+		}
+		return apperr.Internal("Failed to fetch signing", err)
+	}
+
+	now := time.Now()
+	signing.Status = model.SigningStatusCancelled
+	signing.CancelledAt = &now
+	if err := tx.repos.Signing.UpdateStatus(ctx, signing, model.SigningStatusInProgress); err != nil {
+		if errors.Is(err, repository.ErrSigningStatusChanged) {
+			return apperr.Conflict("Signing was completed or cancelled by someone else, please retry", err)
+		}
+		return apperr.Internal("Failed to cancel signing", err)
+	}
+	if err := tx.repos.Signing.ClearLinks(ctx, signing.ID); err != nil {
+		return apperr.Internal("Failed to revoke signing links", err)
+	}
+	return s.recordSigningEvent(ctx, tx, signing, nil, actorID, model.SigningEventCancelled, ClientInfo{}, reason)
+}
+
+// signerByToken resolves a signing link to its signer and in-progress signing
+func (s *leaseService) signerByToken(ctx context.Context, token string) (*model.LeaseSigner, *model.LeaseSigning, error) {
+	invalid := "This signing link is invalid or has already been used"
+
+	found, err := s.signingRepo.GetSignerByTokenHash(ctx, auth.HashLinkToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrSignerNotFound) {
+			return nil, nil, apperr.NotFound(invalid, err)
+		}
+		return nil, nil, apperr.Internal("Failed to fetch signer", err)
+	}
+	if found.LinkExpiresAt == nil || time.Now().After(*found.LinkExpiresAt) {
+		return nil, nil, apperr.Invalid("This signing link has expired; ask the owner to send a new one", nil)
+	}
+
+	signing, err := s.fetchSigning(ctx, found.SigningID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if signing.Status != model.SigningStatusInProgress {
+		return nil, nil, apperr.NotFound(invalid, nil)
+	}
+
+	signer := signing.Signer(found.ID)
+	if signer == nil || !isNextSigner(signing, signer) {
+		return nil, nil, apperr.NotFound(invalid, nil)
+	}
+	return signer, signing, nil
+}
+
+func (s *leaseService) fetchSigning(ctx context.Context, id uuid.UUID) (*model.LeaseSigning, error) {
+	signing, err := s.signingRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSigningNotFound) {
+			return nil, apperr.NotFound("Signing not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch signing", err)
+	}
+	return signing, nil
+}
+
+func (s *leaseService) openSigningDocument(ctx context.Context, signing *model.LeaseSigning) (io.ReadCloser, error) {
+	content, err := s.storage.Open(ctx, signing.DocumentKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, apperr.NotFound("Signing document not found", err)
+		}
+		return nil, apperr.Internal("Failed to open signing document", err)
+	}
+	return content, nil
+}
+
+// recordSigningEvent appends to the signing's audit trail, keeping the loaded signing in step
+func (s *leaseService) recordSigningEvent(ctx context.Context, tx *Services, signing *model.LeaseSigning, signerID, actorID *uuid.UUID, event string, client ClientInfo, detail string) error {
+	e := model.SigningEvent{
+		ID:        uuid.New(),
+		SigningID: signing.ID,
+		SignerID:  signerID,
+		ActorID:   actorID,
+		Event:     event,
+		IPAddress: client.IPAddress,
+		UserAgent: truncate(client.UserAgent, 500),
+		Detail:    detail,
+		CreatedAt: time.Now(),
+	}
+	if err := tx.repos.Signing.CreateEvent(ctx, &e); err != nil {
+		return apperr.Internal("Failed to record signing event", err)
+	}
+	signing.Events = append(signing.Events, e)
+	return nil
+}
+
+func isNextSigner(signing *model.LeaseSigning, signer *model.LeaseSigner) bool {
+	for _, next := range signing.NextSigners() {
+		if next.ID == signer.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// signatureData validates a signature and returns the form it is stored in:
+// the trimmed name for a typed signature, and plain base64 PNG for a drawn one
+func signatureData(signatureType, signature string) (string, error) {
+	if signatureType == model.SignatureTypeTyped {
+		name := strings.TrimSpace(signature)
+		if len(name) < 2 || len(name) > 100 {
+			return "", apperr.Invalid("Type your full name to sign", nil)
+		}
+		return name, nil
+	}
+
+	encoded := strings.TrimPrefix(strings.TrimSpace(signature), "data:image/png;base64,")
+	image, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", apperr.Invalid("Drawn signature must be a base64 PNG image", err)
+	}
+	if len(image) > maxSignatureImageBytes {
+		return "", apperr.Invalid(fmt.Sprintf("Drawn signature must not exceed %d KB", maxSignatureImageBytes>>10), nil)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(image))
+	if err != nil {
+		return "", apperr.Invalid("Drawn signature must be a base64 PNG image", err)
+	}
+	if cfg.Width > maxSignatureImageSide || cfg.Height > maxSignatureImageSide {
+		return "", apperr.Invalid(fmt.Sprintf("Drawn signature must not exceed %d pixels on a side", maxSignatureImageSide), nil)
+	}
+
+	// Re-encoding drops interlacing and ancillary chunks the PDF writer cannot embed
+	decoded, err := png.Decode(bytes.NewReader(image))
+	if err != nil {
+		return "", apperr.Invalid("Drawn signature must be a base64 PNG image", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, decoded); err != nil {
+		return "", apperr.Internal("Failed to process drawn signature", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
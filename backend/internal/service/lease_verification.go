@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"backend/internal/model"
+	"backend/internal/policeform"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/internal/storage"
+	"backend/pkg/apperr"
+	"backend/pkg/india"
+
+	"github.com/google/uuid"
+)
+
+// photoExtensions lists the accepted photograph content types and the file
+// extension used when storing them
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// verificationTransitions lists the statuses a verification can move to from
+// each status. A submitted verification goes back to pending when the police
+// ask for corrections; a verified one is final.
+var verificationTransitions = map[string][]string{
+	model.VerificationStatusPending:   {model.VerificationStatusSubmitted},
+	model.VerificationStatusSubmitted: {model.VerificationStatusPending, model.VerificationStatusSubmitted, model.VerificationStatusVerified},
+}
+
+type SaveVerificationInput struct {
+	FatherName       string
+	DateOfBirth      time.Time
+	PermanentAddress model.Address
+	// PreviousAddress is left empty when the tenant has none
+	PreviousAddress  model.Address
+	IDProofType      string
+	IDProofNumber    string
+	Occupation       string
+	Workplace        string
+	WorkplaceAddress string
+}
+
+type VerificationPhotoInput struct {
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
+
+type SetVerificationStatusInput struct {
+	Status          string
+	PoliceStation   string
+	ReferenceNumber string
+}
+
+// ListVerifications returns the police verifications of the lease's tenants.
+// A tenant sees only their own.
+func (s *leaseService) ListVerifications(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.TenantVerification, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, id)
+	if err != nil {
+		return nil, err
+	}
+
+	verifications, err := s.leaseRepo.ListVerifications(ctx, lease.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch verifications", err)
+	}
+	if policy.Authorize(actor, policy.ActionUpdate, policy.ForLease(lease)) != nil {
+		verifications = slices.DeleteFunc(verifications, func(v model.TenantVerification) bool {
+			return v.UserID != actor.ID
+		})
+	}
+	return verifications, nil
+}
+
+func (s *leaseService) GetVerification(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.TenantVerification, error) {
+	lease, err := s.verificationLease(ctx, actor, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.fetchVerification(ctx, lease.ID, userID)
+}
+
+// SaveVerification records the details the police ask for about a tenant.
+// They can be changed until the verification is submitted. Of an Aadhaar
+// number only the last four digits are kept.
+func (s *leaseService) SaveVerification(ctx context.Context, actor *model.User, id, userID uuid.UUID, input SaveVerificationInput) (*model.TenantVerification, error) {
+	lease, err := s.verificationLease(ctx, actor, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if lease.IsClosed() {
+		return nil, apperr.Invalid("Tenants of a lease that is "+lease.Status+" cannot be verified", nil)
+	}
+
+	verification, err := s.leaseRepo.GetVerification(ctx, lease.ID, userID)
+	if err != nil && !errors.Is(err, repository.ErrVerificationNotFound) {
+		return nil, apperr.Internal("Failed to fetch verification", err)
+	}
+	if verification == nil {
+		verification = &model.TenantVerification{
+			ID:      uuid.New(),
+			LeaseID: lease.ID,
+			UserID:  userID,
+			Status:  model.VerificationStatusPending,
+		}
+	}
+	if verification.Status != model.VerificationStatusPending {
+		return nil, apperr.Invalid("Details cannot be changed once the verification is "+verification.Status, nil)
+	}
+
+	number, err := idProofNumber(input.IDProofType, input.IDProofNumber, verification)
+	if err != nil {
+		return nil, err
+	}
+	if !input.DateOfBirth.Before(today()) {
+		return nil, apperr.Invalid("Date of birth must be in the past", nil)
+	}
+
+	verification.FatherName = strings.TrimSpace(input.FatherName)
+	verification.DateOfBirth = input.DateOfBirth
+	verification.PermanentAddress = input.PermanentAddress
+	verification.PreviousAddress = input.PreviousAddress
+	verification.IDProofType = input.IDProofType
+	verification.IDProofNumber = number
+	verification.Occupation = strings.TrimSpace(input.Occupation)
+	verification.Workplace = strings.TrimSpace(input.Workplace)
+	verification.WorkplaceAddress = strings.TrimSpace(input.WorkplaceAddress)
+	verification.UpdatedBy = actor.ID
+	verification.UpdatedAt = time.Now()
+
+	if err := s.leaseRepo.SaveVerification(ctx, verification); err != nil {
+		return nil, apperr.Internal("Failed to save verification", err)
+	}
+	return s.fetchVerification(ctx, lease.ID, userID)
+}
+
+// idProofNumber checks and normalises an identity document number, masking
+// Aadhaar numbers. An Aadhaar number already saved may be sent back masked.
+func idProofNumber(proofType, number string, current *model.TenantVerification) (string, error) {
+	number = strings.ToUpper(strings.TrimSpace(number))
+
+	switch proofType {
+	case model.IDProofAadhaar:
+		if current.IDProofType == model.IDProofAadhaar && number == current.IDProofNumber {
+			return number, nil
+		}
+		if !india.IsAadhaar(number) {
+			return "", apperr.Invalid("Aadhaar number must be 12 digits with a valid check digit", nil)
+		}
+		return india.MaskAadhaar(number), nil
+	case model.IDProofPAN:
+		if !india.IsPAN(number) {
+			return "", apperr.Invalid("PAN must look like ABCDE1234F", nil)
+		}
+	case model.IDProofPassport:
+		if !india.IsPassportNumber(number) {
+			return "", apperr.Invalid("Passport number must look like A1234567", nil)
+		}
+	case model.IDProofVoterID:
+		if !india.IsVoterID(number) {
+			return "", apperr.Invalid("Voter ID must look like ABC1234567", nil)
+		}
+	}
+	return number, nil
+}
+
+// UploadVerificationPhoto stores the tenant's photograph for the form,
+// replacing any earlier one
+func (s *leaseService) UploadVerificationPhoto(ctx context.Context, actor *model.User, id, userID uuid.UUID, input VerificationPhotoInput) (*model.TenantVerification, error) {
+	lease, err := s.verificationLease(ctx, actor, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, ok := photoExtensions[input.ContentType]
+	if !ok {
+		return nil, apperr.Invalid("Only JPEG and PNG photographs are accepted", nil)
+	}
+	if input.Size > s.maxUpload {
+		return nil, apperr.Invalid(fmt.Sprintf("Photograph must not exceed %d MB", s.maxUpload>>20), nil)
+	}
+
+	verification, err := s.fetchVerification(ctx, lease.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if verification.Status != model.VerificationStatusPending {
+		return nil, apperr.Invalid("The photograph cannot be changed once the verification is "+verification.Status, nil)
+	}
+
+	previous := verification.PhotoStorageKey
+	verification.PhotoContentType = input.ContentType
+	verification.PhotoStorageKey = fmt.Sprintf("leases/%s/verifications/%s%s", lease.ID, uuid.New(), ext)
+	if err := s.storage.Put(ctx, verification.PhotoStorageKey, input.Content); err != nil {
+		return nil, apperr.Internal("Failed to store photograph", err)
+	}
+
+	verification.UpdatedBy = actor.ID
+	verification.UpdatedAt = time.Now()
+	if err := s.leaseRepo.SaveVerification(ctx, verification); err != nil {
+		s.removeFile(ctx, verification.PhotoStorageKey)
+		return nil, apperr.Internal("Failed to save verification", err)
+	}
+	if previous != "" {
+		s.removeFile(ctx, previous)
+	}
+
+	return verification, nil
+}
+
+// OpenVerificationPhoto returns the verification and the tenant's
+// photograph. The caller must close the reader.
+func (s *leaseService) OpenVerificationPhoto(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.TenantVerification, io.ReadCloser, error) {
+	verification, err := s.GetVerification(ctx, actor, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !verification.HasPhoto() {
+		return nil, nil, apperr.NotFound("No photograph has been uploaded", nil)
+	}
+
+	content, err := s.storage.Open(ctx, verification.PhotoStorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, nil, apperr.NotFound("Photograph not found", err)
+		}
+		return nil, nil, apperr.Internal("Failed to open photograph", err)
+	}
+
+	return verification, content, nil
+}
+
+// VerificationForm prints the tenant's verification form in the format of
+// the state the property is in, pre-filled from the lease and the saved details
+func (s *leaseService) VerificationForm(ctx context.Context, actor *model.User, id, userID uuid.UUID) ([]byte, error) {
+	lease, err := s.verificationLease(ctx, actor, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	verification, err := s.fetchVerification(ctx, lease.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, lease.OwnerID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch owner", err)
+	}
+
+	form := &policeform.Form{
+		Lease:        lease,
+		Property:     lease.Property,
+		Owner:        *owner,
+		Verification: verification,
+		GeneratedAt:  verification.UpdatedAt,
+	}
+	for _, tenant := range tenantUsers(lease) {
+		if tenant.ID == userID {
+			form.Tenant = tenant
+		} else {
+			form.CoTenants = append(form.CoTenants, tenant.Name)
+		}
+	}
+
+	if verification.HasPhoto() {
+		content, err := s.storage.Open(ctx, verification.PhotoStorageKey)
+		if err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, apperr.Internal("Failed to open photograph", err)
+		}
+		if content != nil {
+			form.Photo, err = io.ReadAll(content)
+			content.Close()
+			if err != nil {
+				return nil, apperr.Internal("Failed to read photograph", err)
+			}
+		}
+	}
+
+	pdf, err := s.policeForms.Render(form)
+	if err != nil {
+		return nil, apperr.Internal("Failed to render verification form", err)
+	}
+	return pdf, nil
+}
+
+// SetVerificationStatus tracks the verification with the police. Submitting
+// needs the police station it was filed with.
+func (s *leaseService) SetVerificationStatus(ctx context.Context, actor *model.User, id, userID uuid.UUID, input SetVerificationStatusInput) (*model.TenantVerification, error) {
+	lease, err := s.verificationLease(ctx, actor, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Authorize(actor, policy.ActionUpdate, policy.ForLease(lease)); err != nil {
+		return nil, err
+	}
+
+	verification, err := s.fetchVerification(ctx, lease.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(verificationTransitions[verification.Status], input.Status) {
+		return nil, apperr.Invalid("Cannot mark a verification that is "+verification.Status+" as "+input.Status, nil)
+	}
+
+	if station := strings.TrimSpace(input.PoliceStation); station != "" {
+		verification.PoliceStation = station
+	}
+	if reference := strings.TrimSpace(input.ReferenceNumber); reference != "" {
+		verification.ReferenceNumber = reference
+	}
+
+	now := time.Now()
+	switch input.Status {
+	case model.VerificationStatusSubmitted:
+		if verification.PoliceStation == "" {
+			return nil, apperr.Invalid("Name the police station the verification was submitted to", nil)
+		}
+		if verification.SubmittedAt == nil {
+			verification.SubmittedAt = &now
+		}
+	case model.VerificationStatusVerified:
+		verification.VerifiedAt = &now
+	case model.VerificationStatusPending:
+		verification.SubmittedAt = nil
+	}
+	verification.Status = input.Status
+	verification.UpdatedBy = actor.ID
+	verification.UpdatedAt = now
+
+	if err := s.leaseRepo.SaveVerification(ctx, verification); err != nil {
+		return nil, apperr.Internal("Failed to save verification", err)
+	}
+	return verification, nil
+}
+
+// verificationLease returns the lease if userID is one of its tenants and the
+// actor may see that tenant's verification: the tenant themself, or those who
+// manage the lease
+func (s *leaseService) verificationLease(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Lease, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(lease.TenantIDs(), userID) {
+		return nil, apperr.NotFound("User is not a tenant of this lease", nil)
+	}
+	if actor.ID != userID {
+		if err := policy.Authorize(actor, policy.ActionUpdate, policy.ForLease(lease)); err != nil {
+			return nil, err
+		}
+	}
+	return lease, nil
+}
+
+func (s *leaseService) fetchVerification(ctx context.Context, leaseID, userID uuid.UUID) (*model.TenantVerification, error) {
+	verification, err := s.leaseRepo.GetVerification(ctx, leaseID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrVerificationNotFound) {
+			return nil, apperr.NotFound("No verification details have been saved for this tenant", err)
+		}
+		return nil, apperr.Internal("Failed to fetch verification", err)
+	}
+	return verification, nil
+}
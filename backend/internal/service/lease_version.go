@@ -0,0 +1,403 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"backend/internal/clausetext"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/textdiff"
+
+	"github.com/google/uuid"
+)
+
+// ListVersions returns the lease's versions, oldest first, without their clause text
+func (s *leaseService) ListVersions(ctx context.Context, actor *model.User, id uuid.UUID) ([]model.LeaseVersion, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.leaseRepo.ListVersions(ctx, id)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch lease versions", err)
+	}
+	return versions, nil
+}
+
+func (s *leaseService) GetVersion(ctx context.Context, actor *model.User, id uuid.UUID, number int) (*model.LeaseVersion, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+	return s.fetchVersion(ctx, id, number)
+}
+
+// DiffVersions compares two versions of a lease term by term, tenant by
+// tenant and clause by clause, with the wording of each clause as a redline
+func (s *leaseService) DiffVersions(ctx context.Context, actor *model.User, id uuid.UUID, from, to int) (*model.LeaseVersionDiff, error) {
+	if _, err := s.authorized(ctx, actor, policy.ActionRead, id); err != nil {
+		return nil, err
+	}
+
+	older, err := s.fetchVersion(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := s.fetchVersion(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffVersions(older, newer), nil
+}
+
+func (s *leaseService) fetchVersion(ctx context.Context, leaseID uuid.UUID, number int) (*model.LeaseVersion, error) {
+	version, err := s.leaseRepo.GetVersion(ctx, leaseID, number)
+	if err != nil {
+		if errors.Is(err, repository.ErrLeaseVersionNotFound) {
+			return nil, apperr.NotFound(fmt.Sprintf("Version %d not found", number), err)
+		}
+		return nil, apperr.Internal("Failed to fetch lease version", err)
+	}
+	return version, nil
+}
+
+// recordVersion snapshots the lease as it stands within tx and returns the
+// latest version. No version is added when nothing changed since the last one.
+func (s *leaseService) recordVersion(ctx context.Context, tx *Services, leaseID uuid.UUID, actorID *uuid.UUID) (*model.LeaseVersion, error) {
+	lease, err := tx.repos.Lease.GetByID(ctx, leaseID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch lease", err)
+	}
+	clauses, err := tx.repos.Lease.ListClauses(ctx, leaseID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch lease clauses", err)
+	}
+	data, err := s.templateData(ctx, lease)
+	if err != nil {
+		return nil, err
+	}
+
+	version := &model.LeaseVersion{
+		ID:                    uuid.New(),
+		LeaseID:               lease.ID,
+		Number:                1,
+		StartDate:             lease.StartDate,
+		EndDate:               lease.EndDate,
+		TermMonths:            lease.TermMonths,
+		MonthlyRentPaise:      lease.MonthlyRentPaise,
+		SecurityDepositPaise:  lease.SecurityDepositPaise,
+		MaintenancePaise:      lease.MaintenancePaise,
+		RentDueDay:            lease.RentDueDay,
+		RentCycleMonths:       lease.RentCycleMonths,
+		NoticePeriodDays:      lease.NoticePeriodDays,
+		LockInMonths:          lease.LockInMonths,
+		EscalationType:        lease.EscalationType,
+		EscalationBasisPoints: lease.EscalationBasisPoints,
+		EscalationAmountPaise: lease.EscalationAmountPaise,
+		EscalationCompounding: lease.EscalationCompounding,
+		PremisesUse:           lease.PremisesUse,
+		CreatedBy:             actorID,
+		CreatedAt:             time.Now(),
+	}
+	for _, tenant := range tenantUsers(lease) {
+		version.Tenants = append(version.Tenants, model.LeaseVersionTenant{UserID: tenant.ID, Name: tenant.Name})
+	}
+	slices.SortFunc(version.Tenants, func(a, b model.LeaseVersionTenant) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.UserID.String(), b.UserID.String()))
+	})
+	for _, lc := range clauses {
+		version.Clauses = append(version.Clauses, model.LeaseVersionClause{
+			Position:        lc.Position,
+			ClauseID:        lc.ClauseID,
+			ClauseVersionID: lc.ClauseVersionID,
+			ClauseVersion:   lc.ClauseVersion.Version,
+			Category:        lc.Clause.Category,
+			Title:           lc.Clause.Title,
+			Text:            draftText(lc.ClauseVersion.Body, data),
+		})
+	}
+	if version.ClauseTranslations, err = versionTranslations(ctx, tx, clauses, data); err != nil {
+		return nil, err
+	}
+	if version.ContentSHA256, err = versionHash(version); err != nil {
+		return nil, apperr.Internal("Failed to record lease version", err)
+	}
+
+	latest, err := tx.repos.Lease.GetLatestVersion(ctx, leaseID)
+	if err != nil && !errors.Is(err, repository.ErrLeaseVersionNotFound) {
+		return nil, apperr.Internal("Failed to fetch lease version", err)
+	}
+	if latest != nil {
+		if latest.ContentSHA256 == version.ContentSHA256 {
+			return latest, nil
+		}
+		version.Number = latest.Number + 1
+	}
+
+	if err := tx.repos.Lease.CreateVersion(ctx, version); err != nil {
+		return nil, apperr.Internal("Failed to record lease version", err)
+	}
+	return version, nil
+}
+
+// currentVersion returns the version matching the lease as it stands,
+// recording one for leases drafted before versions were kept
+func (s *leaseService) currentVersion(ctx context.Context, leaseID uuid.UUID, actorID *uuid.UUID) (*model.LeaseVersion, error) {
+	var version *model.LeaseVersion
+	err := s.services.Transaction(func(tx *Services) error {
+		var err error
+		version, err = s.recordVersion(ctx, tx, leaseID, actorID)
+		return err
+	})
+	return version, err
+}
+
+// draftText fills in the variables that have a value and leaves the rest as
+// placeholders. Clause bodies are checked when saved, so a body that no longer
+// parses is kept as written.
+func draftText(body string, data *clausetext.Data) string {
+	template, err := clausetext.Parse(body)
+	if err != nil {
+		return body
+	}
+	return template.Draft(data)
+}
+
+// versionTranslations copies the current translations of the lease's clauses,
+// filled in like the English text, in clause order
+func versionTranslations(ctx context.Context, tx *Services, clauses []model.LeaseClause, data *clausetext.Data) ([]model.LeaseVersionClauseTranslation, error) {
+	if len(clauses) == 0 {
+		return nil, nil
+	}
+	versionIDs := make([]uuid.UUID, 0, len(clauses))
+	for _, lc := range clauses {
+		versionIDs = append(versionIDs, lc.ClauseVersionID)
+	}
+	found, err := tx.repos.Clause.GetTranslations(ctx, versionIDs, "")
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch clause translations", err)
+	}
+	byVersion := map[uuid.UUID][]model.ClauseTranslation{}
+	for _, t := range found {
+		byVersion[t.ClauseVersionID] = append(byVersion[t.ClauseVersionID], t)
+	}
+
+	var translations []model.LeaseVersionClauseTranslation
+	for _, lc := range clauses {
+		for _, t := range byVersion[lc.ClauseVersionID] {
+			translations = append(translations, model.LeaseVersionClauseTranslation{
+				Position:      lc.Position,
+				Lang:          t.Lang,
+				TranslationID: t.ID,
+				Title:         t.Title,
+				Text:          draftText(t.Body, data),
+			})
+		}
+	}
+	slices.SortFunc(translations, func(a, b model.LeaseVersionClauseTranslation) int {
+		return cmp.Or(cmp.Compare(a.Position, b.Position), cmp.Compare(a.Lang, b.Lang))
+	})
+	return translations, nil
+}
+
+// versionHash is the SHA-256 of what a version says, leaving out when and by
+// whom it was recorded and which revision a translation was copied from.
+// Versions without translations hash as they did before translations were kept.
+func versionHash(v *model.LeaseVersion) (string, error) {
+	type translation struct {
+		Position    int
+		Lang        string
+		Title, Text string
+	}
+	var translations []translation
+	for _, t := range v.ClauseTranslations {
+		translations = append(translations, translation{t.Position, t.Lang, t.Title, t.Text})
+	}
+	content, err := json.Marshal(struct {
+		Terms        []model.LeaseTermChange
+		Tenants      []model.LeaseVersionTenant
+		Clauses      []model.LeaseVersionClause
+		Translations []translation `json:",omitempty"`
+	}{versionTerms(v), v.Tenants, v.Clauses, translations})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// versionTerms lists the terms of a version by field name, with only From set
+func versionTerms(v *model.LeaseVersion) []model.LeaseTermChange {
+	date := func(t time.Time) string { return t.Format("2006-01-02") }
+	paise := func(n int64) string { return strconv.FormatInt(n, 10) }
+	return []model.LeaseTermChange{
+		{Field: "start_date", From: date(v.StartDate)},
+		{Field: "end_date", From: date(v.EndDate)},
+		{Field: "term_months", From: strconv.Itoa(v.TermMonths)},
+		{Field: "monthly_rent_paise", From: paise(v.MonthlyRentPaise)},
+		{Field: "security_deposit_paise", From: paise(v.SecurityDepositPaise)},
+		{Field: "maintenance_paise", From: paise(v.MaintenancePaise)},
+		{Field: "rent_due_day", From: strconv.Itoa(v.RentDueDay)},
+		{Field: "rent_cycle_months", From: strconv.Itoa(v.RentCycleMonths)},
+		{Field: "notice_period_days", From: strconv.Itoa(v.NoticePeriodDays)},
+		{Field: "lock_in_months", From: strconv.Itoa(v.LockInMonths)},
+		{Field: "premises_use", From: v.PremisesUse},
+		{Field: "escalation_type", From: v.EscalationType},
+		{Field: "escalation_basis_points", From: strconv.Itoa(v.EscalationBasisPoints)},
+		{Field: "escalation_amount_paise", From: paise(v.EscalationAmountPaise)},
+		{Field: "escalation_compounding", From: strconv.FormatBool(v.EscalationCompounding)},
+	}
+}
+
+func diffVersions(from, to *model.LeaseVersion) *model.LeaseVersionDiff {
+	diff := &model.LeaseVersionDiff{
+		LeaseID: to.LeaseID,
+		From:    from.Number,
+		To:      to.Number,
+		Terms:   []model.LeaseTermChange{},
+		Tenants: []model.LeaseTenantChange{},
+		Clauses: []model.LeaseClauseChange{},
+	}
+
+	newTerms := versionTerms(to)
+	for i, term := range versionTerms(from) {
+		if term.From != newTerms[i].From {
+			diff.Terms = append(diff.Terms, model.LeaseTermChange{Field: term.Field, From: term.From, To: newTerms[i].From})
+		}
+	}
+
+	hasTenant := func(tenants []model.LeaseVersionTenant, userID uuid.UUID) bool {
+		return slices.ContainsFunc(tenants, func(t model.LeaseVersionTenant) bool { return t.UserID == userID })
+	}
+	for _, tenant := range from.Tenants {
+		if !hasTenant(to.Tenants, tenant.UserID) {
+			diff.Tenants = append(diff.Tenants, model.LeaseTenantChange{UserID: tenant.UserID, Name: tenant.Name, Change: model.VersionChangeRemoved})
+		}
+	}
+	for _, tenant := range to.Tenants {
+		if !hasTenant(from.Tenants, tenant.UserID) {
+			diff.Tenants = append(diff.Tenants, model.LeaseTenantChange{UserID: tenant.UserID, Name: tenant.Name, Change: model.VersionChangeAdded})
+		}
+	}
+
+	diff.Clauses = diffClauses(from.Clauses, to.Clauses)
+	return diff
+}
+
+// diffClauses walks the clauses of the newer version in order, placing each
+// removed clause just before the first clause that followed it in the older one
+func diffClauses(from, to []model.LeaseVersionClause) []model.LeaseClauseChange {
+	fromIndex := make(map[uuid.UUID]int, len(from))
+	for i, c := range from {
+		fromIndex[c.ClauseID] = i
+	}
+	kept := make(map[uuid.UUID]bool, len(to))
+	for _, c := range to {
+		kept[c.ClauseID] = true
+	}
+
+	inOrder := keptInOrder(from, to)
+
+	changes := make([]model.LeaseClauseChange, 0, len(to))
+	next := 0
+	removedUpTo := func(end int) {
+		for ; next < end; next++ {
+			if c := from[next]; !kept[c.ClauseID] {
+				changes = append(changes, model.LeaseClauseChange{
+					ClauseID:          c.ClauseID,
+					Category:          c.Category,
+					Title:             c.Title,
+					Change:            model.VersionChangeRemoved,
+					FromPosition:      &c.Position,
+					FromClauseVersion: &c.ClauseVersion,
+					Words:             []textdiff.Edit{{Op: textdiff.OpDelete, Text: c.Text}},
+				})
+			}
+		}
+	}
+
+	for _, c := range to {
+		change := model.LeaseClauseChange{
+			ClauseID:        c.ClauseID,
+			Category:        c.Category,
+			Title:           c.Title,
+			ToPosition:      &c.Position,
+			ToClauseVersion: &c.ClauseVersion,
+		}
+
+		i, found := fromIndex[c.ClauseID]
+		if !found {
+			change.Change = model.VersionChangeAdded
+			change.Words = []textdiff.Edit{{Op: textdiff.OpInsert, Text: c.Text}}
+			changes = append(changes, change)
+			continue
+		}
+
+		removedUpTo(i)
+		next = max(next, i+1)
+		old := from[i]
+		change.FromPosition = &old.Position
+		change.FromClauseVersion = &old.ClauseVersion
+		switch {
+		case old.Text != c.Text:
+			change.Change = model.VersionChangeChanged
+			change.Words = textdiff.Words(old.Text, c.Text)
+		case !inOrder[c.ClauseID]:
+			change.Change = model.VersionChangeMoved
+			change.Words = []textdiff.Edit{{Op: textdiff.OpEqual, Text: c.Text}}
+		default:
+			change.Change = model.VersionChangeUnchanged
+			change.Words = []textdiff.Edit{{Op: textdiff.OpEqual, Text: c.Text}}
+		}
+		changes = append(changes, change)
+	}
+	removedUpTo(len(from))
+
+	return changes
+}
+
+// keptInOrder finds the largest set of clauses in both versions that kept
+// their order relative to each other. The other clauses in both were moved;
+// clauses shifting because others were added or removed were not.
+func keptInOrder(from, to []model.LeaseVersionClause) map[uuid.UUID]bool {
+	// lcs[i][j] is the length of the longest common subsequence of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i].ClauseID == to[j].ClauseID {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	kept := make(map[uuid.UUID]bool, lcs[0][0])
+	for i, j := 0, 0; i < len(from) && j < len(to); {
+		switch {
+		case from[i].ClauseID == to[j].ClauseID:
+			kept[from[i].ClauseID] = true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return kept
+}
@@ -0,0 +1,1063 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/internal/clausetext"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/inr"
+
+	"github.com/google/uuid"
+)
+
+type LedgerService interface {
+	LeaseBalance(ctx context.Context, actor *model.User, leaseID uuid.UUID) (*model.LedgerBalance, error)
+	LeaseStatement(ctx context.Context, actor *model.User, leaseID uuid.UUID, period StatementPeriod) (*model.LedgerStatement, error)
+	TenantBalance(ctx context.Context, actor *model.User, userID uuid.UUID) (*model.TenantLedgerBalance, error)
+	TenantStatement(ctx context.Context, actor *model.User, userID uuid.UUID, period StatementPeriod) (*model.LedgerStatement, error)
+	GetEntry(ctx context.Context, actor *model.User, leaseID, entryID uuid.UUID) (*model.JournalEntry, error)
+	Post(ctx context.Context, actor *model.User, leaseID uuid.UUID, input PostLedgerEntryInput) (*model.JournalEntry, error)
+	Reverse(ctx context.Context, actor *model.User, leaseID, entryID uuid.UUID, reason string) (*model.JournalEntry, error)
+	SyncLease(ctx context.Context, lease *model.Lease) (int, error)
+	PostSettlement(ctx context.Context, lease *model.Lease, input PostLedgerEntryInput, createdBy *uuid.UUID) (*model.JournalEntry, error)
+	PostDueCharges(ctx context.Context) (int, error)
+}
+
+type PostLedgerEntryInput struct {
+	Kind        string
+	AmountPaise int64
+	OccurredOn  time.Time // defaults to today
+	Description string
+	Reference   string
+	// Source is where a settled payment or refund came from; entries posted
+	// by hand have none
+	Source string
+	// TenantID is the tenant the money is from or to, or who is charged;
+	// without one the entry is shared among the lease's tenants
+	TenantID *uuid.UUID
+}
+
+// StatementPeriod limits a statement to entries on or after From and on or
+// before To; either may be nil
+type StatementPeriod struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// entryAccounts gives the account debited and the account credited by each
+// kind of entry recorded by hand
+var entryAccounts = map[string][2]string{
+	model.JournalPayment:           {model.LedgerAccountCash, model.LedgerAccountReceivable},
+	model.JournalLateFee:           {model.LedgerAccountReceivable, model.LedgerAccountLateFeeIncome},
+	model.JournalMaintenanceCharge: {model.LedgerAccountReceivable, model.LedgerAccountMaintenanceIncome},
+	model.JournalRefund:            {model.LedgerAccountReceivable, model.LedgerAccountCash},
+	model.JournalDepositRefund:     {model.LedgerAccountDeposit, model.LedgerAccountCash},
+	model.JournalDepositAdjustment: {model.LedgerAccountDeposit, model.LedgerAccountReceivable},
+}
+
+// entryDescriptions are used when an entry recorded by hand has no description
+var entryDescriptions = map[string]string{
+	model.JournalPayment:           "Payment received",
+	model.JournalLateFee:           "Late fee",
+	model.JournalMaintenanceCharge: "Maintenance charge",
+	model.JournalRefund:            "Refund to tenants",
+	model.JournalDepositRefund:     "Security deposit refunded",
+	model.JournalDepositAdjustment: "Security deposit applied to dues",
+}
+
+// creditNormal lists the account kinds whose balance is normally a credit
+var creditNormal = []string{
+	model.LedgerAccountDeposit,
+	model.LedgerAccountRentIncome,
+	model.LedgerAccountMaintenanceIncome,
+	model.LedgerAccountLateFeeIncome,
+}
+
+type ledgerService struct {
+	services   *Services
+	ledgerRepo repository.LedgerRepository
+	leaseRepo  repository.LeaseRepository
+	userRepo   repository.UserRepository
+}
+
+func NewLedgerService(
+	services *Services,
+	ledgerRepo repository.LedgerRepository,
+	leaseRepo repository.LeaseRepository,
+	userRepo repository.UserRepository,
+) LedgerService {
+	return &ledgerService{
+		services:   services,
+		ledgerRepo: ledgerRepo,
+		leaseRepo:  leaseRepo,
+		userRepo:   userRepo,
+	}
+}
+
+func (s *ledgerService) LeaseBalance(ctx context.Context, actor *model.User, leaseID uuid.UUID) (*model.LedgerBalance, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.ledgerRepo.ListEntries(ctx, []uuid.UUID{lease.ID})
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch ledger", err)
+	}
+	return ledgerOf(lease.ID, entries).balance(lease.ID), nil
+}
+
+func (s *ledgerService) LeaseStatement(ctx context.Context, actor *model.User, leaseID uuid.UUID, period StatementPeriod) (*model.LedgerStatement, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.ledgerRepo.ListEntries(ctx, []uuid.UUID{lease.ID})
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch ledger", err)
+	}
+	return statement([]uuid.UUID{lease.ID}, nil, entries, period), nil
+}
+
+func (s *ledgerService) TenantBalance(ctx context.Context, actor *model.User, userID uuid.UUID) (*model.TenantLedgerBalance, error) {
+	leaseIDs, err := s.tenantLeases(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.ledgerRepo.ListEntries(ctx, leaseIDs)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch ledger", err)
+	}
+
+	balance := &model.TenantLedgerBalance{UserID: userID, Leases: make([]model.LedgerTenantBalance, 0, len(leaseIDs))}
+	for _, id := range leaseIDs {
+		lease := ledgerOf(id, entries).tenantBalance(id, userID)
+		balance.OutstandingPaise += lease.OutstandingPaise
+		balance.DepositHeldPaise += lease.DepositHeldPaise
+		balance.Leases = append(balance.Leases, lease)
+	}
+	return balance, nil
+}
+
+func (s *ledgerService) TenantStatement(ctx context.Context, actor *model.User, userID uuid.UUID, period StatementPeriod) (*model.LedgerStatement, error) {
+	leaseIDs, err := s.tenantLeases(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.ledgerRepo.ListEntries(ctx, leaseIDs)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch ledger", err)
+	}
+	return statement(leaseIDs, &userID, entries, period), nil
+}
+
+func (s *ledgerService) GetEntry(ctx context.Context, actor *model.User, leaseID, entryID uuid.UUID) (*model.JournalEntry, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.ledgerRepo.GetEntry(ctx, lease.ID, entryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrJournalEntryNotFound) {
+			return nil, apperr.NotFound("Journal entry not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch journal entry", err)
+	}
+	return entry, nil
+}
+
+// Post records money received or paid out, or a charge, on a lease
+func (s *ledgerService) Post(ctx context.Context, actor *model.User, leaseID uuid.UUID, input PostLedgerEntryInput) (*model.JournalEntry, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionUpdate, leaseID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasLedger(lease) {
+		return nil, apperr.Invalid("Money can be recorded once the lease has been activated", nil)
+	}
+	accounts, ok := entryAccounts[input.Kind]
+	if !ok {
+		return nil, apperr.Invalid("Unknown entry kind", nil)
+	}
+	if input.AmountPaise <= 0 {
+		return nil, apperr.Invalid("Amount must be positive", nil)
+	}
+	if input.TenantID != nil && !slices.Contains(lease.TenantIDs(), *input.TenantID) {
+		return nil, apperr.Invalid("The tenant is not on this lease", nil)
+	}
+
+	occurredOn := input.OccurredOn
+	if occurredOn.IsZero() {
+		occurredOn = today()
+	}
+	if occurredOn.After(today()) {
+		return nil, apperr.Invalid("Entries cannot be dated in the future", nil)
+	}
+	description := input.Description
+	if description == "" {
+		description = entryDescriptions[input.Kind]
+	}
+
+	entry := &model.JournalEntry{
+		LeaseID:     lease.ID,
+		Kind:        input.Kind,
+		OccurredOn:  occurredOn,
+		Description: description,
+		Reference:   input.Reference,
+		CreatedBy:   &actor.ID,
+	}
+	err = s.services.Transaction(func(tx *Services) error {
+		ids, err := s.accounts(ctx, tx, lease)
+		if err != nil {
+			return err
+		}
+		entries, err := tx.repos.Ledger.ListEntries(ctx, []uuid.UUID{lease.ID})
+		if err != nil {
+			return apperr.Internal("Failed to fetch ledger", err)
+		}
+
+		ledger := ledgerOf(lease.ID, entries)
+		held, credit := -ledger.balances[model.LedgerAccountDeposit], -ledger.balances[model.LedgerAccountReceivable]
+		if input.TenantID != nil {
+			tenant := ledger.tenants[*input.TenantID]
+			held, credit = tenant.held, -tenant.owed
+		}
+		switch input.Kind {
+		case model.JournalDepositRefund, model.JournalDepositAdjustment:
+			if input.AmountPaise > held {
+				return apperr.Invalid("Amount exceeds the security deposit held: "+inr.FormatWithSymbol(held), nil)
+			}
+		case model.JournalRefund:
+			if input.AmountPaise > credit {
+				return apperr.Invalid("Refund exceeds what the tenants have paid in advance: "+inr.FormatWithSymbol(max(credit, 0)), nil)
+			}
+		}
+
+		if err := s.post(ctx, tx, entry, ids.entryLegs(accounts, input.AmountPaise, input.TenantID, ledger.shareWeight(input.Kind))...); err != nil {
+			return err
+		}
+		return s.allocate(ctx, tx, lease)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Reverse undoes an entry recorded by hand by posting its mirror image
+func (s *ledgerService) Reverse(ctx context.Context, actor *model.User, leaseID, entryID uuid.UUID, reason string) (*model.JournalEntry, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionUpdate, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reversal *model.JournalEntry
+	err = s.services.Transaction(func(tx *Services) error {
+		if _, err := s.accounts(ctx, tx, lease); err != nil {
+			return err
+		}
+		original, err := tx.repos.Ledger.GetEntry(ctx, lease.ID, entryID)
+		if err != nil {
+			if errors.Is(err, repository.ErrJournalEntryNotFound) {
+				return apperr.NotFound("Journal entry not found", err)
+			}
+			return apperr.Internal("Failed to fetch journal entry", err)
+		}
+		if _, ok := entryAccounts[original.Kind]; !ok {
+			return apperr.Invalid("Rent and deposit charges follow the lease's terms and cannot be reversed", nil)
+		}
+		if original.ReversesID != nil {
+			return apperr.Invalid("A reversal cannot itself be reversed", nil)
+		}
+		if _, err := tx.repos.Ledger.GetReversal(ctx, original.ID); err == nil {
+			return apperr.Conflict("Journal entry has already been reversed", nil)
+		} else if !errors.Is(err, repository.ErrJournalEntryNotFound) {
+			return apperr.Internal("Failed to fetch journal entry", err)
+		}
+
+		legs := make([]leg, 0, len(original.Postings))
+		for _, p := range original.Postings {
+			legs = append(legs, leg{p.AccountID, p.CreditPaise - p.DebitPaise})
+		}
+		reversal = &model.JournalEntry{
+			LeaseID:     lease.ID,
+			Kind:        original.Kind,
+			OccurredOn:  today(),
+			Description: "Reversal: " + reason,
+			Reference:   original.Reference,
+			Source:      original.Source,
+			ReversesID:  &original.ID,
+			CreatedBy:   &actor.ID,
+		}
+		if err := s.post(ctx, tx, reversal, legs...); err != nil {
+			return err
+		}
+		if err := tx.repos.Ledger.MarkReversed(ctx, original.ID, reversal.ID); err != nil {
+			if errors.Is(err, repository.ErrJournalEntryReversed) {
+				return apperr.Conflict("Journal entry has already been reversed", err)
+			}
+			return apperr.Internal("Failed to reverse journal entry", err)
+		}
+		return s.allocate(ctx, tx, lease)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reversal, nil
+}
+
+// SyncLease posts what the lease's terms have made due since it was last
+// synced: the security deposit, and the rent of each due whose due date has
+// come, adjusted when a due changed after it was charged. It then spreads
+// what the tenants have paid over their dues. It runs within the transaction
+// of the services it belongs to and returns how many entries it posted.
+func (s *ledgerService) SyncLease(ctx context.Context, lease *model.Lease) (int, error) {
+	tx := s.services
+	if !hasLedger(lease) {
+		return 0, nil
+	}
+
+	ids, err := s.accounts(ctx, tx, lease)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := tx.repos.Ledger.ListEntries(ctx, []uuid.UUID{lease.ID})
+	if err != nil {
+		return 0, apperr.Internal("Failed to fetch ledger", err)
+	}
+	ledger := ledgerOf(lease.ID, entries)
+
+	posted := 0
+	if !ledger.hasDeposit {
+		n, err := s.postDeposit(ctx, tx, lease, ids)
+		if err != nil {
+			return 0, err
+		}
+		posted += n
+	}
+
+	dues, err := tx.repos.Lease.ListDues(ctx, lease.ID)
+	if err != nil {
+		return 0, apperr.Internal("Failed to fetch rent dues", err)
+	}
+	now := today()
+	for _, due := range dues {
+		if due.DueDate.After(now) {
+			continue
+		}
+		charged := ledger.charged[due.ID]
+		rent, maintenance := due.RentPaise-charged.rent, due.MaintenancePaise-charged.maintenance
+		if rent == 0 && maintenance == 0 {
+			continue
+		}
+
+		period := due.PeriodStart.Format(clausetext.DateLayout) + " to " + due.PeriodEnd.Format(clausetext.DateLayout)
+		entry := &model.JournalEntry{
+			LeaseID:     lease.ID,
+			Kind:        model.JournalRentCharge,
+			OccurredOn:  due.DueDate,
+			Description: "Rent for " + period,
+			RentDueID:   &due.ID,
+		}
+		if charged.rent != 0 || charged.maintenance != 0 {
+			entry.Kind = model.JournalRentAdjustment
+			entry.Description = "Rent for " + period + " revised"
+			entry.OccurredOn = now
+		}
+		legs := append(ids.tenantLegs(model.LedgerAccountReceivable, rent+maintenance, nil, nil),
+			leg{ids.owner[model.LedgerAccountRentIncome], -rent},
+			leg{ids.owner[model.LedgerAccountMaintenanceIncome], -maintenance},
+		)
+		if err := s.post(ctx, tx, entry, legs...); err != nil {
+			return 0, err
+		}
+		posted++
+	}
+
+	if posted > 0 {
+		if entries, err = tx.repos.Ledger.ListEntries(ctx, []uuid.UUID{lease.ID}); err != nil {
+			return 0, apperr.Internal("Failed to fetch ledger", err)
+		}
+	}
+	return posted, s.allocateDues(ctx, tx, lease, ledgerOf(lease.ID, entries), dues)
+}
+
+// PostSettlement records a payment or refund that has already been settled
+// outside the ledger, such as through the payment gateway, and spreads what
+// the tenants have paid over their dues. A refund is not limited to what the
+// tenants have paid in advance, since the money has already gone back. A
+// payer who is not a tenant of the lease leaves the entry shared among the
+// tenants. It runs within the transaction of the services it belongs to.
+func (s *ledgerService) PostSettlement(ctx context.Context, lease *model.Lease, input PostLedgerEntryInput, createdBy *uuid.UUID) (*model.JournalEntry, error) {
+	tx := s.services
+	if !hasLedger(lease) {
+		return nil, apperr.Invalid("Money can be recorded once the lease has been activated", nil)
+	}
+	if input.Kind != model.JournalPayment && input.Kind != model.JournalRefund {
+		return nil, apperr.Invalid("Only payments and refunds can be settled", nil)
+	}
+	if input.AmountPaise <= 0 {
+		return nil, apperr.Invalid("Amount must be positive", nil)
+	}
+
+	occurredOn := input.OccurredOn
+	if occurredOn.IsZero() || occurredOn.After(today()) {
+		occurredOn = today()
+	}
+	description := input.Description
+	if description == "" {
+		description = entryDescriptions[input.Kind]
+	}
+
+	ids, err := s.accounts(ctx, tx, lease)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := tx.repos.Ledger.ListEntries(ctx, []uuid.UUID{lease.ID})
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch ledger", err)
+	}
+	tenant := input.TenantID
+	if tenant != nil && !slices.Contains(lease.TenantIDs(), *tenant) {
+		tenant = nil
+	}
+	accounts := entryAccounts[input.Kind]
+	entry := &model.JournalEntry{
+		LeaseID:     lease.ID,
+		Kind:        input.Kind,
+		OccurredOn:  occurredOn,
+		Description: description,
+		Reference:   input.Reference,
+		Source:      input.Source,
+		CreatedBy:   createdBy,
+	}
+	legs := ids.entryLegs(accounts, input.AmountPaise, tenant, ledgerOf(lease.ID, entries).shareWeight(input.Kind))
+	if err := s.post(ctx, tx, entry, legs...); err != nil {
+		return nil, err
+	}
+	return entry, s.allocate(ctx, tx, lease)
+}
+
+// PostDueCharges syncs the ledger of every running lease, so rent is charged
+// as it falls due. It returns how many entries were posted.
+func (s *ledgerService) PostDueCharges(ctx context.Context) (int, error) {
+	leases, err := s.leaseRepo.ListByStatus(ctx, model.LeaseStatusActive, model.LeaseStatusNoticePeriod)
+	if err != nil {
+		return 0, fmt.Errorf("list running leases: %w", err)
+	}
+
+	var (
+		posted int
+		errs   []error
+	)
+	for _, lease := range leases {
+		if ctx.Err() != nil {
+			break
+		}
+		err := s.services.Transaction(func(tx *Services) error {
+			n, err := tx.Ledger.SyncLease(ctx, &lease)
+			posted += n
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lease %s: %w", lease.ID, err))
+		}
+	}
+	return posted, errors.Join(errs...)
+}
+
+// postDeposit charges the tenants the security deposit, in equal shares. A
+// renewal takes over what each tenant has on deposit on the lease it renews,
+// and only what it asks for beyond that is charged.
+func (s *ledgerService) postDeposit(ctx context.Context, tx *Services, lease *model.Lease, ids *leaseAccounts) (int, error) {
+	posted := 0
+	charge := lease.SecurityDepositPaise
+
+	if lease.RenewalOfID != nil {
+		previous, err := s.heldDepositOf(ctx, tx, *lease.RenewalOfID)
+		if err != nil {
+			return 0, err
+		}
+		if carried := min(previous.total(), charge); carried > 0 {
+			// A tenant who has left keeps their deposit on the renewal until it is refunded
+			if ids, err = s.accounts(ctx, tx, lease, previous.tenants...); err != nil {
+				return 0, err
+			}
+			weights := make([]int64, len(previous.tenants))
+			for i, tenantID := range previous.tenants {
+				weights[i] = previous.held[tenantID]
+			}
+			var legs []leg
+			for i, share := range shares(carried, weights) {
+				tenantID := previous.tenants[i]
+				legs = append(legs,
+					leg{previous.accounts[tenantID], share},
+					leg{ids.tenants[tenantID][model.LedgerAccountDeposit], -share},
+				)
+			}
+			if err := s.post(ctx, tx, &model.JournalEntry{
+				LeaseID:     lease.ID,
+				Kind:        model.JournalDepositTransfer,
+				OccurredOn:  lease.StartDate,
+				Description: "Security deposit carried forward from the previous lease",
+			}, legs...); err != nil {
+				return 0, err
+			}
+			posted++
+			charge -= carried
+		}
+	}
+
+	if charge > 0 {
+		legs := append(ids.tenantLegs(model.LedgerAccountReceivable, charge, nil, nil),
+			ids.tenantLegs(model.LedgerAccountDeposit, -charge, nil, nil)...)
+		if err := s.post(ctx, tx, &model.JournalEntry{
+			LeaseID:     lease.ID,
+			Kind:        model.JournalDepositCharge,
+			OccurredOn:  lease.StartDate,
+			Description: "Security deposit",
+		}, legs...); err != nil {
+			return 0, err
+		}
+		posted++
+	}
+	return posted, nil
+}
+
+// heldDeposit is what each tenant has on deposit on a lease, and their
+// deposit accounts
+type heldDeposit struct {
+	tenants  []uuid.UUID // those holding a deposit, first to pay first
+	accounts map[uuid.UUID]uuid.UUID
+	held     map[uuid.UUID]int64
+}
+
+func (d *heldDeposit) total() int64 {
+	var total int64
+	for _, held := range d.held {
+		total += held
+	}
+	return total
+}
+
+// heldDepositOf returns the deposit held on a lease, locking its accounts
+func (s *ledgerService) heldDepositOf(ctx context.Context, tx *Services, leaseID uuid.UUID) (*heldDeposit, error) {
+	lease, err := tx.repos.Lease.GetByID(ctx, leaseID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch renewed lease", err)
+	}
+	ids, err := s.accounts(ctx, tx, lease)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := tx.repos.Ledger.ListEntries(ctx, []uuid.UUID{leaseID})
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch ledger", err)
+	}
+
+	ledger := ledgerOf(leaseID, entries)
+	deposit := &heldDeposit{accounts: map[uuid.UUID]uuid.UUID{}, held: map[uuid.UUID]int64{}}
+	for _, tenantID := range ledger.tenantOrder {
+		if held := ledger.tenants[tenantID].held; held > 0 {
+			deposit.tenants = append(deposit.tenants, tenantID)
+			deposit.accounts[tenantID] = ids.tenants[tenantID][model.LedgerAccountDeposit]
+			deposit.held[tenantID] = held
+		}
+	}
+	return deposit, nil
+}
+
+// allocate spreads what the tenants have paid over their dues within tx
+func (s *ledgerService) allocate(ctx context.Context, tx *Services, lease *model.Lease) error {
+	entries, err := tx.repos.Ledger.ListEntries(ctx, []uuid.UUID{lease.ID})
+	if err != nil {
+		return apperr.Internal("Failed to fetch ledger", err)
+	}
+	dues, err := tx.repos.Lease.ListDues(ctx, lease.ID)
+	if err != nil {
+		return apperr.Internal("Failed to fetch rent dues", err)
+	}
+	return s.allocateDues(ctx, tx, lease, ledgerOf(lease.ID, entries), dues)
+}
+
+// allocateDues records on each rent due how much of it is paid within tx
+func (s *ledgerService) allocateDues(ctx context.Context, tx *Services, lease *model.Lease, ledger *leaseLedger, dues []model.RentDue) error {
+	changed, _ := ledger.allocate(dues)
+	if err := tx.repos.Lease.SaveDues(ctx, changed); err != nil {
+		return apperr.Internal("Failed to update rent dues", err)
+	}
+	return nil
+}
+
+// leg is one side of an entry: a debit when paise is positive, a credit when negative
+type leg struct {
+	account uuid.UUID
+	paise   int64
+}
+
+// post records the entry with a posting for each non-zero leg within tx.
+// The legs must balance.
+func (s *ledgerService) post(ctx context.Context, tx *Services, entry *model.JournalEntry, legs ...leg) error {
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+	entry.Postings = nil
+
+	var sum int64
+	for _, l := range legs {
+		if l.paise == 0 {
+			continue
+		}
+		posting := model.LedgerPosting{ID: uuid.New(), EntryID: entry.ID, AccountID: l.account}
+		if l.paise > 0 {
+			posting.DebitPaise = l.paise
+		} else {
+			posting.CreditPaise = -l.paise
+		}
+		entry.Postings = append(entry.Postings, posting)
+		sum += l.paise
+	}
+	if sum != 0 || len(entry.Postings) < 2 {
+		return apperr.Internal("Failed to post journal entry", fmt.Errorf("unbalanced %s entry: %d", entry.Kind, sum))
+	}
+
+	if err := tx.repos.Ledger.CreateEntry(ctx, entry); err != nil {
+		if errors.Is(err, repository.ErrDuplicatePaymentReference) {
+			return apperr.Conflict("A payment with reference "+entry.Reference+" is already recorded on this lease", err)
+		}
+		return apperr.Internal("Failed to post journal entry", err)
+	}
+	return nil
+}
+
+// leaseAccounts are the IDs of a lease's accounts
+type leaseAccounts struct {
+	owner   map[string]uuid.UUID               // the owner's, by kind
+	tenants map[uuid.UUID]map[string]uuid.UUID // each tenant's, by tenant and kind
+	// sharing lists the lease's tenants, first to join first; an entry naming
+	// no tenant is shared among them
+	sharing []uuid.UUID
+}
+
+// entryLegs debits the first account kind and credits the second with
+// amount. See tenantLegs for how a tenant account kind is posted to.
+func (a *leaseAccounts) entryLegs(kinds [2]string, amount int64, tenant *uuid.UUID, weight func(uuid.UUID) int64) []leg {
+	var legs []leg
+	for i, kind := range kinds {
+		paise := amount
+		if i == 1 {
+			paise = -amount
+		}
+		if slices.Contains(model.TenantAccountKinds, kind) {
+			legs = append(legs, a.tenantLegs(kind, paise, tenant, weight)...)
+		} else {
+			legs = append(legs, leg{a.owner[kind], paise})
+		}
+	}
+	return legs
+}
+
+// tenantLegs posts paise to the tenants' accounts of kind: all of it to
+// tenant when one is named, and otherwise shared among the lease's tenants
+// by weight, or equally when weight is nil
+func (a *leaseAccounts) tenantLegs(kind string, paise int64, tenant *uuid.UUID, weight func(uuid.UUID) int64) []leg {
+	parties := a.sharing
+	if tenant != nil {
+		parties = []uuid.UUID{*tenant}
+	}
+	weights := make([]int64, len(parties))
+	if weight != nil {
+		for i, tenantID := range parties {
+			weights[i] = weight(tenantID)
+		}
+	}
+	legs := make([]leg, 0, len(parties))
+	for i, share := range shares(paise, weights) {
+		legs = append(legs, leg{a.tenants[parties[i]][kind], share})
+	}
+	return legs
+}
+
+// shares splits amount over the weights: as much of it as the weights add up
+// to in proportion to them, and the rest equally. Paise left over by rounding
+// go to the first shares. A negative amount is split as its opposite.
+func shares(amount int64, weights []int64) []int64 {
+	out := make([]int64, len(weights))
+	if len(weights) == 0 {
+		return out
+	}
+	sign := int64(1)
+	if amount < 0 {
+		sign, amount = -1, -amount
+	}
+
+	var total int64
+	for _, w := range weights {
+		total += max(w, 0)
+	}
+	weighted := min(amount, total)
+	given := int64(0)
+	for i, w := range weights {
+		if w <= 0 || weighted == 0 {
+			continue
+		}
+		// amount and weight may both run to crores, so the product needs 128 bits
+		hi, lo := bits.Mul64(uint64(weighted), uint64(w))
+		share, _ := bits.Div64(hi, lo, uint64(total))
+		out[i] = int64(share)
+		given += out[i]
+	}
+	for i := 0; given < weighted; i++ {
+		if weights[i] > 0 {
+			out[i]++
+			given++
+		}
+	}
+
+	rest := amount - weighted
+	n := int64(len(weights))
+	for i := range out {
+		out[i] += rest / n
+		if int64(i) < rest%n {
+			out[i]++
+		}
+		out[i] *= sign
+	}
+	return out
+}
+
+// accounts opens the accounts of the lease's owner and tenants, and of any
+// other tenants given, if needed and returns their IDs
+func (s *ledgerService) accounts(ctx context.Context, tx *Services, lease *model.Lease, others ...uuid.UUID) (*leaseAccounts, error) {
+	sharing := leaseTenants(lease)
+	parties := slices.Clone(sharing)
+	for _, tenantID := range others {
+		if !slices.Contains(parties, tenantID) {
+			parties = append(parties, tenantID)
+		}
+	}
+	accounts, err := tx.repos.Ledger.EnsureAccounts(ctx, lease.ID, lease.OwnerID, parties)
+	if err != nil {
+		return nil, apperr.Internal("Failed to open ledger accounts", err)
+	}
+
+	ids := &leaseAccounts{owner: map[string]uuid.UUID{}, tenants: map[uuid.UUID]map[string]uuid.UUID{}, sharing: sharing}
+	for _, account := range accounts {
+		if !account.IsTenantAccount() {
+			ids.owner[account.Kind] = account.ID
+			continue
+		}
+		if ids.tenants[account.PartyID] == nil {
+			ids.tenants[account.PartyID] = map[string]uuid.UUID{}
+		}
+		ids.tenants[account.PartyID][account.Kind] = account.ID
+	}
+	return ids, nil
+}
+
+// leaseTenants returns the IDs of the lease's tenants, first to join first
+func leaseTenants(lease *model.Lease) []uuid.UUID {
+	tenants := slices.Clone(lease.Tenants)
+	slices.SortStableFunc(tenants, func(a, b model.LeaseTenant) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UserID.String(), b.UserID.String())
+	})
+	ids := make([]uuid.UUID, 0, len(tenants))
+	for _, t := range tenants {
+		ids = append(ids, t.UserID)
+	}
+	return ids
+}
+
+func (s *ledgerService) authorized(ctx context.Context, actor *model.User, action policy.Action, leaseID uuid.UUID) (*model.Lease, error) {
+	lease, err := s.leaseRepo.GetByID(ctx, leaseID)
+	if err != nil {
+		if errors.Is(err, repository.ErrLeaseNotFound) {
+			return nil, apperr.NotFound("Lease not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch lease", err)
+	}
+	if err := policy.Authorize(actor, action, policy.ForLease(lease)); err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// tenantLeases returns the IDs of the leases the user is a tenant on that the
+// actor may read
+func (s *ledgerService) tenantLeases(ctx context.Context, actor *model.User, userID uuid.UUID) ([]uuid.UUID, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, apperr.NotFound("User not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch user", err)
+	}
+
+	leases, err := s.leaseRepo.ListByTenant(ctx, userID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch leases", err)
+	}
+	ids := make([]uuid.UUID, 0, len(leases))
+	for i := range leases {
+		if policy.Authorize(actor, policy.ActionRead, policy.ForLease(&leases[i])) == nil {
+			ids = append(ids, leases[i].ID)
+		}
+	}
+	if len(ids) == 0 && actor.ID != userID && !actor.IsAdmin() {
+		return nil, apperr.Forbidden("You do not have permission to read this tenant's ledger", nil)
+	}
+	return ids, nil
+}
+
+// hasLedger reports whether money moves on the lease, which it does from activation
+func hasLedger(lease *model.Lease) bool {
+	switch lease.Status {
+	case model.LeaseStatusDraft, model.LeaseStatusPendingSignatures, model.LeaseStatusSigned:
+		return false
+	}
+	return true
+}
+
+// dueCharge is what has been charged for a rent due
+type dueCharge struct {
+	rent        int64
+	maintenance int64
+}
+
+// otherCharge is a charge to the tenants other than rent, net of its reversal
+type otherCharge struct {
+	date   time.Time
+	amount int64
+}
+
+// tenantBalance is what a tenant owes and has on deposit
+type tenantBalance struct {
+	owed int64
+	held int64
+}
+
+// leaseLedger is a lease's ledger worked out from its entries
+type leaseLedger struct {
+	balances    map[string]int64 // debit less credit, by account kind
+	tenants     map[uuid.UUID]tenantBalance
+	tenantOrder []uuid.UUID // tenants in the order their accounts were first posted to
+	charged     map[uuid.UUID]dueCharge
+	charges     []*otherCharge
+	paid        int64 // paid towards the receivable, net of refunds
+	hasDeposit  bool
+}
+
+func ledgerOf(leaseID uuid.UUID, entries []model.JournalEntry) *leaseLedger {
+	ledger := &leaseLedger{balances: map[string]int64{}, tenants: map[uuid.UUID]tenantBalance{}, charged: map[uuid.UUID]dueCharge{}}
+	charges := map[uuid.UUID]*otherCharge{}
+	for _, entry := range entries {
+		if entry.LeaseID == leaseID && (entry.Kind == model.JournalDepositCharge || entry.Kind == model.JournalDepositTransfer) {
+			ledger.hasDeposit = true
+		}
+		origin := entry.ID
+		if entry.ReversesID != nil {
+			origin = *entry.ReversesID
+		}
+
+		for _, p := range entry.Postings {
+			if p.Account == nil || p.Account.LeaseID != leaseID {
+				continue
+			}
+			delta := p.DebitPaise - p.CreditPaise
+			ledger.balances[p.Account.Kind] += delta
+			if p.Account.IsTenantAccount() {
+				tenant, seen := ledger.tenants[p.Account.PartyID]
+				if !seen {
+					ledger.tenantOrder = append(ledger.tenantOrder, p.Account.PartyID)
+				}
+				if p.Account.Kind == model.LedgerAccountReceivable {
+					tenant.owed += delta
+				} else {
+					tenant.held -= delta
+				}
+				ledger.tenants[p.Account.PartyID] = tenant
+			}
+
+			switch p.Account.Kind {
+			case model.LedgerAccountReceivable:
+				switch entry.Kind {
+				case model.JournalPayment, model.JournalDepositAdjustment, model.JournalRefund:
+					ledger.paid -= delta
+				case model.JournalDepositCharge, model.JournalLateFee, model.JournalMaintenanceCharge:
+					charge, ok := charges[origin]
+					if !ok {
+						charge = &otherCharge{date: entry.OccurredOn}
+						charges[origin] = charge
+						ledger.charges = append(ledger.charges, charge)
+					}
+					charge.amount += delta
+				}
+			case model.LedgerAccountRentIncome, model.LedgerAccountMaintenanceIncome:
+				if entry.RentDueID == nil {
+					continue
+				}
+				charged := ledger.charged[*entry.RentDueID]
+				if p.Account.Kind == model.LedgerAccountRentIncome {
+					charged.rent -= delta
+				} else {
+					charged.maintenance -= delta
+				}
+				ledger.charged[*entry.RentDueID] = charged
+			}
+		}
+	}
+	return ledger
+}
+
+// balance returns the balance of each account, on its normal side
+func (l *leaseLedger) balance(leaseID uuid.UUID) *model.LedgerBalance {
+	balance := &model.LedgerBalance{
+		LeaseID:          leaseID,
+		OutstandingPaise: l.balances[model.LedgerAccountReceivable],
+		DepositHeldPaise: -l.balances[model.LedgerAccountDeposit],
+		CollectedPaise:   l.balances[model.LedgerAccountCash],
+		Accounts:         make([]model.LedgerAccountBalance, 0, len(model.LedgerAccountKinds)),
+		Tenants:          make([]model.LedgerTenantBalance, 0, len(l.tenantOrder)),
+	}
+	for _, kind := range model.LedgerAccountKinds {
+		amount := l.balances[kind]
+		if slices.Contains(creditNormal, kind) {
+			amount = -amount
+		}
+		balance.Accounts = append(balance.Accounts, model.LedgerAccountBalance{Kind: kind, BalancePaise: amount})
+	}
+	for _, tenantID := range l.tenantOrder {
+		balance.Tenants = append(balance.Tenants, l.tenantBalance(leaseID, tenantID))
+	}
+	return balance
+}
+
+// tenantBalance returns what one tenant owes and has on deposit
+func (l *leaseLedger) tenantBalance(leaseID, tenantID uuid.UUID) model.LedgerTenantBalance {
+	tenant := l.tenants[tenantID]
+	return model.LedgerTenantBalance{LeaseID: leaseID, UserID: tenantID, OutstandingPaise: tenant.owed, DepositHeldPaise: tenant.held}
+}
+
+// shareWeight returns what an entry of the kind naming no tenant is shared
+// among the tenants by: a payment by what each owes, a refund by what each
+// has paid in advance and the deposit by what each holds. Charges are
+// shared equally.
+func (l *leaseLedger) shareWeight(kind string) func(uuid.UUID) int64 {
+	switch kind {
+	case model.JournalPayment:
+		return func(tenantID uuid.UUID) int64 { return l.tenants[tenantID].owed }
+	case model.JournalRefund:
+		return func(tenantID uuid.UUID) int64 { return -l.tenants[tenantID].owed }
+	case model.JournalDepositRefund, model.JournalDepositAdjustment:
+		return func(tenantID uuid.UUID) int64 { return l.tenants[tenantID].held }
+	}
+	return nil
+}
+
+// allocate spreads what the tenants have paid over what they were charged,
+// oldest first; anything left over pays dues still to fall due in advance.
+// It returns the dues whose paid amount changed and the credit left once
+// every due is paid.
+func (l *leaseLedger) allocate(dues []model.RentDue) ([]model.RentDue, int64) {
+	type claim struct {
+		date   time.Time
+		amount int64
+		due    int // index into dues, or -1
+	}
+	claims := make([]claim, 0, len(l.charges)+len(dues))
+	for _, charge := range l.charges {
+		claims = append(claims, claim{charge.date, charge.amount, -1})
+	}
+	for i, due := range dues {
+		amount := due.AmountPaise
+		if charged, ok := l.charged[due.ID]; ok {
+			amount = charged.rent + charged.maintenance
+		}
+		claims = append(claims, claim{due.DueDate, amount, i})
+	}
+	sort.SliceStable(claims, func(i, j int) bool { return claims[i].date.Before(claims[j].date) })
+
+	paid := l.paid
+	now := time.Now()
+	var changed []model.RentDue
+	for _, c := range claims {
+		share := min(max(paid, 0), max(c.amount, 0))
+		paid -= share
+		if c.due < 0 || dues[c.due].PaidPaise == share {
+			continue
+		}
+		due := dues[c.due]
+		due.PaidPaise = share
+		due.UpdatedAt = now
+		changed = append(changed, due)
+	}
+	return changed, max(paid, 0)
+}
+
+// statement lists the entries moving what the tenants of the leases owe or
+// hold as deposit, one line per lease an entry touches. Given a party, only
+// that tenant's accounts count.
+func statement(leaseIDs []uuid.UUID, party *uuid.UUID, entries []model.JournalEntry, period StatementPeriod) *model.LedgerStatement {
+	st := &model.LedgerStatement{From: period.From, To: period.To, Lines: []model.LedgerStatementLine{}}
+	var owed, held int64
+	for _, entry := range entries {
+		if period.To != nil && entry.OccurredOn.After(*period.To) {
+			break
+		}
+		for _, leaseID := range leaseIDs {
+			var charge, deposit int64
+			for _, p := range entry.Postings {
+				if p.Account == nil || p.Account.LeaseID != leaseID || (party != nil && p.Account.PartyID != *party) {
+					continue
+				}
+				switch p.Account.Kind {
+				case model.LedgerAccountReceivable:
+					charge += p.DebitPaise - p.CreditPaise
+				case model.LedgerAccountDeposit:
+					deposit += p.CreditPaise - p.DebitPaise
+				}
+			}
+			if charge == 0 && deposit == 0 {
+				continue
+			}
+
+			owed += charge
+			held += deposit
+			if period.From != nil && entry.OccurredOn.Before(*period.From) {
+				st.OpeningBalancePaise, st.OpeningDepositPaise = owed, held
+				continue
+			}
+			st.Lines = append(st.Lines, model.LedgerStatementLine{
+				EntryID:          entry.ID,
+				LeaseID:          leaseID,
+				Date:             entry.OccurredOn,
+				Kind:             entry.Kind,
+				Description:      entry.Description,
+				Reference:        entry.Reference,
+				ReversesID:       entry.ReversesID,
+				ChargePaise:      max(charge, 0),
+				PaidPaise:        max(-charge, 0),
+				BalancePaise:     owed,
+				DepositPaise:     deposit,
+				DepositHeldPaise: held,
+			})
+		}
+	}
+	st.ClosingBalancePaise, st.ClosingDepositPaise = owed, held
+	return st
+}
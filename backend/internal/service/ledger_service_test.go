@@ -0,0 +1,414 @@
+package service
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// testJournal builds the entries of one lease's ledger
+type testJournal struct {
+	leaseID  uuid.UUID
+	accounts map[string]*model.LedgerAccount
+	entries  []model.JournalEntry
+}
+
+func newTestJournal() *testJournal {
+	j := &testJournal{leaseID: uuid.New(), accounts: map[string]*model.LedgerAccount{}}
+	for _, kind := range model.LedgerAccountKinds {
+		j.accounts[kind] = &model.LedgerAccount{ID: uuid.New(), LeaseID: j.leaseID, Kind: kind}
+	}
+	return j
+}
+
+// post adds an entry debiting the first account and crediting the second
+func (j *testJournal) post(kind string, on time.Time, amount int64, debit, credit string) *model.JournalEntry {
+	j.entries = append(j.entries, model.JournalEntry{
+		ID:         uuid.New(),
+		LeaseID:    j.leaseID,
+		Kind:       kind,
+		OccurredOn: on,
+		Postings: []model.LedgerPosting{
+			{Account: j.accounts[debit], DebitPaise: amount},
+			{Account: j.accounts[credit], CreditPaise: amount},
+		},
+	})
+	return &j.entries[len(j.entries)-1]
+}
+
+func (j *testJournal) charge(due *model.RentDue, amount int64) {
+	entry := j.post(model.JournalRentCharge, due.DueDate, amount, model.LedgerAccountReceivable, model.LedgerAccountRentIncome)
+	entry.RentDueID = &due.ID
+}
+
+func (j *testJournal) pay(on time.Time, amount int64) *model.JournalEntry {
+	return j.post(model.JournalPayment, on, amount, model.LedgerAccountCash, model.LedgerAccountReceivable)
+}
+
+// reverse adds the mirror image of entry
+func (j *testJournal) reverse(entry *model.JournalEntry, on time.Time) {
+	reversal := model.JournalEntry{ID: uuid.New(), LeaseID: j.leaseID, Kind: entry.Kind, OccurredOn: on, ReversesID: &entry.ID, RentDueID: entry.RentDueID}
+	for _, p := range entry.Postings {
+		reversal.Postings = append(reversal.Postings, model.LedgerPosting{Account: p.Account, DebitPaise: p.CreditPaise, CreditPaise: p.DebitPaise})
+	}
+	j.entries = append(j.entries, reversal)
+}
+
+func TestLeaseLedgerAllocate(t *testing.T) {
+	date := func(month time.Month, day int) time.Time { return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC) }
+	const rent = 1500000
+
+	tests := []struct {
+		name     string
+		recorded []int64 // paid already recorded on each due
+		build    func(j *testJournal, dues []model.RentDue)
+		want     []int64
+		credit   int64
+	}{
+		{
+			name: "nothing paid",
+			want: []int64{0, 0, 0},
+		},
+		{
+			name: "part payment goes to the oldest due",
+			build: func(j *testJournal, dues []model.RentDue) {
+				j.pay(date(time.April, 3), 1000000)
+			},
+			want: []int64{1000000, 0, 0},
+		},
+		{
+			name: "payment spanning dues",
+			build: func(j *testJournal, dues []model.RentDue) {
+				j.pay(date(time.April, 3), 2000000)
+			},
+			want: []int64{rent, 500000, 0},
+		},
+		{
+			name: "advance pays dues not yet charged",
+			build: func(j *testJournal, dues []model.RentDue) {
+				j.pay(date(time.April, 3), 3*rent)
+			},
+			want: []int64{rent, rent, rent},
+		},
+		{
+			name: "over-allocation stops at what the dues ask",
+			build: func(j *testJournal, dues []model.RentDue) {
+				j.pay(date(time.April, 3), 5000000)
+			},
+			want:   []int64{rent, rent, rent},
+			credit: 500000,
+		},
+		{
+			name: "late fee is claimed in date order",
+			build: func(j *testJournal, dues []model.RentDue) {
+				j.post(model.JournalLateFee, date(time.April, 10), 50000, model.LedgerAccountReceivable, model.LedgerAccountLateFeeIncome)
+				j.pay(date(time.May, 3), 2000000)
+			},
+			want: []int64{rent, 450000, 0},
+		},
+		{
+			name: "deposit is claimed before rent on the same day",
+			build: func(j *testJournal, dues []model.RentDue) {
+				j.post(model.JournalDepositCharge, date(time.April, 1), 3000000, model.LedgerAccountReceivable, model.LedgerAccountDeposit)
+				j.pay(date(time.April, 1), 4000000)
+			},
+			want: []int64{1000000, 0, 0},
+		},
+		{
+			name: "charged amount replaces the due's when the due was revised",
+			build: func(j *testJournal, dues []model.RentDue) {
+				j.post(model.JournalRentAdjustment, date(time.April, 5), 300000, model.LedgerAccountRentIncome, model.LedgerAccountReceivable).RentDueID = &dues[0].ID
+				j.pay(date(time.April, 10), rent)
+			},
+			want: []int64{1200000, 300000, 0},
+		},
+		{
+			name:     "reversed payment takes back what it paid",
+			recorded: []int64{rent, 0, 0},
+			build: func(j *testJournal, dues []model.RentDue) {
+				payment := j.pay(date(time.April, 3), rent)
+				j.reverse(payment, date(time.April, 20))
+			},
+			want: []int64{0, 0, 0},
+		},
+		{
+			name:     "refund takes back the advance",
+			recorded: []int64{rent, rent, 0},
+			build: func(j *testJournal, dues []model.RentDue) {
+				j.pay(date(time.April, 3), 2*rent)
+				j.post(model.JournalRefund, date(time.April, 20), 500000, model.LedgerAccountReceivable, model.LedgerAccountCash)
+			},
+			want: []int64{rent, 1000000, 0},
+		},
+		{
+			name: "deposit applied to dues pays them",
+			build: func(j *testJournal, dues []model.RentDue) {
+				j.post(model.JournalDepositAdjustment, date(time.May, 10), rent, model.LedgerAccountDeposit, model.LedgerAccountReceivable)
+			},
+			want: []int64{rent, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := newTestJournal()
+			dues := make([]model.RentDue, 3)
+			for i := range dues {
+				dues[i] = model.RentDue{ID: uuid.New(), LeaseID: j.leaseID, DueDate: date(time.April+time.Month(i), 1), AmountPaise: rent}
+				if tt.recorded != nil {
+					dues[i].PaidPaise = tt.recorded[i]
+				}
+			}
+			// April and May have been charged; June is still to fall due
+			j.charge(&dues[0], rent)
+			j.charge(&dues[1], rent)
+			if tt.build != nil {
+				tt.build(j, dues)
+			}
+
+			changed, credit := ledgerOf(j.leaseID, j.entries).allocate(dues)
+			got := make([]int64, len(dues))
+			for i, due := range dues {
+				got[i] = due.PaidPaise
+				for _, c := range changed {
+					if c.ID == due.ID {
+						if c.PaidPaise == due.PaidPaise {
+							t.Errorf("due %d returned as changed with the same paid amount", i)
+						}
+						got[i] = c.PaidPaise
+					}
+				}
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("paid %v, want %v", got, tt.want)
+					break
+				}
+			}
+			if credit != tt.credit {
+				t.Errorf("credit %d, want %d", credit, tt.credit)
+			}
+		})
+	}
+}
+
+func TestStatement(t *testing.T) {
+	date := func(month time.Month, day int) time.Time { return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC) }
+	j := newTestJournal()
+	april := &model.RentDue{ID: uuid.New(), DueDate: date(time.April, 1)}
+	may := &model.RentDue{ID: uuid.New(), DueDate: date(time.May, 1)}
+
+	j.post(model.JournalDepositCharge, date(time.April, 1), 3000000, model.LedgerAccountReceivable, model.LedgerAccountDeposit)
+	j.charge(april, 1500000)
+	j.pay(date(time.April, 2), 4500000)
+	j.charge(may, 1500000)
+	payment := j.pay(date(time.May, 3), 1000000)
+	j.reverse(payment, date(time.May, 5))
+	j.post(model.JournalDepositAdjustment, date(time.May, 20), 500000, model.LedgerAccountDeposit, model.LedgerAccountReceivable)
+	// An entry of another lease is left out
+	other := newTestJournal()
+	other.pay(date(time.May, 21), 99)
+	j.entries = append(j.entries, other.entries...)
+
+	april2, may1, may3 := date(time.April, 2), date(time.May, 1), date(time.May, 3)
+
+	type line struct {
+		kind                  string
+		charge, paid, balance int64
+		deposit, depositHeld  int64
+		reversal              bool
+	}
+	tests := []struct {
+		name                    string
+		period                  StatementPeriod
+		openBalance, openHeld   int64
+		want                    []line
+		closeBalance, closeHeld int64
+	}{
+		{
+			name: "whole ledger",
+			want: []line{
+				{kind: model.JournalDepositCharge, charge: 3000000, balance: 3000000, deposit: 3000000, depositHeld: 3000000},
+				{kind: model.JournalRentCharge, charge: 1500000, balance: 4500000, depositHeld: 3000000},
+				{kind: model.JournalPayment, paid: 4500000, balance: 0, depositHeld: 3000000},
+				{kind: model.JournalRentCharge, charge: 1500000, balance: 1500000, depositHeld: 3000000},
+				{kind: model.JournalPayment, paid: 1000000, balance: 500000, depositHeld: 3000000},
+				{kind: model.JournalPayment, charge: 1000000, balance: 1500000, depositHeld: 3000000, reversal: true},
+				{kind: model.JournalDepositAdjustment, paid: 500000, balance: 1000000, deposit: -500000, depositHeld: 2500000},
+			},
+			closeBalance: 1000000,
+			closeHeld:    2500000,
+		},
+		{
+			name:        "from May carries April as the opening balance",
+			period:      StatementPeriod{From: &may1},
+			openBalance: 0,
+			openHeld:    3000000,
+			want: []line{
+				{kind: model.JournalRentCharge, charge: 1500000, balance: 1500000, depositHeld: 3000000},
+				{kind: model.JournalPayment, paid: 1000000, balance: 500000, depositHeld: 3000000},
+				{kind: model.JournalPayment, charge: 1000000, balance: 1500000, depositHeld: 3000000, reversal: true},
+				{kind: model.JournalDepositAdjustment, paid: 500000, balance: 1000000, deposit: -500000, depositHeld: 2500000},
+			},
+			closeBalance: 1000000,
+			closeHeld:    2500000,
+		},
+		{
+			name:   "period ending before the reversal",
+			period: StatementPeriod{From: &april2, To: &may3},
+			// The deposit and April's rent come before the period
+			openBalance: 4500000,
+			openHeld:    3000000,
+			want: []line{
+				{kind: model.JournalPayment, paid: 4500000, balance: 0, depositHeld: 3000000},
+				{kind: model.JournalRentCharge, charge: 1500000, balance: 1500000, depositHeld: 3000000},
+				{kind: model.JournalPayment, paid: 1000000, balance: 500000, depositHeld: 3000000},
+			},
+			closeBalance: 500000,
+			closeHeld:    3000000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := statement([]uuid.UUID{j.leaseID}, nil, j.entries, tt.period)
+			if st.OpeningBalancePaise != tt.openBalance || st.OpeningDepositPaise != tt.openHeld {
+				t.Errorf("opening %d owed and %d held, want %d and %d", st.OpeningBalancePaise, st.OpeningDepositPaise, tt.openBalance, tt.openHeld)
+			}
+			if st.ClosingBalancePaise != tt.closeBalance || st.ClosingDepositPaise != tt.closeHeld {
+				t.Errorf("closing %d owed and %d held, want %d and %d", st.ClosingBalancePaise, st.ClosingDepositPaise, tt.closeBalance, tt.closeHeld)
+			}
+			if len(st.Lines) != len(tt.want) {
+				t.Fatalf("%d lines, want %d", len(st.Lines), len(tt.want))
+			}
+			for i, l := range st.Lines {
+				got := line{l.Kind, l.ChargePaise, l.PaidPaise, l.BalancePaise, l.DepositPaise, l.DepositHeldPaise, l.ReversesID != nil}
+				if got != tt.want[i] {
+					t.Errorf("line %d = %+v, want %+v", i, got, tt.want[i])
+				}
+				if l.LeaseID != j.leaseID {
+					t.Errorf("line %d is for lease %s", i, l.LeaseID)
+				}
+			}
+		})
+	}
+}
+
+func TestShares(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{"no one to share with", 100, nil, []int64{}},
+		{"equally", 100, []int64{0, 0, 0}, []int64{34, 33, 33}},
+		{"by weight", 200, []int64{300, 100}, []int64{150, 50}},
+		{"beyond the weights equally", 1000, []int64{300, 100}, []int64{600, 400}},
+		{"negative weights count as none", 500, []int64{-200, 100}, []int64{200, 300}},
+		{"rounding goes to the first", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"negative amount", -100, []int64{0, 0, 0}, []int64{-34, -33, -33}},
+		{"large amounts", 1_000_000_000_000, []int64{3_000_000_000_000, 1_000_000_000_000}, []int64{750_000_000_000, 250_000_000_000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shares(tt.amount, tt.weights)
+			var sum int64
+			for _, share := range got {
+				sum += share
+			}
+			if len(got) > 0 && sum != tt.amount {
+				t.Errorf("shares add up to %d, want %d", sum, tt.amount)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shares(%d, %v) = %v, want %v", tt.amount, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaseLedgerTenants(t *testing.T) {
+	leaseID, ownerID, a, b := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	accounts := map[uuid.UUID]*model.LedgerAccount{}
+	ids := &leaseAccounts{owner: map[string]uuid.UUID{}, tenants: map[uuid.UUID]map[string]uuid.UUID{}, sharing: []uuid.UUID{a, b}}
+	for _, kind := range model.LedgerAccountKinds {
+		parties := []uuid.UUID{ownerID}
+		if slices.Contains(model.TenantAccountKinds, kind) {
+			parties = []uuid.UUID{a, b}
+		}
+		for _, party := range parties {
+			account := &model.LedgerAccount{ID: uuid.New(), LeaseID: leaseID, Kind: kind, PartyID: party}
+			accounts[account.ID] = account
+			if party == ownerID {
+				ids.owner[kind] = account.ID
+			} else {
+				if ids.tenants[party] == nil {
+					ids.tenants[party] = map[string]uuid.UUID{}
+				}
+				ids.tenants[party][kind] = account.ID
+			}
+		}
+	}
+
+	var entries []model.JournalEntry
+	post := func(kind string, legs []leg) {
+		entry := model.JournalEntry{ID: uuid.New(), LeaseID: leaseID, Kind: kind, OccurredOn: time.Date(2024, time.April, len(entries)+1, 0, 0, 0, 0, time.UTC)}
+		var sum int64
+		for _, l := range legs {
+			sum += l.paise
+			if l.paise > 0 {
+				entry.Postings = append(entry.Postings, model.LedgerPosting{Account: accounts[l.account], DebitPaise: l.paise})
+			} else if l.paise < 0 {
+				entry.Postings = append(entry.Postings, model.LedgerPosting{Account: accounts[l.account], CreditPaise: -l.paise})
+			}
+		}
+		if sum != 0 {
+			t.Fatalf("%s entry is unbalanced by %d", kind, sum)
+		}
+		entries = append(entries, entry)
+	}
+	current := func() *leaseLedger { return ledgerOf(leaseID, entries) }
+
+	// The deposit and the rent are charged in equal shares, the odd paisa to the first tenant
+	post(model.JournalDepositCharge, append(ids.tenantLegs(model.LedgerAccountReceivable, 3000000, nil, nil),
+		ids.tenantLegs(model.LedgerAccountDeposit, -3000000, nil, nil)...))
+	post(model.JournalRentCharge, append(ids.tenantLegs(model.LedgerAccountReceivable, 1500001, nil, nil),
+		leg{ids.owner[model.LedgerAccountRentIncome], -1500001}))
+	// The first tenant pays their share themselves
+	post(model.JournalPayment, ids.entryLegs(entryAccounts[model.JournalPayment], 2250001, &a, current().shareWeight(model.JournalPayment)))
+	// A payment from neither goes to who still owes
+	post(model.JournalPayment, ids.entryLegs(entryAccounts[model.JournalPayment], 1000000, nil, current().shareWeight(model.JournalPayment)))
+	// The deposit applied to dues comes out of what each holds
+	post(model.JournalDepositAdjustment, ids.entryLegs(entryAccounts[model.JournalDepositAdjustment], 500000, nil, current().shareWeight(model.JournalDepositAdjustment)))
+
+	balance := current().balance(leaseID)
+	if balance.OutstandingPaise != 750000 || balance.DepositHeldPaise != 2500000 {
+		t.Errorf("lease owes %d and holds %d, want 750000 and 2500000", balance.OutstandingPaise, balance.DepositHeldPaise)
+	}
+	wantTenants := []model.LedgerTenantBalance{
+		{LeaseID: leaseID, UserID: a, OutstandingPaise: -250000, DepositHeldPaise: 1250000},
+		{LeaseID: leaseID, UserID: b, OutstandingPaise: 1000000, DepositHeldPaise: 1250000},
+	}
+	if !reflect.DeepEqual(balance.Tenants, wantTenants) {
+		t.Errorf("tenants %+v, want %+v", balance.Tenants, wantTenants)
+	}
+
+	st := statement([]uuid.UUID{leaseID}, &a, entries, StatementPeriod{})
+	if st.ClosingBalancePaise != -250000 || st.ClosingDepositPaise != 1250000 {
+		t.Errorf("first tenant's statement closes at %d owed and %d held, want -250000 and 1250000", st.ClosingBalancePaise, st.ClosingDepositPaise)
+	}
+	var kinds []string
+	for _, l := range st.Lines {
+		kinds = append(kinds, l.Kind)
+	}
+	wantKinds := []string{model.JournalDepositCharge, model.JournalRentCharge, model.JournalPayment, model.JournalDepositAdjustment}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Errorf("first tenant's statement lists %v, want %v", kinds, wantKinds)
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/auth"
+	"backend/internal/config"
+	"backend/internal/model"
+	"backend/internal/notify"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/phone"
+
+	"github.com/google/uuid"
+)
+
+// otpCodes sends one-time codes by SMS and checks the codes entered. A code
+// is only accepted for the purpose, and the user, it was sent for.
+type otpCodes struct {
+	repo repository.OTPRepository
+	sms  notify.SMSSender
+	cfg  config.OTPConfig
+}
+
+// send texts a new code to a normalized phone number. message formats the
+// SMS from the code and the minutes it is valid for.
+func (o *otpCodes) send(ctx context.Context, normalized, purpose string, userID *uuid.UUID, message string) (*model.OTPRequestResponse, error) {
+	now := time.Now()
+	cooldown := time.Duration(o.cfg.ResendCooldown) * time.Second
+
+	latest, err := o.repo.GetLatestByPhone(ctx, normalized, purpose)
+	if err != nil && !errors.Is(err, repository.ErrOTPChallengeNotFound) {
+		return nil, apperr.Internal("Failed to check previous OTP", err)
+	}
+	if latest != nil && now.Before(latest.CreatedAt.Add(cooldown)) {
+		return nil, apperr.RateLimited("Please wait before requesting another OTP", nil)
+	}
+
+	code, err := auth.GenerateOTP(o.cfg.Length)
+	if err != nil {
+		return nil, apperr.Internal("Failed to generate OTP", err)
+	}
+
+	challenge := &model.OTPChallenge{
+		ID:          uuid.New(),
+		Phone:       normalized,
+		Purpose:     purpose,
+		UserID:      userID,
+		CodeHash:    auth.HashOTP(o.cfg.Secret, normalized, code),
+		MaxAttempts: o.cfg.MaxAttempts,
+		ExpiresAt:   now.Add(time.Duration(o.cfg.TTL) * time.Minute),
+		CreatedAt:   now,
+	}
+
+	if err := o.repo.Create(ctx, challenge); err != nil {
+		return nil, apperr.Internal("Failed to store OTP", err)
+	}
+
+	if err := o.sms.SendSMS(ctx, normalized, fmt.Sprintf(message, code, o.cfg.TTL)); err != nil {
+		return nil, apperr.Internal("Failed to send OTP", err)
+	}
+
+	return &model.OTPRequestResponse{
+		Phone:       phone.Mask(normalized),
+		ExpiresAt:   challenge.ExpiresAt,
+		ResendAfter: now.Add(cooldown),
+	}, nil
+}
+
+// consume checks code against the last code sent to a normalized phone
+// number for purpose and uses it up. userID must be the user a phone change
+// code was sent for.
+func (o *otpCodes) consume(ctx context.Context, normalized, purpose string, userID *uuid.UUID, code string) error {
+	challenge, err := o.repo.GetLatestByPhone(ctx, normalized, purpose)
+	if err != nil {
+		if errors.Is(err, repository.ErrOTPChallengeNotFound) {
+			return apperr.Unauthorized("Invalid or expired OTP", err)
+		}
+		return apperr.Internal("Failed to fetch OTP", err)
+	}
+
+	now := time.Now()
+	if challenge.ConsumedAt != nil || now.After(challenge.ExpiresAt) {
+		return apperr.Unauthorized("Invalid or expired OTP", nil)
+	}
+	if (challenge.UserID == nil) != (userID == nil) || (userID != nil && *challenge.UserID != *userID) {
+		return apperr.Unauthorized("Invalid or expired OTP", nil)
+	}
+	if challenge.Attempts >= challenge.MaxAttempts {
+		return apperr.RateLimited("Too many incorrect attempts, please request a new OTP", nil)
+	}
+
+	if err := o.repo.IncrementAttempts(ctx, challenge.ID); err != nil {
+		return apperr.Internal("Failed to record OTP attempt", err)
+	}
+
+	if !auth.CheckOTP(o.cfg.Secret, normalized, code, challenge.CodeHash) {
+		return apperr.Unauthorized("Invalid or expired OTP", nil)
+	}
+
+	if err := o.repo.Consume(ctx, challenge.ID, now); err != nil {
+		if errors.Is(err, repository.ErrOTPAlreadyConsumed) {
+			return apperr.Unauthorized("Invalid or expired OTP", err)
+		}
+		return apperr.Internal("Failed to consume OTP", err)
+	}
+	return nil
+}
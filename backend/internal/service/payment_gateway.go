@@ -0,0 +1,510 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"backend/internal/clausetext"
+	"backend/internal/gateway"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/inr"
+
+	"github.com/google/uuid"
+)
+
+type ConfirmPaymentInput struct {
+	PaymentID string
+	Signature string
+}
+
+type RefundPaymentInput struct {
+	AmountPaise int64 // all that is left when zero
+	Reason      string
+}
+
+// CreatePaymentOrder places an order with the payment gateway for what is
+// outstanding on a rent due and returns it with what the checkout needs
+func (s *paymentService) CreatePaymentOrder(ctx context.Context, actor *model.User, leaseID, dueID uuid.UUID) (*model.PaymentOrder, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasLedger(lease) {
+		return nil, apperr.Invalid("Rent can be paid once the lease has been activated", nil)
+	}
+
+	due, err := s.leaseRepo.GetDue(ctx, lease.ID, dueID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRentDueNotFound) {
+			return nil, apperr.NotFound("Rent due not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch rent due", err)
+	}
+	amount := due.OutstandingPaise()
+	if amount == 0 {
+		return nil, apperr.Invalid("Rent due has already been paid", nil)
+	}
+
+	now := time.Now()
+	order := &model.PaymentOrder{
+		ID:          uuid.New(),
+		LeaseID:     lease.ID,
+		RentDueID:   &due.ID,
+		Gateway:     s.gateway.Name(),
+		AmountPaise: amount,
+		Status:      model.PaymentOrderCreated,
+		CreatedBy:   &actor.ID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	placed, err := s.gateway.CreateOrder(ctx, gateway.OrderRequest{
+		AmountPaise: amount,
+		Receipt:     order.ID.String(),
+		Notes: map[string]string{
+			"lease_id":    lease.ID.String(),
+			"rent_due_id": due.ID.String(),
+		},
+	})
+	if err != nil {
+		return nil, apperr.Internal("Failed to create payment order", err)
+	}
+	order.GatewayOrderID = placed.ID
+	order.KeyID, order.CheckoutURL = placed.KeyID, placed.CheckoutURL
+
+	if err := s.paymentRepo.CreateOrder(ctx, order); err != nil {
+		return nil, apperr.Internal("Failed to save payment order", err)
+	}
+	return order, nil
+}
+
+// ConfirmPayment records the payment of an order from the confirmation the
+// gateway's checkout handed the payer, when it arrives before the webhook
+func (s *paymentService) ConfirmPayment(ctx context.Context, actor *model.User, leaseID, orderID uuid.UUID, input ConfirmPaymentInput) (*model.PaymentOrder, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.fetchOrder(ctx, lease.ID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.gateway.VerifyPayment(ctx, gateway.PaymentConfirmation{
+		OrderID:   order.GatewayOrderID,
+		PaymentID: input.PaymentID,
+		Signature: input.Signature,
+	}); err != nil {
+		if errors.Is(err, gateway.ErrInvalidSignature) {
+			return nil, apperr.Invalid("The payment confirmation could not be verified", err)
+		}
+		return nil, apperr.Internal("Failed to verify payment", err)
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		locked, err := tx.repos.Payment.LockOrder(ctx, lease.ID, order.ID)
+		if err != nil {
+			return apperr.Internal("Failed to fetch payment order", err)
+		}
+		if locked.Status == model.PaymentOrderPaid && *locked.GatewayPaymentID != input.PaymentID {
+			return apperr.Conflict("Payment order has already been paid by another payment", nil)
+		}
+		_, err = s.settle(ctx, tx, lease, locked, input.PaymentID, today())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.fetchOrder(ctx, lease.ID, order.ID)
+}
+
+// RefundPayment refunds some or all of a paid order through the gateway and
+// records the refund on the ledger. The refund is stored as pending before
+// the gateway is asked, so a refund the gateway made is not lost when the
+// gateway's answer is: the refund.processed webhook completes it instead. A
+// refund left pending that way is asked for again, with the same idempotency
+// key, and returned the next time the order is refunded.
+func (s *paymentService) RefundPayment(ctx context.Context, actor *model.User, leaseID, orderID uuid.UUID, input RefundPaymentInput) (*model.PaymentRefund, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionUpdate, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *model.PaymentRefund
+	var paymentID string
+	err = s.services.Transaction(func(tx *Services) error {
+		order, err := tx.repos.Payment.LockOrder(ctx, lease.ID, orderID)
+		if err != nil {
+			if errors.Is(err, repository.ErrPaymentOrderNotFound) {
+				return apperr.NotFound("Payment order not found", err)
+			}
+			return apperr.Internal("Failed to fetch payment order", err)
+		}
+		if order.Status != model.PaymentOrderPaid {
+			return apperr.Invalid("Only a paid order can be refunded", nil)
+		}
+		paymentID = *order.GatewayPaymentID
+
+		record, err = tx.repos.Payment.GetPendingRefund(ctx, order.ID)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, repository.ErrPaymentRefundNotFound) {
+			return apperr.Internal("Failed to fetch refund", err)
+		}
+
+		left := order.AmountPaise - order.RefundedPaise
+		if left == 0 {
+			return apperr.Invalid("Payment has already been refunded in full", nil)
+		}
+		amount := input.AmountPaise
+		if amount == 0 {
+			amount = left
+		}
+		if amount > left {
+			return apperr.Invalid("Amount exceeds what is left to refund: "+inr.FormatWithSymbol(left), nil)
+		}
+
+		now := time.Now()
+		record = &model.PaymentRefund{
+			ID:          uuid.New(),
+			OrderID:     order.ID,
+			Status:      model.PaymentRefundPending,
+			AmountPaise: amount,
+			Reason:      input.Reason,
+			CreatedBy:   &actor.ID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := tx.repos.Payment.CreateRefund(ctx, record); err != nil {
+			return apperr.Internal("Failed to save refund", err)
+		}
+		// The amount is held while the refund is pending, so it cannot be
+		// refunded twice
+		order.RefundedPaise += amount
+		order.UpdatedAt = now
+		if err := tx.repos.Payment.SaveOrder(ctx, order); err != nil {
+			return apperr.Internal("Failed to update payment order", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := s.gateway.Refund(ctx, gateway.RefundRequest{
+		PaymentID:      paymentID,
+		AmountPaise:    record.AmountPaise,
+		IdempotencyKey: record.ID.String(),
+		Receipt:        record.ID.String(),
+		Notes:          map[string]string{"reason": record.Reason},
+	})
+	if err != nil {
+		if errors.Is(err, gateway.ErrRefundRejected) || errors.Is(err, gateway.ErrPaymentNotFound) {
+			if err := s.failRefund(ctx, lease.ID, record, err.Error()); err != nil {
+				return nil, err
+			}
+			return nil, apperr.Invalid("The payment gateway did not accept the refund", err)
+		}
+		return nil, apperr.Internal("The payment gateway did not confirm the refund; it stays pending until the gateway reports it or the order is refunded again", err)
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		order, err := tx.repos.Payment.LockOrder(ctx, lease.ID, orderID)
+		if err != nil {
+			return apperr.Internal("Failed to fetch payment order", err)
+		}
+		record, err = tx.repos.Payment.LockRefund(ctx, order.ID, record.ID)
+		if err != nil {
+			return apperr.Internal("Failed to fetch refund", err)
+		}
+		// The webhook may have completed it already
+		if record.Status != model.PaymentRefundPending {
+			return nil
+		}
+		return s.completeRefund(ctx, tx, lease, order, record, refund.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// completeRefund records a pending refund, locked within tx, that the
+// gateway has made on the ledger. It goes back to the tenant who paid the order.
+func (s *paymentService) completeRefund(ctx context.Context, tx *Services, lease *model.Lease, order *model.PaymentOrder, refund *model.PaymentRefund, gatewayRefundID string) error {
+	entry, err := tx.Ledger.PostSettlement(ctx, lease, PostLedgerEntryInput{
+		Kind:        model.JournalRefund,
+		AmountPaise: refund.AmountPaise,
+		Description: "Online payment refunded: " + refund.Reason,
+		Reference:   gatewayRefundID,
+		Source:      model.JournalSourcePaymentGateway,
+		TenantID:    order.CreatedBy,
+	}, refund.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	refund.Status = model.PaymentRefundProcessed
+	refund.GatewayRefundID = &gatewayRefundID
+	refund.JournalEntryID = &entry.ID
+	refund.UpdatedAt = time.Now()
+	if err := tx.repos.Payment.SaveRefund(ctx, refund); err != nil {
+		return apperr.Internal("Failed to update refund", err)
+	}
+	return nil
+}
+
+// failRefund marks a pending refund the gateway rejected as failed and
+// releases the amount it held on the order
+func (s *paymentService) failRefund(ctx context.Context, leaseID uuid.UUID, refund *model.PaymentRefund, reason string) error {
+	return s.services.Transaction(func(tx *Services) error {
+		order, err := tx.repos.Payment.LockOrder(ctx, leaseID, refund.OrderID)
+		if err != nil {
+			return apperr.Internal("Failed to fetch payment order", err)
+		}
+		locked, err := tx.repos.Payment.LockRefund(ctx, order.ID, refund.ID)
+		if err != nil {
+			return apperr.Internal("Failed to fetch refund", err)
+		}
+		if locked.Status != model.PaymentRefundPending {
+			return nil
+		}
+
+		now := time.Now()
+		locked.Status = model.PaymentRefundFailed
+		locked.FailureReason = reason
+		locked.UpdatedAt = now
+		if err := tx.repos.Payment.SaveRefund(ctx, locked); err != nil {
+			return apperr.Internal("Failed to update refund", err)
+		}
+		order.RefundedPaise -= locked.AmountPaise
+		order.UpdatedAt = now
+		if err := tx.repos.Payment.SaveOrder(ctx, order); err != nil {
+			return apperr.Internal("Failed to update payment order", err)
+		}
+		return nil
+	})
+}
+
+func (s *paymentService) ListPaymentOrders(ctx context.Context, actor *model.User, leaseID uuid.UUID) ([]model.PaymentOrder, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.paymentRepo.ListOrders(ctx, lease.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch payment orders", err)
+	}
+	return orders, nil
+}
+
+// HandleGatewayWebhook stores a webhook delivery from the payment gateway and
+// acts on it. Only the first delivery of an event is acted on; retries are
+// stored as duplicates of it. Events that cannot be acted on, such as those
+// for unknown orders or of types we do not handle, are stored as ignored with
+// the reason, so the gateway stops retrying them. Only a delivery without a
+// valid signature is turned away.
+func (s *paymentService) HandleGatewayWebhook(ctx context.Context, payload []byte, header http.Header) (*model.GatewayEvent, error) {
+	event, parseErr := s.gateway.ParseWebhook(payload, header)
+	if parseErr != nil && !errors.Is(parseErr, gateway.ErrMalformedEvent) {
+		if errors.Is(parseErr, gateway.ErrInvalidSignature) {
+			return nil, apperr.Unauthorized("Invalid webhook signature", parseErr)
+		}
+		return nil, apperr.Internal("Failed to read webhook event", parseErr)
+	}
+
+	record := &model.GatewayEvent{
+		ID:         uuid.New(),
+		Gateway:    s.gateway.Name(),
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Payload:    string(payload),
+		Status:     model.GatewayEventIgnored,
+		ReceivedAt: time.Now(),
+	}
+	err := s.services.Transaction(func(tx *Services) error {
+		claimed, err := tx.repos.Payment.ClaimEvent(ctx, record)
+		if err != nil {
+			return apperr.Internal("Failed to save webhook event", err)
+		}
+		if !claimed {
+			original, err := tx.repos.Payment.GetEvent(ctx, record.Gateway, record.EventID)
+			if err != nil {
+				return apperr.Internal("Failed to fetch webhook event", err)
+			}
+			record.Status = model.GatewayEventDuplicate
+			record.DuplicateOf = &original.ID
+			record.Reason = "Event already received"
+			if err := tx.repos.Payment.CreateEvent(ctx, record); err != nil {
+				return apperr.Internal("Failed to save webhook event", err)
+			}
+			return nil
+		}
+
+		if parseErr != nil {
+			record.Reason = parseErr.Error()
+		} else {
+			record.Reason, record.PaymentOrderID, err = s.applyEvent(ctx, tx, event)
+			if err != nil {
+				return err
+			}
+			if record.Reason == "" {
+				record.Status = model.GatewayEventProcessed
+			}
+		}
+		if err := tx.repos.Payment.SaveEvent(ctx, record); err != nil {
+			return apperr.Internal("Failed to save webhook event", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// applyEvent acts on a gateway event within tx. It returns why the event was
+// ignored, or an empty reason when it was acted on, and the order it concerns.
+func (s *paymentService) applyEvent(ctx context.Context, tx *Services, event *gateway.Event) (string, *uuid.UUID, error) {
+	switch event.Type {
+	case gateway.EventPaymentCaptured, gateway.EventPaymentFailed:
+		order, err := tx.repos.Payment.LockOrderByGatewayID(ctx, s.gateway.Name(), event.OrderID)
+		if err != nil {
+			if errors.Is(err, repository.ErrPaymentOrderNotFound) {
+				return "Unknown order " + event.OrderID, nil, nil
+			}
+			return "", nil, apperr.Internal("Failed to fetch payment order", err)
+		}
+
+		if event.Type == gateway.EventPaymentFailed {
+			if order.Status == model.PaymentOrderPaid {
+				return "Order has already been paid", &order.ID, nil
+			}
+			order.Status = model.PaymentOrderFailed
+			order.FailureReason = event.Reason
+			order.UpdatedAt = time.Now()
+			if err := tx.repos.Payment.SaveOrder(ctx, order); err != nil {
+				return "", nil, apperr.Internal("Failed to update payment order", err)
+			}
+			return "", &order.ID, nil
+		}
+
+		if event.Currency != "INR" || event.AmountPaise != order.AmountPaise {
+			return fmt.Sprintf("Payment of %d %s does not match the order's %s", event.AmountPaise, event.Currency, inr.FormatWithSymbol(order.AmountPaise)), &order.ID, nil
+		}
+		lease, err := tx.repos.Lease.GetByID(ctx, order.LeaseID)
+		if err != nil {
+			return "", nil, apperr.Internal("Failed to fetch lease", err)
+		}
+		y, m, d := event.OccurredAt.Date()
+		reason, err := s.settle(ctx, tx, lease, order, event.PaymentID, time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+		return reason, &order.ID, err
+
+	case gateway.EventRefundProcessed:
+		// Lock the order first, so a refund still being recorded is seen
+		order, err := tx.repos.Payment.LockOrderByPaymentID(ctx, s.gateway.Name(), event.PaymentID)
+		if err != nil {
+			if errors.Is(err, repository.ErrPaymentOrderNotFound) {
+				return "Refund of unknown payment " + event.PaymentID, nil, nil
+			}
+			return "", nil, apperr.Internal("Failed to fetch payment order", err)
+		}
+		if _, err := tx.repos.Payment.GetRefundByGatewayID(ctx, event.RefundID); err == nil {
+			return "Refund was recorded when it was issued", &order.ID, nil
+		} else if !errors.Is(err, repository.ErrPaymentRefundNotFound) {
+			return "", nil, apperr.Internal("Failed to fetch refund", err)
+		}
+
+		// A refund whose answer from the gateway was lost is still pending,
+		// and is found by the receipt it was asked for with
+		notIssued := "Refund " + event.RefundID + " was not issued from the app; record it on the ledger by hand"
+		refundID, err := uuid.Parse(event.Receipt)
+		if err != nil {
+			return notIssued, &order.ID, nil
+		}
+		refund, err := tx.repos.Payment.LockRefund(ctx, order.ID, refundID)
+		if err != nil {
+			if errors.Is(err, repository.ErrPaymentRefundNotFound) {
+				return notIssued, &order.ID, nil
+			}
+			return "", nil, apperr.Internal("Failed to fetch refund", err)
+		}
+		if refund.Status != model.PaymentRefundPending {
+			return "Refund " + event.RefundID + " was made for a refund marked " + refund.Status + "; record it on the ledger by hand", &order.ID, nil
+		}
+		if event.AmountPaise != refund.AmountPaise {
+			return fmt.Sprintf("Refund of %d does not match the pending refund's %s", event.AmountPaise, inr.FormatWithSymbol(refund.AmountPaise)), &order.ID, nil
+		}
+		lease, err := tx.repos.Lease.GetByID(ctx, order.LeaseID)
+		if err != nil {
+			return "", nil, apperr.Internal("Failed to fetch lease", err)
+		}
+		return "", &order.ID, s.completeRefund(ctx, tx, lease, order, refund, event.RefundID)
+	}
+	return "Unhandled event type", nil, nil
+}
+
+// settle credits the tenants with the payment of an order locked within tx
+// and marks the order paid. An order is credited once: it returns why when
+// the order had already been paid.
+func (s *paymentService) settle(ctx context.Context, tx *Services, lease *model.Lease, order *model.PaymentOrder, paymentID string, paidOn time.Time) (string, error) {
+	if order.Status == model.PaymentOrderPaid {
+		if *order.GatewayPaymentID == paymentID {
+			return "Payment has already been recorded", nil
+		}
+		return "Order has already been paid by payment " + *order.GatewayPaymentID + "; refund " + paymentID + " from the gateway", nil
+	}
+
+	description := "Online payment"
+	if order.RentDueID != nil {
+		due, err := tx.repos.Lease.GetDue(ctx, lease.ID, *order.RentDueID)
+		if err != nil && !errors.Is(err, repository.ErrRentDueNotFound) {
+			return "", apperr.Internal("Failed to fetch rent due", err)
+		}
+		if due != nil {
+			description += " for rent " + due.PeriodStart.Format(clausetext.DateLayout) + " to " + due.PeriodEnd.Format(clausetext.DateLayout)
+		}
+	}
+	entry, err := tx.Ledger.PostSettlement(ctx, lease, PostLedgerEntryInput{
+		Kind:        model.JournalPayment,
+		AmountPaise: order.AmountPaise,
+		OccurredOn:  paidOn,
+		Description: description,
+		Reference:   paymentID,
+		Source:      model.JournalSourcePaymentGateway,
+		TenantID:    order.CreatedBy,
+	}, order.CreatedBy)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	order.Status = model.PaymentOrderPaid
+	order.GatewayPaymentID = &paymentID
+	order.FailureReason = ""
+	order.JournalEntryID = &entry.ID
+	order.PaidAt = &now
+	order.UpdatedAt = now
+	if err := tx.repos.Payment.SaveOrder(ctx, order); err != nil {
+		return "", apperr.Internal("Failed to update payment order", err)
+	}
+	return "", nil
+}
+
+func (s *paymentService) fetchOrder(ctx context.Context, leaseID, id uuid.UUID) (*model.PaymentOrder, error) {
+	order, err := s.paymentRepo.GetOrder(ctx, leaseID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentOrderNotFound) {
+			return nil, apperr.NotFound("Payment order not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch payment order", err)
+	}
+	return order, nil
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"backend/internal/gateway"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/upi"
+
+	"github.com/google/uuid"
+)
+
+type PaymentService interface {
+	GetPayoutProfile(ctx context.Context, actor *model.User, userID uuid.UUID) (*model.PayoutProfile, error)
+	SavePayoutProfile(ctx context.Context, actor *model.User, userID uuid.UUID, input SavePayoutProfileInput) (*model.PayoutProfile, error)
+	RequestUPIPayment(ctx context.Context, actor *model.User, leaseID, dueID uuid.UUID) (*model.UPIPaymentRequest, error)
+	ListUPIRequests(ctx context.Context, actor *model.User, leaseID uuid.UUID) ([]model.UPIPaymentRequest, error)
+	UPIQRCode(ctx context.Context, actor *model.User, leaseID, requestID uuid.UUID, format string, size int) ([]byte, string, error)
+	MatchUPIRequest(ctx context.Context, text string) (*model.UPIPaymentRequest, error)
+	CreatePaymentOrder(ctx context.Context, actor *model.User, leaseID, dueID uuid.UUID) (*model.PaymentOrder, error)
+	ConfirmPayment(ctx context.Context, actor *model.User, leaseID, orderID uuid.UUID, input ConfirmPaymentInput) (*model.PaymentOrder, error)
+	RefundPayment(ctx context.Context, actor *model.User, leaseID, orderID uuid.UUID, input RefundPaymentInput) (*model.PaymentRefund, error)
+	ListPaymentOrders(ctx context.Context, actor *model.User, leaseID uuid.UUID) ([]model.PaymentOrder, error)
+	HandleGatewayWebhook(ctx context.Context, payload []byte, header http.Header) (*model.GatewayEvent, error)
+}
+
+type SavePayoutProfileInput struct {
+	VPA       string
+	PayeeName string
+}
+
+// QR code formats
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+const (
+	upiReferencePrefix   = "RNT"
+	upiReferenceLength   = 12
+	upiReferenceAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// upiReferencePattern finds a transaction reference in a payment note or a
+// bank statement narration, which may have run it into other text
+var upiReferencePattern = regexp.MustCompile(fmt.Sprintf("%s[%s]{%d}", upiReferencePrefix, upiReferenceAlphabet, upiReferenceLength))
+
+type paymentService struct {
+	services    *Services
+	paymentRepo repository.PaymentRepository
+	leaseRepo   repository.LeaseRepository
+	userRepo    repository.UserRepository
+	gateway     gateway.PaymentGateway
+}
+
+func NewPaymentService(
+	services *Services,
+	paymentRepo repository.PaymentRepository,
+	leaseRepo repository.LeaseRepository,
+	userRepo repository.UserRepository,
+	paymentGateway gateway.PaymentGateway,
+) PaymentService {
+	return &paymentService{
+		services:    services,
+		paymentRepo: paymentRepo,
+		leaseRepo:   leaseRepo,
+		userRepo:    userRepo,
+		gateway:     paymentGateway,
+	}
+}
+
+func (s *paymentService) GetPayoutProfile(ctx context.Context, actor *model.User, userID uuid.UUID) (*model.PayoutProfile, error) {
+	if err := s.authorizedUser(ctx, actor, policy.ActionRead, userID); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.paymentRepo.GetPayoutProfile(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPayoutProfileNotFound) {
+			return nil, apperr.NotFound("Payout profile not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch payout profile", err)
+	}
+	return profile, nil
+}
+
+func (s *paymentService) SavePayoutProfile(ctx context.Context, actor *model.User, userID uuid.UUID, input SavePayoutProfileInput) (*model.PayoutProfile, error) {
+	if err := s.authorizedUser(ctx, actor, policy.ActionUpdate, userID); err != nil {
+		return nil, err
+	}
+
+	vpa := strings.ToLower(strings.TrimSpace(input.VPA))
+	if !upi.IsVPA(vpa) {
+		return nil, apperr.Invalid("Invalid UPI ID", nil)
+	}
+
+	now := time.Now()
+	profile, err := s.paymentRepo.GetPayoutProfile(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrPayoutProfileNotFound) {
+			return nil, apperr.Internal("Failed to fetch payout profile", err)
+		}
+		profile = &model.PayoutProfile{UserID: userID, CreatedAt: now}
+	}
+	profile.VPA = vpa
+	profile.PayeeName = strings.TrimSpace(input.PayeeName)
+	profile.UpdatedAt = now
+
+	if err := s.paymentRepo.SavePayoutProfile(ctx, profile); err != nil {
+		return nil, apperr.Internal("Failed to save payout profile", err)
+	}
+	return profile, nil
+}
+
+// RequestUPIPayment returns a UPI payment link for what is outstanding on a
+// rent due, payable to the lease owner's UPI ID. The last link generated for
+// the due is returned again while it still asks for the right amount.
+func (s *paymentService) RequestUPIPayment(ctx context.Context, actor *model.User, leaseID, dueID uuid.UUID) (*model.UPIPaymentRequest, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasLedger(lease) {
+		return nil, apperr.Invalid("Rent can be paid once the lease has been activated", nil)
+	}
+
+	due, err := s.leaseRepo.GetDue(ctx, lease.ID, dueID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRentDueNotFound) {
+			return nil, apperr.NotFound("Rent due not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch rent due", err)
+	}
+	amount := due.OutstandingPaise()
+	if amount == 0 {
+		return nil, apperr.Invalid("Rent due has already been paid", nil)
+	}
+
+	profile, err := s.paymentRepo.GetPayoutProfile(ctx, lease.OwnerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPayoutProfileNotFound) {
+			return nil, apperr.Invalid("The owner has not added a UPI ID to receive rent", err)
+		}
+		return nil, apperr.Internal("Failed to fetch payout profile", err)
+	}
+
+	latest, err := s.paymentRepo.GetLatestUPIRequest(ctx, due.ID)
+	if err != nil && !errors.Is(err, repository.ErrUPIRequestNotFound) {
+		return nil, apperr.Internal("Failed to fetch UPI payment request", err)
+	}
+	if latest != nil && latest.AmountPaise == amount && latest.PayeeVPA == profile.VPA && latest.PayeeName == profile.PayeeName {
+		return latest, nil
+	}
+
+	ref, err := newUPIReference()
+	if err != nil {
+		return nil, apperr.Internal("Failed to generate transaction reference", err)
+	}
+	note := "Rent " + due.PeriodStart.Format("Jan 2006") + " " + ref
+	request := &model.UPIPaymentRequest{
+		ID:             uuid.New(),
+		LeaseID:        lease.ID,
+		RentDueID:      due.ID,
+		PayeeVPA:       profile.VPA,
+		PayeeName:      profile.PayeeName,
+		AmountPaise:    amount,
+		TransactionRef: ref,
+		Note:           note,
+		URI: upi.Intent{
+			VPA:         profile.VPA,
+			PayeeName:   profile.PayeeName,
+			AmountPaise: amount,
+			Reference:   ref,
+			Note:        note,
+		}.URI(),
+		CreatedBy: &actor.ID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.paymentRepo.CreateUPIRequest(ctx, request); err != nil {
+		return nil, apperr.Internal("Failed to save UPI payment request", err)
+	}
+	return request, nil
+}
+
+func (s *paymentService) ListUPIRequests(ctx context.Context, actor *model.User, leaseID uuid.UUID) ([]model.UPIPaymentRequest, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := s.paymentRepo.ListUPIRequests(ctx, lease.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch UPI payment requests", err)
+	}
+	return requests, nil
+}
+
+// UPIQRCode returns the QR code of a payment link as a PNG of size pixels or
+// an SVG, with its content type
+func (s *paymentService) UPIQRCode(ctx context.Context, actor *model.User, leaseID, requestID uuid.UUID, format string, size int) ([]byte, string, error) {
+	lease, err := s.authorized(ctx, actor, policy.ActionRead, leaseID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	request, err := s.paymentRepo.GetUPIRequest(ctx, lease.ID, requestID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUPIRequestNotFound) {
+			return nil, "", apperr.NotFound("UPI payment request not found", err)
+		}
+		return nil, "", apperr.Internal("Failed to fetch UPI payment request", err)
+	}
+
+	switch format {
+	case QRFormatSVG:
+		svg, err := upi.SVG(request.URI)
+		if err != nil {
+			return nil, "", apperr.Internal("Failed to draw QR code", err)
+		}
+		return svg, "image/svg+xml", nil
+	case QRFormatPNG:
+		png, err := upi.PNG(request.URI, size)
+		if err != nil {
+			return nil, "", apperr.Internal("Failed to draw QR code", err)
+		}
+		return png, "image/png", nil
+	}
+	return nil, "", apperr.Invalid("QR code format must be png or svg", nil)
+}
+
+// MatchUPIRequest finds the payment request whose transaction reference
+// appears in text, such as the note of an incoming UPI payment or a bank
+// statement narration
+func (s *paymentService) MatchUPIRequest(ctx context.Context, text string) (*model.UPIPaymentRequest, error) {
+	ref := upiReferencePattern.FindString(strings.ToUpper(text))
+	if ref == "" {
+		return nil, apperr.NotFound("No UPI payment reference found", nil)
+	}
+
+	request, err := s.paymentRepo.GetUPIRequestByReference(ctx, ref)
+	if err != nil {
+		if errors.Is(err, repository.ErrUPIRequestNotFound) {
+			return nil, apperr.NotFound("UPI payment request not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch UPI payment request", err)
+	}
+	return request, nil
+}
+
+func (s *paymentService) authorized(ctx context.Context, actor *model.User, action policy.Action, leaseID uuid.UUID) (*model.Lease, error) {
+	lease, err := s.leaseRepo.GetByID(ctx, leaseID)
+	if err != nil {
+		if errors.Is(err, repository.ErrLeaseNotFound) {
+			return nil, apperr.NotFound("Lease not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch lease", err)
+	}
+	if err := policy.Authorize(actor, action, policy.ForLease(lease)); err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+func (s *paymentService) authorizedUser(ctx context.Context, actor *model.User, action policy.Action, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return apperr.NotFound("User not found", err)
+		}
+		return apperr.Internal("Failed to fetch user", err)
+	}
+	return policy.Authorize(actor, action, policy.ForUser(user))
+}
+
+// newUPIReference returns a random transaction reference, e.g. RNT7KQ2M9XD4HPA
+func newUPIReference() (string, error) {
+	random := make([]byte, upiReferenceLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	ref := make([]byte, upiReferenceLength)
+	for i, b := range random {
+		ref[i] = upiReferenceAlphabet[int(b)%len(upiReferenceAlphabet)]
+	}
+	return upiReferencePrefix + string(ref), nil
+}
@@ -0,0 +1,430 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PropertyService interface {
+	Create(ctx context.Context, actor *model.User, input CreatePropertyInput) (*model.Property, error)
+	GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Property, error)
+	List(ctx context.Context, actor *model.User, limit, offset int) ([]model.Property, int64, error)
+	Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdatePropertyInput) (*model.Property, error)
+	Delete(ctx context.Context, actor *model.User, id uuid.UUID) error
+	AddCoOwner(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Property, error)
+	RemoveCoOwner(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Property, error)
+	AssignManager(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Property, error)
+	RemoveManager(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Property, error)
+}
+
+type CreatePropertyInput struct {
+	OwnerID         *uuid.UUID // admin only; defaults to the actor
+	Name            string
+	PropertyType    string
+	AddressLine1    string
+	AddressLine2    string
+	Locality        string
+	City            string
+	State           string
+	Pincode         string
+	BHK             int
+	CarpetAreaSqft  float64
+	BuiltUpAreaSqft *float64
+	Furnishing      string
+	Floor           int
+	TotalFloors     *int
+	Parking         string
+}
+
+type UpdatePropertyInput struct {
+	Name            *string
+	PropertyType    *string
+	AddressLine1    *string
+	AddressLine2    *string
+	Locality        *string
+	City            *string
+	State           *string
+	Pincode         *string
+	BHK             *int
+	CarpetAreaSqft  *float64
+	BuiltUpAreaSqft *float64
+	Furnishing      *string
+	Floor           *int
+	TotalFloors     *int
+	Parking         *string
+	OccupancyStatus *string
+}
+
+func (in UpdatePropertyInput) changesAddress() bool {
+	return in.AddressLine1 != nil || in.AddressLine2 != nil || in.Locality != nil ||
+		in.City != nil || in.State != nil || in.Pincode != nil
+}
+
+type propertyService struct {
+	db           *gorm.DB
+	propertyRepo repository.PropertyRepository
+	userRepo     repository.UserRepository
+	leaseRepo    repository.LeaseRepository
+}
+
+func NewPropertyService(db *gorm.DB, propertyRepo repository.PropertyRepository, userRepo repository.UserRepository, leaseRepo repository.LeaseRepository) PropertyService {
+	return &propertyService{
+		db:           db,
+		propertyRepo: propertyRepo,
+		userRepo:     userRepo,
+		leaseRepo:    leaseRepo,
+	}
+}
+
+func (s *propertyService) Create(ctx context.Context, actor *model.User, input CreatePropertyInput) (*model.Property, error) {
+	if err := policy.AuthorizeCreate(actor, policy.ResourceProperty); err != nil {
+		return nil, err
+	}
+
+	ownerID := actor.ID
+	if input.OwnerID != nil && actor.IsAdmin() {
+		if _, err := s.userRepo.GetByID(ctx, *input.OwnerID); err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return nil, apperr.NotFound("Owner not found", err)
+			}
+			return nil, apperr.Internal("Failed to verify owner", err)
+		}
+		ownerID = *input.OwnerID
+	}
+
+	parking := input.Parking
+	if parking == "" {
+		parking = model.ParkingNone
+	}
+
+	property := &model.Property{
+		ID:           uuid.New(),
+		OwnerID:      ownerID,
+		Name:         input.Name,
+		PropertyType: input.PropertyType,
+		Address: model.Address{
+			AddressLine1: input.AddressLine1,
+			AddressLine2: input.AddressLine2,
+			Locality:     input.Locality,
+			City:         input.City,
+			State:        strings.ToUpper(input.State),
+			Pincode:      input.Pincode,
+		},
+		BHK:             input.BHK,
+		CarpetAreaSqft:  input.CarpetAreaSqft,
+		BuiltUpAreaSqft: input.BuiltUpAreaSqft,
+		Furnishing:      input.Furnishing,
+		Floor:           input.Floor,
+		TotalFloors:     input.TotalFloors,
+		Parking:         parking,
+		OccupancyStatus: model.OccupancyVacant,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	if err := validatePropertyLayout(property); err != nil {
+		return nil, err
+	}
+
+	if err := s.propertyRepo.Create(ctx, property); err != nil {
+		return nil, apperr.Internal("Failed to create property", err)
+	}
+
+	return property, nil
+}
+
+func (s *propertyService) GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Property, error) {
+	property, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.Authorize(actor, policy.ActionRead, policy.ForProperty(property)); err != nil {
+		return nil, err
+	}
+
+	return property, nil
+}
+
+// List returns every property for admins, and otherwise only the properties
+// the actor owns, co-owns or manages
+func (s *propertyService) List(ctx context.Context, actor *model.User, limit, offset int) ([]model.Property, int64, error) {
+	var (
+		properties []model.Property
+		total      int64
+		err        error
+	)
+
+	if actor.IsAdmin() {
+		properties, total, err = s.propertyRepo.List(ctx, limit, offset)
+	} else {
+		properties, total, err = s.propertyRepo.ListAccessibleBy(ctx, actor.ID, limit, offset)
+	}
+	if err != nil {
+		return nil, 0, apperr.Internal("Failed to fetch properties", err)
+	}
+
+	return properties, total, nil
+}
+
+func (s *propertyService) Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdatePropertyInput) (*model.Property, error) {
+	property, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.Authorize(actor, policy.ActionUpdate, policy.ForProperty(property)); err != nil {
+		return nil, err
+	}
+
+	if property.BuildingID != nil && input.changesAddress() {
+		return nil, apperr.Invalid("The address of a unit is managed on its building", nil)
+	}
+
+	if input.Name != nil {
+		property.Name = *input.Name
+	}
+	if input.PropertyType != nil {
+		property.PropertyType = *input.PropertyType
+	}
+	if input.AddressLine1 != nil {
+		property.AddressLine1 = *input.AddressLine1
+	}
+	if input.AddressLine2 != nil {
+		property.AddressLine2 = *input.AddressLine2
+	}
+	if input.Locality != nil {
+		property.Locality = *input.Locality
+	}
+	if input.City != nil {
+		property.City = *input.City
+	}
+	if input.State != nil {
+		property.State = strings.ToUpper(*input.State)
+	}
+	if input.Pincode != nil {
+		property.Pincode = *input.Pincode
+	}
+	if input.BHK != nil {
+		property.BHK = *input.BHK
+	}
+	if input.CarpetAreaSqft != nil {
+		property.CarpetAreaSqft = *input.CarpetAreaSqft
+	}
+	if input.BuiltUpAreaSqft != nil {
+		property.BuiltUpAreaSqft = input.BuiltUpAreaSqft
+	}
+	if input.Furnishing != nil {
+		property.Furnishing = *input.Furnishing
+	}
+	if input.Floor != nil {
+		property.Floor = *input.Floor
+	}
+	if input.TotalFloors != nil {
+		property.TotalFloors = input.TotalFloors
+	}
+	if input.Parking != nil {
+		property.Parking = *input.Parking
+	}
+	if input.OccupancyStatus != nil {
+		property.OccupancyStatus = *input.OccupancyStatus
+	}
+	property.UpdatedAt = time.Now()
+
+	if err := validatePropertyLayout(property); err != nil {
+		return nil, err
+	}
+
+	if err := s.propertyRepo.Update(ctx, property); err != nil {
+		return nil, apperr.Internal("Failed to update property", err)
+	}
+
+	return property, nil
+}
+
+func (s *propertyService) Delete(ctx context.Context, actor *model.User, id uuid.UUID) error {
+	property, err := s.fetch(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := policy.Authorize(actor, policy.ActionDelete, policy.ForProperty(property)); err != nil {
+		return err
+	}
+
+	leases, err := s.leaseRepo.CountByProperty(ctx, id)
+	if err != nil {
+		return apperr.Internal("Failed to check property leases", err)
+	}
+	if leases > 0 {
+		return apperr.Conflict("Properties with leases cannot be deleted", nil)
+	}
+
+	if err := s.propertyRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrPropertyNotFound) {
+			return apperr.NotFound("Property not found", err)
+		}
+		return apperr.Internal("Failed to delete property", err)
+	}
+	return nil
+}
+
+func (s *propertyService) AddCoOwner(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Property, error) {
+	property, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.Authorize(actor, policy.ActionManage, policy.ForProperty(property)); err != nil {
+		return nil, err
+	}
+	if userID == property.OwnerID {
+		return nil, apperr.Invalid("The owner cannot also be a co-owner", nil)
+	}
+	user, err := s.fetchUserWithRole(ctx, userID, model.RoleOwner, model.RoleCoOwner, model.RoleTenant)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repos := repository.NewRepositories(tx)
+		if err := grantRole(ctx, repos, user, model.RoleCoOwner); err != nil {
+			return err
+		}
+		if err := repos.Property.AddCoOwner(ctx, &model.PropertyCoOwner{
+			PropertyID: property.ID,
+			UserID:     userID,
+			CreatedAt:  time.Now(),
+		}); err != nil {
+			return apperr.Internal("Failed to add co-owner", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fetch(ctx, id)
+}
+
+func (s *propertyService) RemoveCoOwner(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Property, error) {
+	property, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.Authorize(actor, policy.ActionManage, policy.ForProperty(property)); err != nil {
+		return nil, err
+	}
+
+	if err := s.propertyRepo.RemoveCoOwner(ctx, property.ID, userID); err != nil {
+		if errors.Is(err, repository.ErrCoOwnerNotFound) {
+			return nil, apperr.NotFound("Co-owner not found", err)
+		}
+		return nil, apperr.Internal("Failed to remove co-owner", err)
+	}
+
+	return s.fetch(ctx, id)
+}
+
+func (s *propertyService) AssignManager(ctx context.Context, actor *model.User, id, userID uuid.UUID) (*model.Property, error) {
+	property, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.Authorize(actor, policy.ActionManage, policy.ForProperty(property)); err != nil {
+		return nil, err
+	}
+	user, err := s.fetchUserWithRole(ctx, userID, model.RoleManager, model.RoleTenant)
+	if err != nil {
+		return nil, err
+	}
+
+	property.ManagerID = &userID
+	property.UpdatedAt = time.Now()
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repos := repository.NewRepositories(tx)
+		if err := grantRole(ctx, repos, user, model.RoleManager); err != nil {
+			return err
+		}
+		if err := repos.Property.Update(ctx, property); err != nil {
+			return apperr.Internal("Failed to assign manager", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return property, nil
+}
+
+func (s *propertyService) RemoveManager(ctx context.Context, actor *model.User, id uuid.UUID) (*model.Property, error) {
+	property, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.Authorize(actor, policy.ActionManage, policy.ForProperty(property)); err != nil {
+		return nil, err
+	}
+
+	property.ManagerID = nil
+	property.UpdatedAt = time.Now()
+
+	if err := s.propertyRepo.Update(ctx, property); err != nil {
+		return nil, apperr.Internal("Failed to remove manager", err)
+	}
+
+	return property, nil
+}
+
+func (s *propertyService) fetch(ctx context.Context, id uuid.UUID) (*model.Property, error) {
+	property, err := s.propertyRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPropertyNotFound) {
+			return nil, apperr.NotFound("Property not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch property", err)
+	}
+	return property, nil
+}
+
+func (s *propertyService) fetchUserWithRole(ctx context.Context, userID uuid.UUID, roles ...string) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, apperr.NotFound("User not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch user", err)
+	}
+	for _, role := range roles {
+		if user.Role == role {
+			return user, nil
+		}
+	}
+	return nil, apperr.Invalid("User must have one of the roles: "+strings.Join(roles, ", "), nil)
+}
+
+// validatePropertyLayout checks rules that span several fields
+func validatePropertyLayout(p *model.Property) error {
+	if p.BuiltUpAreaSqft != nil && *p.BuiltUpAreaSqft < p.CarpetAreaSqft {
+		return apperr.Invalid("Built-up area cannot be smaller than carpet area", nil)
+	}
+	if p.TotalFloors != nil && p.Floor > *p.TotalFloors {
+		return apperr.Invalid("Floor cannot be above the building's total floors", nil)
+	}
+	return nil
+}
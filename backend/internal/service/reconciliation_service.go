@@ -0,0 +1,648 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"backend/internal/bankstatement"
+	"backend/internal/clausetext"
+	"backend/internal/config"
+	"backend/internal/model"
+	"backend/internal/policy"
+	"backend/internal/repository"
+	"backend/pkg/apperr"
+	"backend/pkg/inr"
+
+	"github.com/google/uuid"
+)
+
+type ReconciliationService interface {
+	Banks() []bankstatement.Bank
+	ImportStatement(ctx context.Context, actor *model.User, input ImportBankStatementInput) (*model.BankStatementImport, error)
+	ListImports(ctx context.Context, actor *model.User, limit, offset int) ([]model.BankStatementImport, int64, error)
+	ListTransactions(ctx context.Context, actor *model.User, filter repository.BankTransactionFilter, limit, offset int) ([]model.BankTransaction, int64, error)
+	ConfirmMatch(ctx context.Context, actor *model.User, id uuid.UUID, input ConfirmBankMatchInput) (*model.BankTransaction, error)
+	IgnoreTransaction(ctx context.Context, actor *model.User, id uuid.UUID) (*model.BankTransaction, error)
+}
+
+type ImportBankStatementInput struct {
+	Bank     string
+	FileName string
+	Size     int64
+	Content  io.Reader
+}
+
+type ConfirmBankMatchInput struct {
+	LeaseID   uuid.UUID
+	RentDueID *uuid.UUID
+}
+
+// Match scores, out of 100. A credit is posted without review when its best
+// match scores autoMatchScore or more and leads the next lease's best by
+// autoMatchLead; it goes to the review queue from reviewScore.
+const (
+	autoMatchScore = 80
+	autoMatchLead  = 15
+	reviewScore    = 40
+	// candidateScore is the least a lease must score to be offered for review
+	candidateScore = 25
+	maxCandidates  = 5
+
+	scoreUPIReference = 100
+	scoreOutstanding  = 50
+	scoreClearsDues   = 45
+	scoreRentAmount   = 35
+	scorePartPayment  = 15
+	scoreDate         = 20
+	scoreName         = 30
+
+	// matchLookbackDays is how long after falling due rent may still be paid
+	matchLookbackDays = 90
+)
+
+type reconciliationService struct {
+	services  *Services
+	bankRepo  repository.BankStatementRepository
+	leaseRepo repository.LeaseRepository
+	formats   *bankstatement.Formats
+	maxUpload int64
+	window    int
+}
+
+func NewReconciliationService(
+	services *Services,
+	bankRepo repository.BankStatementRepository,
+	leaseRepo repository.LeaseRepository,
+	formats *bankstatement.Formats,
+	storageCfg config.StorageConfig,
+	cfg config.BankStatementConfig,
+) ReconciliationService {
+	return &reconciliationService{
+		services:  services,
+		bankRepo:  bankRepo,
+		leaseRepo: leaseRepo,
+		formats:   formats,
+		maxUpload: int64(storageCfg.MaxUploadMB) << 20,
+		window:    cfg.DateWindowDays,
+	}
+}
+
+func (s *reconciliationService) Banks() []bankstatement.Bank {
+	return s.formats.Banks()
+}
+
+// ImportStatement reads the credits of a bank statement and matches each to
+// the rent dues of the leases the actor manages. Confident matches are posted
+// to the lease's ledger as payments; the rest wait in the review queue or stay
+// unmatched. Credits imported before, as overlapping statements repeat them,
+// are counted and passed over.
+func (s *reconciliationService) ImportStatement(ctx context.Context, actor *model.User, input ImportBankStatementInput) (*model.BankStatementImport, error) {
+	if input.Size > s.maxUpload {
+		return nil, apperr.Invalid(fmt.Sprintf("Statement must not exceed %d MB", s.maxUpload>>20), nil)
+	}
+	data, err := io.ReadAll(io.LimitReader(input.Content, s.maxUpload+1))
+	if err != nil {
+		return nil, apperr.Internal("Failed to read statement", err)
+	}
+	if int64(len(data)) > s.maxUpload {
+		return nil, apperr.Invalid(fmt.Sprintf("Statement must not exceed %d MB", s.maxUpload>>20), nil)
+	}
+
+	statement, err := s.formats.Parse(input.Bank, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, bankstatement.ErrUnknownBank):
+			return nil, apperr.Invalid("Unknown bank", err)
+		case errors.Is(err, bankstatement.ErrUnsupportedFile), errors.Is(err, bankstatement.ErrNoHeader):
+			return nil, apperr.Invalid("The statement could not be read: "+err.Error(), err)
+		}
+		return nil, apperr.Internal("Failed to read statement", err)
+	}
+	credits := statement.Credits
+	slices.SortStableFunc(credits, func(a, b bankstatement.Credit) int { return a.Date.Compare(b.Date) })
+
+	leases, err := s.candidateLeases(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	record := &model.BankStatementImport{
+		ID:              uuid.New(),
+		UserID:          actor.ID,
+		Bank:            statement.Bank,
+		FileName:        input.FileName,
+		FileSHA256:      hex.EncodeToString(sum[:]),
+		FormatsVersion:  s.formats.Version(),
+		CreditCount:     len(credits),
+		DebitCount:      statement.Debits,
+		UnreadableCount: statement.Unreadable,
+		CreatedAt:       time.Now(),
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		if err := tx.repos.Bank.CreateImport(ctx, record); err != nil {
+			return apperr.Internal("Failed to save statement import", err)
+		}
+
+		seen := make(map[string]int)
+		for _, credit := range credits {
+			txn := newBankTransaction(actor.ID, record.ID, credit, seen)
+			stored, err := tx.repos.Bank.CreateTransaction(ctx, txn)
+			if err != nil {
+				return apperr.Internal("Failed to save bank transaction", err)
+			}
+			if !stored {
+				record.DuplicateCount++
+				continue
+			}
+
+			if err := s.reconcile(ctx, tx, actor, txn, leases); err != nil {
+				return err
+			}
+			switch txn.Status {
+			case model.BankTransactionMatched:
+				record.MatchedCount++
+			case model.BankTransactionAlreadyRecorded:
+				record.AlreadyRecordedCount++
+			case model.BankTransactionReview:
+				record.ReviewCount++
+			default:
+				record.UnmatchedCount++
+			}
+		}
+
+		if err := tx.repos.Bank.SaveImport(ctx, record); err != nil {
+			return apperr.Internal("Failed to save statement import", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *reconciliationService) ListImports(ctx context.Context, actor *model.User, limit, offset int) ([]model.BankStatementImport, int64, error) {
+	imports, total, err := s.bankRepo.ListImports(ctx, actor.ID, limit, offset)
+	if err != nil {
+		return nil, 0, apperr.Internal("Failed to fetch statement imports", err)
+	}
+	return imports, total, nil
+}
+
+// ListTransactions returns the credits the actor has imported; filtered on
+// the review status it is the review queue
+func (s *reconciliationService) ListTransactions(ctx context.Context, actor *model.User, filter repository.BankTransactionFilter, limit, offset int) ([]model.BankTransaction, int64, error) {
+	if filter.Status != "" && !slices.Contains(model.BankTransactionStatuses, filter.Status) {
+		return nil, 0, apperr.Invalid("Unknown bank transaction status", nil)
+	}
+	txns, total, err := s.bankRepo.ListTransactions(ctx, actor.ID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, apperr.Internal("Failed to fetch bank transactions", err)
+	}
+	return txns, total, nil
+}
+
+// ConfirmMatch posts a credit to the lease's ledger as a payment, whether or
+// not the lease was among the matches found for it. A credit already posted
+// can be confirmed again once its entry has been reversed.
+func (s *reconciliationService) ConfirmMatch(ctx context.Context, actor *model.User, id uuid.UUID, input ConfirmBankMatchInput) (*model.BankTransaction, error) {
+	lease, err := s.authorized(ctx, actor, input.LeaseID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasLedger(lease) {
+		return nil, apperr.Invalid("Money can be recorded once the lease has been activated", nil)
+	}
+	if input.RentDueID != nil {
+		if _, err := s.leaseRepo.GetDue(ctx, lease.ID, *input.RentDueID); err != nil {
+			if errors.Is(err, repository.ErrRentDueNotFound) {
+				return nil, apperr.NotFound("Rent due not found", err)
+			}
+			return nil, apperr.Internal("Failed to fetch rent due", err)
+		}
+	}
+
+	err = s.services.Transaction(func(tx *Services) error {
+		txn, err := s.lockOpen(ctx, tx, actor, id)
+		if err != nil {
+			return err
+		}
+		_, err = tx.repos.Ledger.GetPaymentByReference(ctx, []uuid.UUID{lease.ID}, ledgerReference(txn))
+		if err == nil {
+			if txn.UTR != "" {
+				return apperr.Conflict("A payment with UTR "+txn.UTR+" is already recorded on this lease", nil)
+			}
+			return apperr.Conflict("This credit is already recorded on this lease", nil)
+		}
+		if !errors.Is(err, repository.ErrJournalEntryNotFound) {
+			return apperr.Internal("Failed to fetch ledger", err)
+		}
+
+		entry, err := tx.Ledger.PostSettlement(ctx, lease, settlementOf(txn), &actor.ID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		txn.Status = model.BankTransactionMatched
+		txn.LeaseID, txn.RentDueID, txn.JournalEntryID = &lease.ID, input.RentDueID, &entry.ID
+		txn.ReviewedBy, txn.ReviewedAt = &actor.ID, &now
+		txn.UpdatedAt = now
+		if err := tx.repos.Bank.SaveTransaction(ctx, txn); err != nil {
+			return apperr.Internal("Failed to save bank transaction", err)
+		}
+		if err := tx.repos.Bank.DeleteCandidates(ctx, txn.ID); err != nil {
+			return apperr.Internal("Failed to save bank transaction", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.fetchTransaction(ctx, actor, id)
+}
+
+// IgnoreTransaction takes a credit that is not rent out of the review queue
+func (s *reconciliationService) IgnoreTransaction(ctx context.Context, actor *model.User, id uuid.UUID) (*model.BankTransaction, error) {
+	err := s.services.Transaction(func(tx *Services) error {
+		txn, err := s.lockOpen(ctx, tx, actor, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		txn.Status = model.BankTransactionIgnored
+		txn.LeaseID, txn.RentDueID, txn.JournalEntryID = nil, nil, nil
+		txn.ReviewedBy, txn.ReviewedAt = &actor.ID, &now
+		txn.UpdatedAt = now
+		if err := tx.repos.Bank.SaveTransaction(ctx, txn); err != nil {
+			return apperr.Internal("Failed to save bank transaction", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.fetchTransaction(ctx, actor, id)
+}
+
+// lockOpen returns the actor's transaction locked for update, refusing one
+// whose payment is on a ledger
+func (s *reconciliationService) lockOpen(ctx context.Context, tx *Services, actor *model.User, id uuid.UUID) (*model.BankTransaction, error) {
+	txn, err := tx.repos.Bank.LockTransaction(ctx, actor.ID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrBankTransactionNotFound) {
+			return nil, apperr.NotFound("Bank transaction not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch bank transaction", err)
+	}
+
+	switch txn.Status {
+	case model.BankTransactionAlreadyRecorded:
+		return nil, apperr.Conflict("Bank transaction was already recorded on the ledger", nil)
+	case model.BankTransactionMatched:
+		_, err := tx.repos.Ledger.GetReversal(ctx, *txn.JournalEntryID)
+		if errors.Is(err, repository.ErrJournalEntryNotFound) {
+			return nil, apperr.Conflict("Bank transaction has already been posted to the ledger", nil)
+		}
+		if err != nil {
+			return nil, apperr.Internal("Failed to fetch ledger", err)
+		}
+	}
+	return txn, nil
+}
+
+func (s *reconciliationService) fetchTransaction(ctx context.Context, actor *model.User, id uuid.UUID) (*model.BankTransaction, error) {
+	txn, err := s.bankRepo.GetTransaction(ctx, actor.ID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrBankTransactionNotFound) {
+			return nil, apperr.NotFound("Bank transaction not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch bank transaction", err)
+	}
+	return txn, nil
+}
+
+func (s *reconciliationService) authorized(ctx context.Context, actor *model.User, leaseID uuid.UUID) (*model.Lease, error) {
+	lease, err := s.leaseRepo.GetByID(ctx, leaseID)
+	if err != nil {
+		if errors.Is(err, repository.ErrLeaseNotFound) {
+			return nil, apperr.NotFound("Lease not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch lease", err)
+	}
+	if err := policy.Authorize(actor, policy.ActionUpdate, policy.ForLease(lease)); err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// leaseDues is a lease a credit may be for, with its dues still open, oldest
+// first. Outstanding amounts are kept as credits of the statement are posted.
+type leaseDues struct {
+	lease       *model.Lease
+	dues        []model.RentDue
+	outstanding []int64
+}
+
+// settle spreads a payment over the dues, oldest first, as the ledger does
+func (l *leaseDues) settle(amount int64) {
+	for i := range l.outstanding {
+		paid := min(amount, l.outstanding[i])
+		l.outstanding[i] -= paid
+		amount -= paid
+	}
+}
+
+// candidateLeases returns the running leases the actor manages and may
+// record payments on
+func (s *reconciliationService) candidateLeases(ctx context.Context, actor *model.User) ([]*leaseDues, error) {
+	leases, err := s.leaseRepo.ListManagedBy(ctx, actor.ID)
+	if err != nil {
+		return nil, apperr.Internal("Failed to fetch leases", err)
+	}
+
+	var candidates []*leaseDues
+	for i := range leases {
+		lease := &leases[i]
+		if !hasLedger(lease) || policy.Authorize(actor, policy.ActionUpdate, policy.ForLease(lease)) != nil {
+			continue
+		}
+		dues, err := s.leaseRepo.ListDues(ctx, lease.ID)
+		if err != nil {
+			return nil, apperr.Internal("Failed to fetch rent dues", err)
+		}
+		candidate := &leaseDues{lease: lease}
+		for _, due := range dues {
+			if outstanding := due.OutstandingPaise(); outstanding > 0 {
+				candidate.dues = append(candidate.dues, due)
+				candidate.outstanding = append(candidate.outstanding, outstanding)
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// newBankTransaction makes the transaction of a credit, unmatched. Its
+// fingerprint is the UTR, or failing that the credit's details and how many
+// identical credits came before it in the statement.
+func newBankTransaction(userID, importID uuid.UUID, credit bankstatement.Credit, seen map[string]int) *model.BankTransaction {
+	fingerprint := "utr:" + credit.UTR
+	if credit.UTR == "" {
+		key := fmt.Sprintf("%s|%d|%s|%s", credit.Date.Format(time.DateOnly), credit.AmountPaise, strings.ToUpper(credit.Narration), credit.Reference)
+		sum := sha256.Sum256(fmt.Appendf(nil, "%s|%d", key, seen[key]))
+		seen[key]++
+		fingerprint = "row:" + hex.EncodeToString(sum[:16])
+	}
+
+	now := time.Now()
+	return &model.BankTransaction{
+		ID:           uuid.New(),
+		UserID:       userID,
+		ImportID:     importID,
+		StatementRow: credit.Row,
+		TxnDate:      credit.Date,
+		AmountPaise:  credit.AmountPaise,
+		Narration:    credit.Narration,
+		Reference:    truncate(credit.Reference, 100),
+		UTR:          credit.UTR,
+		PayerName:    truncate(credit.PayerName, 100),
+		Fingerprint:  fingerprint,
+		Status:       model.BankTransactionUnmatched,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// bankMatch is a lease's best due for a credit
+type bankMatch struct {
+	leaseDues *leaseDues
+	due       *model.RentDue
+	score     int
+	reasons   []string
+}
+
+// reconcile decides what a newly imported credit is: already on a ledger
+// under its reference, posted to the lease it clearly matches, or left for review
+func (s *reconciliationService) reconcile(ctx context.Context, tx *Services, actor *model.User, txn *model.BankTransaction, leases []*leaseDues) error {
+	ids := make([]uuid.UUID, 0, len(leases))
+	for _, l := range leases {
+		ids = append(ids, l.lease.ID)
+	}
+	if recorded, err := s.markRecorded(ctx, tx, txn, ids); recorded || err != nil {
+		return err
+	}
+
+	upiDue, err := s.upiRequestDue(ctx, tx, txn.Narration)
+	if err != nil {
+		return err
+	}
+	var matches []bankMatch
+	for _, l := range leases {
+		if match, ok := s.bestMatch(txn, l, upiDue); ok {
+			matches = append(matches, match)
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b bankMatch) int { return b.score - a.score })
+
+	if len(matches) == 0 || matches[0].score < candidateScore {
+		return s.saveTransaction(ctx, tx, txn)
+	}
+	best := matches[0]
+	txn.MatchScore, txn.MatchReason = best.score, strings.Join(best.reasons, "; ")
+
+	if best.score >= autoMatchScore && (len(matches) == 1 || best.score-matches[1].score >= autoMatchLead) {
+		entry, err := tx.Ledger.PostSettlement(ctx, best.leaseDues.lease, settlementOf(txn), &actor.ID)
+		if errors.Is(err, repository.ErrDuplicatePaymentReference) {
+			// posted by someone importing the same statement meanwhile
+			if recorded, err := s.markRecorded(ctx, tx, txn, ids); recorded || err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return err
+		}
+		best.leaseDues.settle(txn.AmountPaise)
+		txn.Status = model.BankTransactionMatched
+		txn.LeaseID, txn.RentDueID, txn.JournalEntryID = &best.leaseDues.lease.ID, &best.due.ID, &entry.ID
+		return s.saveTransaction(ctx, tx, txn)
+	}
+
+	if best.score >= reviewScore {
+		txn.Status = model.BankTransactionReview
+	}
+	if err := s.saveTransaction(ctx, tx, txn); err != nil {
+		return err
+	}
+	var candidates []model.BankMatchCandidate
+	for _, match := range matches[:min(len(matches), maxCandidates)] {
+		if match.score < candidateScore {
+			break
+		}
+		candidates = append(candidates, model.BankMatchCandidate{
+			ID:            uuid.New(),
+			TransactionID: txn.ID,
+			LeaseID:       match.leaseDues.lease.ID,
+			RentDueID:     &match.due.ID,
+			Score:         match.score,
+			Reasons:       strings.Join(match.reasons, "; "),
+		})
+	}
+	if err := tx.repos.Bank.CreateCandidates(ctx, candidates); err != nil {
+		return apperr.Internal("Failed to save match candidates", err)
+	}
+	return nil
+}
+
+// markRecorded marks the credit already recorded when one of the leases'
+// ledgers has a payment under its reference, reporting whether it had
+func (s *reconciliationService) markRecorded(ctx context.Context, tx *Services, txn *model.BankTransaction, leaseIDs []uuid.UUID) (bool, error) {
+	entry, err := tx.repos.Ledger.GetPaymentByReference(ctx, leaseIDs, ledgerReference(txn))
+	if errors.Is(err, repository.ErrJournalEntryNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, apperr.Internal("Failed to fetch ledger", err)
+	}
+	txn.Status = model.BankTransactionAlreadyRecorded
+	txn.LeaseID, txn.JournalEntryID = &entry.LeaseID, &entry.ID
+	txn.MatchScore, txn.MatchReason = 100, "recorded on the ledger under "+entry.Reference
+	return true, s.saveTransaction(ctx, tx, txn)
+}
+
+func (s *reconciliationService) saveTransaction(ctx context.Context, tx *Services, txn *model.BankTransaction) error {
+	txn.UpdatedAt = time.Now()
+	if err := tx.repos.Bank.SaveTransaction(ctx, txn); err != nil {
+		return apperr.Internal("Failed to save bank transaction", err)
+	}
+	return nil
+}
+
+// upiRequestDue returns the due of the UPI payment request whose reference
+// the narration carries, or nil when it carries none
+func (s *reconciliationService) upiRequestDue(ctx context.Context, tx *Services, narration string) (*uuid.UUID, error) {
+	request, err := tx.Payment.MatchUPIRequest(ctx, narration)
+	if err != nil {
+		var appErr *apperr.AppError
+		if errors.As(err, &appErr) && appErr.Code == apperr.CodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request.RentDueID, nil
+}
+
+// bestMatch scores the credit against each open due of the lease that fell
+// due from matchLookbackDays before it to the date window after it, and
+// returns the best. The amount scores most: what a due has outstanding, what
+// clears it and the dues before it, the full rent, or less as a part payment.
+// Closeness to the due date and the payer's name resembling a tenant's add
+// to it; a UPI payment reference for one of the dues settles it.
+func (s *reconciliationService) bestMatch(txn *model.BankTransaction, l *leaseDues, upiDue *uuid.UUID) (bankMatch, bool) {
+	names := make([]string, 0, len(l.lease.Tenants))
+	for _, tenant := range l.lease.Tenants {
+		if tenant.User != nil {
+			names = append(names, tenant.User.Name)
+		}
+	}
+	var nameScore int
+	var nameReason string
+	if txn.PayerName != "" {
+		for _, name := range names {
+			similarity := bankstatement.NameSimilarity(txn.PayerName, name)
+			if score := int(similarity * scoreName); score > nameScore {
+				nameScore = score
+				if similarity >= 0.5 {
+					nameReason = fmt.Sprintf("payer %s resembles tenant %s", txn.PayerName, name)
+				}
+			}
+		}
+	}
+
+	var best bankMatch
+	var cumulative int64
+	for i := range l.dues {
+		due := &l.dues[i]
+		outstanding := l.outstanding[i]
+		if outstanding <= 0 {
+			continue
+		}
+		cumulative += outstanding
+		days := int(txn.TxnDate.Sub(due.DueDate).Hours() / 24)
+		if days > matchLookbackDays || -days > s.window {
+			continue
+		}
+
+		match := bankMatch{leaseDues: l, due: due}
+		dueOn := "the rent due on " + due.DueDate.Format(clausetext.DateLayout)
+		switch {
+		case upiDue != nil && *upiDue == due.ID:
+			match.score += scoreUPIReference
+			match.reasons = append(match.reasons, "UPI payment reference of "+dueOn)
+		case txn.AmountPaise == outstanding:
+			match.score += scoreOutstanding
+			match.reasons = append(match.reasons, "amount is what is outstanding on "+dueOn)
+		case txn.AmountPaise == cumulative && i > 0:
+			match.score += scoreClearsDues
+			match.reasons = append(match.reasons, "amount clears the dues up to "+dueOn)
+		case txn.AmountPaise == due.AmountPaise:
+			match.score += scoreRentAmount
+			match.reasons = append(match.reasons, "amount is "+dueOn)
+		case txn.AmountPaise < outstanding:
+			match.score += scorePartPayment
+			match.reasons = append(match.reasons, "part of "+inr.FormatWithSymbol(outstanding)+" outstanding on "+dueOn)
+		}
+
+		distance := max(days, -days)
+		switch {
+		case distance <= s.window:
+			match.score += scoreDate - scoreDate/2*distance/max(s.window, 1)
+			match.reasons = append(match.reasons, fmt.Sprintf("received %d days from the due date", distance))
+		default:
+			match.score += scoreDate / 4
+		}
+
+		match.score += nameScore
+		if nameReason != "" {
+			match.reasons = append(match.reasons, nameReason)
+		}
+		match.score = min(match.score, 100)
+		if match.score > best.score {
+			best = match
+		}
+	}
+	return best, best.due != nil
+}
+
+// ledgerReference is what a credit is recorded under on a ledger: its UTR,
+// or failing that its fingerprint, which is the same whoever imports the
+// statement, so a lease's ledger takes each credit once
+func ledgerReference(txn *model.BankTransaction) string {
+	if txn.UTR != "" {
+		return txn.UTR
+	}
+	return "STMT-" + strings.ToUpper(strings.TrimPrefix(txn.Fingerprint, "row:"))
+}
+
+// settlementOf is the ledger payment of a credit
+func settlementOf(txn *model.BankTransaction) PostLedgerEntryInput {
+	description := "Bank transfer received"
+	if txn.PayerName != "" {
+		description = "Bank transfer from " + txn.PayerName
+	}
+	return PostLedgerEntryInput{
+		Kind:        model.JournalPayment,
+		AmountPaise: txn.AmountPaise,
+		OccurredOn:  txn.TxnDate,
+		Description: description,
+		Reference:   ledgerReference(txn),
+		Source:      model.JournalSourceBankStatement,
+	}
+}
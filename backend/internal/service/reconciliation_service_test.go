@@ -0,0 +1,121 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/model"
+
+	"github.com/google/uuid"
+)
+
+func TestBestMatch(t *testing.T) {
+	date := func(month time.Month, day int) time.Time { return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC) }
+	april := model.RentDue{ID: uuid.New(), DueDate: date(time.April, 1), AmountPaise: 1500000}
+	may := model.RentDue{ID: uuid.New(), DueDate: date(time.May, 1), AmountPaise: 1500000}
+	aprilPart := april
+	aprilPart.PaidPaise = 500000
+	aprilPaid := april
+	aprilPaid.PaidPaise = april.AmountPaise
+
+	tests := []struct {
+		name       string
+		credit     model.BankTransaction
+		dues       []model.RentDue
+		upiDue     *uuid.UUID
+		wantDue    *uuid.UUID
+		wantScore  int
+		wantReason string
+	}{
+		{
+			name:       "outstanding amount near the due date from the tenant",
+			credit:     model.BankTransaction{TxnDate: date(time.April, 3), AmountPaise: 1500000, PayerName: "RAVI KUMAR"},
+			dues:       []model.RentDue{april, may},
+			wantDue:    &april.ID,
+			wantScore:  99,
+			wantReason: "payer RAVI KUMAR resembles tenant Ravi Kumar",
+		},
+		{
+			name:       "amount clearing two dues",
+			credit:     model.BankTransaction{TxnDate: date(time.May, 2), AmountPaise: 3000000},
+			dues:       []model.RentDue{april, may},
+			wantDue:    &may.ID,
+			wantScore:  65,
+			wantReason: "amount clears the dues up to",
+		},
+		{
+			name:       "full rent on a partly paid due",
+			credit:     model.BankTransaction{TxnDate: date(time.April, 1), AmountPaise: 1500000},
+			dues:       []model.RentDue{aprilPart, may},
+			wantDue:    &april.ID,
+			wantScore:  55,
+			wantReason: "amount is the rent due on",
+		},
+		{
+			name:       "part payment from an abbreviated name",
+			credit:     model.BankTransaction{TxnDate: date(time.April, 1), AmountPaise: 500000, PayerName: "R KUMAR"},
+			dues:       []model.RentDue{april},
+			wantDue:    &april.ID,
+			wantScore:  57,
+			wantReason: "payer R KUMAR resembles tenant Ravi Kumar",
+		},
+		{
+			name:      "UPI payment reference",
+			credit:    model.BankTransaction{TxnDate: date(time.May, 1), AmountPaise: 100},
+			dues:      []model.RentDue{april, may},
+			upiDue:    &may.ID,
+			wantDue:   &may.ID,
+			wantScore: 100,
+		},
+		{
+			name:      "someone else paying an unrelated amount",
+			credit:    model.BankTransaction{TxnDate: date(time.April, 20), AmountPaise: 2000000, PayerName: "SURESH NAIR"},
+			dues:      []model.RentDue{april},
+			wantDue:   &april.ID,
+			wantScore: 5,
+		},
+		{
+			name:   "received long before the due date",
+			credit: model.BankTransaction{TxnDate: date(time.January, 1), AmountPaise: 1500000},
+			dues:   []model.RentDue{april, may},
+		},
+		{
+			name:   "received long after the due date",
+			credit: model.BankTransaction{TxnDate: date(time.August, 15), AmountPaise: 1500000},
+			dues:   []model.RentDue{april, may},
+		},
+		{
+			name:   "only a paid due in range",
+			credit: model.BankTransaction{TxnDate: date(time.April, 1), AmountPaise: 1500000},
+			dues:   []model.RentDue{aprilPaid, may},
+		},
+	}
+
+	s := &reconciliationService{window: 15}
+	lease := &model.Lease{ID: uuid.New(), Tenants: []model.LeaseTenant{{User: &model.User{Name: "Ravi Kumar"}}}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &leaseDues{lease: lease}
+			for _, due := range tt.dues {
+				l.dues = append(l.dues, due)
+				l.outstanding = append(l.outstanding, due.OutstandingPaise())
+			}
+
+			match, ok := s.bestMatch(&tt.credit, l, tt.upiDue)
+			if ok != (tt.wantDue != nil) {
+				t.Fatalf("bestMatch found = %v, want %v", ok, tt.wantDue != nil)
+			}
+			if !ok {
+				return
+			}
+			if match.due.ID != *tt.wantDue || match.score != tt.wantScore {
+				t.Errorf("bestMatch = due %s scoring %d (%s), want due %s scoring %d",
+					match.due.DueDate.Format(time.DateOnly), match.score, strings.Join(match.reasons, "; "), tt.wantDue, tt.wantScore)
+			}
+			if reasons := strings.Join(match.reasons, "; "); !strings.Contains(reasons, tt.wantReason) {
+				t.Errorf("reasons %q do not mention %q", reasons, tt.wantReason)
+			}
+		})
+	}
+}
@@ -1,27 +1,78 @@
 package service
 
 import (
+	"backend/internal/auth"
+	"backend/internal/bankstatement"
+	"backend/internal/compliance"
+	"backend/internal/config"
+	"backend/internal/esign"
+	"backend/internal/estamp"
+	"backend/internal/gateway"
+	"backend/internal/leasepdf"
+	"backend/internal/notify"
+	"backend/internal/policeform"
 	"backend/internal/repository"
+	"backend/internal/stampduty"
+	"backend/internal/storage"
 
 	"gorm.io/gorm"
 )
 
+// Deps holds non-database collaborators shared by all services
+type Deps struct {
+	Config      *config.Config
+	Tokens      *auth.TokenManager
+	SMS         notify.SMSSender
+	Storage     storage.Storage
+	StampDuty   *stampduty.Calculator
+	Compliance  *compliance.Checker
+	Fonts       *leasepdf.Fonts
+	PoliceForms *policeform.Formats
+	EStamp      estamp.EStampProvider
+	ESign       esign.ESignProvider
+	Gateway     gateway.PaymentGateway
+	BankFormats *bankstatement.Formats
+}
+
 type Services struct {
-	User UserService
-	db   *gorm.DB
+	User     UserService
+	Auth     AuthService
+	Property PropertyService
+	Building BuildingService
+	Clause   ClauseService
+	Lease    LeaseService
+	Ledger   LedgerService
+	Payment  PaymentService
+	Bank     ReconciliationService
+	db       *gorm.DB
+	repos    *repository.Repositories
+	deps     Deps
 }
 
-func NewServices(db *gorm.DB, repos *repository.Repositories) *Services {
-	return &Services{
-		User: NewUserService(db, repos.User),
-		db:   db,
+func NewServices(db *gorm.DB, repos *repository.Repositories, deps Deps) *Services {
+	s := &Services{
+		db:    db,
+		repos: repos,
+		deps:  deps,
 	}
+	s.User = NewUserService(db, repos.User, repos.OTP, deps.SMS, deps.Config.OTP)
+	s.Auth = NewAuthService(db, repos.User, repos.OTP, repos.Session, deps.Tokens, deps.SMS, deps.Config.Auth, deps.Config.OTP)
+	s.Property = NewPropertyService(db, repos.Property, repos.User, repos.Lease)
+	s.Building = NewBuildingService(s, repos.Building, repos.Property, repos.User, deps.Storage, deps.Config.Storage)
+	s.Clause = NewClauseService(s, repos.Clause)
+	s.Lease = NewLeaseService(s, repos.Lease, repos.Property, repos.Clause, repos.User, repos.Signing, repos.Document,
+		deps.StampDuty, deps.Compliance, deps.Fonts, deps.PoliceForms, deps.EStamp, deps.ESign, deps.Storage, deps.SMS,
+		deps.Config.Storage, deps.Config.LeasePDF, deps.Config.Signing, deps.Config.Document, deps.Config.Renewal)
+	s.Ledger = NewLedgerService(s, repos.Ledger, repos.Lease, repos.User)
+	s.Payment = NewPaymentService(s, repos.Payment, repos.Lease, repos.User, deps.Gateway)
+	s.Bank = NewReconciliationService(s, repos.Bank, repos.Lease, deps.BankFormats, deps.Config.Storage, deps.Config.Bank)
+	return s
 }
 
 func (s *Services) Transaction(fn func(txServices *Services) error) error {
 	return s.db.Transaction(func(tx *gorm.DB) error {
 		txRepos := repository.NewRepositories(tx)
-		txServices := NewServices(tx, txRepos)
+		txServices := NewServices(tx, txRepos, s.deps)
 		return fn(txServices)
 	})
 }
@@ -3,48 +3,68 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"time"
 
+	"backend/internal/auth"
+	"backend/internal/config"
 	"backend/internal/model"
+	"backend/internal/notify"
+	"backend/internal/policy"
 	"backend/internal/repository"
 	"backend/pkg/apperr"
+	"backend/pkg/phone"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type UserService interface {
-	List(ctx context.Context, limit, offset int) ([]model.User, int64, error)
-	GetByID(ctx context.Context, id uuid.UUID) (*model.User, error)
-	Create(ctx context.Context, input CreateUserInput) (*model.User, error)
-	Update(ctx context.Context, id uuid.UUID, input UpdateUserInput) (*model.User, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, actor *model.User, limit, offset int) ([]model.User, int64, error)
+	GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.User, error)
+	Create(ctx context.Context, actor *model.User, input CreateUserInput) (*model.User, error)
+	Register(ctx context.Context, input CreateUserInput) (*model.User, error)
+	Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdateUserInput) (*model.User, error)
+	RequestPhoneChange(ctx context.Context, actor *model.User, id uuid.UUID, phoneNumber string) (*model.OTPRequestResponse, error)
+	Delete(ctx context.Context, actor *model.User, id uuid.UUID) error
 }
 
 type CreateUserInput struct {
-	Name  string
-	Email string
-	Role  string
+	Name     string
+	Email    string
+	Password string
+	Phone    string
+	Role     string
 }
 
 type UpdateUserInput struct {
-	Name *string
-	Role *string
+	Name  *string
+	Phone *string
+	// PhoneCode is the code sent to a new phone number by RequestPhoneChange
+	PhoneCode string
+	Role      *string
 }
 
 type userService struct {
 	db       *gorm.DB
 	userRepo repository.UserRepository
+	otp      *otpCodes
 }
 
-func NewUserService(db *gorm.DB, userRepo repository.UserRepository) UserService {
+func NewUserService(db *gorm.DB, userRepo repository.UserRepository, otpRepo repository.OTPRepository, sms notify.SMSSender, otpCfg config.OTPConfig) UserService {
 	return &userService{
 		db:       db,
 		userRepo: userRepo,
+		otp:      &otpCodes{repo: otpRepo, sms: sms, cfg: otpCfg},
 	}
 }
 
-func (s *userService) List(ctx context.Context, limit, offset int) ([]model.User, int64, error) {
+func (s *userService) List(ctx context.Context, actor *model.User, limit, offset int) ([]model.User, int64, error) {
+	if err := policy.RequireAdmin(actor); err != nil {
+		return nil, 0, err
+	}
+
 	users, total, err := s.userRepo.List(ctx, limit, offset)
 	if err != nil {
 		return nil, 0, apperr.Internal("Failed to fetch users", err)
@@ -52,7 +72,7 @@ func (s *userService) List(ctx context.Context, limit, offset int) ([]model.User
 	return users, total, nil
 }
 
-func (s *userService) GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+func (s *userService) GetByID(ctx context.Context, actor *model.User, id uuid.UUID) (*model.User, error) {
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
@@ -60,41 +80,96 @@ func (s *userService) GetByID(ctx context.Context, id uuid.UUID) (*model.User, e
 		}
 		return nil, apperr.Internal("Failed to fetch user", err)
 	}
+
+	if err := policy.Authorize(actor, policy.ActionRead, policy.ForUser(user)); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
-func (s *userService) Create(ctx context.Context, input CreateUserInput) (*model.User, error) {
+func (s *userService) Create(ctx context.Context, actor *model.User, input CreateUserInput) (*model.User, error) {
+	if err := policy.AuthorizeCreate(actor, policy.ResourceUser); err != nil {
+		return nil, err
+	}
+	return s.create(ctx, input)
+}
+
+func (s *userService) Register(ctx context.Context, input CreateUserInput) (*model.User, error) {
+	if !slices.Contains(model.SignUpRoles, input.Role) {
+		return nil, apperr.Forbidden("Only the owner and tenant roles can be chosen at sign-up", nil)
+	}
+	return s.create(ctx, input)
+}
+
+func (s *userService) create(ctx context.Context, input CreateUserInput) (*model.User, error) {
+	passwordHash, err := auth.HashPassword(input.Password)
+	if err != nil {
+		return nil, apperr.Internal("Failed to hash password", err)
+	}
+
 	user := &model.User{
-		ID:        uuid.New(),
-		Name:      input.Name,
-		Email:     input.Email,
-		Role:      input.Role,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:           uuid.New(),
+		Name:         input.Name,
+		Email:        &input.Email,
+		Role:         input.Role,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if input.Phone != "" {
+		normalized, err := phone.Normalize(input.Phone)
+		if err != nil {
+			return nil, apperr.Invalid("Invalid phone number", err)
+		}
+		user.Phone = &normalized
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
 		if errors.Is(err, repository.ErrUserAlreadyExists) {
 			return nil, apperr.Conflict("User with this email already exists", err)
 		}
+		if errors.Is(err, repository.ErrUserPhoneAlreadyExists) {
+			return nil, apperr.Conflict("User with this phone already exists", err)
+		}
 		return nil, apperr.Internal("Failed to create user", err)
 	}
 
 	return user, nil
 }
 
-func (s *userService) Update(ctx context.Context, id uuid.UUID, input UpdateUserInput) (*model.User, error) {
-	user, err := s.userRepo.GetByID(ctx, id)
+// Update changes a user. The phone number logs the user in by OTP, so a new
+// one is only saved with the code RequestPhoneChange sent to it.
+func (s *userService) Update(ctx context.Context, actor *model.User, id uuid.UUID, input UpdateUserInput) (*model.User, error) {
+	user, err := s.updatable(ctx, actor, id)
 	if err != nil {
-		if errors.Is(err, repository.ErrUserNotFound) {
-			return nil, apperr.NotFound("User not found", err)
+		return nil, err
+	}
+	if input.Role != nil && *input.Role != user.Role {
+		if err := policy.RequireAdmin(actor); err != nil {
+			return nil, apperr.Forbidden("Only administrators can change roles", err)
 		}
-		return nil, apperr.Internal("Failed to fetch user", err)
 	}
 
 	if input.Name != nil {
 		user.Name = *input.Name
 	}
+	if input.Phone != nil {
+		normalized, changed, err := s.newPhone(ctx, user, *input.Phone)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			if input.PhoneCode == "" {
+				return nil, apperr.Invalid("Enter the code sent to the new phone number", nil)
+			}
+			if err := s.otp.consume(ctx, normalized, model.OTPPurposePhoneChange, &user.ID, input.PhoneCode); err != nil {
+				return nil, err
+			}
+		}
+		user.Phone = &normalized
+	}
 	if input.Role != nil {
 		user.Role = *input.Role
 	}
@@ -107,7 +182,76 @@ func (s *userService) Update(ctx context.Context, id uuid.UUID, input UpdateUser
 	return user, nil
 }
 
-func (s *userService) Delete(ctx context.Context, id uuid.UUID) error {
+// RequestPhoneChange texts a code to the number a user's phone is to be
+// changed to, which Update needs to save it
+func (s *userService) RequestPhoneChange(ctx context.Context, actor *model.User, id uuid.UUID, phoneNumber string) (*model.OTPRequestResponse, error) {
+	user, err := s.updatable(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, changed, err := s.newPhone(ctx, user, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, apperr.Invalid("This is already the user's phone number", nil)
+	}
+
+	return s.otp.send(ctx, normalized, model.OTPPurposePhoneChange, &user.ID,
+		"%s is your Rental App code to change your phone number to this one. It expires in %d minutes. Do not share it with anyone.")
+}
+
+// updatable returns the user if the actor may update them
+func (s *userService) updatable(ctx context.Context, actor *model.User, id uuid.UUID) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, apperr.NotFound("User not found", err)
+		}
+		return nil, apperr.Internal("Failed to fetch user", err)
+	}
+
+	if err := policy.Authorize(actor, policy.ActionUpdate, policy.ForUser(user)); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// newPhone normalizes a phone number for the user and reports whether it
+// differs from theirs. Another user's number is a conflict.
+func (s *userService) newPhone(ctx context.Context, user *model.User, phoneNumber string) (string, bool, error) {
+	normalized, err := phone.Normalize(phoneNumber)
+	if err != nil {
+		return "", false, apperr.Invalid("Invalid phone number", err)
+	}
+	if user.Phone != nil && *user.Phone == normalized {
+		return normalized, false, nil
+	}
+
+	existing, err := s.userRepo.GetByPhone(ctx, normalized)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return "", false, apperr.Internal("Failed to check phone", err)
+	}
+	if existing != nil {
+		return "", false, apperr.Conflict("User with this phone already exists", nil)
+	}
+	return normalized, true, nil
+}
+
+func (s *userService) Delete(ctx context.Context, actor *model.User, id uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return apperr.NotFound("User not found", err)
+		}
+		return apperr.Internal("Failed to fetch user", err)
+	}
+
+	if err := policy.Authorize(actor, policy.ActionDelete, policy.ForUser(user)); err != nil {
+		return err
+	}
+
 	if err := s.userRepo.Delete(ctx, id); err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
 			return apperr.NotFound("User not found", err)
@@ -116,3 +260,26 @@ func (s *userService) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 	return nil
 }
+
+// grantRole makes a tenant who rents nothing the co-owner or manager an owner
+// has related them to a property or building as. Users with any other role
+// keep it.
+func grantRole(ctx context.Context, repos *repository.Repositories, user *model.User, role string) error {
+	if user.Role != model.RoleTenant {
+		return nil
+	}
+	leases, err := repos.Lease.ListByTenant(ctx, user.ID)
+	if err != nil {
+		return apperr.Internal("Failed to fetch leases", err)
+	}
+	if len(leases) > 0 {
+		return apperr.Invalid("A tenant on a lease cannot also be made a "+strings.ReplaceAll(role, "_", "-"), nil)
+	}
+
+	user.Role = role
+	user.UpdatedAt = time.Now()
+	if err := repos.User.Update(ctx, user); err != nil {
+		return apperr.Internal("Failed to update user", err)
+	}
+	return nil
+}
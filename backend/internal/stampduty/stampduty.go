@@ -0,0 +1,243 @@
+// Package stampduty estimates the stamp duty and registration fee payable on
+// a rent agreement. The per-state rates live in a versioned rules file rather
+// than in code; rules.json is embedded as the default and STAMP_DUTY_RULES_PATH
+// can point to a newer copy.
+package stampduty
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+
+	"backend/pkg/india"
+)
+
+//go:embed rules.json
+var defaultRules []byte
+
+var (
+	ErrUnsupportedState = errors.New("no stamp duty rules for state")
+	ErrUnsupportedTerm  = errors.New("no stamp duty rule for lease term")
+)
+
+// Amounts a component can be a percentage of
+const (
+	BaseTotalRent         = "total_rent"
+	BaseAverageAnnualRent = "average_annual_rent"
+	BaseDeposit           = "deposit"
+	BaseDepositInterest   = "deposit_interest"
+)
+
+var bases = []string{BaseTotalRent, BaseAverageAnnualRent, BaseDeposit, BaseDepositInterest}
+
+// Rules is the parsed rules file
+type Rules struct {
+	Version             string               `json:"version"`
+	Description         string               `json:"description"`
+	DenominationsRupees []int64              `json:"denominations_rupees"`
+	States              map[string]StateRule `json:"states"`
+}
+
+type StateRule struct {
+	// DepositInterestRatePercent is the yearly rate of notional interest on the deposit
+	DepositInterestRatePercent float64 `json:"deposit_interest_rate_percent"`
+	// Bands are ordered by MaxTermMonths; the first band the term fits in applies
+	Bands []Band `json:"bands"`
+}
+
+type Band struct {
+	MaxTermMonths        int       `json:"max_term_months"`
+	Basis                string    `json:"basis"`
+	RegistrationRequired bool      `json:"registration_required"`
+	StampDuty            Component `json:"stamp_duty"`
+	RegistrationFee      Component `json:"registration_fee"`
+}
+
+// Component is a fee of Percent of the sum of the Of amounts plus a flat
+// amount, kept within Min and Max and rounded up to RoundUpToRupees
+type Component struct {
+	Percent         float64  `json:"percent"`
+	Of              []string `json:"of"`
+	FlatRupees      int64    `json:"flat_rupees"`
+	MinRupees       int64    `json:"min_rupees"`
+	MaxRupees       int64    `json:"max_rupees"`
+	RoundUpToRupees int64    `json:"round_up_to_rupees"`
+}
+
+// Input is the lease data the fees depend on. Amounts are in paise.
+type Input struct {
+	State            string
+	MonthlyRentPaise int64
+	DepositPaise     int64
+	TermMonths       int
+}
+
+// Denomination is a number of stamp papers of one face value
+type Denomination struct {
+	ValuePaise int64 `json:"value_paise"`
+	Count      int   `json:"count"`
+}
+
+// Estimate is the calculated stamp duty and registration fee. Amounts are in paise.
+type Estimate struct {
+	State                string         `json:"state"`
+	StateName            string         `json:"state_name"`
+	RulesVersion         string         `json:"rules_version"`
+	Basis                string         `json:"basis"`
+	StampDutyPaise       int64          `json:"stamp_duty_paise"`
+	RegistrationFeePaise int64          `json:"registration_fee_paise"`
+	TotalPaise           int64          `json:"total_paise"`
+	RegistrationRequired bool           `json:"registration_required"`
+	StampPaper           []Denomination `json:"stamp_paper"`
+}
+
+// Calculator applies a rules file
+type Calculator struct {
+	rules Rules
+}
+
+// Load reads the rules file at path, or the embedded rules when path is empty
+func Load(path string) (*Calculator, error) {
+	data := defaultRules
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read stamp duty rules: %w", err)
+		}
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse stamp duty rules: %w", err)
+	}
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+	return &Calculator{rules: rules}, nil
+}
+
+func (r *Rules) validate() error {
+	if r.Version == "" {
+		return errors.New("stamp duty rules: version is required")
+	}
+	if len(r.DenominationsRupees) == 0 {
+		return errors.New("stamp duty rules: denominations are required")
+	}
+	slices.Sort(r.DenominationsRupees)
+
+	for state, rule := range r.States {
+		if !india.IsStateCode(state) {
+			return fmt.Errorf("stamp duty rules: unknown state %q", state)
+		}
+		if len(rule.Bands) == 0 {
+			return fmt.Errorf("stamp duty rules: %s has no bands", state)
+		}
+		sort.Slice(rule.Bands, func(i, j int) bool { return rule.Bands[i].MaxTermMonths < rule.Bands[j].MaxTermMonths })
+		for _, band := range rule.Bands {
+			for _, c := range []Component{band.StampDuty, band.RegistrationFee} {
+				for _, base := range c.Of {
+					if !slices.Contains(bases, base) {
+						return fmt.Errorf("stamp duty rules: %s uses unknown base %q", state, base)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Version returns the version of the loaded rules
+func (c *Calculator) Version() string {
+	return c.rules.Version
+}
+
+// Calculate returns the fees for the lease in the given state
+func (c *Calculator) Calculate(in Input) (*Estimate, error) {
+	rule, ok := c.rules.States[in.State]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedState, in.State)
+	}
+
+	var band *Band
+	for i := range rule.Bands {
+		if in.TermMonths <= rule.Bands[i].MaxTermMonths {
+			band = &rule.Bands[i]
+			break
+		}
+	}
+	if band == nil {
+		return nil, fmt.Errorf("%w of %d months in %s", ErrUnsupportedTerm, in.TermMonths, in.State)
+	}
+
+	amounts := map[string]int64{
+		BaseTotalRent:         in.MonthlyRentPaise * int64(in.TermMonths),
+		BaseAverageAnnualRent: in.MonthlyRentPaise * int64(min(in.TermMonths, 12)),
+		BaseDeposit:           in.DepositPaise,
+		BaseDepositInterest: int64(math.Round(float64(in.DepositPaise) *
+			rule.DepositInterestRatePercent / 100 * float64(in.TermMonths) / 12)),
+	}
+
+	estimate := &Estimate{
+		State:                in.State,
+		StateName:            india.StateName(in.State),
+		RulesVersion:         c.rules.Version,
+		Basis:                band.Basis,
+		StampDutyPaise:       band.StampDuty.apply(amounts),
+		RegistrationFeePaise: band.RegistrationFee.apply(amounts),
+		RegistrationRequired: band.RegistrationRequired,
+	}
+	estimate.TotalPaise = estimate.StampDutyPaise + estimate.RegistrationFeePaise
+	estimate.StampPaper = c.denominations(estimate.StampDutyPaise)
+
+	return estimate, nil
+}
+
+func (c Component) apply(amounts map[string]int64) int64 {
+	var base int64
+	for _, name := range c.Of {
+		base += amounts[name]
+	}
+
+	paise := math.Ceil(float64(base)*c.Percent/100) + float64(c.FlatRupees*100)
+	if c.MinRupees > 0 {
+		paise = math.Max(paise, float64(c.MinRupees*100))
+	}
+	if c.MaxRupees > 0 {
+		paise = math.Min(paise, float64(c.MaxRupees*100))
+	}
+
+	unit := float64(max(c.RoundUpToRupees, 1) * 100)
+	return int64(math.Ceil(paise/unit) * unit)
+}
+
+// denominations picks stamp papers adding up to at least the duty, largest
+// first, topping up with the smallest paper when the remainder is below it
+func (c *Calculator) denominations(dutyPaise int64) []Denomination {
+	var result []Denomination
+	add := func(value int64) {
+		if n := len(result); n > 0 && result[n-1].ValuePaise == value {
+			result[n-1].Count++
+			return
+		}
+		result = append(result, Denomination{ValuePaise: value, Count: 1})
+	}
+
+	remaining := dutyPaise
+	for i := len(c.rules.DenominationsRupees) - 1; i >= 0 && remaining > 0; i-- {
+		value := c.rules.DenominationsRupees[i] * 100
+		for remaining >= value {
+			add(value)
+			remaining -= value
+		}
+	}
+	if remaining > 0 {
+		add(c.rules.DenominationsRupees[0] * 100)
+	}
+
+	return result
+}
@@ -0,0 +1,229 @@
+package stampduty
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	const (
+		rupee   = 100
+		rent    = 20000 * rupee
+		deposit = 100000 * rupee
+	)
+
+	tests := []struct {
+		name             string
+		in               Input
+		wantStampDuty    int64
+		wantRegistration int64
+		wantRequired     bool
+		wantPaper        []Denomination
+	}{
+		{
+			name: "MH duty on rent and deposit interest, rounded up to Rs. 100",
+			in:   Input{State: "MH", MonthlyRentPaise: rent, DepositPaise: deposit, TermMonths: 11},
+			// 0.25% of 2,20,000 + 9,166.67 = 572.92
+			wantStampDuty:    600 * rupee,
+			wantRegistration: 1000 * rupee,
+			wantRequired:     true,
+			wantPaper:        []Denomination{{500 * rupee, 1}, {100 * rupee, 1}},
+		},
+		{
+			name:             "MH minimum duty",
+			in:               Input{State: "MH", MonthlyRentPaise: 1000 * rupee, TermMonths: 11},
+			wantStampDuty:    100 * rupee,
+			wantRegistration: 1000 * rupee,
+			wantRequired:     true,
+			wantPaper:        []Denomination{{100 * rupee, 1}},
+		},
+		{
+			name: "KA short term duty capped",
+			in:   Input{State: "KA", MonthlyRentPaise: rent, DepositPaise: deposit, TermMonths: 11},
+			// 0.5% of 2,20,000 + 1,00,000 = 1,600
+			wantStampDuty:    500 * rupee,
+			wantRegistration: 1600 * rupee,
+			wantPaper:        []Denomination{{500 * rupee, 1}},
+		},
+		{
+			name:             "KA long term on a year's rent",
+			in:               Input{State: "KA", MonthlyRentPaise: rent, DepositPaise: deposit, TermMonths: 24},
+			wantStampDuty:    3400 * rupee,
+			wantRegistration: 1700 * rupee,
+			wantRequired:     true,
+			wantPaper:        []Denomination{{2000 * rupee, 1}, {1000 * rupee, 1}, {100 * rupee, 4}},
+		},
+		{
+			name: "KA paise rounded up to the rupee, topped up with the smallest paper",
+			in:   Input{State: "KA", MonthlyRentPaise: 123457, TermMonths: 12},
+			// 0.5% of 14,814.84 = 74.0742
+			wantStampDuty:    75 * rupee,
+			wantRegistration: 100 * rupee,
+			wantPaper:        []Denomination{{50 * rupee, 1}, {20 * rupee, 1}, {10 * rupee, 1}},
+		},
+		{
+			name:             "DL flat duty and no fee under a year",
+			in:               Input{State: "DL", MonthlyRentPaise: rent, DepositPaise: deposit, TermMonths: 11},
+			wantStampDuty:    100 * rupee,
+			wantRegistration: 0,
+			wantPaper:        []Denomination{{100 * rupee, 1}},
+		},
+		{
+			name:             "DL percentage plus flat amount",
+			in:               Input{State: "DL", MonthlyRentPaise: rent, DepositPaise: deposit, TermMonths: 36},
+			wantStampDuty:    4900 * rupee,
+			wantRegistration: 2500 * rupee,
+			wantRequired:     true,
+			wantPaper:        []Denomination{{2000 * rupee, 2}, {500 * rupee, 1}, {100 * rupee, 4}},
+		},
+		{
+			name:             "TN on total rent and deposit",
+			in:               Input{State: "TN", MonthlyRentPaise: rent, DepositPaise: deposit, TermMonths: 11},
+			wantStampDuty:    3200 * rupee,
+			wantRegistration: 3200 * rupee,
+			wantRequired:     true,
+			wantPaper:        []Denomination{{2000 * rupee, 1}, {1000 * rupee, 1}, {100 * rupee, 2}},
+		},
+		{
+			name:             "TN registration fee capped",
+			in:               Input{State: "TN", MonthlyRentPaise: 100000 * rupee, TermMonths: 120},
+			wantStampDuty:    120000 * rupee,
+			wantRegistration: 20000 * rupee,
+			wantRequired:     true,
+			wantPaper:        []Denomination{{25000 * rupee, 4}, {20000 * rupee, 1}},
+		},
+		{
+			name:             "TG short term with minimum registration fee",
+			in:               Input{State: "TG", MonthlyRentPaise: 5000 * rupee, DepositPaise: 10000 * rupee, TermMonths: 11},
+			wantStampDuty:    260 * rupee,
+			wantRegistration: 100 * rupee,
+			wantPaper:        []Denomination{{100 * rupee, 2}, {50 * rupee, 1}, {10 * rupee, 1}},
+		},
+		{
+			name:             "TG long term mixing bases",
+			in:               Input{State: "TG", MonthlyRentPaise: rent, DepositPaise: deposit, TermMonths: 60},
+			wantStampDuty:    1700 * rupee,
+			wantRegistration: 1300 * rupee,
+			wantRequired:     true,
+			wantPaper:        []Denomination{{1000 * rupee, 1}, {500 * rupee, 1}, {100 * rupee, 2}},
+		},
+	}
+
+	calc, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calc.Calculate(tt.in)
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			if got.StampDutyPaise != tt.wantStampDuty || got.RegistrationFeePaise != tt.wantRegistration {
+				t.Errorf("duty %d and fee %d, want %d and %d", got.StampDutyPaise, got.RegistrationFeePaise, tt.wantStampDuty, tt.wantRegistration)
+			}
+			if got.TotalPaise != tt.wantStampDuty+tt.wantRegistration {
+				t.Errorf("total %d, want %d", got.TotalPaise, tt.wantStampDuty+tt.wantRegistration)
+			}
+			if got.RegistrationRequired != tt.wantRequired {
+				t.Errorf("registration required = %v, want %v", got.RegistrationRequired, tt.wantRequired)
+			}
+			if !reflect.DeepEqual(got.StampPaper, tt.wantPaper) {
+				t.Errorf("stamp paper %v, want %v", got.StampPaper, tt.wantPaper)
+			}
+			if got.RulesVersion != calc.Version() || got.Basis == "" {
+				t.Errorf("rules version %q and basis %q", got.RulesVersion, got.Basis)
+			}
+		})
+	}
+}
+
+func TestCalculateUnsupported(t *testing.T) {
+	calc, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		in   Input
+		want error
+	}{
+		{Input{State: "GJ", MonthlyRentPaise: 1000000, TermMonths: 11}, ErrUnsupportedState},
+		{Input{State: "MH", MonthlyRentPaise: 1000000, TermMonths: 61}, ErrUnsupportedTerm},
+		{Input{State: "DL", MonthlyRentPaise: 1000000, TermMonths: 120}, ErrUnsupportedTerm},
+	}
+
+	for _, tt := range tests {
+		if _, err := calc.Calculate(tt.in); !errors.Is(err, tt.want) {
+			t.Errorf("Calculate(%+v) = %v, want %v", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr bool
+	}{
+		{
+			name:  "bands sorted by term",
+			rules: `{"version": "test", "denominations_rupees": [100, 10], "states": {"KA": {"bands": [{"max_term_months": 60, "stamp_duty": {"flat_rupees": 200}}, {"max_term_months": 12, "stamp_duty": {"flat_rupees": 50}}]}}}`,
+		},
+		{
+			name:    "missing version",
+			rules:   `{"denominations_rupees": [10], "states": {}}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing denominations",
+			rules:   `{"version": "test", "states": {}}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown state",
+			rules:   `{"version": "test", "denominations_rupees": [10], "states": {"ZZ": {"bands": [{"max_term_months": 12}]}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "state without bands",
+			rules:   `{"version": "test", "denominations_rupees": [10], "states": {"KA": {"bands": []}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown base",
+			rules:   `{"version": "test", "denominations_rupees": [10], "states": {"KA": {"bands": [{"max_term_months": 12, "stamp_duty": {"percent": 1, "of": ["market_value"]}}]}}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.json")
+			if err := os.WriteFile(path, []byte(tt.rules), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			calc, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			estimate, err := calc.Calculate(Input{State: "KA", MonthlyRentPaise: 1000000, TermMonths: 11})
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			want := []Denomination{{1000, 5}}
+			if estimate.StampDutyPaise != 5000 || !reflect.DeepEqual(estimate.StampPaper, want) {
+				t.Errorf("duty %d on %v, want 5000 on %v", estimate.StampDutyPaise, estimate.StampPaper, want)
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"reflect"
 	"strings"
 
+	"backend/pkg/india"
+	"backend/pkg/upi"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 )
@@ -28,7 +31,9 @@ func NewValidator() *CustomValidator {
 	})
 
 	// Register custom validations here
-	// Example: v.RegisterValidation("custom_tag", customValidationFunc)
+	v.RegisterValidation("indian_state", validateIndianState)
+	v.RegisterValidation("pincode", validatePincode)
+	v.RegisterValidation("upi_vpa", validateVPA)
 
 	return &CustomValidator{validator: v}
 }
@@ -76,9 +81,40 @@ func getErrorMessage(e validator.FieldError) string {
 		return "Value must be greater than or equal to " + e.Param()
 	case "lte":
 		return "Value must be less than or equal to " + e.Param()
+	case "gt":
+		return "Value must be greater than " + e.Param()
+	case "lt":
+		return "Value must be less than " + e.Param()
+	case "uuid":
+		return "Invalid ID format"
+	case "numeric":
+		return "Value must contain digits only"
+	case "datetime":
+		return "Invalid date, expected YYYY-MM-DD"
 	case "oneof":
 		return "Value must be one of: " + e.Param()
+	case "indian_state":
+		return "Must be an Indian state or union territory code, e.g. MH or KA"
+	case "pincode":
+		return "Must be a 6-digit PIN code"
+	case "upi_vpa":
+		return "Must be a UPI ID, e.g. name@bank"
 	default:
 		return "Validation failed on " + e.Tag()
 	}
 }
+
+// validateIndianState checks for an ISO 3166-2:IN state or union territory code
+func validateIndianState(fl validator.FieldLevel) bool {
+	return india.IsStateCode(fl.Field().String())
+}
+
+// validatePincode checks for a 6-digit Indian postal PIN code
+func validatePincode(fl validator.FieldLevel) bool {
+	return india.IsPincode(fl.Field().String())
+}
+
+// validateVPA checks for a UPI virtual payment address
+func validateVPA(fl validator.FieldLevel) bool {
+	return upi.IsVPA(fl.Field().String())
+}
@@ -0,0 +1,16 @@
+-- Users who signed up by phone have no email to fall back to. Refuse to roll
+-- back rather than delete their accounts.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE email IS NULL) THEN
+        RAISE EXCEPTION 'users without an email exist; give them an email before rolling back';
+    END IF;
+END;
+$$;
+
+DROP INDEX IF EXISTS idx_otp_challenges_phone_purpose_created_at;
+DROP TABLE IF EXISTS otp_challenges;
+
+DROP INDEX IF EXISTS idx_users_phone;
+ALTER TABLE users DROP COLUMN IF EXISTS phone;
+ALTER TABLE users ALTER COLUMN email SET NOT NULL;
@@ -0,0 +1,22 @@
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+ALTER TABLE users ADD COLUMN phone VARCHAR(16) UNIQUE;
+
+CREATE INDEX idx_users_phone ON users(phone);
+
+-- A code is sent either to log in or to confirm a new phone number for a
+-- user, and is only accepted for what it was sent for.
+CREATE TABLE otp_challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    phone VARCHAR(16) NOT NULL,
+    purpose VARCHAR(20) NOT NULL DEFAULT 'login' CHECK (purpose IN ('login', 'phone_change')),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    consumed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT otp_challenges_user_check CHECK ((purpose = 'phone_change') = (user_id IS NOT NULL))
+);
+
+CREATE INDEX idx_otp_challenges_phone_purpose_created_at ON otp_challenges(phone, purpose, created_at DESC);
@@ -0,0 +1,8 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
+
+UPDATE users SET role = CASE role
+    WHEN 'admin' THEN 'admin'
+    WHEN 'tenant' THEN 'guest'
+    ELSE 'user'
+END;
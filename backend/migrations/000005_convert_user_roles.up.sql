@@ -0,0 +1,11 @@
+-- Map the generic roles onto rental-domain roles.
+-- Existing "user" accounts were created by property owners; "guest" accounts become tenants.
+UPDATE users SET role = CASE role
+    WHEN 'admin' THEN 'admin'
+    WHEN 'guest' THEN 'tenant'
+    ELSE 'owner'
+END;
+
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'tenant';
+ALTER TABLE users ADD CONSTRAINT chk_users_role
+    CHECK (role IN ('owner', 'co_owner', 'manager', 'tenant', 'admin'));
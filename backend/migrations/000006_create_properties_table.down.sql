@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_property_co_owners_user_id;
+DROP TABLE IF EXISTS property_co_owners;
+
+DROP INDEX IF EXISTS idx_properties_pincode;
+DROP INDEX IF EXISTS idx_properties_city;
+DROP INDEX IF EXISTS idx_properties_manager_id;
+DROP INDEX IF EXISTS idx_properties_owner_id;
+DROP TABLE IF EXISTS properties;
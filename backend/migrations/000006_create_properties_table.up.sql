@@ -0,0 +1,36 @@
+CREATE TABLE properties (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    manager_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    property_type VARCHAR(20) NOT NULL CHECK (property_type IN ('apartment', 'flat', 'condo', 'villa')),
+    address_line1 VARCHAR(255) NOT NULL,
+    address_line2 VARCHAR(255) NOT NULL DEFAULT '',
+    locality VARCHAR(100) NOT NULL DEFAULT '',
+    city VARCHAR(100) NOT NULL,
+    state CHAR(2) NOT NULL,
+    pincode CHAR(6) NOT NULL CHECK (pincode ~ '^[1-9][0-9]{5}$'),
+    bhk SMALLINT NOT NULL CHECK (bhk >= 0),
+    carpet_area_sqft NUMERIC(10, 2) NOT NULL CHECK (carpet_area_sqft > 0),
+    built_up_area_sqft NUMERIC(10, 2),
+    furnishing VARCHAR(20) NOT NULL CHECK (furnishing IN ('unfurnished', 'semi_furnished', 'fully_furnished')),
+    floor SMALLINT NOT NULL DEFAULT 0,
+    total_floors SMALLINT,
+    parking VARCHAR(20) NOT NULL DEFAULT 'none' CHECK (parking IN ('none', 'two_wheeler', 'four_wheeler', 'both')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_properties_owner_id ON properties(owner_id);
+CREATE INDEX idx_properties_manager_id ON properties(manager_id);
+CREATE INDEX idx_properties_city ON properties(city);
+CREATE INDEX idx_properties_pincode ON properties(pincode);
+
+CREATE TABLE property_co_owners (
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (property_id, user_id)
+);
+
+CREATE INDEX idx_property_co_owners_user_id ON property_co_owners(user_id);
@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_properties_building_unit;
+
+ALTER TABLE properties
+    DROP COLUMN IF EXISTS occupancy_status,
+    DROP COLUMN IF EXISTS unit_number,
+    DROP COLUMN IF EXISTS building_id;
+
+DROP INDEX IF EXISTS idx_building_documents_building_id;
+DROP TABLE IF EXISTS building_documents;
+
+DROP INDEX IF EXISTS idx_building_charges_building_id;
+DROP TABLE IF EXISTS building_charges;
+
+DROP INDEX IF EXISTS idx_buildings_manager_id;
+DROP INDEX IF EXISTS idx_buildings_owner_id;
+DROP TABLE IF EXISTS buildings;
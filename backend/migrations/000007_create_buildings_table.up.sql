@@ -0,0 +1,54 @@
+CREATE TABLE buildings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    manager_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    society_name VARCHAR(255) NOT NULL DEFAULT '',
+    address_line1 VARCHAR(255) NOT NULL,
+    address_line2 VARCHAR(255) NOT NULL DEFAULT '',
+    locality VARCHAR(100) NOT NULL DEFAULT '',
+    city VARCHAR(100) NOT NULL,
+    state CHAR(2) NOT NULL,
+    pincode CHAR(6) NOT NULL CHECK (pincode ~ '^[1-9][0-9]{5}$'),
+    total_floors SMALLINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_buildings_owner_id ON buildings(owner_id);
+CREATE INDEX idx_buildings_manager_id ON buildings(manager_id);
+
+CREATE TABLE building_charges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    building_id UUID NOT NULL REFERENCES buildings(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    amount_paise BIGINT NOT NULL CHECK (amount_paise > 0),
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('monthly', 'quarterly', 'annual')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_building_charges_building_id ON building_charges(building_id);
+
+CREATE TABLE building_documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    building_id UUID NOT NULL REFERENCES buildings(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    document_type VARCHAR(30) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(500) NOT NULL,
+    uploaded_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_building_documents_building_id ON building_documents(building_id);
+
+ALTER TABLE properties
+    ADD COLUMN building_id UUID REFERENCES buildings(id) ON DELETE RESTRICT,
+    ADD COLUMN unit_number VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN occupancy_status VARCHAR(20) NOT NULL DEFAULT 'vacant'
+        CONSTRAINT chk_properties_occupancy_status CHECK (occupancy_status IN ('vacant', 'occupied'));
+
+CREATE UNIQUE INDEX idx_properties_building_unit ON properties(building_id, unit_number) WHERE building_id IS NOT NULL;
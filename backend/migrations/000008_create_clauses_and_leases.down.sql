@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_lease_clauses_lease_id;
+DROP TABLE IF EXISTS lease_clauses;
+
+ALTER TABLE clauses DROP CONSTRAINT IF EXISTS fk_clauses_current_version;
+DROP TABLE IF EXISTS clause_versions;
+
+DROP INDEX IF EXISTS idx_clauses_category;
+DROP INDEX IF EXISTS idx_clauses_owner_id;
+DROP TABLE IF EXISTS clauses;
+
+DROP INDEX IF EXISTS idx_leases_status;
+DROP INDEX IF EXISTS idx_leases_owner_id;
+DROP INDEX IF EXISTS idx_leases_property_id;
+DROP TABLE IF EXISTS leases;
@@ -0,0 +1,98 @@
+CREATE TABLE leases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE RESTRICT,
+    owner_id UUID NOT NULL REFERENCES users(id),
+    status VARCHAR(30) NOT NULL DEFAULT 'draft',
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    term_months SMALLINT NOT NULL CHECK (term_months > 0),
+    monthly_rent_paise BIGINT NOT NULL CHECK (monthly_rent_paise > 0),
+    security_deposit_paise BIGINT NOT NULL DEFAULT 0 CHECK (security_deposit_paise >= 0),
+    maintenance_paise BIGINT NOT NULL DEFAULT 0 CHECK (maintenance_paise >= 0),
+    rent_due_day SMALLINT NOT NULL CHECK (rent_due_day BETWEEN 1 AND 28),
+    notice_period_days SMALLINT NOT NULL CHECK (notice_period_days >= 0),
+    lock_in_months SMALLINT NOT NULL DEFAULT 0 CHECK (lock_in_months >= 0),
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_leases_dates CHECK (end_date > start_date)
+);
+
+CREATE INDEX idx_leases_property_id ON leases(property_id);
+CREATE INDEX idx_leases_owner_id ON leases(owner_id);
+CREATE INDEX idx_leases_status ON leases(status);
+
+CREATE TABLE clauses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('rent', 'deposit', 'maintenance', 'pets', 'subletting', 'lock_in', 'termination')),
+    title VARCHAR(255) NOT NULL,
+    is_mandatory BOOLEAN NOT NULL DEFAULT FALSE,
+    current_version_id UUID,
+    archived_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_clauses_mandatory_system CHECK (NOT is_mandatory OR owner_id IS NULL)
+);
+
+CREATE INDEX idx_clauses_owner_id ON clauses(owner_id);
+CREATE INDEX idx_clauses_category ON clauses(category);
+
+CREATE TABLE clause_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    clause_id UUID NOT NULL REFERENCES clauses(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (clause_id, version)
+);
+
+ALTER TABLE clauses
+    ADD CONSTRAINT fk_clauses_current_version FOREIGN KEY (current_version_id) REFERENCES clause_versions(id);
+
+CREATE TABLE lease_clauses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    clause_id UUID NOT NULL REFERENCES clauses(id) ON DELETE RESTRICT,
+    clause_version_id UUID NOT NULL REFERENCES clause_versions(id) ON DELETE RESTRICT,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (lease_id, clause_id)
+);
+
+CREATE INDEX idx_lease_clauses_lease_id ON lease_clauses(lease_id);
+
+-- Standard clause library
+INSERT INTO clauses (id, category, title, is_mandatory) VALUES
+    ('c1a05e00-0000-4000-8000-000000000001', 'rent', 'Payment of rent', TRUE),
+    ('c1a05e00-0000-4000-8000-000000000002', 'deposit', 'Security deposit', TRUE),
+    ('c1a05e00-0000-4000-8000-000000000003', 'maintenance', 'Repairs and upkeep', TRUE),
+    ('c1a05e00-0000-4000-8000-000000000004', 'maintenance', 'Society maintenance charges', FALSE),
+    ('c1a05e00-0000-4000-8000-000000000005', 'maintenance', 'Utility bills', FALSE),
+    ('c1a05e00-0000-4000-8000-000000000006', 'pets', 'No pets', FALSE),
+    ('c1a05e00-0000-4000-8000-000000000007', 'pets', 'Pets allowed', FALSE),
+    ('c1a05e00-0000-4000-8000-000000000008', 'subletting', 'No subletting', TRUE),
+    ('c1a05e00-0000-4000-8000-000000000009', 'lock_in', 'Lock-in period', FALSE),
+    ('c1a05e00-0000-4000-8000-00000000000a', 'termination', 'Termination by notice', TRUE),
+    ('c1a05e00-0000-4000-8000-00000000000b', 'termination', 'Handover of possession', TRUE),
+    ('c1a05e00-0000-4000-8000-00000000000c', 'termination', 'Termination on breach', FALSE);
+
+INSERT INTO clause_versions (clause_id, version, body) VALUES
+    ('c1a05e00-0000-4000-8000-000000000001', 1, 'The Tenant shall pay the monthly rent in advance on or before the due date agreed in this agreement, by bank transfer, UPI or cheque.'),
+    ('c1a05e00-0000-4000-8000-000000000002', 1, 'The Tenant has paid the Owner an interest-free refundable security deposit. The Owner shall refund the deposit when the Tenant hands over vacant possession, after deducting any unpaid rent, unpaid charges and the cost of repairing damage beyond normal wear and tear.'),
+    ('c1a05e00-0000-4000-8000-000000000003', 1, 'The Tenant shall keep the premises clean and in good condition and shall bear the cost of minor day-to-day repairs. Structural repairs, and repairs to wiring and plumbing not caused by the Tenant, shall be carried out by the Owner at the Owner''s cost.'),
+    ('c1a05e00-0000-4000-8000-000000000004', 1, 'The monthly society maintenance charges shall be paid by the Tenant along with the rent.'),
+    ('c1a05e00-0000-4000-8000-000000000005', 1, 'Electricity, water, gas and internet charges for the period of occupation shall be paid by the Tenant as per actual consumption.'),
+    ('c1a05e00-0000-4000-8000-000000000006', 1, 'The Tenant shall not keep any pets in the premises without the prior written consent of the Owner.'),
+    ('c1a05e00-0000-4000-8000-000000000007', 1, 'The Tenant may keep domestic pets in the premises, provided they do not cause nuisance to neighbours and any damage caused by them is made good by the Tenant.'),
+    ('c1a05e00-0000-4000-8000-000000000008', 1, 'The Tenant shall not sublet, assign or part with possession of the premises or any part of it to any other person.'),
+    ('c1a05e00-0000-4000-8000-000000000009', 1, 'Neither party may terminate this agreement during the lock-in period except for breach of its terms. If the Tenant vacates during the lock-in period, rent for the remainder of the lock-in period shall be payable.'),
+    ('c1a05e00-0000-4000-8000-00000000000a', 1, 'Either party may terminate this agreement by giving the other party written notice of the period agreed in this agreement.'),
+    ('c1a05e00-0000-4000-8000-00000000000b', 1, 'On expiry or termination of this agreement, the Tenant shall hand over vacant and peaceful possession of the premises to the Owner in the condition in which it was received, subject to normal wear and tear.'),
+    ('c1a05e00-0000-4000-8000-00000000000c', 1, 'If the Tenant fails to pay rent for two consecutive months or breaches any other term of this agreement, the Owner may terminate this agreement by written notice and the Tenant shall vacate the premises within fifteen days of the notice.');
+
+UPDATE clauses c
+SET current_version_id = v.id
+FROM clause_versions v
+WHERE v.clause_id = c.id AND v.version = 1 AND c.owner_id IS NULL;
@@ -0,0 +1,15 @@
+UPDATE lease_clauses lc
+SET clause_version_id = v1.id
+FROM clause_versions v2, clause_versions v1, clauses c
+WHERE lc.clause_version_id = v2.id
+  AND v2.clause_id = c.id AND c.owner_id IS NULL AND v2.version = 2
+  AND v1.clause_id = c.id AND v1.version = 1;
+
+UPDATE clauses c
+SET current_version_id = v.id
+FROM clause_versions v
+WHERE v.clause_id = c.id AND v.version = 1 AND c.owner_id IS NULL;
+
+DELETE FROM clause_versions v
+USING clauses c
+WHERE v.clause_id = c.id AND c.owner_id IS NULL AND v.version = 2;
@@ -0,0 +1,12 @@
+-- Version 2 of the standard clauses fills in the lease terms through template variables
+INSERT INTO clause_versions (clause_id, version, body) VALUES
+    ('c1a05e00-0000-4000-8000-000000000001', 2, 'The Tenant shall pay a monthly rent of ₹{{rent}} ({{rent|words}}) in advance on or before the {{due_day|ordinal}} day of each month, by bank transfer, UPI or cheque.'),
+    ('c1a05e00-0000-4000-8000-000000000002', 2, 'The Tenant has paid the Owner an interest-free refundable security deposit of ₹{{deposit}} ({{deposit|words}}). The Owner shall refund the deposit when the Tenant hands over vacant possession, after deducting any unpaid rent, unpaid charges and the cost of repairing damage beyond normal wear and tear.'),
+    ('c1a05e00-0000-4000-8000-000000000004', 2, 'The monthly society maintenance charges of ₹{{maintenance}} shall be paid by the Tenant along with the rent.'),
+    ('c1a05e00-0000-4000-8000-000000000009', 2, 'Neither party may terminate this agreement during the lock-in period of {{lock_in_months}} months from the start of this agreement except for breach of its terms. If the Tenant vacates during the lock-in period, rent for the remainder of the lock-in period shall be payable.'),
+    ('c1a05e00-0000-4000-8000-00000000000a', 2, 'Either party may terminate this agreement by giving the other party {{notice_period_days}} days'' written notice.');
+
+UPDATE clauses c
+SET current_version_id = v.id, updated_at = NOW()
+FROM clause_versions v
+WHERE v.clause_id = c.id AND v.version = 2 AND c.owner_id IS NULL;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS lease_transitions;
+DROP TABLE IF EXISTS lease_tenants;
+DROP INDEX IF EXISTS idx_leases_property_running;
+
+ALTER TABLE leases
+    DROP CONSTRAINT IF EXISTS chk_leases_status,
+    DROP COLUMN IF EXISTS notice_given_at,
+    DROP COLUMN IF EXISTS notice_given_by,
+    DROP COLUMN IF EXISTS vacate_by;
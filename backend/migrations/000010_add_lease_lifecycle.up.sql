@@ -0,0 +1,33 @@
+ALTER TABLE leases
+    ADD CONSTRAINT chk_leases_status CHECK (status IN (
+        'draft', 'pending_signatures', 'signed', 'active', 'notice_period', 'terminated', 'expired', 'renewed'
+    )),
+    ADD COLUMN notice_given_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN notice_given_by UUID REFERENCES users(id),
+    ADD COLUMN vacate_by DATE;
+
+-- A property can have only one running lease at a time
+CREATE UNIQUE INDEX idx_leases_property_running ON leases(property_id)
+    WHERE status IN ('active', 'notice_period');
+
+CREATE TABLE lease_tenants (
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (lease_id, user_id)
+);
+
+CREATE INDEX idx_lease_tenants_user_id ON lease_tenants(user_id);
+
+CREATE TABLE lease_transitions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    event VARCHAR(30) NOT NULL,
+    from_status VARCHAR(30) NOT NULL,
+    to_status VARCHAR(30) NOT NULL,
+    actor_id UUID NOT NULL REFERENCES users(id),
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_lease_transitions_lease_id ON lease_transitions(lease_id, created_at);
@@ -0,0 +1,16 @@
+CREATE TABLE lease_estamps (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL UNIQUE REFERENCES leases(id) ON DELETE CASCADE,
+    certificate_number VARCHAR(30) NOT NULL UNIQUE,
+    state VARCHAR(2) NOT NULL,
+    denomination_paise BIGINT NOT NULL CHECK (denomination_paise > 0),
+    purchaser VARCHAR(255) NOT NULL,
+    issue_date DATE NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(500) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS lease_signing_events;
+DROP TABLE IF EXISTS lease_signers;
+DROP TABLE IF EXISTS lease_signings;
+
+DELETE FROM lease_transitions WHERE actor_id IS NULL;
+ALTER TABLE lease_transitions ALTER COLUMN actor_id SET NOT NULL;
@@ -0,0 +1,57 @@
+-- Transitions applied by the system, such as when the last signature lands, have no actor
+ALTER TABLE lease_transitions ALTER COLUMN actor_id DROP NOT NULL;
+
+CREATE TABLE lease_signings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress'
+        CHECK (status IN ('in_progress', 'completed', 'cancelled')),
+    sequential BOOLEAN NOT NULL DEFAULT FALSE,
+    document_key VARCHAR(500) NOT NULL,
+    document_sha256 VARCHAR(64) NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_lease_signings_lease_id ON lease_signings(lease_id, created_at);
+
+-- A lease can have only one signing in progress at a time
+CREATE UNIQUE INDEX idx_lease_signings_in_progress ON lease_signings(lease_id)
+    WHERE status = 'in_progress';
+
+CREATE TABLE lease_signers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    signing_id UUID NOT NULL REFERENCES lease_signings(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'tenant', 'witness')),
+    user_id UUID REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    phone VARCHAR(16) NOT NULL,
+    signing_order SMALLINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'signed')),
+    token_hash VARCHAR(64) UNIQUE,
+    link_expires_at TIMESTAMP WITH TIME ZONE,
+    signed_at TIMESTAMP WITH TIME ZONE,
+    signature_type VARCHAR(10) NOT NULL DEFAULT '' CHECK (signature_type IN ('', 'drawn', 'typed')),
+    signature_data TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_lease_signers_signing_id ON lease_signers(signing_id, signing_order);
+
+CREATE TABLE lease_signing_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    signing_id UUID NOT NULL REFERENCES lease_signings(id) ON DELETE CASCADE,
+    signer_id UUID REFERENCES lease_signers(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id),
+    event VARCHAR(20) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(500) NOT NULL DEFAULT '',
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_lease_signing_events_signing_id ON lease_signing_events(signing_id, created_at);
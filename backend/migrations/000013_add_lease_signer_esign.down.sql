@@ -0,0 +1,12 @@
+-- eSigns cannot be represented without their signed copies; they revert to unsigned
+UPDATE lease_signers
+SET status = 'pending', signed_at = NULL, signature_type = '', signature_data = ''
+WHERE signature_type = 'esign';
+
+ALTER TABLE lease_signers DROP CONSTRAINT lease_signers_signature_type_check;
+ALTER TABLE lease_signers ADD CONSTRAINT lease_signers_signature_type_check
+    CHECK (signature_type IN ('', 'drawn', 'typed'));
+
+ALTER TABLE lease_signers
+    DROP COLUMN IF EXISTS signed_document_key,
+    DROP COLUMN IF EXISTS esign_txn;
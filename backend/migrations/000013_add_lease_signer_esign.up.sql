@@ -0,0 +1,7 @@
+ALTER TABLE lease_signers
+    ADD COLUMN esign_txn VARCHAR(64) UNIQUE,
+    ADD COLUMN signed_document_key VARCHAR(500) NOT NULL DEFAULT '';
+
+ALTER TABLE lease_signers DROP CONSTRAINT lease_signers_signature_type_check;
+ALTER TABLE lease_signers ADD CONSTRAINT lease_signers_signature_type_check
+    CHECK (signature_type IN ('', 'drawn', 'typed', 'esign'));
@@ -0,0 +1,19 @@
+CREATE TABLE documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(16) NOT NULL UNIQUE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('lease', 'lease_signing', 'lease_signed')),
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    signing_id UUID REFERENCES lease_signings(id) ON DELETE CASCADE,
+    sha256 VARCHAR(64) NOT NULL,
+    storage_key VARCHAR(500) NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((kind = 'lease') = (signing_id IS NULL))
+);
+
+CREATE INDEX idx_documents_lease_id ON documents(lease_id, kind, created_at);
+
+-- A signing round has one document under signature and one signed version,
+-- so a signed version can never be replaced by a regenerated one
+CREATE UNIQUE INDEX idx_documents_signing_id ON documents(signing_id, kind)
+    WHERE signing_id IS NOT NULL;
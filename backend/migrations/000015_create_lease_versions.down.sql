@@ -0,0 +1,4 @@
+ALTER TABLE lease_signings DROP COLUMN IF EXISTS version_id;
+DROP TABLE IF EXISTS lease_version_clauses;
+DROP TABLE IF EXISTS lease_version_tenants;
+DROP TABLE IF EXISTS lease_versions;
@@ -0,0 +1,41 @@
+CREATE TABLE lease_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL CHECK (number > 0),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    term_months SMALLINT NOT NULL,
+    monthly_rent_paise BIGINT NOT NULL,
+    security_deposit_paise BIGINT NOT NULL,
+    maintenance_paise BIGINT NOT NULL,
+    rent_due_day SMALLINT NOT NULL,
+    notice_period_days SMALLINT NOT NULL,
+    lock_in_months SMALLINT NOT NULL,
+    content_sha256 VARCHAR(64) NOT NULL,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (lease_id, number)
+);
+
+-- Tenants and clause text are copied rather than referenced, so a version
+-- reads the same after users are renamed or clauses reworded
+CREATE TABLE lease_version_tenants (
+    version_id UUID NOT NULL REFERENCES lease_versions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    name VARCHAR(255) NOT NULL,
+    PRIMARY KEY (version_id, user_id)
+);
+
+CREATE TABLE lease_version_clauses (
+    version_id UUID NOT NULL REFERENCES lease_versions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    clause_id UUID NOT NULL REFERENCES clauses(id),
+    clause_version_id UUID NOT NULL REFERENCES clause_versions(id),
+    clause_version INTEGER NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (version_id, position)
+);
+
+ALTER TABLE lease_signings ADD COLUMN version_id UUID REFERENCES lease_versions(id);
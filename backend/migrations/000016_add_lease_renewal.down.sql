@@ -0,0 +1,16 @@
+ALTER TABLE lease_versions
+    DROP COLUMN IF EXISTS escalation_compounding,
+    DROP COLUMN IF EXISTS escalation_amount_paise,
+    DROP COLUMN IF EXISTS escalation_basis_points,
+    DROP COLUMN IF EXISTS escalation_type;
+
+DROP INDEX IF EXISTS idx_leases_renewal_due;
+DROP INDEX IF EXISTS idx_leases_renewal_of_id;
+
+ALTER TABLE leases
+    DROP COLUMN IF EXISTS renewal_drafted_at,
+    DROP COLUMN IF EXISTS renewal_of_id,
+    DROP COLUMN IF EXISTS escalation_compounding,
+    DROP COLUMN IF EXISTS escalation_amount_paise,
+    DROP COLUMN IF EXISTS escalation_basis_points,
+    DROP COLUMN IF EXISTS escalation_type;
@@ -0,0 +1,20 @@
+ALTER TABLE leases
+    ADD COLUMN escalation_type VARCHAR(10) NOT NULL DEFAULT 'none'
+        CHECK (escalation_type IN ('none', 'percent', 'fixed')),
+    ADD COLUMN escalation_basis_points INTEGER NOT NULL DEFAULT 0 CHECK (escalation_basis_points BETWEEN 0 AND 10000),
+    ADD COLUMN escalation_amount_paise BIGINT NOT NULL DEFAULT 0 CHECK (escalation_amount_paise >= 0),
+    ADD COLUMN escalation_compounding BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN renewal_of_id UUID REFERENCES leases(id) ON DELETE SET NULL,
+    ADD COLUMN renewal_drafted_at TIMESTAMP WITH TIME ZONE;
+
+-- A lease has at most one renewal at a time; deleting the draft allows another
+CREATE UNIQUE INDEX idx_leases_renewal_of_id ON leases(renewal_of_id) WHERE renewal_of_id IS NOT NULL;
+
+-- Finds active leases nearing their end that have not been renewed yet
+CREATE INDEX idx_leases_renewal_due ON leases(end_date) WHERE status = 'active' AND renewal_drafted_at IS NULL;
+
+ALTER TABLE lease_versions
+    ADD COLUMN escalation_type VARCHAR(10) NOT NULL DEFAULT 'none',
+    ADD COLUMN escalation_basis_points INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN escalation_amount_paise BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN escalation_compounding BOOLEAN NOT NULL DEFAULT FALSE;
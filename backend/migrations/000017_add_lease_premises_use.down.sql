@@ -0,0 +1,3 @@
+ALTER TABLE lease_versions DROP COLUMN IF EXISTS premises_use;
+
+ALTER TABLE leases DROP COLUMN IF EXISTS premises_use;
@@ -0,0 +1,7 @@
+-- The deposit tenancy law allows depends on what the premises are let for
+ALTER TABLE leases
+    ADD COLUMN premises_use VARCHAR(20) NOT NULL DEFAULT 'residential'
+        CHECK (premises_use IN ('residential', 'non_residential'));
+
+ALTER TABLE lease_versions
+    ADD COLUMN premises_use VARCHAR(20) NOT NULL DEFAULT 'residential';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS clause_translations;
@@ -0,0 +1,10 @@
+CREATE TABLE clause_translations (
+    clause_version_id UUID NOT NULL REFERENCES clause_versions(id) ON DELETE CASCADE,
+    lang VARCHAR(5) NOT NULL CHECK (lang IN ('hi', 'mr', 'kn', 'ta')),
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (clause_version_id, lang)
+);
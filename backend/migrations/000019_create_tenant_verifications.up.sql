@@ -0,0 +1,37 @@
+CREATE TABLE tenant_verifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'submitted', 'verified')),
+    father_name VARCHAR(100) NOT NULL,
+    date_of_birth DATE NOT NULL,
+    permanent_address_line1 VARCHAR(255) NOT NULL,
+    permanent_address_line2 VARCHAR(255) NOT NULL DEFAULT '',
+    permanent_locality VARCHAR(100) NOT NULL DEFAULT '',
+    permanent_city VARCHAR(100) NOT NULL,
+    permanent_state CHAR(2) NOT NULL,
+    permanent_pincode CHAR(6) NOT NULL,
+    -- Left empty when the tenant has no previous address
+    previous_address_line1 VARCHAR(255) NOT NULL DEFAULT '',
+    previous_address_line2 VARCHAR(255) NOT NULL DEFAULT '',
+    previous_locality VARCHAR(100) NOT NULL DEFAULT '',
+    previous_city VARCHAR(100) NOT NULL DEFAULT '',
+    previous_state CHAR(2) NOT NULL DEFAULT '',
+    previous_pincode CHAR(6) NOT NULL DEFAULT '',
+    id_proof_type VARCHAR(20) NOT NULL
+        CHECK (id_proof_type IN ('aadhaar', 'pan', 'passport', 'voter_id', 'driving_licence')),
+    id_proof_number VARCHAR(30) NOT NULL,
+    occupation VARCHAR(100) NOT NULL,
+    workplace VARCHAR(255) NOT NULL DEFAULT '',
+    workplace_address VARCHAR(500) NOT NULL DEFAULT '',
+    photo_content_type VARCHAR(100) NOT NULL DEFAULT '',
+    photo_storage_key VARCHAR(500) NOT NULL DEFAULT '',
+    police_station VARCHAR(255) NOT NULL DEFAULT '',
+    reference_number VARCHAR(100) NOT NULL DEFAULT '',
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (lease_id, user_id)
+);
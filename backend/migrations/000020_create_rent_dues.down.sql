@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS rent_dues;
+
+ALTER TABLE lease_versions
+    DROP COLUMN IF EXISTS rent_cycle_months;
+
+ALTER TABLE leases
+    DROP COLUMN IF EXISTS rent_cycle_months;
@@ -0,0 +1,28 @@
+ALTER TABLE leases
+    ADD COLUMN rent_cycle_months SMALLINT NOT NULL DEFAULT 1 CHECK (rent_cycle_months BETWEEN 1 AND 12);
+
+ALTER TABLE lease_versions
+    ADD COLUMN rent_cycle_months SMALLINT NOT NULL DEFAULT 1;
+
+-- One row per rent period of a running lease, regenerated when the lease is
+-- activated, renewed or terminated. Rows with payments against them are kept.
+CREATE TABLE rent_dues (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    due_date DATE NOT NULL,
+    rent_paise BIGINT NOT NULL CHECK (rent_paise >= 0),
+    maintenance_paise BIGINT NOT NULL DEFAULT 0 CHECK (maintenance_paise >= 0),
+    amount_paise BIGINT NOT NULL CHECK (amount_paise >= 0),
+    paid_paise BIGINT NOT NULL DEFAULT 0 CHECK (paid_paise >= 0),
+    prorated BOOLEAN NOT NULL DEFAULT FALSE,
+    escalated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (period_end >= period_start),
+    UNIQUE (lease_id, period_start)
+);
+
+-- Finds unpaid dues across leases by date
+CREATE INDEX idx_rent_dues_unpaid ON rent_dues(due_date) WHERE paid_paise < amount_paise;
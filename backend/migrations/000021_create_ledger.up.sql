@@ -0,0 +1,74 @@
+-- Double-entry ledger. Each running lease has one account of each kind: the
+-- tenants' receivable and security deposit, and the owner's cash and income.
+CREATE TABLE ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id),
+    kind VARCHAR(30) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (lease_id, kind)
+);
+
+-- Entries are never changed or removed; a mistake is undone by posting a
+-- reversal, which points at the entry it reverses.
+CREATE TABLE journal_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id),
+    kind VARCHAR(30) NOT NULL,
+    occurred_on DATE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    rent_due_id UUID REFERENCES rent_dues(id),
+    reverses_id UUID UNIQUE REFERENCES journal_entries(id),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_journal_entries_lease ON journal_entries(lease_id, occurred_on);
+CREATE INDEX idx_journal_entries_rent_due ON journal_entries(rent_due_id) WHERE rent_due_id IS NOT NULL;
+
+CREATE TABLE ledger_postings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    entry_id UUID NOT NULL REFERENCES journal_entries(id),
+    account_id UUID NOT NULL REFERENCES ledger_accounts(id),
+    debit_paise BIGINT NOT NULL DEFAULT 0 CHECK (debit_paise >= 0),
+    credit_paise BIGINT NOT NULL DEFAULT 0 CHECK (credit_paise >= 0),
+    CHECK ((debit_paise > 0) <> (credit_paise > 0))
+);
+
+CREATE INDEX idx_ledger_postings_entry ON ledger_postings(entry_id);
+CREATE INDEX idx_ledger_postings_account ON ledger_postings(account_id);
+
+CREATE FUNCTION ledger_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION '% is append-only; post a reversal instead', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER journal_entries_immutable
+    BEFORE UPDATE OR DELETE ON journal_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_immutable();
+
+CREATE TRIGGER ledger_postings_immutable
+    BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW EXECUTE FUNCTION ledger_immutable();
+
+-- Checked at commit, once all the postings of an entry are in
+CREATE FUNCTION journal_entry_balanced() RETURNS TRIGGER AS $$
+DECLARE
+    debits BIGINT;
+    credits BIGINT;
+BEGIN
+    SELECT COALESCE(SUM(debit_paise), 0), COALESCE(SUM(credit_paise), 0)
+        INTO debits, credits
+        FROM ledger_postings WHERE entry_id = NEW.entry_id;
+    IF debits <> credits THEN
+        RAISE EXCEPTION 'journal entry % is unbalanced: debits % <> credits %', NEW.entry_id, debits, credits;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_postings_balanced
+    AFTER INSERT ON ledger_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION journal_entry_balanced();
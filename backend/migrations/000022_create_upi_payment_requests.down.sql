@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS upi_payment_requests;
+DROP TABLE IF EXISTS payout_profiles;
@@ -0,0 +1,26 @@
+CREATE TABLE payout_profiles (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    vpa VARCHAR(255) NOT NULL,
+    payee_name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One row per UPI payment link generated for a rent due. The transaction
+-- reference is also written into the payment note, which banks carry into
+-- the payee's statement, so a payment can be traced back to its due.
+CREATE TABLE upi_payment_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    rent_due_id UUID NOT NULL REFERENCES rent_dues(id) ON DELETE CASCADE,
+    payee_vpa VARCHAR(255) NOT NULL,
+    payee_name VARCHAR(100) NOT NULL,
+    amount_paise BIGINT NOT NULL CHECK (amount_paise > 0),
+    transaction_ref VARCHAR(35) NOT NULL UNIQUE,
+    note VARCHAR(80) NOT NULL,
+    uri TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_upi_payment_requests_due ON upi_payment_requests(rent_due_id, created_at);
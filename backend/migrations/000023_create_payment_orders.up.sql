@@ -0,0 +1,60 @@
+-- One row per order placed with the payment gateway to collect a rent due.
+-- An order is paid once; a payment the gateway reports for an order already
+-- paid is not credited again.
+CREATE TABLE payment_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    rent_due_id UUID REFERENCES rent_dues(id) ON DELETE SET NULL,
+    gateway VARCHAR(30) NOT NULL,
+    gateway_order_id VARCHAR(100) NOT NULL,
+    amount_paise BIGINT NOT NULL CHECK (amount_paise > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'created' CHECK (status IN ('created', 'failed', 'paid')),
+    gateway_payment_id VARCHAR(100),
+    failure_reason TEXT NOT NULL DEFAULT '',
+    refunded_paise BIGINT NOT NULL DEFAULT 0 CHECK (refunded_paise >= 0 AND refunded_paise <= amount_paise),
+    journal_entry_id UUID REFERENCES journal_entries(id),
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (gateway, gateway_order_id),
+    UNIQUE (gateway, gateway_payment_id),
+    CHECK ((status = 'paid') = (journal_entry_id IS NOT NULL))
+);
+
+CREATE INDEX idx_payment_orders_lease ON payment_orders(lease_id, created_at);
+CREATE INDEX idx_payment_orders_due ON payment_orders(rent_due_id, created_at) WHERE rent_due_id IS NOT NULL;
+
+CREATE TABLE payment_refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES payment_orders(id) ON DELETE CASCADE,
+    gateway_refund_id VARCHAR(100) NOT NULL,
+    amount_paise BIGINT NOT NULL CHECK (amount_paise > 0),
+    reason TEXT NOT NULL,
+    journal_entry_id UUID NOT NULL REFERENCES journal_entries(id),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_payment_refunds_order ON payment_refunds(order_id);
+CREATE UNIQUE INDEX idx_payment_refunds_gateway_id ON payment_refunds(gateway_refund_id);
+
+-- Every webhook delivery is kept, including retries and events we do not act
+-- on. The first delivery of an event is the one processed; later deliveries
+-- point at it through duplicate_of and change nothing.
+CREATE TABLE gateway_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    gateway VARCHAR(30) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(50) NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('processed', 'ignored', 'duplicate')),
+    reason TEXT NOT NULL DEFAULT '',
+    duplicate_of UUID REFERENCES gateway_events(id),
+    payment_order_id UUID REFERENCES payment_orders(id) ON DELETE SET NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((status = 'duplicate') = (duplicate_of IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX idx_gateway_events_event ON gateway_events(gateway, event_id) WHERE duplicate_of IS NULL;
+CREATE INDEX idx_gateway_events_received ON gateway_events(received_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bank_match_candidates;
+DROP TABLE IF EXISTS bank_transactions;
+DROP TABLE IF EXISTS bank_statement_imports;
@@ -0,0 +1,65 @@
+-- One row per bank statement file imported by an owner or manager.
+CREATE TABLE bank_statement_imports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    bank VARCHAR(30) NOT NULL,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    file_sha256 CHAR(64) NOT NULL,
+    formats_version VARCHAR(20) NOT NULL,
+    credit_count INTEGER NOT NULL DEFAULT 0,
+    debit_count INTEGER NOT NULL DEFAULT 0,
+    duplicate_count INTEGER NOT NULL DEFAULT 0,
+    matched_count INTEGER NOT NULL DEFAULT 0,
+    review_count INTEGER NOT NULL DEFAULT 0,
+    unmatched_count INTEGER NOT NULL DEFAULT 0,
+    already_recorded_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_bank_statement_imports_user ON bank_statement_imports(user_id, created_at);
+
+-- One row per credit read from a statement. A credit seen in an earlier
+-- import, as overlapping statements repeat them, has the same fingerprint and
+-- is not stored again. A matched credit has been posted to the lease's ledger
+-- as a payment; an already recorded one was found there under its UTR.
+CREATE TABLE bank_transactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    import_id UUID NOT NULL REFERENCES bank_statement_imports(id) ON DELETE CASCADE,
+    statement_row INTEGER NOT NULL,
+    txn_date DATE NOT NULL,
+    amount_paise BIGINT NOT NULL CHECK (amount_paise > 0),
+    narration TEXT NOT NULL DEFAULT '',
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    utr VARCHAR(30) NOT NULL DEFAULT '',
+    payer_name VARCHAR(100) NOT NULL DEFAULT '',
+    fingerprint VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('matched', 'review', 'unmatched', 'ignored', 'already_recorded')),
+    lease_id UUID REFERENCES leases(id) ON DELETE SET NULL,
+    rent_due_id UUID REFERENCES rent_dues(id) ON DELETE SET NULL,
+    journal_entry_id UUID REFERENCES journal_entries(id),
+    match_score INTEGER NOT NULL DEFAULT 0,
+    match_reason TEXT NOT NULL DEFAULT '',
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, fingerprint),
+    CHECK ((status IN ('matched', 'already_recorded')) = (journal_entry_id IS NOT NULL))
+);
+
+CREATE INDEX idx_bank_transactions_import ON bank_transactions(import_id, statement_row);
+CREATE INDEX idx_bank_transactions_status ON bank_transactions(user_id, status, txn_date);
+CREATE INDEX idx_bank_transactions_utr ON bank_transactions(utr) WHERE utr <> '';
+
+-- The leases and dues a credit that was not posted may be for, until it is.
+CREATE TABLE bank_match_candidates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL REFERENCES bank_transactions(id) ON DELETE CASCADE,
+    lease_id UUID NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    rent_due_id UUID REFERENCES rent_dues(id) ON DELETE SET NULL,
+    score INTEGER NOT NULL,
+    reasons TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_bank_match_candidates_transaction ON bank_match_candidates(transaction_id, score DESC);
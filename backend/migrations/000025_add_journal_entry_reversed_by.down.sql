@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS journal_entries_immutable ON journal_entries;
+CREATE TRIGGER journal_entries_immutable
+    BEFORE UPDATE OR DELETE ON journal_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_immutable();
+DROP FUNCTION IF EXISTS journal_entry_immutable();
+
+ALTER TABLE journal_entries DROP COLUMN IF EXISTS reversed_by_id;
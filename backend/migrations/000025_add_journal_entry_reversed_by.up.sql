@@ -0,0 +1,28 @@
+-- An entry that has been reversed is stamped with its reversal, so whether a
+-- payment still stands can be read from its own row. Index predicates cannot
+-- look at other rows, and the payment reference index that follows covers
+-- only payments not reversed. The unique constraint also stops an entry
+-- being reversed twice, whichever path posts the reversal.
+--
+-- Entries stay append-only otherwise: the stamp is the one change allowed,
+-- and only once.
+ALTER TABLE journal_entries ADD COLUMN reversed_by_id UUID UNIQUE REFERENCES journal_entries(id);
+
+CREATE FUNCTION journal_entry_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.reversed_by_id IS NULL AND NEW.reversed_by_id IS NOT NULL
+        AND to_jsonb(NEW) - 'reversed_by_id' = to_jsonb(OLD) - 'reversed_by_id' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION '% is append-only; post a reversal instead', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER journal_entries_immutable ON journal_entries;
+CREATE TRIGGER journal_entries_immutable
+    BEFORE UPDATE OR DELETE ON journal_entries
+    FOR EACH ROW EXECUTE FUNCTION journal_entry_immutable();
+
+UPDATE journal_entries o SET reversed_by_id = r.id
+    FROM journal_entries r
+    WHERE r.reverses_id = o.id;
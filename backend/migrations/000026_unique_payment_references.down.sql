@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_journal_entries_payment_reference;
+
+ALTER TABLE journal_entries DROP COLUMN IF EXISTS source;
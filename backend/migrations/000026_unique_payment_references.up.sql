@@ -0,0 +1,20 @@
+-- Where a settled payment or refund came from. Entries recorded by hand,
+-- such as cash and cheques, have none.
+ALTER TABLE journal_entries ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT ''
+    CHECK (source IN ('', 'bank_statement', 'payment_gateway'));
+
+ALTER TABLE journal_entries DISABLE TRIGGER journal_entries_immutable;
+UPDATE journal_entries SET source = 'payment_gateway'
+    WHERE id IN (SELECT journal_entry_id FROM payment_orders WHERE journal_entry_id IS NOT NULL)
+       OR id IN (SELECT journal_entry_id FROM payment_refunds);
+UPDATE journal_entries SET source = 'bank_statement'
+    WHERE id IN (SELECT journal_entry_id FROM bank_transactions WHERE status = 'matched');
+ALTER TABLE journal_entries ENABLE TRIGGER journal_entries_immutable;
+
+-- A bank or gateway payment reference (a UTR, a gateway payment ID, or what
+-- identifies a bank statement credit) is recorded at most once on a lease's
+-- ledger, however many people import the statement or at once, until that
+-- payment is reversed. Cash and cheque entries are left out: their reference
+-- is a receipt or cheque number the owner types, which may repeat.
+CREATE UNIQUE INDEX idx_journal_entries_payment_reference ON journal_entries(lease_id, reference)
+    WHERE kind = 'payment' AND source <> '' AND reference <> '' AND reverses_id IS NULL AND reversed_by_id IS NULL;
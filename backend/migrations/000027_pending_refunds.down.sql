@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_payment_refunds_pending;
+
+UPDATE payment_orders o SET refunded_paise = refunded_paise - r.amount_paise
+FROM (SELECT order_id, SUM(amount_paise) AS amount_paise FROM payment_refunds WHERE status = 'pending' GROUP BY order_id) r
+WHERE r.order_id = o.id;
+DELETE FROM payment_refunds WHERE status <> 'processed';
+
+ALTER TABLE payment_refunds
+    DROP CONSTRAINT IF EXISTS payment_refunds_processed_check,
+    DROP CONSTRAINT IF EXISTS payment_refunds_status_check,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS failure_reason,
+    DROP COLUMN IF EXISTS status,
+    ALTER COLUMN journal_entry_id SET NOT NULL,
+    ALTER COLUMN gateway_refund_id SET NOT NULL;
@@ -0,0 +1,22 @@
+-- A refund is stored as pending before the gateway is asked for it, with its
+-- ID sent as the idempotency key, so a refund the gateway made is never lost
+-- when recording it fails. It is completed from the gateway's response or its
+-- refund.processed webhook, and marked failed when the gateway rejects it.
+-- The order's refunded_paise counts pending refunds, so the amount is held
+-- until the gateway answers.
+ALTER TABLE payment_refunds
+    ALTER COLUMN gateway_refund_id DROP NOT NULL,
+    ALTER COLUMN journal_entry_id DROP NOT NULL,
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'processed',
+    ADD COLUMN failure_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+ALTER TABLE payment_refunds
+    ALTER COLUMN status SET DEFAULT 'pending',
+    ADD CONSTRAINT payment_refunds_status_check CHECK (status IN ('pending', 'processed', 'failed')),
+    ADD CONSTRAINT payment_refunds_processed_check CHECK (
+        (status = 'processed') = (journal_entry_id IS NOT NULL)
+        AND (status <> 'processed' OR gateway_refund_id IS NOT NULL)
+    );
+
+CREATE INDEX idx_payment_refunds_pending ON payment_refunds(order_id) WHERE status = 'pending';
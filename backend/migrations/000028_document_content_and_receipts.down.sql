@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_documents_journal_entry_id;
+DELETE FROM documents WHERE kind = 'receipt';
+
+ALTER TABLE documents DROP CONSTRAINT documents_check;
+ALTER TABLE documents DROP CONSTRAINT documents_kind_check;
+ALTER TABLE documents
+    ADD CONSTRAINT documents_kind_check CHECK (kind IN ('lease', 'lease_signing', 'lease_signed')),
+    ADD CONSTRAINT documents_check CHECK ((kind = 'lease') = (signing_id IS NULL));
+
+DROP INDEX IF EXISTS idx_documents_content;
+ALTER TABLE documents
+    DROP COLUMN IF EXISTS journal_entry_id,
+    DROP COLUMN IF EXISTS stamp_margin_mm,
+    DROP COLUMN IF EXISTS content_sha256;
@@ -0,0 +1,23 @@
+-- A printed lease is identified by what it says, not by how it is laid out.
+-- Each stamp margin it is printed with gets its own code and hash, and a
+-- printout is superseded only once the lease says something else. Documents
+-- recorded before this have no content hash and are superseded by the next
+-- one generated.
+ALTER TABLE documents
+    ADD COLUMN content_sha256 VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN stamp_margin_mm INTEGER,
+    ADD COLUMN journal_entry_id UUID REFERENCES journal_entries(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_documents_content ON documents(lease_id, content_sha256, stamp_margin_mm) WHERE kind = 'lease';
+
+-- Rent receipts are documents too, one per payment
+ALTER TABLE documents DROP CONSTRAINT documents_kind_check;
+ALTER TABLE documents DROP CONSTRAINT documents_check;
+ALTER TABLE documents
+    ADD CONSTRAINT documents_kind_check CHECK (kind IN ('lease', 'lease_signing', 'lease_signed', 'receipt')),
+    ADD CONSTRAINT documents_check CHECK (
+        (kind IN ('lease_signing', 'lease_signed')) = (signing_id IS NOT NULL)
+        AND (kind = 'receipt') = (journal_entry_id IS NOT NULL)
+    );
+
+CREATE UNIQUE INDEX idx_documents_journal_entry_id ON documents(journal_entry_id) WHERE journal_entry_id IS NOT NULL;
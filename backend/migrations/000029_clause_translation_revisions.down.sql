@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS lease_version_clause_translations;
+
+DROP TRIGGER IF EXISTS clause_translations_immutable ON clause_translations;
+DROP FUNCTION IF EXISTS clause_translation_immutable();
+
+-- Keep the current revision of each translation and drop the rest
+DELETE FROM clause_translations t
+USING clause_translations later
+WHERE later.clause_version_id = t.clause_version_id AND later.lang = t.lang AND later.revision > t.revision;
+DELETE FROM clause_translations WHERE withdrawn;
+
+DROP INDEX IF EXISTS idx_clause_translations_revision;
+ALTER TABLE clause_translations
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    DROP COLUMN withdrawn,
+    DROP COLUMN revision,
+    DROP COLUMN id;
+ALTER TABLE clause_translations ADD PRIMARY KEY (clause_version_id, lang);
@@ -0,0 +1,35 @@
+-- Translations are never changed in place: each edit adds a revision, and
+-- removing a translation adds a withdrawn one. The latest revision of a clause
+-- version in a language is its current translation.
+ALTER TABLE clause_translations DROP CONSTRAINT clause_translations_pkey;
+ALTER TABLE clause_translations
+    ADD COLUMN id UUID NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+    ADD COLUMN revision INTEGER NOT NULL DEFAULT 1 CHECK (revision > 0),
+    ADD COLUMN withdrawn BOOLEAN NOT NULL DEFAULT FALSE,
+    DROP COLUMN updated_at;
+ALTER TABLE clause_translations ALTER COLUMN revision DROP DEFAULT;
+
+CREATE UNIQUE INDEX idx_clause_translations_revision ON clause_translations(clause_version_id, lang, revision);
+
+CREATE FUNCTION clause_translation_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'clause_translations is append-only; add a revision instead';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER clause_translations_immutable
+    BEFORE UPDATE ON clause_translations
+    FOR EACH ROW EXECUTE FUNCTION clause_translation_immutable();
+
+-- A lease version keeps the translations its clauses had, copied like the
+-- English text, so a translated copy of a signed lease never changes
+CREATE TABLE lease_version_clause_translations (
+    version_id UUID NOT NULL,
+    position INTEGER NOT NULL,
+    lang VARCHAR(5) NOT NULL,
+    translation_id UUID NOT NULL REFERENCES clause_translations(id),
+    title VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (version_id, position, lang),
+    FOREIGN KEY (version_id, position) REFERENCES lease_version_clauses(version_id, position) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE bank_statement_imports DROP COLUMN IF EXISTS unreadable_count;
@@ -0,0 +1,3 @@
+-- Rows whose amount could not be read are counted apart from the debits, so
+-- an owner can tell a statement that was only partly read.
+ALTER TABLE bank_statement_imports ADD COLUMN unreadable_count INTEGER NOT NULL DEFAULT 0;
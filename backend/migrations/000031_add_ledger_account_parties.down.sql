@@ -0,0 +1,14 @@
+-- Postings cannot be moved between accounts, so a lease whose tenants have
+-- separate accounts cannot go back to one account of each kind.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM ledger_accounts GROUP BY lease_id, kind HAVING COUNT(*) > 1) THEN
+        RAISE EXCEPTION 'leases with an account for each tenant exist; the ledger cannot be rolled back';
+    END IF;
+END;
+$$;
+
+DROP INDEX IF EXISTS idx_ledger_accounts_party;
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS ledger_accounts_lease_id_kind_party_id_key;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_lease_id_kind_key UNIQUE (lease_id, kind);
+ALTER TABLE ledger_accounts DROP COLUMN IF EXISTS party_id;
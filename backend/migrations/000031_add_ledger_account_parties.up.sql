@@ -0,0 +1,22 @@
+-- Each party to a lease has its own accounts: every tenant a receivable and a
+-- security deposit, and the owner the cash and income accounts.
+ALTER TABLE ledger_accounts ADD COLUMN party_id UUID REFERENCES users(id);
+
+-- A lease had one receivable and one deposit for all its tenants. Their
+-- history stays with the first tenant to join; the others start afresh.
+UPDATE ledger_accounts a SET party_id = (
+    SELECT lt.user_id FROM lease_tenants lt
+    WHERE lt.lease_id = a.lease_id
+    ORDER BY lt.created_at, lt.user_id
+    LIMIT 1
+)
+WHERE a.kind IN ('tenant_receivable', 'security_deposit');
+
+UPDATE ledger_accounts a SET party_id = l.owner_id
+FROM leases l
+WHERE l.id = a.lease_id AND a.party_id IS NULL;
+
+ALTER TABLE ledger_accounts ALTER COLUMN party_id SET NOT NULL;
+ALTER TABLE ledger_accounts DROP CONSTRAINT ledger_accounts_lease_id_kind_key;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_lease_id_kind_party_id_key UNIQUE (lease_id, kind, party_id);
+CREATE INDEX idx_ledger_accounts_party ON ledger_accounts(party_id);
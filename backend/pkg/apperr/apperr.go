@@ -11,6 +11,7 @@ const (
 	CodeInternal     Code = "internal"
 	CodeUnauthorized Code = "unauthorized"
 	CodeForbidden    Code = "forbidden"
+	CodeRateLimited  Code = "rate_limited"
 )
 
 type AppError struct {
@@ -57,3 +58,7 @@ func Unauthorized(message string, err error) *AppError {
 func Forbidden(message string, err error) *AppError {
 	return New(CodeForbidden, message, err)
 }
+
+func RateLimited(message string, err error) *AppError {
+	return New(CodeRateLimited, message, err)
+}
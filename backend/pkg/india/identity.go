@@ -0,0 +1,79 @@
+package india
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	panPattern      = regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`)
+	aadhaarPattern  = regexp.MustCompile(`^[2-9][0-9]{11}$`)
+	passportPattern = regexp.MustCompile(`^[A-Z][0-9]{7}$`)
+	voterIDPattern  = regexp.MustCompile(`^[A-Z]{3}[0-9]{7}$`)
+)
+
+// Verhoeff tables for the Aadhaar check digit
+var (
+	verhoeffMultiply = [10][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+		{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+		{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+		{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+		{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+		{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+		{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+		{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+		{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+	}
+	verhoeffPermute = [8][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+		{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+		{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+		{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+		{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+		{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+		{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+	}
+)
+
+// IsPAN reports whether value is a well-formed Permanent Account Number, e.g. ABCDE1234F
+func IsPAN(value string) bool {
+	return panPattern.MatchString(value)
+}
+
+// IsPassportNumber reports whether value is a well-formed Indian passport number, e.g. A1234567
+func IsPassportNumber(value string) bool {
+	return passportPattern.MatchString(value)
+}
+
+// IsVoterID reports whether value is a well-formed voter ID (EPIC) number, e.g. ABC1234567
+func IsVoterID(value string) bool {
+	return voterIDPattern.MatchString(value)
+}
+
+// IsAadhaar reports whether value is a 12-digit Aadhaar number with a valid
+// check digit. Spaces between the groups of four are ignored.
+func IsAadhaar(value string) bool {
+	value = strings.ReplaceAll(value, " ", "")
+	if !aadhaarPattern.MatchString(value) {
+		return false
+	}
+
+	check := 0
+	for i := range len(value) {
+		digit := int(value[len(value)-1-i] - '0')
+		check = verhoeffMultiply[check][verhoeffPermute[i%8][digit]]
+	}
+	return check == 0
+}
+
+// MaskAadhaar hides all but the last four digits of an Aadhaar number, e.g. XXXX XXXX 1234
+func MaskAadhaar(value string) string {
+	value = strings.ReplaceAll(value, " ", "")
+	if len(value) < 4 {
+		return value
+	}
+	return "XXXX XXXX " + value[len(value)-4:]
+}
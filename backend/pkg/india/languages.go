@@ -0,0 +1,34 @@
+package india
+
+// Language is a language agreements can be rendered in
+type Language struct {
+	Name string
+	// Script is the ISO 15924 code of the script the language is written in
+	Script string
+}
+
+// English is the language agreements are drafted in
+const English = "en"
+
+// Languages maps ISO 639-1 codes to the languages agreements can be rendered in
+var Languages = map[string]Language{
+	"en": {Name: "English", Script: "Latn"},
+	"hi": {Name: "Hindi", Script: "Deva"},
+	"mr": {Name: "Marathi", Script: "Deva"},
+	"kn": {Name: "Kannada", Script: "Knda"},
+	"ta": {Name: "Tamil", Script: "Taml"},
+}
+
+// IsLanguageCode reports whether code is a supported language code
+func IsLanguageCode(code string) bool {
+	_, ok := Languages[code]
+	return ok
+}
+
+// LanguageName returns the name of a language code, or the code itself if unknown
+func LanguageName(code string) string {
+	if lang, ok := Languages[code]; ok {
+		return lang.Name
+	}
+	return code
+}
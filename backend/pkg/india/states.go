@@ -0,0 +1,67 @@
+package india
+
+import (
+	"regexp"
+	"strings"
+)
+
+// States maps ISO 3166-2:IN subdivision codes of states and union territories to their names
+var States = map[string]string{
+	"AN": "Andaman and Nicobar Islands",
+	"AP": "Andhra Pradesh",
+	"AR": "Arunachal Pradesh",
+	"AS": "Assam",
+	"BR": "Bihar",
+	"CH": "Chandigarh",
+	"CT": "Chhattisgarh",
+	"DH": "Dadra and Nagar Haveli and Daman and Diu",
+	"DL": "Delhi",
+	"GA": "Goa",
+	"GJ": "Gujarat",
+	"HP": "Himachal Pradesh",
+	"HR": "Haryana",
+	"JH": "Jharkhand",
+	"JK": "Jammu and Kashmir",
+	"KA": "Karnataka",
+	"KL": "Kerala",
+	"LA": "Ladakh",
+	"LD": "Lakshadweep",
+	"MH": "Maharashtra",
+	"ML": "Meghalaya",
+	"MN": "Manipur",
+	"MP": "Madhya Pradesh",
+	"MZ": "Mizoram",
+	"NL": "Nagaland",
+	"OR": "Odisha",
+	"PB": "Punjab",
+	"PY": "Puducherry",
+	"RJ": "Rajasthan",
+	"SK": "Sikkim",
+	"TG": "Telangana",
+	"TN": "Tamil Nadu",
+	"TR": "Tripura",
+	"UP": "Uttar Pradesh",
+	"UT": "Uttarakhand",
+	"WB": "West Bengal",
+}
+
+var pincodePattern = regexp.MustCompile(`^[1-9][0-9]{5}$`)
+
+// IsStateCode reports whether code is a known state or union territory code
+func IsStateCode(code string) bool {
+	_, ok := States[strings.ToUpper(code)]
+	return ok
+}
+
+// StateName returns the full name for a state code, or the code itself if unknown
+func StateName(code string) string {
+	if name, ok := States[strings.ToUpper(code)]; ok {
+		return name
+	}
+	return code
+}
+
+// IsPincode reports whether value is a valid 6-digit Indian postal PIN code
+func IsPincode(value string) bool {
+	return pincodePattern.MatchString(value)
+}
@@ -0,0 +1,133 @@
+package inr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Format renders an amount in paise with Indian digit grouping, e.g.
+// 12500000 -> "1,25,000". Paise are shown only when non-zero: "1,25,000.50".
+func Format(paise int64) string {
+	sign := ""
+	if paise < 0 {
+		sign = "-"
+		paise = -paise
+	}
+
+	rupees := Group(paise / 100)
+	if rem := paise % 100; rem != 0 {
+		return sign + rupees + "." + twoDigits(rem)
+	}
+	return sign + rupees
+}
+
+// FormatWithSymbol is Format prefixed with the rupee sign, e.g. "₹1,25,000"
+func FormatWithSymbol(paise int64) string {
+	if paise < 0 {
+		return "-₹" + Format(-paise)
+	}
+	return "₹" + Format(paise)
+}
+
+// Group inserts Indian digit-group separators into a non-negative whole number:
+// the last three digits, then groups of two (1,00,00,000)
+func Group(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	var groups []string
+	for len(head) > 2 {
+		groups = append([]string{head[len(head)-2:]}, groups...)
+		head = head[:len(head)-2]
+	}
+	groups = append([]string{head}, groups...)
+
+	return strings.Join(groups, ",") + "," + tail
+}
+
+var (
+	ones = []string{
+		"", "One", "Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine",
+		"Ten", "Eleven", "Twelve", "Thirteen", "Fourteen", "Fifteen", "Sixteen",
+		"Seventeen", "Eighteen", "Nineteen",
+	}
+	tens = []string{"", "", "Twenty", "Thirty", "Forty", "Fifty", "Sixty", "Seventy", "Eighty", "Ninety"}
+)
+
+// Words spells out an amount in paise using the Indian numbering system, e.g.
+// 12500000 -> "One Lakh Twenty-Five Thousand Rupees" and
+// 5050 -> "Fifty Rupees and Fifty Paise"
+func Words(paise int64) string {
+	prefix := ""
+	if paise < 0 {
+		prefix = "Minus "
+		paise = -paise
+	}
+
+	rupees, rem := paise/100, paise%100
+
+	text := "Zero Rupees"
+	switch {
+	case rupees == 1:
+		text = "One Rupee"
+	case rupees > 1:
+		text = NumberWords(rupees) + " Rupees"
+	case rem > 0:
+		return prefix + NumberWords(rem) + " Paise"
+	}
+
+	if rem > 0 {
+		text += " and " + NumberWords(rem) + " Paise"
+	}
+	return prefix + text
+}
+
+// NumberWords spells out a non-negative whole number using crore, lakh and thousand
+func NumberWords(n int64) string {
+	if n == 0 {
+		return "Zero"
+	}
+
+	var parts []string
+	if crore := n / 10000000; crore > 0 {
+		parts = append(parts, NumberWords(crore)+" Crore")
+		n %= 10000000
+	}
+	if lakh := n / 100000; lakh > 0 {
+		parts = append(parts, belowHundred(lakh)+" Lakh")
+		n %= 100000
+	}
+	if thousand := n / 1000; thousand > 0 {
+		parts = append(parts, belowHundred(thousand)+" Thousand")
+		n %= 1000
+	}
+	if hundred := n / 100; hundred > 0 {
+		parts = append(parts, ones[hundred]+" Hundred")
+		n %= 100
+	}
+	if n > 0 {
+		parts = append(parts, belowHundred(n))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func belowHundred(n int64) string {
+	if n < 20 {
+		return ones[n]
+	}
+	if n%10 == 0 {
+		return tens[n/10]
+	}
+	return tens[n/10] + "-" + ones[n%10]
+}
+
+func twoDigits(n int64) string {
+	if n < 10 {
+		return "0" + strconv.FormatInt(n, 10)
+	}
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,102 @@
+package inr
+
+import "testing"
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{99999, "99,999"},
+		{100000, "1,00,000"},
+		{1234567, "12,34,567"},
+		{10000000, "1,00,00,000"},
+		{123456789012, "1,23,45,67,89,012"},
+	}
+
+	for _, tt := range tests {
+		if got := Group(tt.n); got != tt.want {
+			t.Errorf("Group(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		paise      int64
+		want       string
+		withSymbol string
+	}{
+		{0, "0", "₹0"},
+		{5, "0.05", "₹0.05"},
+		{5050, "50.50", "₹50.50"},
+		{12500000, "1,25,000", "₹1,25,000"},
+		{12500050, "1,25,000.50", "₹1,25,000.50"},
+		{1000000000, "1,00,00,000", "₹1,00,00,000"},
+		{-150000, "-1,500", "-₹1,500"},
+		{-5, "-0.05", "-₹0.05"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.paise); got != tt.want {
+			t.Errorf("Format(%d) = %q, want %q", tt.paise, got, tt.want)
+		}
+		if got := FormatWithSymbol(tt.paise); got != tt.withSymbol {
+			t.Errorf("FormatWithSymbol(%d) = %q, want %q", tt.paise, got, tt.withSymbol)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		paise int64
+		want  string
+	}{
+		{0, "Zero Rupees"},
+		{1, "One Paise"},
+		{50, "Fifty Paise"},
+		{100, "One Rupee"},
+		{101, "One Rupee and One Paise"},
+		{5050, "Fifty Rupees and Fifty Paise"},
+		{1100000, "Eleven Thousand Rupees"},
+		{12500000, "One Lakh Twenty-Five Thousand Rupees"},
+		{1000000000, "One Crore Rupees"},
+		{1234567899, "One Crore Twenty-Three Lakh Forty-Five Thousand Six Hundred Seventy-Eight Rupees and Ninety-Nine Paise"},
+		{1500000000000, "One Thousand Five Hundred Crore Rupees"},
+		{-5000, "Minus Fifty Rupees"},
+		{-25, "Minus Twenty-Five Paise"},
+	}
+
+	for _, tt := range tests {
+		if got := Words(tt.paise); got != tt.want {
+			t.Errorf("Words(%d) = %q, want %q", tt.paise, got, tt.want)
+		}
+	}
+}
+
+func TestNumberWords(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "Zero"},
+		{7, "Seven"},
+		{19, "Nineteen"},
+		{20, "Twenty"},
+		{99, "Ninety-Nine"},
+		{100, "One Hundred"},
+		{1001, "One Thousand One"},
+		{110000, "One Lakh Ten Thousand"},
+		{9999999, "Ninety-Nine Lakh Ninety-Nine Thousand Nine Hundred Ninety-Nine"},
+		{10000001, "One Crore One"},
+	}
+
+	for _, tt := range tests {
+		if got := NumberWords(tt.n); got != tt.want {
+			t.Errorf("NumberWords(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultCountryCode is applied to numbers entered without a country code
+const DefaultCountryCode = "91"
+
+var ErrInvalidNumber = errors.New("invalid phone number")
+
+// Normalize converts a user-entered phone number to E.164 (+<country><number>).
+// Numbers without a country code are assumed to be Indian (+91).
+func Normalize(raw string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+			// formatting characters are dropped
+		default:
+			return "", ErrInvalidNumber
+		}
+	}
+	number := b.String()
+
+	switch {
+	case strings.HasPrefix(number, "+"):
+		number = number[1:]
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case len(number) == 11 && strings.HasPrefix(number, "0"):
+		// trunk prefix used for domestic dialling, e.g. 09876543210
+		number = DefaultCountryCode + number[1:]
+	case len(number) == 10:
+		number = DefaultCountryCode + number
+	}
+
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", ErrInvalidNumber
+	}
+
+	if strings.HasPrefix(number, DefaultCountryCode) && !isIndianMobile(number[len(DefaultCountryCode):]) {
+		return "", ErrInvalidNumber
+	}
+
+	return "+" + number, nil
+}
+
+// Mask hides all but the last four digits, e.g. +91******3210
+func Mask(e164 string) string {
+	if len(e164) <= 7 {
+		return e164
+	}
+	return e164[:3] + strings.Repeat("*", len(e164)-7) + e164[len(e164)-4:]
+}
+
+// isIndianMobile reports whether the national number is a valid 10-digit Indian mobile number
+func isIndianMobile(national string) bool {
+	return len(national) == 10 && national[0] >= '6' && national[0] <= '9'
+}
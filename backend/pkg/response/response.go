@@ -53,6 +53,9 @@ func FromError(c echo.Context, err error) error {
 	var ae *apperr.AppError
 	if errors.As(err, &ae) {
 		status := codeToStatus(ae.Code)
+		if ae.Code == apperr.CodeUnauthorized {
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="api"`)
+		}
 		c.Logger().Error(err)
 		return c.JSON(status, ErrorResponse{
 			Success: false,
@@ -77,6 +80,8 @@ func codeToStatus(code apperr.Code) int {
 		return http.StatusUnauthorized
 	case apperr.CodeForbidden:
 		return http.StatusForbidden
+	case apperr.CodeRateLimited:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
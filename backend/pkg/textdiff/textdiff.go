@@ -0,0 +1,152 @@
+// Package textdiff compares two texts word by word, for showing the changes
+// between drafts as a redline.
+package textdiff
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Op is what happened to a run of text going from the old text to the new one
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// maxCells bounds the comparison table. Texts with more differing words than
+// this allows are shown as entirely replaced.
+const maxCells = 4_000_000
+
+// Edit is a run of text with what happened to it. Joining the text of the
+// equal and delete edits gives the old text; equal and insert give the new one.
+type Edit struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Words returns the edits turning from into to. Words, runs of whitespace and
+// punctuation marks are compared as units, so a changed word is shown as
+// deleted and inserted whole.
+func Words(from, to string) []Edit {
+	a, b := tokenize(from), tokenize(to)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []Edit
+	edits = appendEdit(edits, OpEqual, a[:prefix]...)
+	edits = appendMiddle(edits, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	edits = appendEdit(edits, OpEqual, a[len(a)-suffix:]...)
+	return edits
+}
+
+// appendMiddle diffs the tokens between the common prefix and suffix using
+// the longest common subsequence
+func appendMiddle(edits []Edit, a, b []string) []Edit {
+	if len(a) == 0 || len(b) == 0 || len(a)*len(b) > maxCells {
+		edits = appendEdit(edits, OpDelete, a...)
+		return appendEdit(edits, OpInsert, b...)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = appendEdit(edits, OpEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = appendEdit(edits, OpDelete, a[i])
+			i++
+		default:
+			edits = appendEdit(edits, OpInsert, b[j])
+			j++
+		}
+	}
+	edits = appendEdit(edits, OpDelete, a[i:]...)
+	return appendEdit(edits, OpInsert, b[j:]...)
+}
+
+// appendEdit adds tokens to the last edit when it has the same op
+func appendEdit(edits []Edit, op Op, tokens ...string) []Edit {
+	if len(tokens) == 0 {
+		return edits
+	}
+	text := strings.Join(tokens, "")
+	if n := len(edits); n > 0 && edits[n-1].Op == op {
+		edits[n-1].Text += text
+		return edits
+	}
+	return append(edits, Edit{Op: op, Text: text})
+}
+
+// tokenize splits text into words, runs of whitespace and single other
+// characters. Separators inside numbers such as 1,25,000.50 are kept with the
+// number, so a changed amount reads as one change.
+func tokenize(text string) []string {
+	var tokens []string
+	start := -1
+	kind := 0
+	prev := rune(0)
+	for i, r := range text {
+		k := tokenKind(r)
+		if (r == ',' || r == '.') && unicode.IsDigit(prev) {
+			if next, _ := utf8.DecodeRuneInString(text[i+1:]); unicode.IsDigit(next) {
+				k = kindWord
+			}
+		}
+		prev = r
+		if start >= 0 && k == kind && k != kindOther {
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, text[start:i])
+		}
+		start, kind = i, k
+	}
+	if start >= 0 {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+const (
+	kindWord = iota + 1
+	kindSpace
+	kindOther
+)
+
+func tokenKind(r rune) int {
+	switch {
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+		return kindWord
+	case unicode.IsSpace(r):
+		return kindSpace
+	}
+	return kindOther
+}
@@ -0,0 +1,131 @@
+package textdiff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []Edit
+	}{
+		{
+			name: "unchanged",
+			from: "Rent is due monthly.",
+			to:   "Rent is due monthly.",
+			want: []Edit{{OpEqual, "Rent is due monthly."}},
+		},
+		{
+			name: "both empty",
+		},
+		{
+			name: "from empty",
+			to:   "New clause",
+			want: []Edit{{OpInsert, "New clause"}},
+		},
+		{
+			name: "to empty",
+			from: "Old clause",
+			want: []Edit{{OpDelete, "Old clause"}},
+		},
+		{
+			name: "insertion",
+			from: "Rent is due monthly",
+			to:   "Rent is due on the 5th monthly",
+			want: []Edit{{OpEqual, "Rent is due "}, {OpInsert, "on the 5th "}, {OpEqual, "monthly"}},
+		},
+		{
+			name: "deletion",
+			from: "Rent is due on the 5th monthly",
+			to:   "Rent is due monthly",
+			want: []Edit{{OpEqual, "Rent is due "}, {OpDelete, "on the 5th "}, {OpEqual, "monthly"}},
+		},
+		{
+			name: "changed amount is one replacement",
+			from: "Rent of 15,000.50 rupees",
+			to:   "Rent of 18,500.50 rupees",
+			want: []Edit{{OpEqual, "Rent of "}, {OpDelete, "15,000.50"}, {OpInsert, "18,500.50"}, {OpEqual, " rupees"}},
+		},
+		{
+			name: "comma after a number is punctuation",
+			from: "Rent 15000, paid",
+			to:   "Rent 15000; paid",
+			want: []Edit{{OpEqual, "Rent 15000"}, {OpDelete, ","}, {OpInsert, ";"}, {OpEqual, " paid"}},
+		},
+		{
+			name: "replacements between common words",
+			from: "the tenant shall pay rent",
+			to:   "the owner shall collect rent",
+			want: []Edit{
+				{OpEqual, "the "}, {OpDelete, "tenant"}, {OpInsert, "owner"}, {OpEqual, " shall "},
+				{OpDelete, "pay"}, {OpInsert, "collect"}, {OpEqual, " rent"},
+			},
+		},
+		{
+			name: "Devanagari words keep their vowel signs and conjuncts",
+			from: "मासिक किराया ₹15,000 प्रति माह",
+			to:   "वार्षिक किराया ₹15,000 प्रति माह",
+			want: []Edit{{OpDelete, "मासिक"}, {OpInsert, "वार्षिक"}, {OpEqual, " किराया ₹15,000 प्रति माह"}},
+		},
+		{
+			name: "Devanagari amount",
+			from: "किराया ₹15,000 प्रति माह",
+			to:   "किराया ₹18,000 प्रति माह",
+			want: []Edit{{OpEqual, "किराया ₹"}, {OpDelete, "15,000"}, {OpInsert, "18,000"}, {OpEqual, " प्रति माह"}},
+		},
+		{
+			name: "Telugu insertion",
+			from: "నెలవారీ అద్దె",
+			to:   "నెలవారీ అద్దె మరియు నిర్వహణ",
+			want: []Edit{{OpEqual, "నెలవారీ అద్దె"}, {OpInsert, " మరియు నిర్వహణ"}},
+		},
+		{
+			name: "Telugu word changed as a whole",
+			from: "ఈ అద్దె",
+			to:   "ఈ అద్దెలు",
+			want: []Edit{{OpEqual, "ఈ "}, {OpDelete, "అద్దె"}, {OpInsert, "అద్దెలు"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Words(tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Words(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+			checkRebuilds(t, got, tt.from, tt.to)
+		})
+	}
+}
+
+func TestWordsTooManyChanges(t *testing.T) {
+	from := strings.Repeat("a ", 1500)
+	to := strings.Repeat("b ", 1500)
+
+	got := Words(from, to)
+	want := []Edit{{OpDelete, from[:len(from)-1]}, {OpInsert, to[:len(to)-1]}, {OpEqual, " "}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Words gave %d edits, want the whole text replaced", len(got))
+	}
+	checkRebuilds(t, got, from, to)
+}
+
+// checkRebuilds checks the edits give back both texts
+func checkRebuilds(t *testing.T, edits []Edit, from, to string) {
+	t.Helper()
+	var before, after strings.Builder
+	for _, e := range edits {
+		if e.Op != OpInsert {
+			before.WriteString(e.Text)
+		}
+		if e.Op != OpDelete {
+			after.WriteString(e.Text)
+		}
+	}
+	if before.String() != from || after.String() != to {
+		t.Errorf("edits rebuild %q and %q, want %q and %q", before.String(), after.String(), from, to)
+	}
+}
@@ -0,0 +1,86 @@
+// Package upi builds UPI payment links (upi://pay URIs, as in the NPCI
+// linking specification) and their QR codes.
+package upi
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// vpaPattern matches a virtual payment address: a handle, then @ and the PSP's name
+var vpaPattern = regexp.MustCompile(`^[a-zA-Z0-9.\-_]{2,256}@[a-zA-Z][a-zA-Z0-9]{1,63}$`)
+
+// IsVPA reports whether s is a well-formed UPI ID, e.g. "asha.rao@okhdfcbank"
+func IsVPA(s string) bool {
+	return vpaPattern.MatchString(s)
+}
+
+// Intent asks the payer's UPI app to pay a fixed amount to a payee
+type Intent struct {
+	VPA         string
+	PayeeName   string
+	AmountPaise int64
+	// Reference is the payee's transaction reference (tr), at most 35 characters
+	Reference string
+	// Note is shown to the payer and carried into the payee's statement (tn)
+	Note string
+}
+
+// URI returns the upi://pay link for the intent
+func (i Intent) URI() string {
+	params := []string{
+		"pa=" + escape(i.VPA),
+		"pn=" + escape(i.PayeeName),
+		"am=" + fmt.Sprintf("%d.%02d", i.AmountPaise/100, i.AmountPaise%100),
+		"cu=INR",
+	}
+	if i.Reference != "" {
+		params = append(params, "tr="+escape(i.Reference))
+	}
+	if i.Note != "" {
+		params = append(params, "tn="+escape(i.Note))
+	}
+	return "upi://pay?" + strings.Join(params, "&")
+}
+
+// escape query-escapes s the way UPI apps expect: spaces as %20, and the @
+// of a VPA left as it is, since some apps do not decode %40
+func escape(s string) string {
+	return strings.NewReplacer("+", "%20", "%40", "@").Replace(url.QueryEscape(s))
+}
+
+// PNG returns a QR code of content as a size×size pixel PNG
+func PNG(content string, size int) ([]byte, error) {
+	png, err := qrcode.Encode(content, qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("encode QR code: %w", err)
+	}
+	return png, nil
+}
+
+// SVG returns a QR code of content as a scalable SVG, one unit per module
+func SVG(content string) ([]byte, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("encode QR code: %w", err)
+	}
+	bitmap := qr.Bitmap()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, len(bitmap), len(bitmap))
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, len(bitmap), len(bitmap))
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes(), nil
+}